@@ -31,12 +31,16 @@ import (
 
 	"github.com/stolostron/multicluster-global-hub/agent/pkg/configs"
 	"github.com/stolostron/multicluster-global-hub/agent/pkg/controllers"
+	"github.com/stolostron/multicluster-global-hub/agent/pkg/controllers/inventory/fullsync"
+	"github.com/stolostron/multicluster-global-hub/pkg/compressor"
 	"github.com/stolostron/multicluster-global-hub/pkg/constants"
 	"github.com/stolostron/multicluster-global-hub/pkg/jobs"
 	"github.com/stolostron/multicluster-global-hub/pkg/logger"
 	commonobjects "github.com/stolostron/multicluster-global-hub/pkg/objects"
+	"github.com/stolostron/multicluster-global-hub/pkg/statistics"
 	"github.com/stolostron/multicluster-global-hub/pkg/transport"
 	"github.com/stolostron/multicluster-global-hub/pkg/transport/controller"
+	"github.com/stolostron/multicluster-global-hub/pkg/transport/producer"
 	"github.com/stolostron/multicluster-global-hub/pkg/utils"
 )
 
@@ -54,6 +58,11 @@ func main() {
 	// adding and parsing flags should be done before the call of 'ctrl.GetConfigOrDie()',
 	// otherwise kubeconfig will not be passed to agent main process
 	agentConfig := parseFlags()
+	if agentConfig.TransportConfig.OutboxDir != "" {
+		producer.RegisterMetrics()
+	}
+	statistics.RegisterTransportMetrics()
+	fullsync.RegisterMetrics()
 
 	restConfig := ctrl.GetConfigOrDie()
 	restConfig.QPS = agentConfig.QPS
@@ -126,6 +135,13 @@ func parseFlags() *configs.AgentConfig {
 		"The goroutine number to propagate the bundles on managed cluster.")
 	pflag.IntVar(&agentConfig.TransportConfig.FailureThreshold, "transport-failure-threshold", 10,
 		"Restart the pod if the transport error count exceeds the transport-failure-threshold within 5 minutes.")
+	pflag.StringVar((*string)(&agentConfig.TransportConfig.CompressionType), "transport-compression-type",
+		string(compressor.NoOp), "The compression type of the status events, can be 'no-op', 'gzip', 'zstd' or 'snappy'.")
+	pflag.StringVar(&agentConfig.TransportConfig.OutboxDir, "transport-outbox-dir", "",
+		"The directory to spill the undelivered status events, and replay them once the transport recovers. "+
+			"The outbox is disabled if it's empty.")
+	pflag.IntVar(&agentConfig.TransportConfig.OutboxMaxSizeKB, "transport-outbox-max-size-kb",
+		producer.DefaultOutboxMaxSizeKB, "The size bound of the outbox, the oldest events are dropped once it's exceeded.")
 	pflag.BoolVar(&agentConfig.SpecEnforceHohRbac, "enforce-hoh-rbac", false,
 		"enable hoh RBAC or not, default false")
 	pflag.IntVar(&agentConfig.StatusDeltaCountSwitchFactor,
@@ -151,6 +167,8 @@ func parseFlags() *configs.AgentConfig {
 		"Enable StackRox integration")
 	pflag.DurationVar(&agentConfig.StackroxPollInterval, "stackrox-poll-interval", 30*time.Minute,
 		"The interval between each StackRox polling")
+	pflag.DurationVar(&agentConfig.InventoryFullSyncInterval, "inventory-full-sync-interval", time.Hour,
+		"The interval to converge the inventory with the resources of the hub, the full sync is disabled if it's 0.")
 	pflag.Parse()
 
 	return agentConfig
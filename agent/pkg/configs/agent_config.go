@@ -26,6 +26,7 @@ type AgentConfig struct {
 	Standalone                   bool
 	EnableStackroxIntegration    bool
 	StackroxPollInterval         time.Duration
+	InventoryFullSyncInterval    time.Duration
 }
 
 func SetAgentConfig(agentConfig *AgentConfig) {
@@ -0,0 +1,254 @@
+// Copyright (c) 2025 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package fullsync
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/stolostron/multicluster-global-hub/agent/pkg/configs"
+	"github.com/stolostron/multicluster-global-hub/pkg/logger"
+)
+
+// reportedFlushInterval is how often the changed record of the reported resources is persisted.
+var reportedFlushInterval = 30 * time.Second
+
+// Reporter reports a resource type of the hub to the inventory.
+type Reporter[T any] interface {
+	// ResourceType is the type of the resource in the inventory, e.g. k8s_cluster
+	ResourceType() string
+	// List returns the local resources which should be held by the inventory, keyed by the local resource id
+	List(ctx context.Context) (map[string]T, error)
+	Create(ctx context.Context, resource T) error
+	// Update updates the resource, the previous is the one reported before, it's nil if the resource isn't reported
+	Update(ctx context.Context, previous, resource T) error
+	Delete(ctx context.Context, resource T) error
+	Equal(a, b T) bool
+	// Marshal encodes the resource into the persisted record, only the fields which are required to update and delete
+	// the resource need to be kept
+	Marshal(resource T) ([]byte, error)
+	Unmarshal(data []byte) (T, error)
+}
+
+// Drift is the number of the resources which are converged by a full sync pass.
+type Drift struct {
+	Created int
+	Updated int
+	Deleted int
+}
+
+// FullSyncer periodically converges the inventory with the local resources. The inventory API doesn't list the
+// resources of the reporter, so the local resources are compared with the ones recorded as reported by the agent: the
+// missing and changed resources are reported, and the recorded resources which don't exist on the hub are deleted.
+// The record is persisted and seeded before the first pass, so that the resources deleted while the agent is down are
+// deleted as well, but the resources held by the inventory without being recorded, e.g. the ones reported by an agent
+// of the previous release, aren't known and are left. Every local resource is reported again in the pass, so that the
+// resources lost by the inventory are recreated.
+type FullSyncer[T any] struct {
+	log      *zap.SugaredLogger
+	reporter Reporter[T]
+	reported *Reported[T]
+	interval time.Duration
+	// store persists the reported resources, the record isn't persisted if it's nil
+	store  *reportedStore
+	seeded bool
+	synced bool
+}
+
+// AddFullSyncer adds the full syncer of the reporter to the manager, it's disabled if the interval isn't positive.
+func AddFullSyncer[T any](mgr ctrl.Manager, reporter Reporter[T], reported *Reported[T],
+	interval time.Duration,
+) error {
+	if interval <= 0 {
+		return nil
+	}
+	syncer := NewFullSyncer(reporter, reported, interval)
+	syncer.store = &reportedStore{
+		client:       mgr.GetClient(),
+		namespace:    configs.GetAgentConfig().PodNamespace,
+		resourceType: reporter.ResourceType(),
+	}
+	return mgr.Add(syncer)
+}
+
+func NewFullSyncer[T any](reporter Reporter[T], reported *Reported[T], interval time.Duration) *FullSyncer[T] {
+	return &FullSyncer[T]{
+		log:      logger.ZapLogger(fmt.Sprintf("inventory-full-sync-%s", reporter.ResourceType())),
+		reporter: reporter,
+		reported: reported,
+		interval: interval,
+	}
+}
+
+// Start runs the pass once the interval elapses, the first pass is delayed so that the resources are reported by the
+// watch events at first. The pass isn't run until the record is seeded, and the changed record is persisted in the
+// reportedFlushInterval.
+func (s *FullSyncer[T]) Start(ctx context.Context) error {
+	if err := s.Seed(ctx); err != nil {
+		s.log.Warnw("failed to seed the reported resources", "error", err)
+	}
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+	flushTicker := time.NewTicker(reportedFlushInterval)
+	defer flushTicker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-flushTicker.C:
+			if err := s.Flush(ctx); err != nil {
+				s.log.Warnw("failed to persist the reported resources", "error", err)
+			}
+		case <-ticker.C:
+			if err := s.Seed(ctx); err != nil {
+				s.log.Warnw("failed to seed the reported resources, skip the pass", "error", err)
+				continue
+			}
+			drift, err := s.Sync(ctx)
+			if err != nil {
+				s.log.Warnw("failed to converge the inventory", "error", err)
+			}
+			s.log.Infow("the inventory is synced", "created", drift.Created, "updated", drift.Updated,
+				"deleted", drift.Deleted)
+			if err := s.Flush(ctx); err != nil {
+				s.log.Warnw("failed to persist the reported resources", "error", err)
+			}
+		}
+	}
+}
+
+// Seed adds the resources persisted before the agent restarts into the record once, the undecodable resources are
+// skipped, since they can't be deleted anyway.
+func (s *FullSyncer[T]) Seed(ctx context.Context) error {
+	if s.seeded || s.store == nil {
+		s.seeded = true
+		return nil
+	}
+	persisted, err := s.store.load(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load the reported %s: %w", s.reporter.ResourceType(), err)
+	}
+
+	resources := map[string]T{}
+	for id, data := range persisted {
+		resource, err := s.reporter.Unmarshal(data)
+		if err != nil {
+			s.log.Warnw("failed to decode the reported resource", "id", id, "error", err)
+			continue
+		}
+		resources[id] = resource
+	}
+	s.reported.seed(resources)
+	s.seeded = true
+	s.log.Infow("the reported resources are seeded", "count", len(resources))
+	return nil
+}
+
+// Flush persists the record if it's changed since the last flush.
+func (s *FullSyncer[T]) Flush(ctx context.Context) error {
+	if s.store == nil {
+		return nil
+	}
+	reported, changed := s.reported.changedSnapshot()
+	if !changed {
+		return nil
+	}
+
+	persisted := map[string]json.RawMessage{}
+	for id, resource := range reported {
+		data, err := s.reporter.Marshal(resource)
+		if err != nil {
+			s.log.Warnw("failed to encode the reported resource", "id", id, "error", err)
+			continue
+		}
+		persisted[id] = data
+	}
+	if err := s.store.save(ctx, persisted); err != nil {
+		s.reported.markChanged()
+		return fmt.Errorf("failed to save the reported %s: %w", s.reporter.ResourceType(), err)
+	}
+	return nil
+}
+
+// Sync converges the inventory with the local resources, and returns the drift which is converged in the pass. The
+// drift of the first pass isn't counted in the metrics: the seeded resources only keep the persisted fields, and the
+// changes while the agent is down aren't left by the watch events.
+func (s *FullSyncer[T]) Sync(ctx context.Context) (Drift, error) {
+	drift := Drift{}
+	resourceType := s.reporter.ResourceType()
+
+	local, err := s.reporter.List(ctx)
+	if err != nil {
+		failedSyncCounter.WithLabelValues(resourceType).Inc()
+		return drift, fmt.Errorf("failed to list the local %s: %w", resourceType, err)
+	}
+	reported := s.reported.snapshot()
+
+	var errs []error
+	for id, resource := range local {
+		previous, found := reported[id]
+		if err := s.report(ctx, previous, resource, found); err != nil {
+			errs = append(errs, fmt.Errorf("failed to report the %s %s: %w", resourceType, id, err))
+			continue
+		}
+		s.reported.Record(id, resource)
+		if !found {
+			drift.Created++
+		} else if !s.reporter.Equal(previous, resource) {
+			drift.Updated++
+		}
+	}
+
+	for id, previous := range reported {
+		if _, found := local[id]; found {
+			continue
+		}
+		if err := s.reporter.Delete(ctx, previous); err != nil {
+			errs = append(errs, fmt.Errorf("failed to delete the %s %s: %w", resourceType, id, err))
+			continue
+		}
+		s.reported.Forget(id)
+		drift.Deleted++
+	}
+
+	if s.synced {
+		driftCounter.WithLabelValues(resourceType, DriftActionCreated).Add(float64(drift.Created))
+		driftCounter.WithLabelValues(resourceType, DriftActionUpdated).Add(float64(drift.Updated))
+		driftCounter.WithLabelValues(resourceType, DriftActionDeleted).Add(float64(drift.Deleted))
+	}
+	s.synced = true
+	if len(errs) > 0 {
+		failedSyncCounter.WithLabelValues(resourceType).Inc()
+	}
+	return drift, errors.Join(errs...)
+}
+
+// report creates the resource if it isn't reported by the agent, otherwise updates it. The other request is tried if
+// the first one fails, since the inventory might not hold the reported resource, or hold the one reported before the
+// agent restarted.
+func (s *FullSyncer[T]) report(ctx context.Context, previous, resource T, reported bool) error {
+	create := s.reporter.Create
+	update := func(ctx context.Context, resource T) error {
+		return s.reporter.Update(ctx, previous, resource)
+	}
+	first, second := create, update
+	if reported {
+		first, second = update, create
+	}
+	err := first(ctx, resource)
+	if err == nil {
+		return nil
+	}
+	if secondErr := second(ctx, resource); secondErr != nil {
+		return err
+	}
+	return nil
+}
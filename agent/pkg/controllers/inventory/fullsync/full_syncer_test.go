@@ -0,0 +1,174 @@
+// Copyright (c) 2025 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package fullsync
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+type resource struct {
+	id      string
+	content string
+}
+
+// fakeReporter holds the inventory in memory, the update fails if the resource doesn't exist in the inventory
+type fakeReporter struct {
+	local     map[string]*resource
+	inventory map[string]*resource
+	failed    map[string]bool
+}
+
+func (r *fakeReporter) ResourceType() string { return "fake" }
+
+func (r *fakeReporter) List(ctx context.Context) (map[string]*resource, error) {
+	return r.local, nil
+}
+
+func (r *fakeReporter) Create(ctx context.Context, res *resource) error {
+	if r.failed[res.id] {
+		return fmt.Errorf("failed to create %s", res.id)
+	}
+	if _, found := r.inventory[res.id]; found {
+		return fmt.Errorf("%s already exists", res.id)
+	}
+	r.inventory[res.id] = res
+	return nil
+}
+
+func (r *fakeReporter) Update(ctx context.Context, previous, res *resource) error {
+	if _, found := r.inventory[res.id]; !found {
+		return fmt.Errorf("%s not found", res.id)
+	}
+	r.inventory[res.id] = res
+	return nil
+}
+
+func (r *fakeReporter) Delete(ctx context.Context, res *resource) error {
+	delete(r.inventory, res.id)
+	return nil
+}
+
+func (r *fakeReporter) Equal(a, b *resource) bool { return *a == *b }
+
+// Marshal only keeps the id, like the reporters only keep the fields to delete the resource
+func (r *fakeReporter) Marshal(res *resource) ([]byte, error) { return json.Marshal(res.id) }
+
+func (r *fakeReporter) Unmarshal(data []byte) (*resource, error) {
+	res := &resource{}
+	return res, json.Unmarshal(data, &res.id)
+}
+
+func TestFullSync(t *testing.T) {
+	ctx := context.Background()
+	reporter := &fakeReporter{
+		local: map[string]*resource{
+			"a": {id: "a", content: "1"},
+			"b": {id: "b", content: "1"},
+			"c": {id: "c", content: "1"},
+		},
+		inventory: map[string]*resource{},
+		failed:    map[string]bool{},
+	}
+	reported := NewReported[*resource]()
+	syncer := NewFullSyncer[*resource](reporter, reported, time.Hour)
+	counted := func(action string) float64 {
+		return testutil.ToFloat64(driftCounter.WithLabelValues("fake", action))
+	}
+	created, updated, deleted := counted(DriftActionCreated), counted(DriftActionUpdated), counted(DriftActionDeleted)
+
+	// the resource a is reported by the watch events, the others are missed
+	reporter.inventory["a"] = reporter.local["a"]
+	reported.Record("a", reporter.local["a"])
+	drift, err := syncer.Sync(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, Drift{Created: 2}, drift)
+	assert.Equal(t, reporter.local, reporter.inventory)
+	// the drift of the first pass isn't counted
+	assert.Equal(t, created, counted(DriftActionCreated))
+
+	// converged
+	drift, err = syncer.Sync(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, Drift{}, drift)
+
+	// the change and the deletion are missed, and the inventory loses the resource c
+	reporter.local["a"] = &resource{id: "a", content: "2"}
+	delete(reporter.local, "b")
+	delete(reporter.inventory, "c")
+	drift, err = syncer.Sync(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, Drift{Updated: 1, Deleted: 1}, drift)
+	assert.Equal(t, reporter.local, reporter.inventory)
+	assert.Len(t, reported.snapshot(), 2)
+	assert.Equal(t, updated+1, counted(DriftActionUpdated))
+	assert.Equal(t, deleted+1, counted(DriftActionDeleted))
+
+	// the failed resource isn't recorded as reported, and it's retried in the next pass
+	reporter.local["d"] = &resource{id: "d", content: "1"}
+	reporter.failed["d"] = true
+	drift, err = syncer.Sync(ctx)
+	assert.Error(t, err)
+	assert.Equal(t, Drift{}, drift)
+	assert.NotContains(t, reported.snapshot(), "d")
+
+	reporter.failed["d"] = false
+	drift, err = syncer.Sync(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, Drift{Created: 1}, drift)
+	assert.Equal(t, reporter.local, reporter.inventory)
+}
+
+func TestSeedReported(t *testing.T) {
+	ctx := context.Background()
+	fakeClient := fake.NewClientBuilder().Build()
+	reporter := &fakeReporter{
+		local: map[string]*resource{
+			"a": {id: "a", content: "1"},
+			"b": {id: "b", content: "1"},
+		},
+		inventory: map[string]*resource{},
+		failed:    map[string]bool{},
+	}
+	newSyncer := func(reported *Reported[*resource]) *FullSyncer[*resource] {
+		syncer := NewFullSyncer[*resource](reporter, reported, time.Hour)
+		syncer.store = &reportedStore{client: fakeClient, namespace: "default", resourceType: "fake"}
+		return syncer
+	}
+
+	// the reported resources are persisted once they're changed
+	syncer := newSyncer(NewReported[*resource]())
+	require.NoError(t, syncer.Seed(ctx))
+	_, err := syncer.Sync(ctx)
+	require.NoError(t, err)
+	require.NoError(t, syncer.Flush(ctx))
+	cm := &corev1.ConfigMap{}
+	require.NoError(t, fakeClient.Get(ctx, client.ObjectKey{Namespace: "default", Name: ReportedStateConfigMap}, cm))
+	assert.JSONEq(t, `{"a":"a","b":"b"}`, cm.Data["fake"])
+
+	// the resource b is deleted while the agent is down, it's deleted from the inventory by the first pass after the
+	// agent restarts, and the record is persisted again
+	delete(reporter.local, "b")
+	reported := NewReported[*resource]()
+	syncer = newSyncer(reported)
+	require.NoError(t, syncer.Seed(ctx))
+	assert.Len(t, reported.snapshot(), 2)
+	drift, err := syncer.Sync(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, drift.Deleted)
+	assert.Equal(t, reporter.local, reporter.inventory)
+	require.NoError(t, syncer.Flush(ctx))
+	require.NoError(t, fakeClient.Get(ctx, client.ObjectKey{Namespace: "default", Name: ReportedStateConfigMap}, cm))
+	assert.JSONEq(t, `{"a":"a"}`, cm.Data["fake"])
+}
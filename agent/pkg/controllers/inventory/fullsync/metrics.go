@@ -0,0 +1,34 @@
+// Copyright (c) 2025 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package fullsync
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const (
+	// DriftActionCreated means the local resource isn't reported to the inventory
+	DriftActionCreated = "created"
+	// DriftActionUpdated means the local resource is changed since it's reported to the inventory
+	DriftActionUpdated = "updated"
+	// DriftActionDeleted means the resource reported to the inventory doesn't exist on the hub
+	DriftActionDeleted = "deleted"
+)
+
+var (
+	driftCounter = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "multicluster_global_hub_inventory_drift_total",
+		Help: "The number of the inventory resources converged by the full sync of the agent.",
+	}, []string{"resource_type", "action"})
+	failedSyncCounter = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "multicluster_global_hub_inventory_full_sync_failures_total",
+		Help: "The number of the full sync passes which fail to converge the inventory.",
+	}, []string{"resource_type"})
+)
+
+// RegisterMetrics will register the inventory full sync metrics with the global prometheus registry
+func RegisterMetrics() {
+	metrics.Registry.MustRegister(driftCounter, failedSyncCounter)
+}
@@ -0,0 +1,111 @@
+// Copyright (c) 2025 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package fullsync
+
+import (
+	"sync"
+)
+
+// Reported records the resources which are reported to the inventory by the agent, keyed by the local resource id.
+// It's shared by the syncer of the watch events and the full syncer, the nil Reported records nothing. The record is
+// persisted by the full syncer once it's changed, so that it's seeded with the resources reported before the restart.
+type Reported[T any] struct {
+	lock      sync.Mutex
+	resources map[string]T
+	changed   bool
+}
+
+func NewReported[T any]() *Reported[T] {
+	return &Reported[T]{resources: map[string]T{}}
+}
+
+// Record records the resource once it's created or updated in the inventory.
+func (r *Reported[T]) Record(id string, resource T) {
+	if r == nil {
+		return
+	}
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.resources[id] = resource
+	r.changed = true
+}
+
+// Forget removes the resource once it's deleted from the inventory.
+func (r *Reported[T]) Forget(id string) {
+	if r == nil {
+		return
+	}
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if _, found := r.resources[id]; found {
+		delete(r.resources, id)
+		r.changed = true
+	}
+}
+
+// Get returns the resource which is reported to the inventory.
+func (r *Reported[T]) Get(id string) (T, bool) {
+	var resource T
+	if r == nil {
+		return resource, false
+	}
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	resource, found := r.resources[id]
+	return resource, found
+}
+
+// seed adds the resources which are reported before the agent restarts, the resources recorded since the agent starts
+// are newer and kept.
+func (r *Reported[T]) seed(resources map[string]T) {
+	if r == nil {
+		return
+	}
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	for id, resource := range resources {
+		if _, found := r.resources[id]; !found {
+			r.resources[id] = resource
+			r.changed = true
+		}
+	}
+}
+
+// changedSnapshot returns the snapshot if the record is changed since the last call, and marks it unchanged.
+func (r *Reported[T]) changedSnapshot() (map[string]T, bool) {
+	if r == nil {
+		return nil, false
+	}
+	r.lock.Lock()
+	changed := r.changed
+	r.changed = false
+	r.lock.Unlock()
+	if !changed {
+		return nil, false
+	}
+	return r.snapshot(), true
+}
+
+// markChanged marks the record changed again once it fails to be persisted.
+func (r *Reported[T]) markChanged() {
+	if r == nil {
+		return
+	}
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.changed = true
+}
+
+func (r *Reported[T]) snapshot() map[string]T {
+	resources := map[string]T{}
+	if r == nil {
+		return resources
+	}
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	for id, resource := range r.resources {
+		resources[id] = resource
+	}
+	return resources
+}
@@ -0,0 +1,74 @@
+// Copyright (c) 2025 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package fullsync
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ReportedStateConfigMap persists the resources reported to the inventory by the agent, the resources of each type are
+// stored in the key of the resource type. The inventory API doesn't list the resources of the reporter, so it's the
+// only way to know which resources are deleted from the hub while the agent is down.
+const ReportedStateConfigMap = "multicluster-global-hub-agent-inventory-state"
+
+// reportedStore loads and saves the encoded resources of a type, keyed by the local resource id.
+type reportedStore struct {
+	client       client.Client
+	namespace    string
+	resourceType string
+}
+
+func (s *reportedStore) load(ctx context.Context) (map[string]json.RawMessage, error) {
+	cm := &corev1.ConfigMap{}
+	err := s.client.Get(ctx, client.ObjectKey{Namespace: s.namespace, Name: ReportedStateConfigMap}, cm)
+	if errors.IsNotFound(err) {
+		return map[string]json.RawMessage{}, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	resources := map[string]json.RawMessage{}
+	val, found := cm.Data[s.resourceType]
+	if !found {
+		return resources, nil
+	}
+	if err := json.Unmarshal([]byte(val), &resources); err != nil {
+		return nil, fmt.Errorf("failed to decode the reported %s: %w", s.resourceType, err)
+	}
+	return resources, nil
+}
+
+// save replaces the resources of the type, the keys of the other types are kept by the merge patch.
+func (s *reportedStore) save(ctx context.Context, resources map[string]json.RawMessage) error {
+	val, err := json.Marshal(resources)
+	if err != nil {
+		return err
+	}
+
+	cm := &corev1.ConfigMap{}
+	err = s.client.Get(ctx, client.ObjectKey{Namespace: s.namespace, Name: ReportedStateConfigMap}, cm)
+	if errors.IsNotFound(err) {
+		cm = &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: ReportedStateConfigMap, Namespace: s.namespace},
+			Data:       map[string]string{s.resourceType: string(val)},
+		}
+		return s.client.Create(ctx, cm)
+	} else if err != nil {
+		return err
+	}
+
+	patch := client.MergeFrom(cm.DeepCopy())
+	if cm.Data == nil {
+		cm.Data = map[string]string{}
+	}
+	cm.Data[s.resourceType] = string(val)
+	return s.client.Patch(ctx, cm, patch)
+}
@@ -31,10 +31,12 @@ func AddToManager(ctx context.Context, mgr ctrl.Manager, transportClient transpo
 	}
 	configs.SetMCHVersion(mch.Status.CurrentVersion)
 
-	if err := managedclusterinfo.AddManagedClusterInfoInventorySyncer(mgr, transportClient.GetRequester()); err != nil {
+	if err := managedclusterinfo.AddManagedClusterInfoInventorySyncer(mgr, transportClient.GetRequester(),
+		agentConfig.InventoryFullSyncInterval); err != nil {
 		return err
 	}
-	if err := policy.AddPolicyInventorySyncer(mgr, transportClient.GetRequester()); err != nil {
+	if err := policy.AddPolicyInventorySyncer(mgr, transportClient.GetRequester(),
+		agentConfig.InventoryFullSyncInterval); err != nil {
 		return err
 	}
 
@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 	"reflect"
+	"sort"
+	"time"
 
 	kessel "github.com/project-kessel/inventory-api/api/kessel/inventory/v1beta1/resources"
 	clusterinfov1beta1 "github.com/stolostron/cluster-lifecycle-api/clusterinfo/v1beta1"
@@ -20,6 +22,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
 
 	"github.com/stolostron/multicluster-global-hub/agent/pkg/configs"
+	"github.com/stolostron/multicluster-global-hub/agent/pkg/controllers/inventory/fullsync"
 	"github.com/stolostron/multicluster-global-hub/pkg/constants"
 	"github.com/stolostron/multicluster-global-hub/pkg/logger"
 	"github.com/stolostron/multicluster-global-hub/pkg/transport"
@@ -31,6 +34,8 @@ type ManagedClusterInfoInventorySyncer struct {
 	runtimeClient client.Client
 	requester     transport.Requester
 	clientCN      string
+	// reported records the k8s clusters reported to the inventory for the full sync
+	reported *fullsync.Reported[*kessel.K8SCluster]
 }
 
 func (r *ManagedClusterInfoInventorySyncer) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
@@ -56,6 +61,7 @@ func (r *ManagedClusterInfoInventorySyncer) Reconcile(ctx context.Context, req c
 				&kessel.CreateK8SClusterRequest{K8SCluster: k8sCluster}); err != nil {
 				return ctrl.Result{}, fmt.Errorf("failed to create k8sCluster %v: %w", resp, err)
 			}
+			r.reported.Record(clusterInfo.Name, k8sCluster)
 			return ctrl.Result{}, nil
 		}
 	}
@@ -75,6 +81,7 @@ func (r *ManagedClusterInfoInventorySyncer) Reconcile(ctx context.Context, req c
 				&kessel.DeleteK8SClusterRequest{ReporterData: k8sCluster.ReporterData}); err != nil {
 				return ctrl.Result{}, fmt.Errorf("failed to delete k8sCluster %v: %w", resp, err)
 			}
+			r.reported.Forget(clusterInfo.Name)
 			// remove finalizer
 			controllerutil.RemoveFinalizer(clusterInfo, constants.InventoryResourceFinalizer)
 			if err := r.runtimeClient.Update(ctx, clusterInfo); err != nil {
@@ -88,10 +95,15 @@ func (r *ManagedClusterInfoInventorySyncer) Reconcile(ctx context.Context, req c
 		&kessel.UpdateK8SClusterRequest{K8SCluster: k8sCluster}); err != nil {
 		return ctrl.Result{}, fmt.Errorf("failed to update k8sCluster %v: %w", resp, err)
 	}
+	r.reported.Record(clusterInfo.Name, k8sCluster)
 	return ctrl.Result{}, nil
 }
 
-func AddManagedClusterInfoInventorySyncer(mgr ctrl.Manager, inventoryRequester transport.Requester) error {
+// AddManagedClusterInfoInventorySyncer adds the syncer of the watch events and the full syncer, which converges the
+// inventory in the fullSyncInterval, the full sync is disabled if the interval isn't positive.
+func AddManagedClusterInfoInventorySyncer(mgr ctrl.Manager, inventoryRequester transport.Requester,
+	fullSyncInterval time.Duration,
+) error {
 	clusterInfoPredicate := predicate.Funcs{
 		UpdateFunc: func(e event.UpdateEvent) bool {
 			return !reflect.DeepEqual(e.ObjectNew.(*clusterinfov1beta1.ManagedClusterInfo).Status,
@@ -115,7 +127,8 @@ func AddManagedClusterInfoInventorySyncer(mgr ctrl.Manager, inventoryRequester t
 		},
 	}
 
-	return ctrl.NewControllerManagedBy(mgr).Named("inventory-managedclusterinfo-controller").
+	reported := fullsync.NewReported[*kessel.K8SCluster]()
+	err := ctrl.NewControllerManagedBy(mgr).Named("inventory-managedclusterinfo-controller").
 		For(&clusterinfov1beta1.ManagedClusterInfo{}).
 		WithEventFilter(clusterInfoPredicate).
 		Complete(&ManagedClusterInfoInventorySyncer{
@@ -123,7 +136,17 @@ func AddManagedClusterInfoInventorySyncer(mgr ctrl.Manager, inventoryRequester t
 			runtimeClient: mgr.GetClient(),
 			requester:     inventoryRequester,
 			clientCN:      configs.GetLeafHubName(),
+			reported:      reported,
 		})
+	if err != nil {
+		return err
+	}
+
+	return fullsync.AddFullSyncer[*kessel.K8SCluster](mgr, &k8sClusterReporter{
+		runtimeClient: mgr.GetClient(),
+		requester:     inventoryRequester,
+		clientCN:      configs.GetLeafHubName(),
+	}, reported, fullSyncInterval)
 }
 
 func GetK8SCluster(ctx context.Context,
@@ -163,6 +186,7 @@ func GetK8SCluster(ctx context.Context,
 			Value: value,
 		})
 	}
+	sortLabels(kesselLabels)
 	k8sCluster := &kessel.K8SCluster{
 		Metadata: &kessel.Metadata{
 			ResourceType: "k8s_cluster",
@@ -255,9 +279,15 @@ func GetK8SCluster(ctx context.Context,
 				labels = append(labels, &kessel.ResourceLabel{Key: key, Value: val})
 			}
 		}
+		sortLabels(labels)
 		kesselNode.Labels = labels
 
 		k8sCluster.ResourceData.Nodes = append(k8sCluster.ResourceData.Nodes, kesselNode)
 	}
 	return k8sCluster
 }
+
+// sortLabels sorts the labels by the key, so that the same cluster is always reported with the same content
+func sortLabels(labels []*kessel.ResourceLabel) {
+	sort.Slice(labels, func(i, j int) bool { return labels[i].Key < labels[j].Key })
+}
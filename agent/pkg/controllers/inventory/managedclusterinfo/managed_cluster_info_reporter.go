@@ -0,0 +1,93 @@
+package managedclusterinfo
+
+import (
+	"context"
+	"fmt"
+
+	kessel "github.com/project-kessel/inventory-api/api/kessel/inventory/v1beta1/resources"
+	clusterinfov1beta1 "github.com/stolostron/cluster-lifecycle-api/clusterinfo/v1beta1"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"k8s.io/apimachinery/pkg/api/errors"
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/transport"
+)
+
+// k8sClusterReporter reports the managed cluster infos of the hub as the k8s clusters for the full sync.
+type k8sClusterReporter struct {
+	runtimeClient client.Client
+	requester     transport.Requester
+	clientCN      string
+}
+
+func (r *k8sClusterReporter) ResourceType() string {
+	return "k8s_cluster"
+}
+
+func (r *k8sClusterReporter) List(ctx context.Context) (map[string]*kessel.K8SCluster, error) {
+	clusterInfos := &clusterinfov1beta1.ManagedClusterInfoList{}
+	if err := r.runtimeClient.List(ctx, clusterInfos); err != nil {
+		return nil, err
+	}
+
+	k8sClusters := map[string]*kessel.K8SCluster{}
+	for i := range clusterInfos.Items {
+		clusterInfo := &clusterInfos.Items[i]
+		// the deleting cluster is removed from the inventory by the syncer
+		if !clusterInfo.DeletionTimestamp.IsZero() {
+			continue
+		}
+		cluster := &clusterv1.ManagedCluster{}
+		err := r.runtimeClient.Get(ctx, client.ObjectKey{Name: clusterInfo.Name}, cluster)
+		if errors.IsNotFound(err) {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+		k8sClusters[clusterInfo.Name] = GetK8SCluster(ctx, clusterInfo, cluster, r.clientCN, r.runtimeClient)
+	}
+	return k8sClusters, nil
+}
+
+func (r *k8sClusterReporter) Create(ctx context.Context, k8sCluster *kessel.K8SCluster) error {
+	if resp, err := r.requester.GetHttpClient().K8sClusterService.CreateK8SCluster(ctx,
+		&kessel.CreateK8SClusterRequest{K8SCluster: k8sCluster}); err != nil {
+		return fmt.Errorf("failed to create k8sCluster %v: %w", resp, err)
+	}
+	return nil
+}
+
+func (r *k8sClusterReporter) Update(ctx context.Context, _, k8sCluster *kessel.K8SCluster) error {
+	if resp, err := r.requester.GetHttpClient().K8sClusterService.UpdateK8SCluster(ctx,
+		&kessel.UpdateK8SClusterRequest{K8SCluster: k8sCluster}); err != nil {
+		return fmt.Errorf("failed to update k8sCluster %v: %w", resp, err)
+	}
+	return nil
+}
+
+func (r *k8sClusterReporter) Delete(ctx context.Context, k8sCluster *kessel.K8SCluster) error {
+	if resp, err := r.requester.GetHttpClient().K8sClusterService.DeleteK8SCluster(ctx,
+		&kessel.DeleteK8SClusterRequest{ReporterData: k8sCluster.ReporterData}); err != nil {
+		return fmt.Errorf("failed to delete k8sCluster %v: %w", resp, err)
+	}
+	return nil
+}
+
+func (r *k8sClusterReporter) Equal(a, b *kessel.K8SCluster) bool {
+	return proto.Equal(a, b)
+}
+
+// Marshal keeps the reporter data of the k8s cluster, which is all that's required to delete it.
+func (r *k8sClusterReporter) Marshal(k8sCluster *kessel.K8SCluster) ([]byte, error) {
+	return protojson.Marshal(&kessel.K8SCluster{ReporterData: k8sCluster.ReporterData})
+}
+
+func (r *k8sClusterReporter) Unmarshal(data []byte) (*kessel.K8SCluster, error) {
+	k8sCluster := &kessel.K8SCluster{}
+	if err := protojson.Unmarshal(data, k8sCluster); err != nil {
+		return nil, err
+	}
+	return k8sCluster, nil
+}
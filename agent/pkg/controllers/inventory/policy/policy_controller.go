@@ -8,6 +8,8 @@ import (
 	"context"
 	"fmt"
 	"reflect"
+	"sort"
+	"time"
 
 	kesselv1betarelations "github.com/project-kessel/inventory-api/api/kessel/inventory/v1beta1/relationships"
 	kessel "github.com/project-kessel/inventory-api/api/kessel/inventory/v1beta1/resources"
@@ -21,6 +23,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
 
 	"github.com/stolostron/multicluster-global-hub/agent/pkg/configs"
+	"github.com/stolostron/multicluster-global-hub/agent/pkg/controllers/inventory/fullsync"
 	"github.com/stolostron/multicluster-global-hub/pkg/constants"
 	"github.com/stolostron/multicluster-global-hub/pkg/transport"
 )
@@ -29,9 +32,15 @@ type PolicyInventorySyncer struct {
 	runtimeClient      client.Client
 	reporterInstanceId string
 	requester          transport.Requester
+	// reported records the policies reported to the inventory for the full sync
+	reported *fullsync.Reported[*policyReport]
 }
 
-func AddPolicyInventorySyncer(mgr ctrl.Manager, inventoryRequester transport.Requester) error {
+// AddPolicyInventorySyncer adds the syncer of the watch events and the full syncer, which converges the inventory in
+// the fullSyncInterval, the full sync is disabled if the interval isn't positive.
+func AddPolicyInventorySyncer(mgr ctrl.Manager, inventoryRequester transport.Requester,
+	fullSyncInterval time.Duration,
+) error {
 	policyPredicate := predicate.Funcs{
 		UpdateFunc: func(e event.UpdateEvent) bool {
 			// do not trigger the delete event for the replicated policies
@@ -67,14 +76,25 @@ func AddPolicyInventorySyncer(mgr ctrl.Manager, inventoryRequester transport.Req
 		},
 	}
 
-	return ctrl.NewControllerManagedBy(mgr).Named("inventory-policy-controller").
+	reported := fullsync.NewReported[*policyReport]()
+	err := ctrl.NewControllerManagedBy(mgr).Named("inventory-policy-controller").
 		For(&policiesv1.Policy{}).
 		WithEventFilter(policyPredicate).
 		Complete(&PolicyInventorySyncer{
 			runtimeClient:      mgr.GetClient(),
 			reporterInstanceId: configs.GetLeafHubName(),
 			requester:          inventoryRequester,
+			reported:           reported,
 		})
+	if err != nil {
+		return err
+	}
+
+	return fullsync.AddFullSyncer[*policyReport](mgr, &k8sPolicyReporter{
+		runtimeClient:      mgr.GetClient(),
+		reporterInstanceId: configs.GetLeafHubName(),
+		requester:          inventoryRequester,
+	}, reported, fullSyncInterval)
 }
 
 func (p *PolicyInventorySyncer) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
@@ -96,6 +116,7 @@ func (p *PolicyInventorySyncer) Reconcile(ctx context.Context, req ctrl.Request)
 				ctx, &kessel.CreateK8SPolicyRequest{K8SPolicy: k8sPolicy}); err != nil {
 				return ctrl.Result{}, fmt.Errorf("failed to create k8s_policy %v: %w", resp, err)
 			}
+			p.reported.Record(k8sPolicy.ReporterData.LocalResourceId, newPolicyReport(policy, p.reporterInstanceId))
 			return ctrl.Result{}, nil
 		}
 	}
@@ -123,6 +144,7 @@ func (p *PolicyInventorySyncer) Reconcile(ctx context.Context, req ctrl.Request)
 					return ctrl.Result{}, fmt.Errorf("failed to delete k8spolicy_is-propagated-to_k8scluster %v: %w", resp, err)
 				}
 			}
+			p.reported.Forget(k8sPolicy.ReporterData.LocalResourceId)
 			// remove finalizer
 			controllerutil.RemoveFinalizer(policy, constants.InventoryResourceFinalizer)
 			if err := p.runtimeClient.Update(ctx, policy); err != nil {
@@ -136,15 +158,13 @@ func (p *PolicyInventorySyncer) Reconcile(ctx context.Context, req ctrl.Request)
 		ctx, &kessel.UpdateK8SPolicyRequest{K8SPolicy: k8sPolicy}); err != nil {
 		return ctrl.Result{}, fmt.Errorf("failed to update k8s_policy %v: %w", resp, err)
 	}
-	for _, compliancePerClusterStatus := range policy.Status.Status {
-		if resp, err := p.requester.GetHttpClient().K8SPolicyIsPropagatedToK8SClusterServiceHTTPClient.
-			UpdateK8SPolicyIsPropagatedToK8SCluster(
-				ctx, updateK8SPolicyIsPropagatedToK8SCluster(policy.Namespace+"/"+policy.Name,
-					compliancePerClusterStatus.ClusterName, string(compliancePerClusterStatus.ComplianceState),
-					p.reporterInstanceId)); err != nil {
-			return ctrl.Result{}, fmt.Errorf("failed to update k8spolicy_is-propagated-to_k8scluster %v: %w", resp, err)
-		}
+	// the relationships of the clusters which the policy isn't propagated to anymore are deleted by the previous report
+	report := newPolicyReport(policy, p.reporterInstanceId)
+	previous, _ := p.reported.Get(report.localResourceId())
+	if err := updateCompliance(ctx, p.requester, previous, report); err != nil {
+		return ctrl.Result{}, err
 	}
+	p.reported.Record(report.localResourceId(), report)
 
 	return ctrl.Result{}, nil
 }
@@ -201,11 +221,17 @@ func generateK8SPolicy(policy *policiesv1.Policy, reporterInstanceId string) *ke
 		})
 	}
 	for key, value := range policy.Annotations {
+		// the annotation only marks the create event of the syncer
+		if key == constants.InventoryResourceCreatingAnnotationlKey {
+			continue
+		}
 		kesselLabels = append(kesselLabels, &kessel.ResourceLabel{
 			Key:   key,
 			Value: value,
 		})
 	}
+	// sort the labels, so that the same policy is always reported with the same content
+	sort.Slice(kesselLabels, func(i, j int) bool { return kesselLabels[i].Key < kesselLabels[j].Key })
 	return &kessel.K8SPolicy{
 		Metadata: &kessel.Metadata{
 			ResourceType: "k8s_policy",
@@ -118,7 +118,7 @@ func TestPolicyControllerReconcile(t *testing.T) {
 		Scheme: scheme,
 	})
 	assert.NoError(t, err)
-	assert.NoError(t, AddPolicyInventorySyncer(mgr, nil))
+	assert.NoError(t, AddPolicyInventorySyncer(mgr, nil, 0))
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
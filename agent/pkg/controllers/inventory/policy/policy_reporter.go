@@ -0,0 +1,170 @@
+package policy
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+
+	kessel "github.com/project-kessel/inventory-api/api/kessel/inventory/v1beta1/resources"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	policiesv1 "open-cluster-management.io/governance-policy-propagator/api/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/constants"
+	"github.com/stolostron/multicluster-global-hub/pkg/transport"
+)
+
+// policyReport is the k8s policy and the compliance of the clusters which the policy is propagated to, the
+// compliance is reported as the relationships between the policy and the clusters.
+type policyReport struct {
+	policy *kessel.K8SPolicy
+	// compliance is the compliance state by the cluster name
+	compliance map[string]string
+}
+
+func newPolicyReport(policy *policiesv1.Policy, reporterInstanceId string) *policyReport {
+	report := &policyReport{
+		policy:     generateK8SPolicy(policy, reporterInstanceId),
+		compliance: map[string]string{},
+	}
+	for _, compliancePerClusterStatus := range policy.Status.Status {
+		report.compliance[compliancePerClusterStatus.ClusterName] = string(compliancePerClusterStatus.ComplianceState)
+	}
+	return report
+}
+
+func (r *policyReport) localResourceId() string {
+	return r.policy.ReporterData.LocalResourceId
+}
+
+// persistedPolicyReport is the policy report in the persisted record, it keeps the reporter data of the policy and the
+// compliance, which are required to delete the policy and the relationships.
+type persistedPolicyReport struct {
+	ReporterData json.RawMessage   `json:"reporterData"`
+	Compliance   map[string]string `json:"compliance,omitempty"`
+}
+
+// k8sPolicyReporter reports the root policies of the hub as the k8s policies for the full sync.
+type k8sPolicyReporter struct {
+	runtimeClient      client.Client
+	reporterInstanceId string
+	requester          transport.Requester
+}
+
+func (r *k8sPolicyReporter) ResourceType() string {
+	return "k8s_policy"
+}
+
+func (r *k8sPolicyReporter) List(ctx context.Context) (map[string]*policyReport, error) {
+	policies := &policiesv1.PolicyList{}
+	if err := r.runtimeClient.List(ctx, policies); err != nil {
+		return nil, err
+	}
+
+	reports := map[string]*policyReport{}
+	for i := range policies.Items {
+		policy := &policies.Items[i]
+		// the replicated policies and the deleting policies aren't held by the inventory
+		if _, exist := policy.GetLabels()[constants.PolicyEventRootPolicyNameLabelKey]; exist {
+			continue
+		}
+		if !policy.DeletionTimestamp.IsZero() {
+			continue
+		}
+		report := newPolicyReport(policy, r.reporterInstanceId)
+		reports[report.localResourceId()] = report
+	}
+	return reports, nil
+}
+
+func (r *k8sPolicyReporter) Create(ctx context.Context, report *policyReport) error {
+	if resp, err := r.requester.GetHttpClient().PolicyServiceClient.CreateK8SPolicy(
+		ctx, &kessel.CreateK8SPolicyRequest{K8SPolicy: report.policy}); err != nil {
+		return fmt.Errorf("failed to create k8s_policy %v: %w", resp, err)
+	}
+	return updateCompliance(ctx, r.requester, nil, report)
+}
+
+func (r *k8sPolicyReporter) Update(ctx context.Context, previous, report *policyReport) error {
+	if resp, err := r.requester.GetHttpClient().PolicyServiceClient.UpdateK8SPolicy(
+		ctx, &kessel.UpdateK8SPolicyRequest{K8SPolicy: report.policy}); err != nil {
+		return fmt.Errorf("failed to update k8s_policy %v: %w", resp, err)
+	}
+	return updateCompliance(ctx, r.requester, previous, report)
+}
+
+// updateCompliance reports the compliance of the clusters as the relationships, and deletes the relationships of the
+// clusters which are in the previous report but the policy isn't propagated to anymore.
+func updateCompliance(ctx context.Context, requester transport.Requester, previous, report *policyReport) error {
+	reporterInstanceId := report.policy.ReporterData.ReporterInstanceId
+	for clusterName, complianceState := range report.compliance {
+		if resp, err := requester.GetHttpClient().K8SPolicyIsPropagatedToK8SClusterServiceHTTPClient.
+			UpdateK8SPolicyIsPropagatedToK8SCluster(ctx, updateK8SPolicyIsPropagatedToK8SCluster(
+				report.localResourceId(), clusterName, complianceState, reporterInstanceId)); err != nil {
+			return fmt.Errorf("failed to update k8spolicy_is-propagated-to_k8scluster %v: %w", resp, err)
+		}
+	}
+	if previous == nil {
+		return nil
+	}
+	for clusterName := range previous.compliance {
+		if _, found := report.compliance[clusterName]; found {
+			continue
+		}
+		if resp, err := requester.GetHttpClient().K8SPolicyIsPropagatedToK8SClusterServiceHTTPClient.
+			DeleteK8SPolicyIsPropagatedToK8SCluster(ctx, deleteK8SPolicyIsPropagatedToK8SCluster(
+				report.localResourceId(), clusterName, reporterInstanceId)); err != nil {
+			return fmt.Errorf("failed to delete k8spolicy_is-propagated-to_k8scluster %v: %w", resp, err)
+		}
+	}
+	return nil
+}
+
+func (r *k8sPolicyReporter) Delete(ctx context.Context, report *policyReport) error {
+	if resp, err := r.requester.GetHttpClient().PolicyServiceClient.DeleteK8SPolicy(ctx, &kessel.DeleteK8SPolicyRequest{
+		ReporterData: report.policy.ReporterData,
+	}); err != nil {
+		return fmt.Errorf("failed to delete k8s_policy %v: %w", resp, err)
+	}
+	for clusterName := range report.compliance {
+		if resp, err := r.requester.GetHttpClient().K8SPolicyIsPropagatedToK8SClusterServiceHTTPClient.
+			DeleteK8SPolicyIsPropagatedToK8SCluster(ctx, deleteK8SPolicyIsPropagatedToK8SCluster(
+				report.localResourceId(), clusterName, r.reporterInstanceId)); err != nil {
+			return fmt.Errorf("failed to delete k8spolicy_is-propagated-to_k8scluster %v: %w", resp, err)
+		}
+	}
+	return nil
+}
+
+func (r *k8sPolicyReporter) Equal(a, b *policyReport) bool {
+	return proto.Equal(a.policy, b.policy) && maps.Equal(a.compliance, b.compliance)
+}
+
+func (r *k8sPolicyReporter) Marshal(report *policyReport) ([]byte, error) {
+	reporterData, err := protojson.Marshal(report.policy.ReporterData)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(&persistedPolicyReport{ReporterData: reporterData, Compliance: report.compliance})
+}
+
+func (r *k8sPolicyReporter) Unmarshal(data []byte) (*policyReport, error) {
+	persisted := &persistedPolicyReport{}
+	if err := json.Unmarshal(data, persisted); err != nil {
+		return nil, err
+	}
+	reporterData := &kessel.ReporterData{}
+	if err := protojson.Unmarshal(persisted.ReporterData, reporterData); err != nil {
+		return nil, err
+	}
+	report := &policyReport{
+		policy:     &kessel.K8SPolicy{ReporterData: reporterData},
+		compliance: persisted.Compliance,
+	}
+	if report.compliance == nil {
+		report.compliance = map[string]string{}
+	}
+	return report, nil
+}
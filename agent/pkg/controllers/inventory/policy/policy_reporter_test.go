@@ -0,0 +1,92 @@
+package policy
+
+import (
+	"context"
+	"testing"
+
+	http "github.com/go-kratos/kratos/v2/transport/http"
+	kesselv1betarelations "github.com/project-kessel/inventory-api/api/kessel/inventory/v1beta1/relationships"
+	"github.com/project-kessel/inventory-client-go/v1beta1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	policiesv1 "open-cluster-management.io/governance-policy-propagator/api/v1"
+)
+
+// relationsRecorder records the clusters of the updated and deleted relationships
+type relationsRecorder struct {
+	K8SPolicyIsPropagatedToK8SClusterServiceHTTPClient
+	updated []string
+	deleted []string
+}
+
+func (r *relationsRecorder) UpdateK8SPolicyIsPropagatedToK8SCluster(ctx context.Context,
+	in *kesselv1betarelations.UpdateK8SPolicyIsPropagatedToK8SClusterRequest, opts ...http.CallOption,
+) (*kesselv1betarelations.UpdateK8SPolicyIsPropagatedToK8SClusterResponse, error) {
+	r.updated = append(r.updated, in.K8SpolicyIspropagatedtoK8Scluster.ReporterData.ObjectLocalResourceId)
+	return nil, nil
+}
+
+func (r *relationsRecorder) DeleteK8SPolicyIsPropagatedToK8SCluster(ctx context.Context,
+	in *kesselv1betarelations.DeleteK8SPolicyIsPropagatedToK8SClusterRequest, opts ...http.CallOption,
+) (*kesselv1betarelations.DeleteK8SPolicyIsPropagatedToK8SClusterResponse, error) {
+	r.deleted = append(r.deleted, in.ReporterData.ObjectLocalResourceId)
+	return nil, nil
+}
+
+type recordingRequester struct {
+	MockRequest
+	relations *relationsRecorder
+}
+
+func (c *recordingRequester) GetHttpClient() *v1beta1.InventoryHttpClient {
+	return &v1beta1.InventoryHttpClient{
+		PolicyServiceClient: &PolicyServiceClient{},
+		K8SPolicyIsPropagatedToK8SClusterServiceHTTPClient: c.relations,
+	}
+}
+
+func newTestPolicy(clusters ...string) *policiesv1.Policy {
+	policy := &policiesv1.Policy{ObjectMeta: metav1.ObjectMeta{Name: "test-policy", Namespace: "default"}}
+	for _, cluster := range clusters {
+		policy.Status.Status = append(policy.Status.Status, &policiesv1.CompliancePerClusterStatus{
+			ClusterName:     cluster,
+			ComplianceState: policiesv1.Compliant,
+		})
+	}
+	return policy
+}
+
+func TestPolicyReporterUpdate(t *testing.T) {
+	requester := &recordingRequester{relations: &relationsRecorder{}}
+	reporter := &k8sPolicyReporter{requester: requester, reporterInstanceId: "hub1"}
+
+	// the relationship of the cluster which the policy isn't propagated to anymore is deleted
+	previous := newPolicyReport(newTestPolicy("cluster1", "cluster2"), "hub1")
+	report := newPolicyReport(newTestPolicy("cluster2", "cluster3"), "hub1")
+	require.NoError(t, reporter.Update(context.Background(), previous, report))
+	assert.ElementsMatch(t, []string{"cluster2", "cluster3"}, requester.relations.updated)
+	assert.Equal(t, []string{"cluster1"}, requester.relations.deleted)
+
+	// nothing is deleted if the policy isn't reported before
+	requester.relations = &relationsRecorder{}
+	require.NoError(t, reporter.Update(context.Background(), nil, report))
+	assert.Len(t, requester.relations.updated, 2)
+	assert.Empty(t, requester.relations.deleted)
+}
+
+func TestPolicyReporterMarshal(t *testing.T) {
+	reporter := &k8sPolicyReporter{reporterInstanceId: "hub1"}
+	report := newPolicyReport(newTestPolicy("cluster1"), "hub1")
+
+	data, err := reporter.Marshal(report)
+	require.NoError(t, err)
+	persisted, err := reporter.Unmarshal(data)
+	require.NoError(t, err)
+
+	// only the reporter data and the compliance are kept
+	assert.True(t, proto.Equal(report.policy.ReporterData, persisted.policy.ReporterData))
+	assert.Nil(t, persisted.policy.ResourceData)
+	assert.Equal(t, report.compliance, persisted.compliance)
+}
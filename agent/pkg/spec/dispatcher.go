@@ -90,6 +90,9 @@ func (d *genericDispatcher) dispatch(ctx context.Context) {
 				continue
 			}
 			if err := retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+				if eventSyncer, ok := syncer.(EventSyncer); ok {
+					return eventSyncer.SyncEvent(ctx, evt)
+				}
 				if err := syncer.Sync(ctx, evt.Data()); err != nil {
 					return err
 				}
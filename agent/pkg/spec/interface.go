@@ -2,12 +2,19 @@ package spec
 
 import (
 	"context"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
 )
 
 type Syncer interface {
 	Sync(ctx context.Context, payload []byte) error
 }
 
+// EventSyncer is preferred by the dispatcher if the syncer needs the attributes of the event, e.g. the event type
+type EventSyncer interface {
+	SyncEvent(ctx context.Context, evt *cloudevents.Event) error
+}
+
 type Dispatcher interface {
 	Start(ctx context.Context) error
 	RegisterSyncer(messageID string, syncer Syncer)
@@ -3,7 +3,10 @@ package rbac
 import (
 	"encoding/base64"
 	"fmt"
+	"sort"
 	"strings"
+	"sync"
+	"time"
 
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/client-go/rest"
@@ -17,22 +20,73 @@ const (
 	UserIdentityAnnotation = "open-cluster-management.io/user-identity"
 	// UserGroupsAnnotation is the annotation that is used to store the user groups.
 	UserGroupsAnnotation = "open-cluster-management.io/user-group"
+	// ImpersonatedClientTTL is how long the client of the user is reused, it's rebuilt once expired so that the
+	// clients of the users who no longer push objects are released.
+	ImpersonatedClientTTL = 30 * time.Minute
 )
 
 // NewImpersonationManager creates a new instance of ImpersonationManager.
 func NewImpersonationManager(config *rest.Config) *ImpersonationManager {
 	return &ImpersonationManager{
 		k8sConfig: config,
+		clientTTL: ImpersonatedClientTTL,
+		clients:   map[string]*impersonatedClient{},
 	}
 }
 
 // ImpersonationManager manages the k8s clients for the various users and for the controller.
 type ImpersonationManager struct {
-	k8sConfig *rest.Config
+	k8sConfig   *rest.Config
+	clientTTL   time.Duration
+	clients     map[string]*impersonatedClient
+	clientsLock sync.Mutex
 }
 
-// Impersonate gets the user identity and returns the k8s client that represents the requesting user.
+type impersonatedClient struct {
+	client     client.Client
+	expiration time.Time
+}
+
+// Impersonate gets the user identity and returns the k8s client that represents the requesting user. The client is
+// cached by the user and the groups until the TTL expires, the expired clients are evicted on the way.
 func (manager *ImpersonationManager) Impersonate(userIdentity string, userGroups []string) (client.Client, error) {
+	manager.clientsLock.Lock()
+	defer manager.clientsLock.Unlock()
+
+	now := time.Now()
+	for key, cached := range manager.clients {
+		if !now.Before(cached.expiration) {
+			delete(manager.clients, key)
+		}
+	}
+
+	key := IdentityKey(userIdentity, userGroups)
+	if cached, found := manager.clients[key]; found {
+		return cached.client, nil
+	}
+
+	userK8sClient, err := manager.newClient(userIdentity, userGroups)
+	if err != nil {
+		return nil, err
+	}
+	manager.clients[key] = &impersonatedClient{client: userK8sClient, expiration: now.Add(manager.clientTTL)}
+	return userK8sClient, nil
+}
+
+// IdentityKey returns the key of the user and the groups, the parts are length-prefixed so that the different identities
+// can't collide, and the groups are sorted so that the order doesn't matter.
+func IdentityKey(userIdentity string, userGroups []string) string {
+	groups := append([]string{}, userGroups...)
+	sort.Strings(groups)
+
+	var key strings.Builder
+	for _, part := range append([]string{userIdentity}, groups...) {
+		fmt.Fprintf(&key, "%d:%s", len(part), part)
+	}
+	return key.String()
+}
+
+func (manager *ImpersonationManager) newClient(userIdentity string, userGroups []string) (client.Client, error) {
 	newConfig := rest.CopyConfig(manager.k8sConfig)
 	newConfig.Impersonate = rest.ImpersonationConfig{
 		UserName: userIdentity,
@@ -48,6 +102,23 @@ func (manager *ImpersonationManager) Impersonate(userIdentity string, userGroups
 	return userK8sClient, nil
 }
 
+// GetIdentity returns the user identity and the decoded user groups in the obj, the identity is NoIdentity in case it
+// can't be found on the object.
+func (manager *ImpersonationManager) GetIdentity(obj interface{}) (string, []string, error) {
+	userIdentity, err := manager.GetUserIdentity(obj)
+	if err != nil || userIdentity == NoIdentity {
+		return NoIdentity, nil, err
+	}
+	base64UserGroups, userGroups, err := manager.GetUserGroups(obj)
+	if err != nil {
+		return NoIdentity, nil, err
+	}
+	if base64UserGroups == NoIdentity {
+		userGroups = nil
+	}
+	return userIdentity, userGroups, nil
+}
+
 // GetUserIdentity returns the user identity in the obj or NoIdentity in case it can't be found on the object.
 func (manager *ImpersonationManager) GetUserIdentity(obj interface{}) (string, error) {
 	unstructuredObj, ok := obj.(*unstructured.Unstructured)
@@ -0,0 +1,75 @@
+package rbac
+
+import (
+	"encoding/base64"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/rest"
+)
+
+func TestImpersonateCache(t *testing.T) {
+	manager := NewImpersonationManager(&rest.Config{Host: "https://127.0.0.1:6443"})
+
+	client1, err := manager.Impersonate("alice", []string{"admins"})
+	require.NoError(t, err)
+	cached, err := manager.Impersonate("alice", []string{"admins"})
+	require.NoError(t, err)
+	assert.Same(t, client1, cached)
+
+	// the client is cached by the user and the groups
+	client2, err := manager.Impersonate("alice", []string{"devs"})
+	require.NoError(t, err)
+	assert.NotSame(t, client1, client2)
+	assert.Len(t, manager.clients, 2)
+
+	// the expired clients are evicted and the client is rebuilt
+	for _, cached := range manager.clients {
+		cached.expiration = time.Now()
+	}
+	rebuilt, err := manager.Impersonate("alice", []string{"admins"})
+	require.NoError(t, err)
+	assert.NotSame(t, client1, rebuilt)
+	assert.Len(t, manager.clients, 1)
+}
+
+func TestIdentityKey(t *testing.T) {
+	// the user and the groups can't be shifted into each other
+	assert.NotEqual(t, IdentityKey("a.b", []string{"c"}), IdentityKey("a", []string{"b.c"}))
+	assert.NotEqual(t, IdentityKey("alice", []string{"a,b"}), IdentityKey("alice", []string{"a", "b"}))
+	assert.NotEqual(t, IdentityKey("alice", nil), IdentityKey("alice", []string{""}))
+	// the order of the groups doesn't matter
+	assert.Equal(t, IdentityKey("alice", []string{"admins", "devs"}), IdentityKey("alice", []string{"devs", "admins"}))
+}
+
+func TestGetIdentity(t *testing.T) {
+	manager := NewImpersonationManager(nil)
+
+	obj := &unstructured.Unstructured{}
+	user, groups, err := manager.GetIdentity(obj)
+	require.NoError(t, err)
+	assert.Equal(t, NoIdentity, user)
+	assert.Nil(t, groups)
+
+	obj.SetAnnotations(map[string]string{UserIdentityAnnotation: base64.StdEncoding.EncodeToString([]byte("alice"))})
+	user, groups, err = manager.GetIdentity(obj)
+	require.NoError(t, err)
+	assert.Equal(t, "alice", user)
+	assert.Nil(t, groups)
+
+	obj.SetAnnotations(map[string]string{
+		UserIdentityAnnotation: base64.StdEncoding.EncodeToString([]byte("alice")),
+		UserGroupsAnnotation:   base64.StdEncoding.EncodeToString([]byte("admins,devs")),
+	})
+	user, groups, err = manager.GetIdentity(obj)
+	require.NoError(t, err)
+	assert.Equal(t, "alice", user)
+	assert.Equal(t, []string{"admins", "devs"}, groups)
+
+	obj.SetAnnotations(map[string]string{UserIdentityAnnotation: "not-base64!"})
+	_, _, err = manager.GetIdentity(obj)
+	assert.Error(t, err)
+}
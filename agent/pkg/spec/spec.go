@@ -42,7 +42,7 @@ func AddToManager(context context.Context, mgr ctrl.Manager, transportClient tra
 	// register syncer to the dispatcher
 	if agentConfig.EnableGlobalResource {
 		dispatcher.RegisterSyncer(constants.GenericSpecMsgKey,
-			syncers.NewGenericSyncer(workers, agentConfig))
+			syncers.NewGenericSyncer(workers, agentConfig, transportClient.GetProducer()))
 		dispatcher.RegisterSyncer(constants.ManagedClustersLabelsMsgKey,
 			syncers.NewManagedClusterLabelSyncer(workers))
 	}
@@ -3,8 +3,11 @@ package syncers
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"sync"
 
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/google/uuid"
 	"go.uber.org/zap"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -13,24 +16,45 @@ import (
 	"github.com/stolostron/multicluster-global-hub/agent/pkg/spec/rbac"
 	"github.com/stolostron/multicluster-global-hub/agent/pkg/spec/workers"
 	"github.com/stolostron/multicluster-global-hub/pkg/bundle/spec"
+	eventversion "github.com/stolostron/multicluster-global-hub/pkg/bundle/version"
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
 	"github.com/stolostron/multicluster-global-hub/pkg/logger"
+	"github.com/stolostron/multicluster-global-hub/pkg/transport"
 	"github.com/stolostron/multicluster-global-hub/pkg/utils"
 )
 
-// genericBundleSyncer syncs objects spec from received bundles.
+// genericBundleSyncer syncs objects spec from received bundles. The versioned bundles are reported back to the global
+// hub once applied, so that the global hub only sends the objects changed after the applied version in a delta bundle.
 type genericBundleSyncer struct {
 	log                          *zap.SugaredLogger
 	workerPool                   *workers.WorkerPool
 	bundleProcessingWaitingGroup sync.WaitGroup
 	enforceHohRbac               bool
+	leafHubName                  string
+	producer                     transport.Producer
+	// appliedVersions is the versions of the spec bundles applied by the hub by the event type
+	appliedVersions map[string]*spec.AppliedSpecVersion
+	// reportVersion is the version of the event which reports the applied versions
+	reportVersion *eventversion.Version
+	// applyReportVersion is the version of the event which acknowledges the results of applying the bundle
+	applyReportVersion *eventversion.Version
+	resultsLock        sync.Mutex
+	results            []spec.SpecApplyResult
 }
 
-func NewGenericSyncer(workerPool *workers.WorkerPool, config *configs.AgentConfig) *genericBundleSyncer {
+func NewGenericSyncer(workerPool *workers.WorkerPool, config *configs.AgentConfig,
+	producer transport.Producer,
+) *genericBundleSyncer {
 	return &genericBundleSyncer{
 		log:                          logger.DefaultZapLogger(),
 		workerPool:                   workerPool,
 		bundleProcessingWaitingGroup: sync.WaitGroup{},
 		enforceHohRbac:               config.SpecEnforceHohRbac,
+		leafHubName:                  config.LeafHubName,
+		producer:                     producer,
+		appliedVersions:              map[string]*spec.AppliedSpecVersion{},
+		reportVersion:                eventversion.NewVersion(),
+		applyReportVersion:           eventversion.NewVersion(),
 	}
 }
 
@@ -39,11 +63,152 @@ func (syncer *genericBundleSyncer) Sync(ctx context.Context, payload []byte) err
 	if err := json.Unmarshal(payload, genericBundle); err != nil {
 		return err
 	}
+	syncer.apply(genericBundle)
+	return nil
+}
+
+// SyncEvent applies the bundle, then acknowledges the result of each object and reports the applied version. The delta bundle is skipped if it's based on the
+// version which isn't applied by the hub, e.g. the agent restarted or missed a bundle, then a gap is reported to
+// request the bundle based on the applied version.
+func (syncer *genericBundleSyncer) SyncEvent(ctx context.Context, evt *cloudevents.Event) error {
+	genericBundle := &spec.GenericSpecBundle{}
+	if err := json.Unmarshal(evt.Data(), genericBundle); err != nil {
+		return err
+	}
+
+	eventType := evt.Type()
+	applied, found := syncer.appliedVersions[eventType]
+	if !found {
+		applied = &spec.AppliedSpecVersion{}
+	}
+	if genericBundle.IsDelta() && applied.Version < genericBundle.BaseVersion {
+		syncer.log.Infow("skip the delta bundle based on the unapplied version", "type", eventType,
+			"baseVersion", genericBundle.BaseVersion, "appliedVersion", applied.Version)
+		applied.Gap = true
+		syncer.appliedVersions[eventType] = applied
+		return syncer.reportVersions(ctx)
+	}
+
+	results := syncer.apply(genericBundle)
+	if err := syncer.reportResults(ctx, eventType, genericBundle.Version, results); err != nil {
+		syncer.log.Warnw("failed to acknowledge the results of applying the bundle", "type", eventType, "error", err)
+	}
+	if !allApplied(results) {
+		// keep the applied version, so that the failed objects are resent in the next delta bundle
+		syncer.log.Warnw("failed to apply the bundle", "type", eventType, "version", genericBundle.Version)
+		return nil
+	}
+	if genericBundle.Version == 0 {
+		return nil // the bundle isn't versioned by the global hub
+	}
+	syncer.appliedVersions[eventType] = &spec.AppliedSpecVersion{Version: genericBundle.Version}
+	return syncer.reportVersions(ctx)
+}
+
+// apply syncs the objects of the bundle, and returns the result of each object
+func (syncer *genericBundleSyncer) apply(genericBundle *spec.GenericSpecBundle) []spec.SpecApplyResult {
+	syncer.resultsLock.Lock()
+	syncer.results = []spec.SpecApplyResult{}
+	syncer.resultsLock.Unlock()
 
 	syncer.bundleProcessingWaitingGroup.Add(len(genericBundle.Objects) + len(genericBundle.DeletedObjects))
 	syncer.syncObjects(genericBundle.Objects)
 	syncer.syncDeletedObjects(genericBundle.DeletedObjects)
 	syncer.bundleProcessingWaitingGroup.Wait()
+
+	syncer.resultsLock.Lock()
+	defer syncer.resultsLock.Unlock()
+	return syncer.results
+}
+
+func (syncer *genericBundleSyncer) recordResult(obj *unstructured.Unstructured, operation string, err error) {
+	result := spec.SpecApplyResult{
+		Kind:      obj.GetKind(),
+		Namespace: obj.GetNamespace(),
+		Name:      obj.GetName(),
+		Operation: operation,
+		Applied:   err == nil,
+	}
+	if err != nil {
+		result.Reason = err.Error()
+	}
+	// the object is applied with the identity of the user if the rbac is enforced, the identity is audited on the
+	// global hub to trace who pushed the object to the hub
+	if syncer.enforceHohRbac {
+		userIdentity, userGroups, identityErr := syncer.workerPool.GetIdentity(obj)
+		if identityErr != nil {
+			syncer.log.Warnw("failed to get the user identity for the audit", "name", obj.GetName(),
+				"namespace", obj.GetNamespace(), "kind", obj.GetKind(), "error", identityErr)
+		}
+		result.Audit = &spec.SpecApplyAudit{User: userIdentity, Groups: userGroups}
+	}
+	syncer.resultsLock.Lock()
+	defer syncer.resultsLock.Unlock()
+	syncer.results = append(syncer.results, result)
+}
+
+func allApplied(results []spec.SpecApplyResult) bool {
+	for _, result := range results {
+		if !result.Applied {
+			return false
+		}
+	}
+	return true
+}
+
+// reportResults acknowledges the results of applying the bundle to the global hub
+func (syncer *genericBundleSyncer) reportResults(ctx context.Context, eventType string, version int64,
+	results []spec.SpecApplyResult,
+) error {
+	if syncer.producer == nil || len(results) == 0 {
+		return nil
+	}
+	applyBundle := &spec.SpecApplyBundle{EventType: eventType, Version: version, Results: results}
+
+	syncer.applyReportVersion.Incr()
+	e := cloudevents.NewEvent()
+	e.SetSource(syncer.leafHubName)
+	e.SetType(string(enum.HubSpecApplyType))
+	// the global hub records the audits of the event once by the id, so the redelivered event isn't audited again
+	e.SetID(uuid.New().String())
+	e.SetExtension(eventversion.ExtVersion, syncer.applyReportVersion.String())
+	if err := e.SetData(cloudevents.ApplicationJSON, applyBundle); err != nil {
+		return fmt.Errorf("failed to set the spec apply results: %w", err)
+	}
+	if err := syncer.producer.SendEvent(ctx, e); err != nil {
+		return fmt.Errorf("failed to send the spec apply results: %w", err)
+	}
+	syncer.applyReportVersion.Next()
+	return nil
+}
+
+// reportVersions sends the applied versions of all the event types to the global hub
+func (syncer *genericBundleSyncer) reportVersions(ctx context.Context) error {
+	if syncer.producer == nil {
+		return nil
+	}
+	versionBundle := spec.NewSpecVersionBundle()
+	for eventType, applied := range syncer.appliedVersions {
+		versionBundle.Versions[eventType] = &spec.AppliedSpecVersion{Version: applied.Version, Gap: applied.Gap}
+	}
+
+	syncer.reportVersion.Incr()
+	e := cloudevents.NewEvent()
+	e.SetSource(syncer.leafHubName)
+	e.SetType(string(enum.HubSpecVersionType))
+	e.SetExtension(eventversion.ExtVersion, syncer.reportVersion.String())
+	if err := e.SetData(cloudevents.ApplicationJSON, versionBundle); err != nil {
+		return fmt.Errorf("failed to set the applied spec versions: %w", err)
+	}
+	if err := syncer.producer.SendEvent(ctx, e); err != nil {
+		return fmt.Errorf("failed to report the applied spec versions: %w", err)
+	}
+	syncer.reportVersion.Next()
+
+	// the gap is only reported once
+	for _, applied := range syncer.appliedVersions {
+		applied.Gap = false
+	}
 	return nil
 }
 
@@ -59,9 +224,11 @@ func (s *genericBundleSyncer) syncObjects(bundleObjects []*unstructured.Unstruct
 			defer s.bundleProcessingWaitingGroup.Done()
 
 			unstructuredObject, _ := obj.(*unstructured.Unstructured)
+			var err error
+			defer func() { s.recordResult(unstructuredObject, spec.SpecApplyOperationUpdate, err) }()
 
 			if !s.enforceHohRbac { // if rbac not enforced, create missing namespaces.
-				if err := utils.CreateNamespaceIfNotExist(ctx, k8sClient,
+				if err = utils.CreateNamespaceIfNotExist(ctx, k8sClient,
 					unstructuredObject.GetNamespace()); err != nil {
 					s.log.Error(err, "failed to create namespace", unstructuredObject.GetNamespace())
 					return
@@ -85,7 +252,7 @@ func (s *genericBundleSyncer) syncObjects(bundleObjects []*unstructured.Unstruct
 			}
 
 			delete(unstructuredObject.Object, "status")
-			err := utils.UpdateObject(ctx, k8sClient, unstructuredObject)
+			err = utils.UpdateObject(ctx, k8sClient, unstructuredObject)
 			if err != nil {
 				s.log.Error(err, "failed to update object", "name", unstructuredObject.GetName(),
 					"namespace", unstructuredObject.GetNamespace(), "kind", unstructuredObject.GetKind())
@@ -111,7 +278,9 @@ func (s *genericBundleSyncer) syncDeletedObjects(deletedObjects []*unstructured.
 			unstructuredObject, _ := obj.(*unstructured.Unstructured)
 
 			// syncer.deleteObject(ctx, k8sClient, obj.(*unstructured.Unstructured))
-			if deleted, err := utils.DeleteObject(ctx, k8sClient, unstructuredObject); err != nil {
+			deleted, err := utils.DeleteObject(ctx, k8sClient, unstructuredObject)
+			s.recordResult(unstructuredObject, spec.SpecApplyOperationDelete, err)
+			if err != nil {
 				s.log.Error("failed to delete object",
 					"error", err,
 					"name", unstructuredObject.GetName(),
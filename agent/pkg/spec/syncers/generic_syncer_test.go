@@ -0,0 +1,104 @@
+// Copyright (c) 2025 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package syncers
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stolostron/multicluster-global-hub/agent/pkg/configs"
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/spec"
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
+	"github.com/stolostron/multicluster-global-hub/pkg/transport"
+)
+
+type versionReportProducer struct {
+	reports []*spec.SpecVersionBundle
+}
+
+func (p *versionReportProducer) SendEvent(ctx context.Context, evt cloudevents.Event) error {
+	versionBundle := spec.NewSpecVersionBundle()
+	if err := json.Unmarshal(evt.Data(), versionBundle); err != nil {
+		return err
+	}
+	p.reports = append(p.reports, versionBundle)
+	return nil
+}
+
+func (p *versionReportProducer) Reconnect(config *transport.TransportInternalConfig) error {
+	return nil
+}
+
+type eventProducer struct {
+	events []cloudevents.Event
+}
+
+func (p *eventProducer) SendEvent(ctx context.Context, evt cloudevents.Event) error {
+	p.events = append(p.events, evt)
+	return nil
+}
+
+func (p *eventProducer) Reconnect(config *transport.TransportInternalConfig) error {
+	return nil
+}
+
+func specBundleEvent(t *testing.T, eventType string, version, baseVersion int64) *cloudevents.Event {
+	evt := cloudevents.NewEvent()
+	evt.SetType(eventType)
+	require.NoError(t, evt.SetData(cloudevents.ApplicationJSON, &spec.GenericSpecBundle{
+		Version:     version,
+		BaseVersion: baseVersion,
+	}))
+	return &evt
+}
+
+func TestGenericSyncerVersions(t *testing.T) {
+	ctx := context.Background()
+	producer := &versionReportProducer{}
+	syncer := NewGenericSyncer(nil, &configs.AgentConfig{LeafHubName: "hub1"}, producer)
+
+	// the delta bundle isn't applied before the full bundle
+	require.NoError(t, syncer.SyncEvent(ctx, specBundleEvent(t, "policies", 200, 100)))
+	require.Len(t, producer.reports, 1)
+	require.Equal(t, spec.AppliedSpecVersion{Version: 0, Gap: true}, *producer.reports[0].Versions["policies"])
+
+	// apply the full bundle
+	require.NoError(t, syncer.SyncEvent(ctx, specBundleEvent(t, "policies", 200, 0)))
+	require.Len(t, producer.reports, 2)
+	require.Equal(t, spec.AppliedSpecVersion{Version: 200}, *producer.reports[1].Versions["policies"])
+
+	// apply the delta bundle based on the applied version
+	require.NoError(t, syncer.SyncEvent(ctx, specBundleEvent(t, "policies", 300, 200)))
+	require.NoError(t, syncer.SyncEvent(ctx, specBundleEvent(t, "placements", 150, 0)))
+	require.Len(t, producer.reports, 4)
+	require.Equal(t, int64(300), producer.reports[3].Versions["policies"].Version)
+	require.Equal(t, int64(150), producer.reports[3].Versions["placements"].Version)
+
+	// report the gap with the applied version if the delta bundle is based on a newer version
+	require.NoError(t, syncer.SyncEvent(ctx, specBundleEvent(t, "policies", 500, 400)))
+	require.Equal(t, spec.AppliedSpecVersion{Version: 300, Gap: true}, *producer.reports[4].Versions["policies"])
+	require.False(t, syncer.appliedVersions["policies"].Gap)
+
+	// the unversioned bundle isn't reported
+	require.NoError(t, syncer.SyncEvent(ctx, specBundleEvent(t, string(enum.HubSpecVersionType), 0, 0)))
+	require.Len(t, producer.reports, 5)
+}
+
+func TestGenericSyncerReportResults(t *testing.T) {
+	producer := &eventProducer{}
+	syncer := NewGenericSyncer(nil, &configs.AgentConfig{LeafHubName: "hub1"}, producer)
+	results := []spec.SpecApplyResult{{Kind: "Policy", Namespace: "default", Name: "policy1", Applied: true}}
+
+	// the bundle applied again with the same version is acknowledged by another event
+	require.NoError(t, syncer.reportResults(context.Background(), "policies", 200, results))
+	require.NoError(t, syncer.reportResults(context.Background(), "policies", 200, results))
+	require.Len(t, producer.events, 2)
+	require.NotEmpty(t, producer.events[0].ID())
+	require.NotEqual(t, producer.events[0].ID(), producer.events[1].ID())
+	require.Equal(t, string(enum.HubSpecApplyType), producer.events[0].Type())
+}
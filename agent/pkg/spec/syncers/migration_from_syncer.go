@@ -14,10 +14,10 @@ import (
 	addonv1 "github.com/stolostron/klusterlet-addon-controller/pkg/apis/agent/v1"
 	"go.uber.org/zap"
 	corev1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/types"
 	clusterv1 "open-cluster-management.io/api/cluster/v1"
-	operatorv1 "open-cluster-management.io/api/operator/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	bundleevent "github.com/stolostron/multicluster-global-hub/pkg/bundle/event"
@@ -31,6 +31,8 @@ import (
 // This is a temporary solution to wait for applying the klusterletconfig
 var sleepForApplying = 20 * time.Second
 
+const klusterletConfigAnnotation = "agent.open-cluster-management.io/klusterlet-config"
+
 type managedClusterMigrationFromSyncer struct {
 	log             *zap.SugaredLogger
 	client          client.Client
@@ -54,38 +56,38 @@ func (s *managedClusterMigrationFromSyncer) Sync(ctx context.Context, payload []
 		return err
 	}
 
-	// create or update bootstrap secret
-	bootstrapSecret := managedClusterMigrationEvent.BootstrapSecret
-	foundBootstrapSecret := &corev1.Secret{}
-	if err := s.client.Get(ctx,
-		types.NamespacedName{
-			Name:      bootstrapSecret.Name,
-			Namespace: bootstrapSecret.Namespace,
-		}, foundBootstrapSecret); err != nil {
-		if apierrors.IsNotFound(err) {
-			s.log.Infof("creating bootstrap secret %s", bootstrapSecret.GetName())
-			if err := s.client.Create(ctx, bootstrapSecret); err != nil {
-				return err
-			}
-		} else {
-			return err
+	switch managedClusterMigrationEvent.Stage {
+	case bundleevent.MigrationStageCleaning:
+		return s.detachManagedClusters(ctx, managedClusterMigrationEvent.ManagedClusters)
+	case bundleevent.MigrationStageRollingBack:
+		return s.rollbackManagedClusters(ctx, managedClusterMigrationEvent.ManagedClusters)
+	default:
+		return s.deploy(ctx, managedClusterMigrationEvent)
+	}
+}
+
+// deploy points the managed clusters to the bootstrap kubeconfig of the target hub, then rejects them on the current
+// hub so that the klusterlet registers to the target hub. The managed clusters are kept until the cleaning stage, so
+// that they can be rolled back if the registration fails. The klusterlet config also lists the bootstrap kubeconfig of
+// the current hub, which the klusterlet falls back to if it can't connect to the target hub.
+func (s *managedClusterMigrationFromSyncer) deploy(ctx context.Context,
+	managedClusterMigrationEvent *bundleevent.ManagedClusterMigrationFromEvent,
+) error {
+	// create or update the bootstrap secrets of the target hub and the current hub
+	for _, bootstrapSecret := range []*corev1.Secret{
+		managedClusterMigrationEvent.BootstrapSecret,
+		managedClusterMigrationEvent.SourceBootstrapSecret,
+	} {
+		if bootstrapSecret == nil {
+			continue
 		}
-	} else {
-		// update the bootstrap secret if it already exists
-		s.log.Infof("updating bootstrap secret %s", bootstrapSecret.GetName())
-		if err := s.client.Update(ctx, bootstrapSecret); err != nil {
+		if err := s.ensureBootstrapSecret(ctx, bootstrapSecret); err != nil {
 			return err
 		}
 	}
 
-	// create klusterlet config if it does not exist
+	// create klusterlet config if it does not exist, or update it if the bootstrap kubeconfigs are changed
 	klusterletConfig := managedClusterMigrationEvent.KlusterletConfig
-	// set the bootstrap kubeconfig secrets in klusterlet config
-	klusterletConfig.Spec.BootstrapKubeConfigs.LocalSecrets.KubeConfigSecrets = []operatorv1.KubeConfigSecret{
-		{
-			Name: bootstrapSecret.Name,
-		},
-	}
 	foundKlusterletConfig := &klusterletv1alpha1.KlusterletConfig{}
 	if err := s.client.Get(ctx,
 		types.NamespacedName{
@@ -100,6 +102,12 @@ func (s *managedClusterMigrationFromSyncer) Sync(ctx context.Context, payload []
 		} else {
 			return err
 		}
+	} else if !apiequality.Semantic.DeepEqual(foundKlusterletConfig.Spec, klusterletConfig.Spec) {
+		s.log.Infof("updating klusterlet config %s", klusterletConfig.GetName())
+		foundKlusterletConfig.Spec = klusterletConfig.Spec
+		if err := s.client.Update(ctx, foundKlusterletConfig); err != nil {
+			return err
+		}
 	}
 	managedClusters := managedClusterMigrationEvent.ManagedClusters
 	// update managed cluster annotations to point to the new klusterlet config
@@ -116,10 +124,10 @@ func (s *managedClusterMigrationFromSyncer) Sync(ctx context.Context, payload []
 		}
 
 		_, migrating := annotations[constants.ManagedClusterMigrating]
-		if migrating && annotations["agent.open-cluster-management.io/klusterlet-config"] == klusterletConfig.Name {
+		if migrating && annotations[klusterletConfigAnnotation] == klusterletConfig.Name {
 			continue
 		}
-		annotations["agent.open-cluster-management.io/klusterlet-config"] = klusterletConfig.Name
+		annotations[klusterletConfigAnnotation] = klusterletConfig.Name
 		annotations[constants.ManagedClusterMigrating] = ""
 		mc.SetAnnotations(annotations)
 		if err := s.client.Update(ctx, mc); err != nil {
@@ -150,13 +158,56 @@ func (s *managedClusterMigrationFromSyncer) Sync(ctx context.Context, payload []
 			return err
 		}
 	}
+	return nil
+}
 
-	time.Sleep(sleepForApplying)
-	if err := s.detachManagedClusters(ctx, managedClusters); err != nil {
-		s.log.Error(err, "failed to detach managed clusters")
+func (s *managedClusterMigrationFromSyncer) ensureBootstrapSecret(ctx context.Context,
+	bootstrapSecret *corev1.Secret,
+) error {
+	foundBootstrapSecret := &corev1.Secret{}
+	if err := s.client.Get(ctx,
+		types.NamespacedName{
+			Name:      bootstrapSecret.Name,
+			Namespace: bootstrapSecret.Namespace,
+		}, foundBootstrapSecret); err != nil {
+		if apierrors.IsNotFound(err) {
+			s.log.Infof("creating bootstrap secret %s", bootstrapSecret.GetName())
+			return s.client.Create(ctx, bootstrapSecret)
+		}
 		return err
 	}
+	// update the bootstrap secret if it already exists
+	s.log.Infof("updating bootstrap secret %s", bootstrapSecret.GetName())
+	return s.client.Update(ctx, bootstrapSecret)
+}
 
+// rollbackManagedClusters restores the klusterlet config of the managed clusters and accepts them again, so that the
+// klusterlet, which falls back to the bootstrap kubeconfig of the current hub, registers to the current hub again
+func (s *managedClusterMigrationFromSyncer) rollbackManagedClusters(ctx context.Context, managedClusters []string) error {
+	for _, managedCluster := range managedClusters {
+		mc := &clusterv1.ManagedCluster{}
+		if err := s.client.Get(ctx, types.NamespacedName{
+			Name: managedCluster,
+		}, mc); err != nil {
+			if apierrors.IsNotFound(err) {
+				s.log.Warnf("the managedcluster %s is not found, skip rolling back it", managedCluster)
+				continue
+			}
+			return err
+		}
+
+		annotations := mc.GetAnnotations()
+		if _, migrating := annotations[constants.ManagedClusterMigrating]; migrating {
+			delete(annotations, klusterletConfigAnnotation)
+			delete(annotations, constants.ManagedClusterMigrating)
+			mc.SetAnnotations(annotations)
+		}
+		mc.Spec.HubAcceptsClient = true
+		s.log.Infof("rolling back the managedcluster %s", mc.Name)
+		if err := s.client.Update(ctx, mc); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
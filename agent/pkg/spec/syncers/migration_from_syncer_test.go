@@ -5,6 +5,7 @@ package syncers
 
 import (
 	"context"
+	"encoding/json"
 	"testing"
 	"time"
 
@@ -12,6 +13,7 @@ import (
 	addonv1 "github.com/stolostron/klusterlet-addon-controller/pkg/apis/agent/v1"
 	corev1 "k8s.io/api/core/v1"
 	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
@@ -20,6 +22,9 @@ import (
 	operatorv1 "open-cluster-management.io/api/operator/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	bundleevent "github.com/stolostron/multicluster-global-hub/pkg/bundle/event"
+	"github.com/stolostron/multicluster-global-hub/pkg/constants"
 )
 
 func TestMigrationFromSyncer(t *testing.T) {
@@ -60,6 +65,17 @@ func TestMigrationFromSyncer(t *testing.T) {
 			"test": "dGVzdA=="
 		}
 	},
+	"sourcebootstrapsecret": {
+		"apiVersion": "v1",
+		"kind": "Secret",
+		"metadata": {
+			"name": "source",
+			"namespace": "test"
+		},
+		"data": {
+			"test": "c291cmNl"
+		}
+	},
 	"klusterletconfig": {
 		"apiVersion": "klusterletconfig.open-cluster-management.io/v1alpha1",
 		"kind": "KlusterletConfig",
@@ -69,12 +85,16 @@ func TestMigrationFromSyncer(t *testing.T) {
 		"spec": {
 			"bootstrapKubeConfigs": {
 				"type": "LocalSecrets",
-				"localSecrets": {
+				"localSecretsConfig": {
 					"kubeConfigSecrets": [
 						{
 							"name": "test"
+						},
+						{
+							"name": "source"
 						}
-					]
+					],
+					"hubConnectionTimeoutSeconds": 180
 				}
 			}
 		}
@@ -121,7 +141,11 @@ func TestMigrationFromSyncer(t *testing.T) {
 								{
 									Name: "test",
 								},
+								{
+									Name: "source",
+								},
 							},
+							HubConnectionTimeoutSeconds: 180,
 						},
 					},
 				},
@@ -170,7 +194,11 @@ func TestMigrationFromSyncer(t *testing.T) {
 								{
 									Name: "test",
 								},
+								{
+									Name: "source",
+								},
 							},
+							HubConnectionTimeoutSeconds: 180,
 						},
 					},
 				},
@@ -227,7 +255,11 @@ func TestMigrationFromSyncer(t *testing.T) {
 								{
 									Name: "test",
 								},
+								{
+									Name: "source",
+								},
 							},
+							HubConnectionTimeoutSeconds: 180,
 						},
 					},
 				},
@@ -276,7 +308,11 @@ func TestMigrationFromSyncer(t *testing.T) {
 								{
 									Name: "test",
 								},
+								{
+									Name: "source",
+								},
 							},
+							HubConnectionTimeoutSeconds: 180,
 						},
 					},
 				},
@@ -305,6 +341,16 @@ func TestMigrationFromSyncer(t *testing.T) {
 				}
 			}
 
+			// the bootstrap secret of the current hub is deployed for rolling back
+			foundSourceBootstrapSecret := &corev1.Secret{}
+			if err := client.Get(ctx, types.NamespacedName{Name: "source", Namespace: "test"},
+				foundSourceBootstrapSecret); err != nil {
+				t.Errorf("Failed to get source bootstrap secret: %v", err)
+			}
+			if string(foundSourceBootstrapSecret.Data["test"]) != "source" {
+				t.Errorf("Expected source bootstrap secret data source, but got %v", foundSourceBootstrapSecret.Data)
+			}
+
 			if c.expectedKlusterletConfig != nil {
 				foundKlusterletConfig := &klusterletv1alpha1.KlusterletConfig{}
 				if err := client.Get(ctx, types.NamespacedName{Name: c.expectedKlusterletConfig.Name}, foundKlusterletConfig); err != nil {
@@ -317,3 +363,82 @@ func TestMigrationFromSyncer(t *testing.T) {
 		})
 	}
 }
+
+func TestMigrationFromSyncerStages(t *testing.T) {
+	ctx := context.Background()
+	scheme := runtime.NewScheme()
+	if err := clusterv1.AddToScheme(scheme); err != nil {
+		t.Fatalf("Failed to add clusterv1 to scheme: %v", err)
+	}
+
+	newCluster := func(name string, hubAcceptsClient bool) *clusterv1.ManagedCluster {
+		return &clusterv1.ManagedCluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: name,
+				Annotations: map[string]string{
+					klusterletConfigAnnotation:        "migration-hub2",
+					constants.ManagedClusterMigrating: "",
+				},
+			},
+			Spec: clusterv1.ManagedClusterSpec{
+				HubAcceptsClient: hubAcceptsClient,
+			},
+		}
+	}
+
+	t.Run("clean up the migrated managed clusters", func(t *testing.T) {
+		client := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+			newCluster("cluster1", false), newCluster("cluster2", true)).Build()
+		syncer := NewManagedClusterMigrationFromSyncer(client, nil)
+
+		payload, err := json.Marshal(&bundleevent.ManagedClusterMigrationFromEvent{
+			Stage:           bundleevent.MigrationStageCleaning,
+			ManagedClusters: []string{"cluster1", "cluster2", "cluster3"},
+		})
+		if err != nil {
+			t.Fatalf("Failed to marshal the event: %v", err)
+		}
+		if err := syncer.Sync(ctx, payload); err != nil {
+			t.Fatalf("Failed to sync managed cluster migration: %v", err)
+		}
+
+		if err := client.Get(ctx, types.NamespacedName{Name: "cluster1"},
+			&clusterv1.ManagedCluster{}); !apierrors.IsNotFound(err) {
+			t.Errorf("Expected the managed cluster cluster1 is deleted, but got %v", err)
+		}
+		// the cluster still accepted by the hub isn't handed off, so it's kept
+		if err := client.Get(ctx, types.NamespacedName{Name: "cluster2"}, &clusterv1.ManagedCluster{}); err != nil {
+			t.Errorf("Expected the managed cluster cluster2 is kept, but got %v", err)
+		}
+	})
+
+	t.Run("roll back the managed clusters", func(t *testing.T) {
+		client := fake.NewClientBuilder().WithScheme(scheme).WithObjects(newCluster("cluster1", false)).Build()
+		syncer := NewManagedClusterMigrationFromSyncer(client, nil)
+
+		payload, err := json.Marshal(&bundleevent.ManagedClusterMigrationFromEvent{
+			Stage:           bundleevent.MigrationStageRollingBack,
+			ManagedClusters: []string{"cluster1", "cluster2"},
+		})
+		if err != nil {
+			t.Fatalf("Failed to marshal the event: %v", err)
+		}
+		if err := syncer.Sync(ctx, payload); err != nil {
+			t.Fatalf("Failed to sync managed cluster migration: %v", err)
+		}
+
+		mc := &clusterv1.ManagedCluster{}
+		if err := client.Get(ctx, types.NamespacedName{Name: "cluster1"}, mc); err != nil {
+			t.Fatalf("Failed to get the managed cluster: %v", err)
+		}
+		if !mc.Spec.HubAcceptsClient {
+			t.Errorf("Expected the managed cluster is accepted again")
+		}
+		if _, ok := mc.Annotations[klusterletConfigAnnotation]; ok {
+			t.Errorf("Expected the klusterlet config annotation is removed, but got %v", mc.Annotations)
+		}
+		if _, ok := mc.Annotations[constants.ManagedClusterMigrating]; ok {
+			t.Errorf("Expected the migrating annotation is removed, but got %v", mc.Annotations)
+		}
+	})
+}
@@ -17,6 +17,7 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/util/retry"
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
 	operatorv1 "open-cluster-management.io/api/operator/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
@@ -43,6 +44,10 @@ func (s *managedClusterMigrationToSyncer) Sync(ctx context.Context, payload []by
 		return err
 	}
 
+	if managedClusterMigrationToEvent.Stage == bundleevent.MigrationStageRollingBack {
+		return s.rollbackManagedClusters(ctx, managedClusterMigrationToEvent.ManagedClusters)
+	}
+
 	msaName := managedClusterMigrationToEvent.ManagedServiceAccountName
 	msaNamespace := managedClusterMigrationToEvent.ManagedServiceAccountInstallNamespace
 
@@ -75,6 +80,25 @@ func (s *managedClusterMigrationToSyncer) Sync(ctx context.Context, payload []by
 	return nil
 }
 
+// rollbackManagedClusters removes the managed clusters that are partially registered to the target hub, so that the
+// klusterlet is rejected by the target hub and turns back to the source hub
+func (s *managedClusterMigrationToSyncer) rollbackManagedClusters(ctx context.Context, managedClusters []string) error {
+	for _, managedCluster := range managedClusters {
+		mc := &clusterv1.ManagedCluster{}
+		if err := s.client.Get(ctx, types.NamespacedName{Name: managedCluster}, mc); err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			return err
+		}
+		s.log.Infof("deleting the managedcluster %s to roll back the migration", managedCluster)
+		if err := s.client.Delete(ctx, mc); err != nil && !apierrors.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
+
 func (s *managedClusterMigrationToSyncer) ensureClusterManager(ctx context.Context,
 	msaName, msaNamespace string,
 ) error {
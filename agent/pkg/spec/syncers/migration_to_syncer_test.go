@@ -11,6 +11,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
@@ -595,3 +596,36 @@ func TestMigrationToSyncer(t *testing.T) {
 		})
 	}
 }
+
+func TestMigrationToSyncerRollback(t *testing.T) {
+	ctx := context.Background()
+	scheme := runtime.NewScheme()
+	if err := clusterv1.AddToScheme(scheme); err != nil {
+		t.Fatalf("Failed to add clusterv1 to scheme: %v", err)
+	}
+	client := fake.NewClientBuilder().WithScheme(scheme).WithObjects(&clusterv1.ManagedCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster1"},
+	}, &clusterv1.ManagedCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster2"},
+	}).Build()
+
+	// the cluster manager isn't required to roll back the managed clusters
+	testPayload := []byte(`
+{
+	"stage": "RollingBack",
+	"managedClusters": ["cluster1", "cluster3"],
+	"managedServiceAccountName": "test",
+	"managedServiceAccountInstallNamespace": "test"
+}`)
+	if err := NewManagedClusterMigrationToSyncer(client).Sync(ctx, testPayload); err != nil {
+		t.Fatalf("Failed to sync managed cluster migration: %v", err)
+	}
+
+	if err := client.Get(ctx, types.NamespacedName{Name: "cluster1"},
+		&clusterv1.ManagedCluster{}); !apierrors.IsNotFound(err) {
+		t.Errorf("Expected the managed cluster cluster1 is deleted, but got %v", err)
+	}
+	if err := client.Get(ctx, types.NamespacedName{Name: "cluster2"}, &clusterv1.ManagedCluster{}); err != nil {
+		t.Errorf("Expected the managed cluster cluster2 is kept, but got %v", err)
+	}
+}
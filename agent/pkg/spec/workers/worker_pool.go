@@ -4,10 +4,12 @@ import (
 	"context"
 	"fmt"
 	"sync"
+	"time"
 
 	"go.uber.org/zap"
 	"k8s.io/client-go/rest"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/stolostron/multicluster-global-hub/agent/pkg/spec/rbac"
 	"github.com/stolostron/multicluster-global-hub/pkg/logger"
@@ -15,6 +17,9 @@ import (
 
 var addToMgr = false
 
+// UserWorkerIdleTimeout is how long the worker of the impersonated user is kept without jobs before it's reaped.
+const UserWorkerIdleTimeout = 10 * time.Minute
+
 // Workpool pool that creates all k8s workers and the assigns k8s jobs to available workers.
 type WorkerPool struct {
 	ctx                        context.Context
@@ -24,8 +29,18 @@ type WorkerPool struct {
 	poolSize                   int
 	initializationWaitingGroup sync.WaitGroup
 	impersonationManager       *rbac.ImpersonationManager
-	impersonationWorkersQueues map[string]chan *Job
+	impersonationWorkers       map[string]*userWorker
 	impersonationWorkersLock   sync.Mutex
+	userWorkerIdleTimeout      time.Duration
+}
+
+// userWorker is the worker of an impersonated user, the pending jobs are counted under the lock of the pool, so that
+// the worker isn't reaped between the job is assigned and pushed into the queue.
+type userWorker struct {
+	queue      chan *Job
+	cancel     context.CancelFunc
+	pending    int
+	lastActive time.Time
 }
 
 // AddK8sWorkerPool adds k8s workers pool to the manager and returns it.
@@ -42,8 +57,9 @@ func AddWorkerPoolToMgr(mgr ctrl.Manager, size int, config *rest.Config) (*Worke
 		poolSize:                   size,
 		initializationWaitingGroup: sync.WaitGroup{},
 		impersonationManager:       rbac.NewImpersonationManager(config),
-		impersonationWorkersQueues: make(map[string]chan *Job),
+		impersonationWorkers:       make(map[string]*userWorker),
 		impersonationWorkersLock:   sync.Mutex{},
+		userWorkerIdleTimeout:      UserWorkerIdleTimeout,
 	}
 	workerPool.initializationWaitingGroup.Add(1)
 
@@ -69,22 +85,31 @@ func (pool *WorkerPool) Start(ctx context.Context) error {
 		worker.start(ctx)
 	}
 
-	<-ctx.Done() // blocking wait for stop event
-
-	// context was cancelled, do cleanup
-	for _, workerQueue := range pool.impersonationWorkersQueues {
-		close(workerQueue)
+	ticker := time.NewTicker(pool.userWorkerIdleTimeout / 2)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done(): // blocking wait for stop event
+			// context was cancelled, the user workers are stopped by the context
+			close(pool.jobsQueue)
+			return nil
+		case now := <-ticker.C:
+			if reaped := pool.reapIdleWorkers(now); reaped > 0 {
+				pool.log.Infow("reaped the idle user workers", "count", reaped)
+			}
+		}
 	}
+}
 
-	close(pool.jobsQueue)
-
-	return nil
+// GetIdentity returns the user identity and the user groups which the obj is applied with.
+func (pool *WorkerPool) GetIdentity(obj interface{}) (string, []string, error) {
+	return pool.impersonationManager.GetIdentity(obj)
 }
 
 func (pool *WorkerPool) Submit(job *Job) {
 	pool.initializationWaitingGroup.Wait() // start running jobs only after some initialization steps have finished.
 
-	userIdentity, err := pool.impersonationManager.GetUserIdentity(job.obj)
+	userIdentity, userGroups, err := pool.impersonationManager.GetIdentity(job.obj)
 	if err != nil {
 		pool.log.Error(err, "failed to get user identity from obj")
 		return
@@ -94,40 +119,67 @@ func (pool *WorkerPool) Submit(job *Job) {
 		pool.jobsQueue <- job
 		return
 	}
+
 	// otherwise, need to impersonate and use the specific worker to enforce permissions.
-	base64UserGroups, userGroups, err := pool.impersonationManager.GetUserGroups(job.obj)
-	if err != nil {
-		pool.log.Error(err, "failed to get user groups from obj")
-		return
-	}
+	workerIdentifier := rbac.IdentityKey(userIdentity, userGroups)
 
 	pool.impersonationWorkersLock.Lock()
-
-	workerIdentifier := fmt.Sprintf("%s.%s", userIdentity, base64UserGroups)
-
-	if _, found := pool.impersonationWorkersQueues[workerIdentifier]; !found {
-		if err := pool.createUserWorker(userIdentity, userGroups, workerIdentifier); err != nil {
+	worker, found := pool.impersonationWorkers[workerIdentifier]
+	if !found {
+		worker, err = pool.createUserWorker(userIdentity, userGroups)
+		if err != nil {
+			pool.impersonationWorkersLock.Unlock()
 			pool.log.Error(err, "failed to create user worker", "user", userIdentity)
 			return
 		}
+		pool.impersonationWorkers[workerIdentifier] = worker
 	}
-	// push the job to the queue of the specific worker that uses the user identity
-	workerQueue := pool.impersonationWorkersQueues[workerIdentifier]
-
+	worker.pending++
 	pool.impersonationWorkersLock.Unlock()
-	workerQueue <- job // since this call might get blocking, first Unlock, then try to insert job into queue
+
+	// push the job to the queue of the specific worker that uses the user identity, since this call might get blocking,
+	// first Unlock, then try to insert job into queue
+	worker.queue <- NewJob(job.obj, func(ctx context.Context, k8sClient client.Client, obj interface{}) {
+		defer pool.userJobDone(worker)
+		job.handlerFunc(ctx, k8sClient, obj)
+	})
 }
 
-func (pool *WorkerPool) createUserWorker(userIdentity string, userGroups []string, workerIdentifier string) error {
+func (pool *WorkerPool) createUserWorker(userIdentity string, userGroups []string) (*userWorker, error) {
 	k8sClient, err := pool.impersonationManager.Impersonate(userIdentity, userGroups)
 	if err != nil {
-		return fmt.Errorf("failed to impersonate - %w", err)
+		return nil, fmt.Errorf("failed to impersonate - %w", err)
 	}
 
+	ctx, cancel := context.WithCancel(pool.ctx)
 	workerQueue := make(chan *Job, pool.poolSize)
 	worker := newWorkerWithClient(1, fmt.Sprintf("impersonation-%s", userIdentity), k8sClient, workerQueue)
-	worker.start(pool.ctx)
-	pool.impersonationWorkersQueues[workerIdentifier] = workerQueue
+	worker.start(ctx)
+
+	return &userWorker{queue: workerQueue, cancel: cancel, lastActive: time.Now()}, nil
+}
+
+func (pool *WorkerPool) userJobDone(worker *userWorker) {
+	pool.impersonationWorkersLock.Lock()
+	defer pool.impersonationWorkersLock.Unlock()
+	worker.pending--
+	worker.lastActive = time.Now()
+}
+
+// reapIdleWorkers stops the user workers which have no pending jobs within the idle timeout, and returns the number of
+// the reaped workers.
+func (pool *WorkerPool) reapIdleWorkers(now time.Time) int {
+	pool.impersonationWorkersLock.Lock()
+	defer pool.impersonationWorkersLock.Unlock()
 
-	return nil
+	reaped := 0
+	for workerIdentifier, worker := range pool.impersonationWorkers {
+		if worker.pending > 0 || now.Sub(worker.lastActive) < pool.userWorkerIdleTimeout {
+			continue
+		}
+		worker.cancel()
+		delete(pool.impersonationWorkers, workerIdentifier)
+		reaped++
+	}
+	return reaped
 }
@@ -0,0 +1,77 @@
+package workers
+
+import (
+	"context"
+	"encoding/base64"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/stolostron/multicluster-global-hub/agent/pkg/spec/rbac"
+	"github.com/stolostron/multicluster-global-hub/pkg/logger"
+)
+
+func newUserObject(user string) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{}
+	obj.SetAnnotations(map[string]string{
+		rbac.UserIdentityAnnotation: base64.StdEncoding.EncodeToString([]byte(user)),
+	})
+	return obj
+}
+
+func TestReapIdleWorkers(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	config := &rest.Config{Host: "https://127.0.0.1:6443"}
+	pool := &WorkerPool{
+		ctx:                   ctx,
+		log:                   logger.DefaultZapLogger(),
+		kubeConfig:            config,
+		poolSize:              1,
+		impersonationManager:  rbac.NewImpersonationManager(config),
+		impersonationWorkers:  map[string]*userWorker{},
+		userWorkerIdleTimeout: time.Minute,
+	}
+
+	done := make(chan struct{})
+	pool.Submit(NewJob(newUserObject("alice"), func(ctx context.Context, c client.Client, obj interface{}) {
+		close(done)
+	}))
+	<-done
+
+	release := make(chan struct{})
+	pool.Submit(NewJob(newUserObject("bob"), func(ctx context.Context, c client.Client, obj interface{}) {
+		<-release
+	}))
+
+	// only the worker of alice is idle, the worker of bob is running the job
+	require.Eventually(t, func() bool {
+		pool.impersonationWorkersLock.Lock()
+		defer pool.impersonationWorkersLock.Unlock()
+		return len(pool.impersonationWorkers) == 2 && pool.impersonationWorkers[rbac.IdentityKey("alice", nil)].pending == 0
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.Equal(t, 0, pool.reapIdleWorkers(time.Now()))
+	assert.Equal(t, 1, pool.reapIdleWorkers(time.Now().Add(time.Minute)))
+	assert.Contains(t, pool.impersonationWorkers, rbac.IdentityKey("bob", nil))
+
+	// the worker of bob is reaped after the job is finished
+	close(release)
+	require.Eventually(t, func() bool {
+		return pool.reapIdleWorkers(time.Now().Add(time.Minute)) == 1
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.Empty(t, pool.impersonationWorkers)
+
+	// the worker is recreated for the user once reaped
+	done = make(chan struct{})
+	pool.Submit(NewJob(newUserObject("alice"), func(ctx context.Context, c client.Client, obj interface{}) {
+		close(done)
+	}))
+	<-done
+	assert.Contains(t, pool.impersonationWorkers, rbac.IdentityKey("alice", nil))
+}
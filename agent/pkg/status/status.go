@@ -18,6 +18,7 @@ import (
 	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/syncers/managedhub"
 	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/syncers/placement"
 	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/syncers/policies"
+	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/syncers/resources"
 	"github.com/stolostron/multicluster-global-hub/pkg/transport"
 )
 
@@ -93,6 +94,11 @@ func addKafkaSyncer(ctx context.Context, mgr ctrl.Manager, producer transport.Pr
 		return fmt.Errorf("failed to launch subscription report syncer: %w", err)
 	}
 
+	// the resources declared in the agent config
+	if err := resources.LaunchResourceSyncer(mgr, producer); err != nil {
+		return fmt.Errorf("failed to launch resource syncer: %w", err)
+	}
+
 	// lunch a time filter, it must be called after filter.RegisterTimeFilter(key)
 	if err := filter.LaunchTimeFilter(ctx, mgr.GetClient(), agentConfig.PodNamespace,
 		agentConfig.TransportConfig.KafkaCredential.StatusTopic); err != nil {
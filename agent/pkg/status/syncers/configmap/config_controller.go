@@ -12,6 +12,7 @@ import (
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/yaml"
 
 	"github.com/stolostron/multicluster-global-hub/agent/pkg/configs"
 	"github.com/stolostron/multicluster-global-hub/pkg/constants"
@@ -64,9 +65,12 @@ func (c *hubOfHubsConfigController) Reconcile(ctx context.Context, request ctrl.
 	c.setSyncInterval(agentConfigMap, HubClusterInfoIntervalKey)
 	c.setSyncInterval(agentConfigMap, HubClusterHeartBeatIntervalKey)
 	c.setSyncInterval(agentConfigMap, EventIntervalKey)
+	c.setSyncInterval(agentConfigMap, ManagedClusterFullSyncKey)
 
 	c.setAgentConfig(agentConfigMap, AgentAggregationKey)
 	c.setAgentConfig(agentConfigMap, EnableLocalPolicyKey)
+	c.setAgentConfig(agentConfigMap, ManagedClusterSyncModeKey)
+	c.setResourceDeclarations(agentConfigMap)
 
 	logLevel := agentConfigMap.Data[string(AgentLogLevelKey)]
 	if logLevel != "" {
@@ -100,3 +104,18 @@ func (c *hubOfHubsConfigController) setAgentConfig(configMap *v1.ConfigMap, conf
 	}
 	agentConfigs[configKey] = AgentConfigValue(val)
 }
+
+func (c *hubOfHubsConfigController) setResourceDeclarations(configMap *v1.ConfigMap) {
+	val, found := configMap.Data[string(ResourcesKey)]
+	if !found {
+		SetResourceDeclarations(nil)
+		return
+	}
+
+	declarations := []ResourceDeclaration{}
+	if err := yaml.Unmarshal([]byte(val), &declarations); err != nil {
+		c.log.Info(fmt.Sprintf("%s has invalid format, keeping the current declarations: %v", ResourcesKey, err))
+		return
+	}
+	SetResourceDeclarations(declarations)
+}
@@ -1,7 +1,10 @@
 package configmap
 
 import (
+	"sync"
 	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 var (
@@ -11,11 +14,15 @@ var (
 		HubClusterInfoIntervalKey:      60 * time.Second,
 		HubClusterHeartBeatIntervalKey: 60 * time.Second,
 		EventIntervalKey:               5 * time.Second,
+		ManagedClusterFullSyncKey:      5 * time.Minute,
 	}
 	agentConfigs = map[AgentConfigKey]AgentConfigValue{
-		AgentAggregationKey:  AggregationFull,
-		EnableLocalPolicyKey: EnableLocalPolicyTrue,
+		AgentAggregationKey:       AggregationFull,
+		EnableLocalPolicyKey:      EnableLocalPolicyTrue,
+		ManagedClusterSyncModeKey: SyncModeComplete,
 	}
+	resourceDeclarations     []ResourceDeclaration
+	resourceDeclarationsLock sync.RWMutex
 )
 
 type AgentConfigKey string
@@ -26,10 +33,13 @@ const (
 	HubClusterInfoIntervalKey      AgentConfigKey = "hubClusterInfo"
 	HubClusterHeartBeatIntervalKey AgentConfigKey = "hubClusterHeartbeat"
 	EventIntervalKey               AgentConfigKey = "events"
+	ManagedClusterFullSyncKey      AgentConfigKey = "managedClusterFullSync"
 
-	AgentAggregationKey  AgentConfigKey = "aggregationLevel"
-	EnableLocalPolicyKey AgentConfigKey = "enableLocalPolicies"
-	AgentLogLevelKey     AgentConfigKey = "logLevel"
+	AgentAggregationKey       AgentConfigKey = "aggregationLevel"
+	EnableLocalPolicyKey      AgentConfigKey = "enableLocalPolicies"
+	AgentLogLevelKey          AgentConfigKey = "logLevel"
+	ResourcesKey              AgentConfigKey = "resources"
+	ManagedClusterSyncModeKey AgentConfigKey = "managedClusterSyncMode"
 )
 
 type AgentConfigValue string
@@ -39,8 +49,26 @@ const (
 	AggregationMinimal     AgentConfigValue = "minimal"
 	EnableLocalPolicyTrue  AgentConfigValue = "true"
 	EnableLocalPolicyFalse AgentConfigValue = "false"
+	// SyncModeComplete sends all the objects in each bundle
+	SyncModeComplete AgentConfigValue = "complete"
+	// SyncModeDelta sends the changed objects in each bundle, and all the objects in the periodic full sync
+	SyncModeDelta AgentConfigValue = "delta"
 )
 
+// DefaultResourceInterval is the interval to list the declared resources if it isn't specified.
+const DefaultResourceInterval = 60 * time.Second
+
+// ResourceDeclaration declares the resources of a kind to be collected from the hub. The objects matched by the label
+// selector are listed in each interval, and only the values of the JSONPath expressions in the fields are reported,
+// e.g. "{.status.powerState}". The fields are required, and the sensitive kinds like the secrets are never collected.
+type ResourceDeclaration struct {
+	APIVersion    string                `json:"apiVersion"`
+	Kind          string                `json:"kind"`
+	LabelSelector *metav1.LabelSelector `json:"labelSelector,omitempty"`
+	Fields        []string              `json:"fields,omitempty"`
+	Interval      metav1.Duration       `json:"interval,omitempty"`
+}
+
 // ResolveSyncIntervalFunc is a function for resolving corresponding sync interval from SyncIntervals data structure.
 type ResolveSyncIntervalFunc func() time.Duration
 
@@ -67,6 +95,11 @@ func GetEventDuration() time.Duration {
 	return syncIntervals[EventIntervalKey]
 }
 
+// GetManagedClusterFullSyncDuration returns the interval to send all the managed clusters in the delta sync mode.
+func GetManagedClusterFullSyncDuration() time.Duration {
+	return syncIntervals[ManagedClusterFullSyncKey]
+}
+
 func GetAggregationLevel() AgentConfigValue {
 	return agentConfigs[AgentAggregationKey]
 }
@@ -75,6 +108,27 @@ func GetEnableLocalPolicy() AgentConfigValue {
 	return agentConfigs[EnableLocalPolicyKey]
 }
 
+func GetManagedClusterSyncMode() AgentConfigValue {
+	return agentConfigs[ManagedClusterSyncModeKey]
+}
+
+func SetAgentConfig(key AgentConfigKey, val AgentConfigValue) {
+	agentConfigs[key] = val
+}
+
 func SetInterval(key AgentConfigKey, val time.Duration) {
 	syncIntervals[key] = val
 }
+
+// GetResourceDeclarations returns the resources declared to be collected from the hub.
+func GetResourceDeclarations() []ResourceDeclaration {
+	resourceDeclarationsLock.RLock()
+	defer resourceDeclarationsLock.RUnlock()
+	return resourceDeclarations
+}
+
+func SetResourceDeclarations(declarations []ResourceDeclaration) {
+	resourceDeclarationsLock.Lock()
+	defer resourceDeclarationsLock.Unlock()
+	resourceDeclarations = declarations
+}
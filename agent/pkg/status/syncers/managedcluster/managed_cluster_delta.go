@@ -0,0 +1,148 @@
+package managedcluster
+
+import (
+	"time"
+
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/interfaces"
+	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/syncers/configmap"
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/cluster"
+)
+
+const clusterIDClaim = "id.k8s.io"
+
+// deltaHandler collects the managed clusters which are changed since the last sent delta bundle. It only works in the
+// delta sync mode, and the bundle is reset once it's sent.
+type deltaHandler struct {
+	eventData    *cluster.ManagedClusterDeltaBundle
+	tweakFunc    func(client.Object)
+	shouldUpdate func(client.Object) bool
+	// the resourceVersion and the cluster id of the managed clusters which are known by the handler
+	versions   map[string]string
+	clusterIDs map[string]string
+}
+
+func newDeltaHandler(tweakFunc func(client.Object), shouldUpdate func(client.Object) bool) *deltaHandler {
+	return &deltaHandler{
+		eventData:    &cluster.ManagedClusterDeltaBundle{},
+		tweakFunc:    tweakFunc,
+		shouldUpdate: shouldUpdate,
+		versions:     map[string]string{},
+		clusterIDs:   map[string]string{},
+	}
+}
+
+func (h *deltaHandler) Get() interface{} {
+	return h.eventData
+}
+
+func (h *deltaHandler) Update(obj client.Object) bool {
+	managedCluster, ok := obj.(*clusterv1.ManagedCluster)
+	if !ok || configmap.GetManagedClusterSyncMode() != configmap.SyncModeDelta || !h.shouldUpdate(obj) {
+		return false
+	}
+	if h.versions[obj.GetName()] == obj.GetResourceVersion() {
+		return false
+	}
+	h.versions[obj.GetName()] = obj.GetResourceVersion()
+	h.clusterIDs[obj.GetName()] = getClusterID(managedCluster)
+
+	updated := managedCluster.DeepCopy()
+	if h.tweakFunc != nil {
+		h.tweakFunc(updated)
+	}
+	h.eventData.Delete = removeDeleted(h.eventData.Delete, obj.GetName())
+	for i := range h.eventData.Update {
+		if h.eventData.Update[i].Name == obj.GetName() {
+			h.eventData.Update[i] = *updated
+			return true
+		}
+	}
+	h.eventData.Update = append(h.eventData.Update, *updated)
+	return true
+}
+
+func (h *deltaHandler) Delete(obj client.Object) bool {
+	if configmap.GetManagedClusterSyncMode() != configmap.SyncModeDelta || !h.shouldUpdate(obj) {
+		return false
+	}
+	if _, found := h.versions[obj.GetName()]; !found {
+		return false
+	}
+	clusterID := h.clusterIDs[obj.GetName()]
+	delete(h.versions, obj.GetName())
+	delete(h.clusterIDs, obj.GetName())
+
+	for i := range h.eventData.Update {
+		if h.eventData.Update[i].Name == obj.GetName() {
+			h.eventData.Update = append(h.eventData.Update[:i], h.eventData.Update[i+1:]...)
+			break
+		}
+	}
+	// the cluster without the id isn't saved by the manager
+	if clusterID == "" {
+		return true
+	}
+	h.eventData.Delete = append(removeDeleted(h.eventData.Delete, obj.GetName()), cluster.DeletedManagedCluster{
+		Name:      obj.GetName(),
+		ClusterID: clusterID,
+	})
+	return true
+}
+
+// reset clears the sent changes, it's called after the delta bundle is sent.
+func (h *deltaHandler) reset(interface{}) {
+	h.eventData.Update = nil
+	h.eventData.Delete = nil
+}
+
+// deltaEmitter only sends the delta bundle in the delta sync mode.
+type deltaEmitter struct {
+	interfaces.Emitter
+}
+
+func (e *deltaEmitter) ShouldSend() bool {
+	return configmap.GetManagedClusterSyncMode() == configmap.SyncModeDelta && e.Emitter.ShouldSend()
+}
+
+// fullSyncEmitter sends the complete bundle at most once in the full sync interval in the delta sync mode, so that the
+// changes lost by the delta bundles are recovered.
+type fullSyncEmitter struct {
+	interfaces.Emitter
+	lastSentTime time.Time
+}
+
+func (e *fullSyncEmitter) ShouldSend() bool {
+	if !e.Emitter.ShouldSend() {
+		return false
+	}
+	if configmap.GetManagedClusterSyncMode() != configmap.SyncModeDelta {
+		return true
+	}
+	return time.Since(e.lastSentTime) >= configmap.GetManagedClusterFullSyncDuration()
+}
+
+func (e *fullSyncEmitter) PostSend(data interface{}) {
+	e.Emitter.PostSend(data)
+	e.lastSentTime = time.Now()
+}
+
+func getClusterID(managedCluster *clusterv1.ManagedCluster) string {
+	for _, claim := range managedCluster.Status.ClusterClaims {
+		if claim.Name == clusterIDClaim {
+			return claim.Value
+		}
+	}
+	return ""
+}
+
+func removeDeleted(deleted []cluster.DeletedManagedCluster, name string) []cluster.DeletedManagedCluster {
+	for i := range deleted {
+		if deleted[i].Name == name {
+			return append(deleted[:i], deleted[i+1:]...)
+		}
+	}
+	return deleted
+}
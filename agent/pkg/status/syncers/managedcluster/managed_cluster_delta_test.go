@@ -0,0 +1,94 @@
+package managedcluster
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/generic"
+	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/syncers/configmap"
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/cluster"
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
+)
+
+func newManagedCluster(name, resourceVersion, clusterID string) *clusterv1.ManagedCluster {
+	managedCluster := &clusterv1.ManagedCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: name, ResourceVersion: resourceVersion},
+	}
+	if clusterID != "" {
+		managedCluster.Status.ClusterClaims = []clusterv1.ManagedClusterClaim{{Name: clusterIDClaim, Value: clusterID}}
+	}
+	return managedCluster
+}
+
+func TestDeltaHandler(t *testing.T) {
+	configmap.SetAgentConfig(configmap.ManagedClusterSyncModeKey, configmap.SyncModeDelta)
+	defer configmap.SetAgentConfig(configmap.ManagedClusterSyncModeKey, configmap.SyncModeComplete)
+
+	h := newDeltaHandler(func(obj client.Object) { obj.SetAnnotations(map[string]string{"tweaked": "true"}) },
+		func(obj client.Object) bool { return true })
+	bundle := h.Get().(*cluster.ManagedClusterDeltaBundle)
+
+	assert.True(t, h.Update(newManagedCluster("cluster1", "1", "id1")))
+	assert.True(t, h.Update(newManagedCluster("cluster2", "1", "")))
+	// the unchanged cluster isn't added again
+	assert.False(t, h.Update(newManagedCluster("cluster1", "1", "id1")))
+	assert.True(t, h.Update(newManagedCluster("cluster1", "2", "id1")))
+	require.Len(t, bundle.Update, 2)
+	assert.Equal(t, "2", bundle.Update[0].ResourceVersion)
+	assert.Equal(t, "true", bundle.Update[0].Annotations["tweaked"])
+
+	h.reset(bundle)
+	assert.Empty(t, bundle.Update)
+
+	// the deleted cluster is identified by the cluster id, which is kept from the updated object
+	assert.True(t, h.Delete(&clusterv1.ManagedCluster{ObjectMeta: metav1.ObjectMeta{Name: "cluster1"}}))
+	assert.True(t, h.Delete(&clusterv1.ManagedCluster{ObjectMeta: metav1.ObjectMeta{Name: "cluster2"}}))
+	assert.False(t, h.Delete(&clusterv1.ManagedCluster{ObjectMeta: metav1.ObjectMeta{Name: "cluster3"}}))
+	assert.Equal(t, []cluster.DeletedManagedCluster{{Name: "cluster1", ClusterID: "id1"}}, bundle.Delete)
+
+	// the cluster is recreated before the delta bundle is sent
+	assert.True(t, h.Update(newManagedCluster("cluster1", "3", "id1")))
+	assert.Empty(t, bundle.Delete)
+	assert.Len(t, bundle.Update, 1)
+
+	// nothing is collected in the complete sync mode
+	configmap.SetAgentConfig(configmap.ManagedClusterSyncModeKey, configmap.SyncModeComplete)
+	assert.False(t, h.Update(newManagedCluster("cluster4", "1", "id4")))
+}
+
+func TestFullSyncEmitter(t *testing.T) {
+	configmap.SetAgentConfig(configmap.ManagedClusterSyncModeKey, configmap.SyncModeDelta)
+	defer configmap.SetAgentConfig(configmap.ManagedClusterSyncModeKey, configmap.SyncModeComplete)
+
+	fullSync := &fullSyncEmitter{Emitter: generic.NewGenericEmitter(enum.ManagedClusterType)}
+	delta := &deltaEmitter{Emitter: generic.NewGenericEmitter(enum.ManagedClusterDeltaType)}
+
+	// the first complete bundle is sent immediately
+	fullSync.PostUpdate()
+	delta.PostUpdate()
+	assert.True(t, fullSync.ShouldSend())
+	assert.True(t, delta.ShouldSend())
+	fullSync.PostSend(nil)
+	delta.PostSend(nil)
+
+	// the later changes are only sent by the delta bundle before the full sync interval elapses
+	fullSync.PostUpdate()
+	delta.PostUpdate()
+	assert.False(t, fullSync.ShouldSend())
+	assert.True(t, delta.ShouldSend())
+
+	fullSync.lastSentTime = time.Now().Add(-configmap.GetManagedClusterFullSyncDuration())
+	assert.True(t, fullSync.ShouldSend())
+
+	// the delta bundle isn't sent in the complete sync mode
+	configmap.SetAgentConfig(configmap.ManagedClusterSyncModeKey, configmap.SyncModeComplete)
+	fullSync.lastSentTime = time.Now()
+	assert.True(t, fullSync.ShouldSend())
+	assert.False(t, delta.ShouldSend())
+}
@@ -30,6 +30,10 @@ func LaunchManagedClusterSyncer(ctx context.Context, mgr ctrl.Manager, agentConf
 	}
 	shouldUpdate := func(obj client.Object) bool { return !utils.HasAnnotation(obj, constants.ManagedClusterMigrating) }
 
+	// in the delta sync mode, the delta bundle carries the changed clusters in each interval, and the complete bundle
+	// is only sent in the full sync interval
+	deltaHandler := newDeltaHandler(tweakFunc, shouldUpdate)
+
 	return generic.LaunchMultiEventSyncer(
 		"status.managed_cluster",
 		mgr,
@@ -42,7 +46,12 @@ func LaunchManagedClusterSyncer(ctx context.Context, mgr ctrl.Manager, agentConf
 			{
 				Handler: generic.NewGenericHandler(&eventData, generic.WithTweakFunc(tweakFunc),
 					generic.WithShouldUpdate(shouldUpdate)),
-				Emitter: generic.NewGenericEmitter(enum.ManagedClusterType),
+				Emitter: &fullSyncEmitter{Emitter: generic.NewGenericEmitter(enum.ManagedClusterType)},
+			},
+			{
+				Handler: deltaHandler,
+				Emitter: &deltaEmitter{Emitter: generic.NewGenericEmitter(enum.ManagedClusterDeltaType,
+					generic.WithPostSend(deltaHandler.reset))},
 			},
 		})
 }
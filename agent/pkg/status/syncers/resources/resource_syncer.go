@@ -0,0 +1,321 @@
+package resources
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	cecontext "github.com/cloudevents/sdk-go/v2/context"
+	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/jsonpath"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/stolostron/multicluster-global-hub/agent/pkg/configs"
+	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/generic"
+	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/interfaces"
+	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/syncers/configmap"
+	genericpayload "github.com/stolostron/multicluster-global-hub/pkg/bundle/generic"
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
+	"github.com/stolostron/multicluster-global-hub/pkg/logger"
+	"github.com/stolostron/multicluster-global-hub/pkg/transport"
+	"github.com/stolostron/multicluster-global-hub/pkg/utils"
+)
+
+const (
+	// FieldsKey is the key of the projected values in the reported object, the values are keyed by the expressions
+	FieldsKey = "fields"
+
+	// the declarations are checked in each tick, and the kinds are listed when their intervals elapse
+	tickInterval = 5 * time.Second
+	listPageSize = 500
+)
+
+// resourceSyncer lists the resources declared in the agent config periodically, and sends the objects of all the kinds
+// in a complete state bundle. The kinds are read from the config in each tick, so they can be changed without
+// restarting the agent.
+type resourceSyncer struct {
+	log         *zap.SugaredLogger
+	reader      client.Reader
+	producer    transport.Producer
+	emitter     interfaces.Emitter
+	leafHubName string
+
+	lock sync.Mutex
+	// the objects and the time they are listed for each declaration
+	objects  map[string][]client.Object
+	listedAt map[string]time.Time
+}
+
+// LaunchResourceSyncer adds the syncer of the declared resources to the manager.
+func LaunchResourceSyncer(mgr ctrl.Manager, producer transport.Producer) error {
+	syncer := &resourceSyncer{
+		log:         logger.ZapLogger("status.resource"),
+		reader:      mgr.GetAPIReader(),
+		producer:    producer,
+		emitter:     generic.NewGenericEmitter(enum.ResourceType),
+		leafHubName: configs.GetLeafHubName(),
+		objects:     map[string][]client.Object{},
+		listedAt:    map[string]time.Time{},
+	}
+	return mgr.Add(syncer)
+}
+
+func (s *resourceSyncer) Start(ctx context.Context) error {
+	ticker := time.NewTicker(tickInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			if s.refresh(ctx, configmap.GetResourceDeclarations(), time.Now()) {
+				s.emitter.PostUpdate()
+			}
+			s.send(ctx)
+		}
+	}
+}
+
+// refresh lists the declarations whose intervals elapse, and drops the objects of the removed declarations. It
+// returns true if the bundle is changed.
+func (s *resourceSyncer) refresh(ctx context.Context, declarations []configmap.ResourceDeclaration,
+	now time.Time,
+) bool {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	changed := false
+	declared := map[string]bool{}
+	for _, declaration := range declarations {
+		key := declarationKey(declaration)
+		declared[key] = true
+
+		interval := declaration.Interval.Duration
+		if interval <= 0 {
+			interval = configmap.DefaultResourceInterval
+		}
+		if listedAt, ok := s.listedAt[key]; ok && now.Sub(listedAt) < interval {
+			continue
+		}
+		s.listedAt[key] = now
+
+		objects, err := s.list(ctx, declaration)
+		if meta.IsNoMatchError(err) {
+			s.log.Debugw("the resource isn't served by the hub", "apiVersion", declaration.APIVersion,
+				"kind", declaration.Kind)
+			continue
+		}
+		if err != nil {
+			s.log.Warnw("failed to list the resources", "apiVersion", declaration.APIVersion,
+				"kind", declaration.Kind, "error", err)
+			continue
+		}
+		if !equalObjects(s.objects[key], objects) {
+			s.objects[key] = objects
+			changed = true
+		}
+	}
+
+	for key := range s.objects {
+		if !declared[key] {
+			delete(s.objects, key)
+			delete(s.listedAt, key)
+			changed = true
+		}
+	}
+	return changed
+}
+
+func (s *resourceSyncer) list(ctx context.Context, declaration configmap.ResourceDeclaration,
+) ([]client.Object, error) {
+	gv, err := schema.ParseGroupVersion(declaration.APIVersion)
+	if err != nil {
+		return nil, err
+	}
+	// the declaration is rejected before listing, so the sensitive resources aren't even read
+	if err := validate(gv.WithKind(declaration.Kind), declaration.Fields); err != nil {
+		return nil, err
+	}
+
+	opts := []client.ListOption{client.Limit(listPageSize)}
+	if declaration.LabelSelector != nil {
+		selector, err := metav1.LabelSelectorAsSelector(declaration.LabelSelector)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, client.MatchingLabelsSelector{Selector: selector})
+	}
+
+	objects := []client.Object{}
+	continueToken := ""
+	for {
+		list := &unstructured.UnstructuredList{}
+		list.SetGroupVersionKind(gv.WithKind(declaration.Kind + "List"))
+		if err := s.reader.List(ctx, list, append(opts, client.Continue(continueToken))...); err != nil {
+			return nil, err
+		}
+		for i := range list.Items {
+			obj, err := Project(&list.Items[i], declaration.Fields)
+			if err != nil {
+				return nil, err
+			}
+			objects = append(objects, obj)
+		}
+		continueToken = list.GetContinue()
+		if continueToken == "" {
+			break
+		}
+	}
+
+	sort.Slice(objects, func(i, j int) bool { return objects[i].GetUID() < objects[j].GetUID() })
+	return objects, nil
+}
+
+func (s *resourceSyncer) send(ctx context.Context) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if !s.emitter.ShouldSend() {
+		return
+	}
+
+	keys := make([]string, 0, len(s.objects))
+	for key := range s.objects {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	// an object might be matched by multiple declarations of the kind, only the first one is reported
+	bundle := genericpayload.GenericObjectBundle{}
+	reported := map[types.UID]bool{}
+	for _, key := range keys {
+		for _, obj := range s.objects[key] {
+			if !reported[obj.GetUID()] {
+				reported[obj.GetUID()] = true
+				bundle = append(bundle, obj)
+			}
+		}
+	}
+
+	evt, err := s.emitter.ToCloudEvent(bundle)
+	if err != nil {
+		s.log.Errorw("failed to get CloudEvent instance", "error", err)
+		return
+	}
+	evt.SetSource(s.leafHubName)
+
+	if s.emitter.Topic() != "" {
+		ctx = cecontext.WithTopic(ctx, s.emitter.Topic())
+	}
+	if err := s.producer.SendEvent(ctx, *evt); err != nil {
+		s.log.Errorw("failed to send event", "error", err)
+		return
+	}
+	s.emitter.PostSend(bundle)
+}
+
+// Project reduces the object to the identity metadata and the values of the JSONPath expressions. The whole object is
+// never reported, so the expressions are required, and the objects of the sensitive kinds are rejected. The
+// resourceVersion of the result is the hash of its content, so the object is reported again only if the projected
+// content is changed.
+func Project(obj *unstructured.Unstructured, fields []string) (*unstructured.Unstructured, error) {
+	if err := validate(obj.GroupVersionKind(), fields); err != nil {
+		return nil, err
+	}
+
+	projected := &unstructured.Unstructured{Object: map[string]interface{}{}}
+	projected.SetAPIVersion(obj.GetAPIVersion())
+	projected.SetKind(obj.GetKind())
+	projected.SetNamespace(obj.GetNamespace())
+	projected.SetName(obj.GetName())
+	projected.SetUID(obj.GetUID())
+	projected.SetLabels(obj.GetLabels())
+
+	values := map[string]interface{}{}
+	for _, field := range fields {
+		value, err := findValue(obj, field)
+		if err != nil {
+			return nil, err
+		}
+		values[field] = value
+	}
+	projected.Object[FieldsKey] = values
+
+	data, err := json.Marshal(projected.Object)
+	if err != nil {
+		return nil, err
+	}
+	hash := sha256.Sum256(data)
+	projected.SetResourceVersion(hex.EncodeToString(hash[:8]))
+	return projected, nil
+}
+
+// validate rejects the sensitive kinds and the declarations without the projected fields.
+func validate(gvk schema.GroupVersionKind, fields []string) error {
+	if utils.IsSensitiveKind(gvk.GroupKind()) {
+		return fmt.Errorf("the resources of the sensitive kind %s aren't collected", gvk.GroupKind())
+	}
+	if len(fields) == 0 {
+		return fmt.Errorf("the fields of the kind %s are required", gvk.GroupKind())
+	}
+	return nil
+}
+
+// findValue returns the value matched by the expression, a list if multiple values are matched, and nil if nothing
+// is matched.
+func findValue(obj *unstructured.Unstructured, field string) (interface{}, error) {
+	path := jsonpath.New(field).AllowMissingKeys(true)
+	if err := path.Parse(field); err != nil {
+		return nil, fmt.Errorf("invalid field %s: %w", field, err)
+	}
+	results, err := path.FindResults(obj.Object)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find the field %s: %w", field, err)
+	}
+
+	values := []interface{}{}
+	for _, result := range results {
+		for _, value := range result {
+			if value.IsValid() && value.CanInterface() {
+				values = append(values, value.Interface())
+			}
+		}
+	}
+	switch len(values) {
+	case 0:
+		return nil, nil
+	case 1:
+		return values[0], nil
+	default:
+		return values, nil
+	}
+}
+
+func declarationKey(declaration configmap.ResourceDeclaration) string {
+	key, _ := json.Marshal(declaration)
+	return string(key)
+}
+
+func equalObjects(current, objects []client.Object) bool {
+	if len(current) != len(objects) {
+		return false
+	}
+	for i := range current {
+		if current[i].GetUID() != objects[i].GetUID() ||
+			current[i].GetResourceVersion() != objects[i].GetResourceVersion() {
+			return false
+		}
+	}
+	return true
+}
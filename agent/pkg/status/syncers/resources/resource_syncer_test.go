@@ -0,0 +1,141 @@
+package resources
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/syncers/configmap"
+	"github.com/stolostron/multicluster-global-hub/pkg/logger"
+)
+
+func newNode(name, zone, heartbeat string) *corev1.Node {
+	return &corev1.Node{
+		TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "Node"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            name,
+			UID:             types.UID("uid-" + name),
+			ResourceVersion: heartbeat,
+			Labels:          map[string]string{"topology.kubernetes.io/zone": zone},
+			ManagedFields:   []metav1.ManagedFieldsEntry{{Manager: "kubelet"}},
+		},
+		Status: corev1.NodeStatus{
+			Capacity: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("4")},
+			Conditions: []corev1.NodeCondition{
+				{Type: corev1.NodeReady, Status: corev1.ConditionTrue},
+			},
+		},
+	}
+}
+
+func toUnstructured(t *testing.T, obj runtime.Object) *unstructured.Unstructured {
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	require.NoError(t, err)
+	return &unstructured.Unstructured{Object: content}
+}
+
+func TestProject(t *testing.T) {
+	fields := []string{`{.status.capacity.cpu}`, `{.status.conditions[?(@.type=="Ready")].status}`, `{.spec.taints}`}
+
+	projected, err := Project(toUnstructured(t, newNode("node1", "a", "1")), fields)
+	require.NoError(t, err)
+	assert.Equal(t, "Node", projected.GetKind())
+	assert.Equal(t, "node1", projected.GetName())
+	assert.Equal(t, "uid-node1", string(projected.GetUID()))
+	assert.Equal(t, "a", projected.GetLabels()["topology.kubernetes.io/zone"])
+	assert.Equal(t, map[string]interface{}{
+		`{.status.capacity.cpu}`:                          "4",
+		`{.status.conditions[?(@.type=="Ready")].status}`: "True",
+		`{.spec.taints}`:                                  nil,
+	}, projected.Object[FieldsKey])
+	_, found, _ := unstructured.NestedMap(projected.Object, "status")
+	assert.False(t, found)
+
+	// the heartbeat of the node doesn't change the projected content
+	heartbeat, err := Project(toUnstructured(t, newNode("node1", "a", "2")), fields)
+	require.NoError(t, err)
+	assert.Equal(t, projected.GetResourceVersion(), heartbeat.GetResourceVersion())
+
+	// the whole object is never reported
+	_, err = Project(toUnstructured(t, newNode("node1", "a", "1")), nil)
+	assert.Error(t, err)
+
+	// the sensitive kinds are rejected even if the fields are projected
+	secret := &corev1.Secret{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Secret"},
+		ObjectMeta: metav1.ObjectMeta{Name: "secret1", Namespace: "default"},
+		Data:       map[string][]byte{"token": []byte("token")},
+	}
+	_, err = Project(toUnstructured(t, secret), []string{"{.data.token}"})
+	assert.Error(t, err)
+
+	_, err = Project(toUnstructured(t, newNode("node1", "a", "1")), []string{"{.status"})
+	assert.Error(t, err)
+}
+
+func TestResourceSyncerRefresh(t *testing.T) {
+	ctx := context.Background()
+	fakeClient := fake.NewClientBuilder().WithObjects(
+		newNode("node1", "a", "1"), newNode("node2", "b", "1"),
+	).Build()
+
+	syncer := &resourceSyncer{
+		log:      logger.ZapLogger("status.resource"),
+		reader:   fakeClient,
+		objects:  map[string][]client.Object{},
+		listedAt: map[string]time.Time{},
+	}
+	declarations := []configmap.ResourceDeclaration{{
+		APIVersion: "v1",
+		Kind:       "Node",
+		LabelSelector: &metav1.LabelSelector{
+			MatchLabels: map[string]string{"topology.kubernetes.io/zone": "a"},
+		},
+		Fields:   []string{`{.status.capacity.cpu}`},
+		Interval: metav1.Duration{Duration: time.Minute},
+	}}
+	key := declarationKey(declarations[0])
+
+	now := time.Now()
+	assert.True(t, syncer.refresh(ctx, declarations, now))
+	require.Len(t, syncer.objects[key], 1)
+	assert.Equal(t, "node1", syncer.objects[key][0].GetName())
+
+	// not listed again before the interval elapses
+	node := &corev1.Node{}
+	require.NoError(t, fakeClient.Get(ctx, client.ObjectKey{Name: "node2"}, node))
+	node.Labels["topology.kubernetes.io/zone"] = "a"
+	require.NoError(t, fakeClient.Update(ctx, node))
+	assert.False(t, syncer.refresh(ctx, declarations, now.Add(30*time.Second)))
+	assert.Len(t, syncer.objects[key], 1)
+
+	assert.True(t, syncer.refresh(ctx, declarations, now.Add(time.Minute)))
+	assert.Len(t, syncer.objects[key], 2)
+
+	// nothing is changed
+	assert.False(t, syncer.refresh(ctx, declarations, now.Add(2*time.Minute)))
+
+	// the secrets aren't listed
+	secretDeclarations := []configmap.ResourceDeclaration{{
+		APIVersion: "v1",
+		Kind:       "Secret",
+		Fields:     []string{`{.data}`},
+	}}
+	assert.False(t, syncer.refresh(ctx, append(declarations, secretDeclarations...), now.Add(2*time.Minute)))
+	assert.Empty(t, syncer.objects[declarationKey(secretDeclarations[0])])
+
+	// the objects are dropped once the declaration is removed
+	assert.True(t, syncer.refresh(ctx, nil, now.Add(3*time.Minute)))
+	assert.Empty(t, syncer.objects)
+}
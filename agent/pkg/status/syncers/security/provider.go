@@ -0,0 +1,91 @@
+package security
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	cecontext "github.com/cloudevents/sdk-go/v2/context"
+	"go.uber.org/zap"
+
+	"github.com/stolostron/multicluster-global-hub/agent/pkg/configs"
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/version"
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
+	"github.com/stolostron/multicluster-global-hub/pkg/transport"
+)
+
+// Finding is a kind of security findings collected by a provider, e.g. the alert counts or the vulnerabilities of the
+// deployments. Each kind is sent with its own event type, and saved into its own table by the manager.
+type Finding struct {
+	EventType enum.EventType
+	Data      any
+}
+
+// FindingsProvider collects the security findings from an instance of a security product running in the hub, e.g. a
+// StackRox central. Other products can be supported by implementing this interface, and sending the findings with
+// the FindingsProducer.
+type FindingsProvider interface {
+	// Source identifies the instance of the product, e.g. the "<namespace>/<name>" of the StackRox central.
+	Source() string
+
+	// Collect returns the latest findings of the instance. The findings which are collected successfully are
+	// returned together with the error of the others.
+	Collect(ctx context.Context) ([]Finding, error)
+}
+
+// FindingsProducer sends the findings to the status topic, the findings of each event type have their own version.
+type FindingsProducer struct {
+	logger   *zap.SugaredLogger
+	topic    string
+	producer transport.Producer
+	lock     sync.Mutex
+	versions map[enum.EventType]*version.Version
+}
+
+func NewFindingsProducer(logger *zap.SugaredLogger, topic string, producer transport.Producer) *FindingsProducer {
+	return &FindingsProducer{
+		logger:   logger,
+		topic:    topic,
+		producer: producer,
+		versions: map[enum.EventType]*version.Version{},
+	}
+}
+
+// Produce sends the findings of the provider, it stops at the first finding which fails to be sent.
+func (p *FindingsProducer) Produce(ctx context.Context, provider FindingsProvider) error {
+	findings, collectErr := provider.Collect(ctx)
+	for _, finding := range findings {
+		if err := p.send(ctx, finding); err != nil {
+			return fmt.Errorf("failed to produce a message to kafka: %v", err)
+		}
+	}
+	return collectErr
+}
+
+func (p *FindingsProducer) send(ctx context.Context, finding Finding) error {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	currentVersion, ok := p.versions[finding.EventType]
+	if !ok {
+		currentVersion = version.NewVersion()
+		p.versions[finding.EventType] = currentVersion
+	}
+	currentVersion.Incr()
+
+	evt := ToEvent(configs.GetLeafHubName(), string(finding.EventType), currentVersion.String())
+	err := evt.SetData(cloudevents.ApplicationJSON, finding.Data)
+	if err != nil {
+		return fmt.Errorf("failed to get CloudEvent instance from event %s: %v", *evt, err)
+	}
+
+	p.logger.Info("pushing message to kafka", "topic", p.topic, "type", finding.EventType,
+		"message", string(evt.Data()))
+	if err = p.producer.SendEvent(cecontext.WithTopic(ctx, p.topic), *evt); err != nil {
+		return fmt.Errorf("failed to send event %s: %v", *evt, err)
+	}
+
+	currentVersion.Next()
+	return nil
+}
@@ -1,13 +1,35 @@
 package security
 
+import (
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
+)
+
 type stackRoxRequest struct {
-	Method            string
-	Path              string
-	Body              string
+	Method string
+	Path   string
+	Body   string
+	// EventType is the type of the message generated from the response, each request is sent as its own message
+	EventType enum.EventType
+	// CacheStruct is a pointer to the type of the response, a new instance of the type is decoded on each poll
 	CacheStruct       any
 	GenerateFromCache func(...any) (any, error)
+	// PagePath returns the path of the page at the given offset, it's only set for the paged list requests. They're
+	// requested page by page until a page shorter than the stackRoxListLimit, and the CacheStruct of them must be a
+	// stackRoxPage.
+	PagePath func(offset int) string
+}
+
+// stackRoxPage is the response of the paged list requests.
+type stackRoxPage interface {
+	// Len returns the number of the items in the page.
+	Len() int
+	// Append appends the items of the next page.
+	Append(next stackRoxPage)
 }
 
 var stackRoxRequests = []stackRoxRequest{
 	AlertsSummeryCountsRequest,
+	ClusterViolationsRequest,
+	DeploymentViolationsRequest,
+	VulnerabilitiesRequest,
 }
@@ -7,12 +7,12 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"reflect"
 	"strings"
 	"sync"
 	"time"
 
 	cloudevents "github.com/cloudevents/sdk-go/v2"
-	cecontext "github.com/cloudevents/sdk-go/v2/context"
 	routev1 "github.com/openshift/api/route/v1"
 	"go.uber.org/zap"
 	corev1 "k8s.io/api/core/v1"
@@ -21,11 +21,8 @@ import (
 	crclient "sigs.k8s.io/controller-runtime/pkg/client"
 	crmanager "sigs.k8s.io/controller-runtime/pkg/manager"
 
-	"github.com/stolostron/multicluster-global-hub/agent/pkg/configs"
 	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/syncers/security/clients"
-	"github.com/stolostron/multicluster-global-hub/pkg/bundle/version"
 	eventversion "github.com/stolostron/multicluster-global-hub/pkg/bundle/version"
-	"github.com/stolostron/multicluster-global-hub/pkg/enum"
 	zaplogger "github.com/stolostron/multicluster-global-hub/pkg/logger"
 	"github.com/stolostron/multicluster-global-hub/pkg/transport"
 )
@@ -50,16 +47,15 @@ type StackRoxSyncerBuilder struct {
 // StackRoxSyncer knows how to pull multiple StackRox API servers to extract information and send it to Kafka. Don't
 // create instances of this type directly, use the NewStackRoxSyncer function instead.
 type StackRoxSyncer struct {
-	logger         *zap.SugaredLogger
-	topic          string
-	producer       transport.Producer
-	kubeClient     crclient.Client
-	pollInterval   time.Duration
-	dataLock       *sync.Mutex
-	dataMap        map[types.NamespacedName]*stackRoxData
-	requests       []stackRoxRequest
-	currentVersion *version.Version
-	lastSentData   any
+	logger       *zap.SugaredLogger
+	topic        string
+	producer     transport.Producer
+	kubeClient   crclient.Client
+	pollInterval time.Duration
+	dataLock     *sync.Mutex
+	dataMap      map[types.NamespacedName]*stackRoxData
+	requests     []stackRoxRequest
+	findings     *FindingsProducer
 }
 
 // stackRoxConnDetails contains the details of the StackRox central.
@@ -145,15 +141,15 @@ func (b *StackRoxSyncerBuilder) Build() (result *StackRoxSyncer, err error) {
 
 	// Create and populate the object:
 	result = &StackRoxSyncer{
-		logger:         b.logger,
-		topic:          b.topic,
-		producer:       b.producer,
-		kubeClient:     b.kubeClient,
-		pollInterval:   b.pollInterval,
-		dataLock:       &sync.Mutex{},
-		dataMap:        map[types.NamespacedName]*stackRoxData{},
-		requests:       stackRoxRequests,
-		currentVersion: version.NewVersion(),
+		logger:       b.logger,
+		topic:        b.topic,
+		producer:     b.producer,
+		kubeClient:   b.kubeClient,
+		pollInterval: b.pollInterval,
+		dataLock:     &sync.Mutex{},
+		dataMap:      map[types.NamespacedName]*stackRoxData{},
+		requests:     stackRoxRequests,
+		findings:     NewFindingsProducer(b.logger, b.topic, b.producer),
 	}
 	return
 }
@@ -416,58 +412,74 @@ func (s *StackRoxSyncer) fetchConsoleURL(ctx context.Context, centralKey types.N
 }
 
 func (s *StackRoxSyncer) sync(ctx context.Context, data *stackRoxData) error {
-	for _, request := range s.requests {
-		if err := s.poll(ctx, data, request); err != nil {
-			return fmt.Errorf("failed to make a request to central: %v", err)
-		}
+	return s.findings.Produce(ctx, &stackRoxProvider{syncer: s, data: data})
+}
 
-		messageStruct, err := request.GenerateFromCache(
-			request.CacheStruct, data.consoleURL, data.key.Namespace, data.key.Name)
+// stackRoxProvider collects the findings of a StackRox central, each request of the syncer is a kind of findings.
+type stackRoxProvider struct {
+	syncer *StackRoxSyncer
+	data   *stackRoxData
+}
+
+func (p *stackRoxProvider) Source() string {
+	return p.data.key.String()
+}
+
+func (p *stackRoxProvider) Collect(ctx context.Context) ([]Finding, error) {
+	findings := []Finding{}
+	for _, request := range p.syncer.requests {
+		response, err := p.syncer.poll(ctx, p.data, request)
 		if err != nil {
-			return fmt.Errorf("failed to generate struct for kafka message: %v", err)
+			return findings, fmt.Errorf("failed to make a request to central: %v", err)
 		}
 
-		// If the payload not updated since the previous one, then don't need sync it again
-		// if equality.Semantic.DeepEqual(messageStruct, s.lastSentData) {
-		// 	return nil
-		// }
-
-		s.currentVersion.Incr()
-		if err := s.produce(ctx, messageStruct); err != nil {
-			return fmt.Errorf("failed to produce a message to kafka: %v", err)
+		messageStruct, err := request.GenerateFromCache(
+			response, p.data.consoleURL, p.data.key.Namespace, p.data.key.Name)
+		if err != nil {
+			return findings, fmt.Errorf("failed to generate struct for kafka message: %v", err)
 		}
-		s.lastSentData = messageStruct
+		findings = append(findings, Finding{EventType: request.EventType, Data: messageStruct})
 	}
-
-	return nil
+	return findings, nil
 }
 
-func (s *StackRoxSyncer) produce(ctx context.Context, messageStruct any) error {
-	s.dataLock.Lock()
-	defer s.dataLock.Unlock()
-
-	evt := ToEvent(configs.GetLeafHubName(), string(enum.SecurityAlertCountsType), s.currentVersion.String())
-	err := evt.SetData(cloudevents.ApplicationJSON, messageStruct)
-	if err != nil {
-		return fmt.Errorf("failed to get CloudEvent instance from event %s: %v", *evt, err)
+// poll sends the request to the central and returns the response decoded into a new instance of the CacheStruct. The
+// paged list requests are sent page by page until a page shorter than the limit, and the pages are appended into the
+// first one.
+func (s *StackRoxSyncer) poll(ctx context.Context, data *stackRoxData, request stackRoxRequest) (any, error) {
+	if request.PagePath == nil {
+		return s.get(ctx, data, request, request.Path)
 	}
 
-	s.logger.Info("pushing message to kafka", "topic", s.topic, "message", string(evt.Data()))
-	cloudEventsContext := cecontext.WithTopic(ctx, s.topic)
-
-	if err = s.producer.SendEvent(cloudEventsContext, *evt); err != nil {
-		return fmt.Errorf("failed to send event %s: %v", *evt, err)
+	var result stackRoxPage
+	for offset := 0; ; offset += stackRoxListLimit {
+		cache, err := s.get(ctx, data, request, request.PagePath(offset))
+		if err != nil {
+			return nil, err
+		}
+		page, ok := cache.(stackRoxPage)
+		if !ok {
+			return nil, fmt.Errorf("response of the paged request (method: %s, path: %s) isn't a page",
+				request.Method, request.Path)
+		}
+		if result == nil {
+			result = page
+		} else {
+			result.Append(page)
+		}
+		if page.Len() < stackRoxListLimit {
+			return result, nil
+		}
 	}
-
-	s.currentVersion.Next()
-
-	return nil
 }
 
-func (s *StackRoxSyncer) poll(ctx context.Context, data *stackRoxData, request stackRoxRequest) error {
-	response, status, err := data.apiClient.DoRequest(request.Method, request.Path, request.Body)
+// get sends the request with the given path to the central and decodes the response into a new instance of the
+// CacheStruct.
+func (s *StackRoxSyncer) get(ctx context.Context, data *stackRoxData, request stackRoxRequest, path string,
+) (any, error) {
+	response, status, err := data.apiClient.DoRequest(request.Method, path, request.Body)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	// If the request fails with an error related to authentication, then we refresh the data and try again, only
@@ -475,26 +487,29 @@ func (s *StackRoxSyncer) poll(ctx context.Context, data *stackRoxData, request s
 	if status != nil && *status == http.StatusUnauthorized || *status == http.StatusForbidden {
 		err = s.refresh(ctx, data)
 		if err != nil {
-			return err
+			return nil, err
 		}
-		response, status, err = data.apiClient.DoRequest(request.Method, request.Path, request.Body)
+		response, status, err = data.apiClient.DoRequest(request.Method, path, request.Body)
 		if err != nil {
-			return err
+			return nil, err
 		}
 		if status != nil && *status != http.StatusOK {
-			return fmt.Errorf("request failed with status code %d", *status)
+			return nil, fmt.Errorf("request failed with status code %d", *status)
 		}
 	}
 
-	err = json.Unmarshal(response, request.CacheStruct)
+	// Decode into a new instance, so that the fields which aren't in the response aren't left from the previous
+	// response, and the centrals synchronized concurrently don't share the same instance:
+	cache := reflect.New(reflect.TypeOf(request.CacheStruct).Elem()).Interface()
+	err = json.Unmarshal(response, cache)
 	if err != nil {
-		return fmt.Errorf(
+		return nil, fmt.Errorf(
 			"failed to unmarshal response (method: %s, path: %s, body: %s): %v",
-			request.Method, request.Path, request.Body, err,
+			request.Method, path, request.Body, err,
 		)
 	}
 
-	return nil
+	return cache, nil
 }
 
 func (s *StackRoxSyncer) Start(ctx context.Context) error {
@@ -6,6 +6,7 @@ import (
 	"encoding/pem"
 	"errors"
 	"net/http"
+	"strings"
 	"sync/atomic"
 	"time"
 
@@ -22,6 +23,7 @@ import (
 	crclient "sigs.k8s.io/controller-runtime/pkg/client"
 	crfakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
 
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
 	"github.com/stolostron/multicluster-global-hub/pkg/transport"
 )
 
@@ -323,6 +325,9 @@ var _ = Describe("StackRox syncer", func() {
 					Build()
 				Expect(err).ToNot(HaveOccurred())
 
+				// Only poll the alert counts, the other findings are verified separately:
+				syncer.requests = []stackRoxRequest{AlertsSummeryCountsRequest}
+
 				// Try to synchronize:
 				centralKey := types.NamespacedName{
 					Namespace: "rhacs-operator",
@@ -334,6 +339,234 @@ var _ = Describe("StackRox syncer", func() {
 				Expect(err).ToNot(HaveOccurred())
 			})
 
+			It("Synchronizes all the findings", func() {
+				var err error
+
+				// Prepare the server:
+				server.AppendHandlers(
+					CombineHandlers(
+						VerifyRequest(http.MethodGet, "/v1/alerts/summary/counts"),
+						RespondOK,
+					),
+					CombineHandlers(
+						VerifyRequest(http.MethodGet, "/v1/alerts/summary/counts", "group_by=CLUSTER"),
+						RespondWith(http.StatusOK, `{
+							"groups": [
+								{
+									"group": "cluster2",
+									"counts": [{"severity": "CRITICAL_SEVERITY", "count": "3"}]
+								},
+								{
+									"group": "cluster1",
+									"counts": [
+										{"severity": "LOW_SEVERITY", "count": "1"},
+										{"severity": "CRITICAL_SEVERITY", "count": "1"}
+									]
+								}
+							]
+						}`),
+					),
+					CombineHandlers(
+						VerifyRequest(http.MethodGet, "/v1/alerts",
+							"pagination.limit=1000&pagination.offset=0&query=Violation+State%3AACTIVE"),
+						RespondWith(http.StatusOK, `{
+							"alerts": [
+								{
+									"policy": {"name": "Privileged Container", "severity": "CRITICAL_SEVERITY"},
+									"deployment": {"name": "app", "namespace": "default", "clusterName": "cluster2"}
+								},
+								{
+									"policy": {"name": "Latest Tag", "severity": "LOW_SEVERITY"},
+									"deployment": {"name": "app", "namespace": "default", "clusterName": "cluster2"}
+								},
+								{
+									"policy": {"name": "Privileged Container", "severity": "CRITICAL_SEVERITY"},
+									"deployment": {"name": "app", "namespace": "default", "clusterName": "cluster2"}
+								},
+								{
+									"policy": {"name": "Kubernetes Actions: Exec into Pod", "severity": "HIGH_SEVERITY"}
+								}
+							]
+						}`),
+					),
+					CombineHandlers(
+						VerifyRequest(http.MethodPost, "/api/graphql", "opname=getVulnerabilities"),
+						VerifyContentType("application/json"),
+						RespondWith(http.StatusOK, `{
+							"data": {
+								"deployments": [{
+									"name": "app",
+									"namespace": "default",
+									"clusterName": "cluster2",
+									"imageCVECountBySeverity": {
+										"low": {"total": 5, "fixable": 1},
+										"moderate": {"total": 4, "fixable": 0},
+										"important": {"total": 3, "fixable": 2},
+										"critical": {"total": 1, "fixable": 1}
+									}
+								}],
+								"images": [{
+									"name": {"fullName": "quay.io/org/app:latest"},
+									"imageCVECountBySeverity": {
+										"low": {"total": 5, "fixable": 1},
+										"moderate": {"total": 4, "fixable": 0},
+										"important": {"total": 3, "fixable": 2},
+										"critical": {"total": 1, "fixable": 1}
+									}
+								}]
+							}
+						}`),
+					),
+				)
+
+				// Create the producer that saves the messages by type:
+				messages := map[string][]byte{}
+				producer := &transport.ProducerMock{
+					SendEventFunc: func(ctx context.Context, evt cloudevents.Event) error {
+						messages[evt.Type()] = evt.Data()
+						return nil
+					},
+					ReconnectFunc: func(config *transport.TransportInternalConfig) error {
+						return nil
+					},
+				}
+
+				// Create the syncer:
+				syncer, err := NewStackRoxSyncer().
+					SetLogger(logger).
+					SetTopic("my-topic").
+					SetProducer(producer).
+					SetKubernetesClient(client).
+					Build()
+				Expect(err).ToNot(HaveOccurred())
+
+				// Synchronize:
+				centralKey := types.NamespacedName{
+					Namespace: "rhacs-operator",
+					Name:      "stackrox-central-services",
+				}
+				err = syncer.Register(ctx, centralKey)
+				Expect(err).ToNot(HaveOccurred())
+				err = syncer.Sync(ctx, centralKey)
+				Expect(err).ToNot(HaveOccurred())
+
+				// Verify the messages:
+				Expect(messages).To(HaveLen(4))
+				Expect(messages[string(enum.SecurityClusterViolationsType)]).To(MatchJSON(`{
+					"clusters": [
+						{"cluster": "cluster1", "low": 1, "critical": 1},
+						{"cluster": "cluster2", "critical": 3}
+					],
+					"detail_url": "https://my-console.com/main/violations",
+					"source": "rhacs-operator/stackrox-central-services"
+				}`))
+				Expect(messages[string(enum.SecurityDeploymentViolationsType)]).To(MatchJSON(`{
+					"deployments": [{
+						"cluster": "cluster2",
+						"namespace": "default",
+						"name": "app",
+						"low": 1,
+						"critical": 2,
+						"policies": ["Latest Tag", "Privileged Container"]
+					}],
+					"detail_url": "https://my-console.com/main/violations",
+					"source": "rhacs-operator/stackrox-central-services"
+				}`))
+				Expect(messages[string(enum.SecurityVulnerabilitiesType)]).To(MatchJSON(`{
+					"deployments": [{
+						"cluster": "cluster2",
+						"namespace": "default",
+						"name": "app",
+						"low": 5,
+						"moderate": 4,
+						"important": 3,
+						"critical": 1,
+						"fixable": 4
+					}],
+					"images": [{
+						"name": "quay.io/org/app:latest",
+						"low": 5,
+						"moderate": 4,
+						"important": 3,
+						"critical": 1,
+						"fixable": 4
+					}],
+					"detail_url": "https://my-console.com/main/vulnerabilities/workload-cves",
+					"source": "rhacs-operator/stackrox-central-services"
+				}`))
+			})
+
+			It("Pages the active alerts until a short page", func() {
+				var err error
+
+				// Prepare the server so that the first page is full and the second one isn't:
+				alert := `{
+					"policy": {"name": "Latest Tag", "severity": "LOW_SEVERITY"},
+					"deployment": {"name": "app", "namespace": "default", "clusterName": "cluster1"}
+				}`
+				fullPage := `{"alerts": [` + strings.TrimSuffix(strings.Repeat(alert+",", stackRoxListLimit), ",") + `]}`
+				server.AppendHandlers(
+					CombineHandlers(
+						VerifyRequest(http.MethodGet, "/v1/alerts",
+							"pagination.limit=1000&pagination.offset=0&query=Violation+State%3AACTIVE"),
+						RespondWith(http.StatusOK, fullPage),
+					),
+					CombineHandlers(
+						VerifyRequest(http.MethodGet, "/v1/alerts",
+							"pagination.limit=1000&pagination.offset=1000&query=Violation+State%3AACTIVE"),
+						RespondWith(http.StatusOK, `{"alerts": [`+alert+`]}`),
+					),
+				)
+
+				// Create the producer that saves the messages by type:
+				messages := map[string][]byte{}
+				producer := &transport.ProducerMock{
+					SendEventFunc: func(ctx context.Context, evt cloudevents.Event) error {
+						messages[evt.Type()] = evt.Data()
+						return nil
+					},
+					ReconnectFunc: func(config *transport.TransportInternalConfig) error {
+						return nil
+					},
+				}
+
+				// Create the syncer:
+				syncer, err := NewStackRoxSyncer().
+					SetLogger(logger).
+					SetTopic("my-topic").
+					SetProducer(producer).
+					SetKubernetesClient(client).
+					Build()
+				Expect(err).ToNot(HaveOccurred())
+
+				// Only poll the deployment violations:
+				syncer.requests = []stackRoxRequest{DeploymentViolationsRequest}
+
+				// Synchronize:
+				centralKey := types.NamespacedName{
+					Namespace: "rhacs-operator",
+					Name:      "stackrox-central-services",
+				}
+				err = syncer.Register(ctx, centralKey)
+				Expect(err).ToNot(HaveOccurred())
+				err = syncer.Sync(ctx, centralKey)
+				Expect(err).ToNot(HaveOccurred())
+
+				// Verify that the alerts of both pages are counted:
+				Expect(server.ReceivedRequests()).To(HaveLen(2))
+				Expect(messages[string(enum.SecurityDeploymentViolationsType)]).To(MatchJSON(`{
+					"deployments": [{
+						"cluster": "cluster1",
+						"namespace": "default",
+						"name": "app",
+						"low": 1001,
+						"policies": ["Latest Tag"]
+					}],
+					"detail_url": "https://my-console.com/main/violations",
+					"source": "rhacs-operator/stackrox-central-services"
+				}`))
+			})
+
 			It("Polls in a loop", func() {
 				var err error
 
@@ -372,6 +605,9 @@ var _ = Describe("StackRox syncer", func() {
 					Build()
 				Expect(err).ToNot(HaveOccurred())
 
+				// Only poll the alert counts, the other findings are verified separately:
+				syncer.requests = []stackRoxRequest{AlertsSummeryCountsRequest}
+
 				// Start the syncer:
 				ctx, cancel := context.WithCancel(ctx)
 				defer cancel()
@@ -437,6 +673,9 @@ var _ = Describe("StackRox syncer", func() {
 					Build()
 				Expect(err).ToNot(HaveOccurred())
 
+				// Only poll the alert counts, the other findings are verified separately:
+				syncer.requests = []stackRoxRequest{AlertsSummeryCountsRequest}
+
 				// Start the syncer:
 				ctx, cancel := context.WithCancel(ctx)
 				defer cancel()
@@ -643,6 +882,9 @@ var _ = Describe("StackRox syncer", func() {
 					Build()
 				Expect(err).ToNot(HaveOccurred())
 
+				// Only poll the alert counts, the other findings are verified separately:
+				syncer.requests = []stackRoxRequest{AlertsSummeryCountsRequest}
+
 				// Try to synchronize:
 				centralKey := types.NamespacedName{
 					Namespace: "rhacs-operator",
@@ -1045,6 +1287,9 @@ var _ = Describe("StackRox syncer", func() {
 					Build()
 				Expect(err).ToNot(HaveOccurred())
 
+				// Only poll the alert counts, the other findings are verified separately:
+				syncer.requests = []stackRoxRequest{AlertsSummeryCountsRequest}
+
 				// Try to synchronize:
 				centralKey := types.NamespacedName{
 					Namespace: "rhacs-operator",
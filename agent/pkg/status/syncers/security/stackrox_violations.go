@@ -0,0 +1,184 @@
+package security
+
+import (
+	"fmt"
+	"net/url"
+	"slices"
+	"sort"
+	"strconv"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
+	wiremodels "github.com/stolostron/multicluster-global-hub/pkg/wire/models"
+)
+
+const (
+	stackRoxAlertsPath = "/v1/alerts"
+	// stackRoxListLimit is the maximum number of the items returned by the list requests
+	stackRoxListLimit = 1000
+)
+
+// ListAlertsResponse is the response of the active alerts, only the fields used by the global hub are decoded.
+type ListAlertsResponse struct {
+	Alerts []ListAlert `json:"alerts"`
+}
+
+func (r *ListAlertsResponse) Len() int {
+	return len(r.Alerts)
+}
+
+func (r *ListAlertsResponse) Append(next stackRoxPage) {
+	r.Alerts = append(r.Alerts, next.(*ListAlertsResponse).Alerts...)
+}
+
+type ListAlert struct {
+	Policy     ListAlertPolicy      `json:"policy"`
+	Deployment *ListAlertDeployment `json:"deployment,omitempty"`
+}
+
+type ListAlertPolicy struct {
+	Name     string `json:"name"`
+	Severity string `json:"severity"`
+}
+
+type ListAlertDeployment struct {
+	Name        string `json:"name"`
+	Namespace   string `json:"namespace"`
+	ClusterName string `json:"clusterName"`
+}
+
+// ClusterViolationsRequest returns the alert counts grouped by the clusters secured by the central.
+var ClusterViolationsRequest = stackRoxRequest{
+	Method:      "GET",
+	Path:        stackRoxAlertsSummaryCountsPath + "?group_by=CLUSTER",
+	Body:        "",
+	EventType:   enum.SecurityClusterViolationsType,
+	CacheStruct: &AlertsSummeryCountsResponse{},
+	GenerateFromCache: func(values ...any) (any, error) {
+		response, detailURL, source, err := parseCacheValues[AlertsSummeryCountsResponse](values)
+		if err != nil {
+			return nil, err
+		}
+
+		violations := wiremodels.SecurityClusterViolations{
+			Clusters:  []wiremodels.SecurityClusterViolation{},
+			DetailURL: detailURL + stackRoxAlertsDetailsPath,
+			Source:    source,
+		}
+		for _, group := range response.Groups {
+			cluster := wiremodels.SecurityClusterViolation{Cluster: group.Group}
+			for _, count := range group.Counts {
+				countInt, err := strconv.Atoi(count.Count)
+				if err != nil {
+					return nil, fmt.Errorf("failed to convert %s to integer: %v", count.Count, err)
+				}
+				addSeverityCount(&cluster.SecuritySeverityCounts, count.Severity, countInt)
+			}
+			violations.Clusters = append(violations.Clusters, cluster)
+		}
+		sort.Slice(violations.Clusters, func(i, j int) bool {
+			return violations.Clusters[i].Cluster < violations.Clusters[j].Cluster
+		})
+		return violations, nil
+	},
+}
+
+// DeploymentViolationsRequest returns the active alerts of the deployments, they're aggregated into the violation
+// counts and the violated policies of each deployment. The alerts of the other resources are ignored.
+var DeploymentViolationsRequest = stackRoxRequest{
+	Method:      "GET",
+	Path:        activeAlertsPagePath(0),
+	Body:        "",
+	EventType:   enum.SecurityDeploymentViolationsType,
+	CacheStruct: &ListAlertsResponse{},
+	PagePath:    activeAlertsPagePath,
+	GenerateFromCache: func(values ...any) (any, error) {
+		response, detailURL, source, err := parseCacheValues[ListAlertsResponse](values)
+		if err != nil {
+			return nil, err
+		}
+
+		deployments := map[string]*wiremodels.SecurityDeploymentViolation{}
+		for _, alert := range response.Alerts {
+			if alert.Deployment == nil {
+				continue
+			}
+			key := fmt.Sprintf("%s/%s/%s", alert.Deployment.ClusterName, alert.Deployment.Namespace,
+				alert.Deployment.Name)
+			deployment, ok := deployments[key]
+			if !ok {
+				deployment = &wiremodels.SecurityDeploymentViolation{
+					Cluster:   alert.Deployment.ClusterName,
+					Namespace: alert.Deployment.Namespace,
+					Name:      alert.Deployment.Name,
+				}
+				deployments[key] = deployment
+			}
+			addSeverityCount(&deployment.SecuritySeverityCounts, alert.Policy.Severity, 1)
+			if !slices.Contains(deployment.Policies, alert.Policy.Name) {
+				deployment.Policies = append(deployment.Policies, alert.Policy.Name)
+			}
+		}
+
+		violations := wiremodels.SecurityDeploymentViolations{
+			Deployments: []wiremodels.SecurityDeploymentViolation{},
+			DetailURL:   detailURL + stackRoxAlertsDetailsPath,
+			Source:      source,
+		}
+		keys := make([]string, 0, len(deployments))
+		for key := range deployments {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			sort.Strings(deployments[key].Policies)
+			violations.Deployments = append(violations.Deployments, *deployments[key])
+		}
+		return violations, nil
+	},
+}
+
+func activeAlertsPagePath(offset int) string {
+	return stackRoxAlertsPath + "?" + url.Values{
+		"query":             []string{"Violation State:ACTIVE"},
+		"pagination.limit":  []string{strconv.Itoa(stackRoxListLimit)},
+		"pagination.offset": []string{strconv.Itoa(offset)},
+	}.Encode()
+}
+
+func addSeverityCount(counts *wiremodels.SecuritySeverityCounts, severity string, count int) {
+	switch severity {
+	case StackRoxResponseLowSeverity:
+		counts.Low += count
+	case StackRoxResponseMediumSeverity:
+		counts.Medium += count
+	case StackRoxResponseHighSeverity:
+		counts.High += count
+	case StackRoxResponseCriticalSeverity:
+		counts.Critical += count
+	}
+}
+
+// parseCacheValues returns the response, the console URL and the "<namespace>/<name>" of the central from the values
+// passed to the GenerateFromCache.
+func parseCacheValues[T any](values []any) (*T, string, string, error) {
+	if len(values) != 4 {
+		return nil, "", "", fmt.Errorf("cache struct or ACS base URL were not provided")
+	}
+	response, ok := values[0].(*T)
+	if !ok {
+		return nil, "", "", fmt.Errorf("cache struct is not of the right type")
+	}
+	consoleURL, ok := values[1].(string)
+	if !ok {
+		return nil, "", "", fmt.Errorf("ACS external URL is not valid")
+	}
+	namespace, ok := values[2].(string)
+	if !ok {
+		return nil, "", "", fmt.Errorf("ACS Central namespace was not provided")
+	}
+	name, ok := values[3].(string)
+	if !ok {
+		return nil, "", "", fmt.Errorf("ACS Central name was not provided")
+	}
+	return response, consoleURL, fmt.Sprintf("%s/%s", namespace, name), nil
+}
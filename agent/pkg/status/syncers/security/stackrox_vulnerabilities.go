@@ -0,0 +1,139 @@
+package security
+
+import (
+	"encoding/json"
+	"sort"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
+	wiremodels "github.com/stolostron/multicluster-global-hub/pkg/wire/models"
+)
+
+const (
+	stackRoxGraphQLPath             = "/api/graphql"
+	stackRoxVulnerabilityDetailPath = "/main/vulnerabilities/workload-cves"
+)
+
+// stackRoxVulnerabilitiesQuery returns the image CVE counts by severity of the deployments and the images
+const stackRoxVulnerabilitiesQuery = `query getVulnerabilities($pagination: Pagination) {
+	deployments(pagination: $pagination) {
+		name
+		namespace
+		clusterName
+		imageCVECountBySeverity { ...cveCounts }
+	}
+	images(pagination: $pagination) {
+		name { fullName }
+		imageCVECountBySeverity { ...cveCounts }
+	}
+}
+
+fragment cveCounts on ResourceCountByCVESeverity {
+	low { total fixable }
+	moderate { total fixable }
+	important { total fixable }
+	critical { total fixable }
+}`
+
+// VulnerabilitiesResponse is the GraphQL response of the stackRoxVulnerabilitiesQuery.
+type VulnerabilitiesResponse struct {
+	Data struct {
+		Deployments []struct {
+			Name                    string            `json:"name"`
+			Namespace               string            `json:"namespace"`
+			ClusterName             string            `json:"clusterName"`
+			ImageCVECountBySeverity CVESeverityCounts `json:"imageCVECountBySeverity"`
+		} `json:"deployments"`
+		Images []struct {
+			Name struct {
+				FullName string `json:"fullName"`
+			} `json:"name"`
+			ImageCVECountBySeverity CVESeverityCounts `json:"imageCVECountBySeverity"`
+		} `json:"images"`
+	} `json:"data"`
+}
+
+type CVESeverityCounts struct {
+	Low       CVECount `json:"low"`
+	Moderate  CVECount `json:"moderate"`
+	Important CVECount `json:"important"`
+	Critical  CVECount `json:"critical"`
+}
+
+type CVECount struct {
+	Total   int `json:"total"`
+	Fixable int `json:"fixable"`
+}
+
+func (c CVESeverityCounts) toWire() wiremodels.SecurityVulnerabilityCounts {
+	return wiremodels.SecurityVulnerabilityCounts{
+		Low:       c.Low.Total,
+		Moderate:  c.Moderate.Total,
+		Important: c.Important.Total,
+		Critical:  c.Critical.Total,
+		Fixable:   c.Low.Fixable + c.Moderate.Fixable + c.Important.Fixable + c.Critical.Fixable,
+	}
+}
+
+// VulnerabilitiesRequest returns the image CVE counts of the deployments and the images scanned by the central.
+var VulnerabilitiesRequest = stackRoxRequest{
+	Method:      "POST",
+	Path:        stackRoxGraphQLPath + "?opname=getVulnerabilities",
+	Body:        graphQLBody("getVulnerabilities", stackRoxVulnerabilitiesQuery),
+	EventType:   enum.SecurityVulnerabilitiesType,
+	CacheStruct: &VulnerabilitiesResponse{},
+	GenerateFromCache: func(values ...any) (any, error) {
+		response, consoleURL, source, err := parseCacheValues[VulnerabilitiesResponse](values)
+		if err != nil {
+			return nil, err
+		}
+
+		vulnerabilities := wiremodels.SecurityVulnerabilities{
+			Deployments: []wiremodels.SecurityDeploymentVulnerability{},
+			Images:      []wiremodels.SecurityImageVulnerability{},
+			DetailURL:   consoleURL + stackRoxVulnerabilityDetailPath,
+			Source:      source,
+		}
+		for _, deployment := range response.Data.Deployments {
+			vulnerabilities.Deployments = append(vulnerabilities.Deployments,
+				wiremodels.SecurityDeploymentVulnerability{
+					Cluster:                     deployment.ClusterName,
+					Namespace:                   deployment.Namespace,
+					Name:                        deployment.Name,
+					SecurityVulnerabilityCounts: deployment.ImageCVECountBySeverity.toWire(),
+				})
+		}
+		for _, image := range response.Data.Images {
+			vulnerabilities.Images = append(vulnerabilities.Images, wiremodels.SecurityImageVulnerability{
+				Name:                        image.Name.FullName,
+				SecurityVulnerabilityCounts: image.ImageCVECountBySeverity.toWire(),
+			})
+		}
+		sort.Slice(vulnerabilities.Deployments, func(i, j int) bool {
+			a, b := vulnerabilities.Deployments[i], vulnerabilities.Deployments[j]
+			if a.Cluster != b.Cluster {
+				return a.Cluster < b.Cluster
+			}
+			if a.Namespace != b.Namespace {
+				return a.Namespace < b.Namespace
+			}
+			return a.Name < b.Name
+		})
+		sort.Slice(vulnerabilities.Images, func(i, j int) bool {
+			return vulnerabilities.Images[i].Name < vulnerabilities.Images[j].Name
+		})
+		return vulnerabilities, nil
+	},
+}
+
+// graphQLBody returns the body of the GraphQL request with the pagination limit as the variable.
+func graphQLBody(operationName, query string) string {
+	// marshaling the strings and the numbers never fails
+	body, _ := json.Marshal(map[string]any{
+		"operationName": operationName,
+		"query":         query,
+		"variables": map[string]any{
+			"pagination": map[string]any{"limit": stackRoxListLimit},
+		},
+	})
+	return string(body)
+}
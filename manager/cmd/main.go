@@ -9,6 +9,7 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"net/http"
 	"os"
 	"strings"
 	"time"
@@ -30,11 +31,14 @@ import (
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/processes/cronjob"
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/processes/hubmanagement"
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/restapis"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/restapis/watcher"
 	specsyncer "github.com/stolostron/multicluster-global-hub/manager/pkg/spec"
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/status"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/status/deadletter"
 	mgrwebhook "github.com/stolostron/multicluster-global-hub/manager/pkg/webhook"
 	"github.com/stolostron/multicluster-global-hub/pkg/constants"
 	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	"github.com/stolostron/multicluster-global-hub/pkg/database/migration"
 	"github.com/stolostron/multicluster-global-hub/pkg/logger"
 	commonobjects "github.com/stolostron/multicluster-global-hub/pkg/objects"
 	"github.com/stolostron/multicluster-global-hub/pkg/statistics"
@@ -44,14 +48,16 @@ import (
 )
 
 const (
-	metricsHost                = "0.0.0.0"
-	metricsPort          int32 = 8384
-	webhookPort                = 9443
-	webhookCertDir             = "/webhook-certs"
-	kafkaTransportType         = "kafka"
-	leaderElectionLockID       = "multicluster-global-hub-manager-lock"
-	launchJobNamesEnv          = "LAUNCH_JOB_NAMES"
-	namespacePath              = "metadata.namespace"
+	metricsHost                   = "0.0.0.0"
+	metricsPort             int32 = 8384
+	webhookPort                   = 9443
+	webhookCertDir                = "/webhook-certs"
+	kafkaTransportType            = "kafka"
+	leaderElectionLockID          = "multicluster-global-hub-manager-lock"
+	launchJobNamesEnv             = "LAUNCH_JOB_NAMES"
+	archiveS3AccessKeyIDEnv       = "ARCHIVE_S3_ACCESS_KEY_ID"
+	archiveS3SecretKeyEnv         = "ARCHIVE_S3_SECRET_ACCESS_KEY"
+	namespacePath                 = "metadata.namespace"
 )
 
 var (
@@ -83,6 +89,9 @@ func parseFlags() *configs.ManagerConfig {
 	pflag.StringVar(&managerConfig.SchedulerInterval, "scheduler-interval", "day",
 		"The job scheduler interval for moving policy compliance history, "+
 			"can be 'month', 'week', 'day', 'hour', 'minute' or 'second', default value is 'day'.")
+	pflag.DurationVar(&managerConfig.HubGracePeriod, "hub-grace-period", hubmanagement.DefaultGracePeriod,
+		"The duration that the hub without heartbeat stays unreachable with its data kept, before it's inactive and "+
+			"its data is removed. The hub is inactive once it's unreachable if it's 0.")
 	pflag.DurationVar(&managerConfig.SyncerConfig.SpecSyncInterval, "spec-sync-interval", 5*time.Second,
 		"The synchronization interval of resources in spec.")
 	pflag.DurationVar(&managerConfig.SyncerConfig.StatusSyncInterval, "status-sync-interval", 5*time.Second,
@@ -107,11 +116,33 @@ func parseFlags() *configs.ManagerConfig {
 		"/var/run/secrets/kubernetes.io/serviceaccount/ca.crt", "The CA bundle path for cluster API.")
 	pflag.StringVar(&managerConfig.RestAPIServerConfig.ServerBasePath, "server-base-path",
 		"/global-hub-api/v1", "The base path for nonK8s API server.")
+	pflag.StringVar(&managerConfig.RestAPIServerConfig.RBACConfigPath, "rest-api-rbac-config", "",
+		"The RBAC config file of the nonK8s API server, the SubjectAccessReview is used if it's empty.")
+	pflag.DurationVar(&managerConfig.RestAPIServerConfig.WatchRetention, "rest-api-watch-retention",
+		watcher.DefaultRetention, "How long the resource changes are kept for resuming the watches of nonK8s API server.")
 	pflag.IntVar(&managerConfig.ElectionConfig.LeaseDuration, "lease-duration", 137, "controller leader lease duration")
 	pflag.IntVar(&managerConfig.ElectionConfig.RenewDeadline, "renew-deadline", 107, "controller leader renew deadline")
 	pflag.IntVar(&managerConfig.ElectionConfig.RetryPeriod, "retry-period", 26, "controller leader retry period")
 	pflag.IntVar(&managerConfig.DatabaseConfig.DataRetention, "data-retention", 18,
 		"data retention indicates how many months the expired data will kept in the database")
+	pflag.StringToIntVar(&managerConfig.DatabaseConfig.TableRetentions, "data-retention-tables", map[string]int{},
+		"the retention months of the tables overriding the data retention, e.g. history.local_compliance=24")
+	pflag.StringVar(&managerConfig.DatabaseConfig.Archive.Format, "data-archive-format", "",
+		"the format(ndjson or parquet) to export the expired partitions before dropping them, disabled if it's empty")
+	pflag.StringVar(&managerConfig.DatabaseConfig.Archive.Dir, "data-archive-dir", "",
+		"the directory to store the exported partitions")
+	pflag.StringVar(&managerConfig.DatabaseConfig.Archive.S3Endpoint, "data-archive-s3-endpoint", "",
+		"the endpoint of the S3-compatible object store to upload the exported partitions")
+	pflag.StringVar(&managerConfig.DatabaseConfig.Archive.S3Bucket, "data-archive-s3-bucket", "",
+		"the bucket to upload the exported partitions")
+	pflag.StringVar(&managerConfig.DatabaseConfig.Archive.S3Prefix, "data-archive-s3-prefix", "",
+		"the prefix of the uploaded partitions in the bucket")
+	pflag.StringVar(&managerConfig.DatabaseConfig.Archive.S3Region, "data-archive-s3-region", "",
+		"the region of the bucket")
+	pflag.BoolVar(&managerConfig.DatabaseConfig.Archive.S3Insecure, "data-archive-s3-insecure", false,
+		"connect to the object store without TLS")
+	pflag.BoolVar(&managerConfig.DatabaseConfig.CheckSchemaVersion, "check-schema-version", false,
+		"refuse to start if the database schema version is older than the manager expects")
 	pflag.BoolVar(&managerConfig.EnableGlobalResource, "enable-global-resource", false,
 		"enable the global resource feature")
 	pflag.BoolVar(&managerConfig.WithACM, "with-acm", false,
@@ -142,6 +173,9 @@ func completeConfig(managerConfig *configs.ManagerConfig) error {
 	if ok && val != "" {
 		managerConfig.LaunchJobNames = val
 	}
+	// the credential of the archive bucket is from the secret
+	managerConfig.DatabaseConfig.Archive.S3AccessKeyID = os.Getenv(archiveS3AccessKeyIDEnv)
+	managerConfig.DatabaseConfig.Archive.S3SecretAccessKey = os.Getenv(archiveS3SecretKeyEnv)
 	return nil
 }
 
@@ -157,6 +191,10 @@ func createManager(ctx context.Context,
 		Scheme: configs.GetRuntimeScheme(),
 		Metrics: metricsserver.Options{
 			BindAddress: fmt.Sprintf("%s:%d", metricsHost, metricsPort),
+			// the status syncers are started after the manager, so the handler is registered ahead
+			ExtraHandlers: map[string]http.Handler{
+				status.ConflationDebugPath: status.ConflationDebugHandler(),
+			},
 		},
 		LeaderElection:          true,
 		LeaderElectionNamespace: managerConfig.ManagerNamespace,
@@ -229,6 +267,11 @@ func createManager(ctx context.Context,
 		return nil, err
 	}
 	if managerConfig.EnableGlobalResource {
+		managerConfig.RestAPIServerConfig.WatchBroker = watcher.NewBroker(&database.DatabaseConfig{
+			URL:        managerConfig.DatabaseConfig.ProcessDatabaseURL,
+			Dialect:    database.PostgresDialect,
+			CaCertPath: managerConfig.DatabaseConfig.CACertPath,
+		}, managerConfig.RestAPIServerConfig.WatchRetention)
 		if err := restapis.AddRestApiServer(mgr, managerConfig.RestAPIServerConfig); err != nil {
 			return nil, fmt.Errorf("failed to add non-k8s-api-server: %w", err)
 		}
@@ -254,7 +297,7 @@ func transportCallback(mgr ctrl.Manager, managerConfig *configs.ManagerConfig) c
 		}
 
 		// add hub management
-		if err := hubmanagement.AddHubManagement(mgr, producer); err != nil {
+		if err := hubmanagement.AddHubManagement(mgr, producer, managerConfig.HubGracePeriod); err != nil {
 			return fmt.Errorf("failed to add hubmanagement to manager - %w", err)
 		}
 
@@ -293,6 +336,12 @@ func doMain(ctx context.Context, restConfig *rest.Config) error {
 	}
 	defer database.CloseGorm(database.GetSqlDb())
 
+	if managerConfig.DatabaseConfig.CheckSchemaVersion {
+		if err := migration.CheckVersion(ctx, database.GetGorm(), migration.SchemaVersion); err != nil {
+			return err
+		}
+	}
+
 	// Init the backup gorm instance, it's used to add lock when backup database
 	_, sqlBackupConn, err := database.NewGormConn(databaseConfig)
 	if err != nil {
@@ -326,6 +375,8 @@ func doMain(ctx context.Context, restConfig *rest.Config) error {
 
 func main() {
 	defer func() { _ = logger.CoreZapLogger().Sync() }()
+	statistics.RegisterTransportMetrics()
+	deadletter.RegisterMetrics()
 	if err := doMain(ctrl.SetupSignalHandler(), ctrl.GetConfigOrDie()); err != nil {
 		logger.DefaultZapLogger().Panicf("failed to run the main: %v", err)
 	}
@@ -27,6 +27,8 @@ type ManagerConfig struct {
 	WithACM               bool
 	LaunchJobNames        string
 	EnablePprof           bool
+	// HubGracePeriod is how long the hub without heartbeat is unreachable before it's inactive
+	HubGracePeriod time.Duration
 }
 
 type SyncerConfig struct {
@@ -41,4 +43,24 @@ type DatabaseConfig struct {
 	CACertPath                 string
 	MaxOpenConns               int
 	DataRetention              int
+	// CheckSchemaVersion refuses to start the manager if the database schema is older than the binary expects
+	CheckSchemaVersion bool
+	// TableRetentions overrides the DataRetention months of the tables
+	TableRetentions map[string]int
+	Archive         ArchiveConfig
+}
+
+// ArchiveConfig defines how and where to export the expired partitions, the archive is disabled if the Format is empty
+type ArchiveConfig struct {
+	Format string
+	// Dir is the local directory, e.g. the mount path of a persistent volume, to store the exported partitions
+	Dir        string
+	S3Endpoint string
+	S3Bucket   string
+	S3Prefix   string
+	S3Region   string
+	S3Insecure bool
+	// the credential of the S3 bucket is read from the environment variables
+	S3AccessKeyID     string
+	S3SecretAccessKey string
 }
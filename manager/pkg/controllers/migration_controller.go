@@ -7,15 +7,20 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"sort"
 	"strings"
 	"time"
 
 	klusterletv1alpha1 "github.com/stolostron/cluster-lifecycle-api/klusterletconfig/v1alpha1"
 	addonv1 "github.com/stolostron/klusterlet-addon-controller/pkg/apis/agent/v1"
 	corev1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/client-go/tools/clientcmd"
 	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
 	clusterv1 "open-cluster-management.io/api/cluster/v1"
@@ -60,6 +65,20 @@ func NewMigrationController(client client.Client, producer transport.Producer,
 const (
 	klusterletConfigNamePrefix = "migration-"
 	bootstrapSecretNamePrefix  = "bootstrap-"
+
+	// DefaultRegistrationTimeout is the time to wait for the managed clusters to register to the target hub
+	DefaultRegistrationTimeout = 10 * time.Minute
+
+	// hubConnectionTimeout is how long the klusterlet fails to connect to the target hub before it falls back to the
+	// bootstrap kubeconfig of the source hub, the klusterlet config requires at least 180 seconds
+	hubConnectionTimeout = 3 * time.Minute
+)
+
+var (
+	// waitingInterval is the interval to check the managed clusters and the token of the managed service account
+	waitingInterval = 2 * time.Second
+	// registeringInterval is the interval to check the managed clusters on the target hub
+	registeringInterval = 10 * time.Second
 )
 
 var migrationLog = logger.ZapLogger("migration-ctrl")
@@ -70,22 +89,12 @@ func (m *MigrationController) SetupWithManager(mgr ctrl.Manager) error {
 		For(&migrationv1alpha1.ManagedClusterMigration{}).
 		Watches(&v1beta1.ManagedServiceAccount{},
 			handler.EnqueueRequestsFromMapFunc(func(ctx context.Context, obj client.Object) []reconcile.Request {
-				if !obj.GetDeletionTimestamp().IsZero() {
-					// trigger to recreate the msa
-					return []reconcile.Request{
-						{
-							NamespacedName: types.NamespacedName{
-								Name:      obj.GetName(),
-								Namespace: utils.GetDefaultNamespace(),
-							},
-						},
-					}
-				}
+				// the msa is named after the migration, trigger to recreate the msa or continue the migration
 				return []reconcile.Request{
 					{
 						NamespacedName: types.NamespacedName{
 							Name:      obj.GetName(),
-							Namespace: obj.GetNamespace(),
+							Namespace: utils.GetDefaultNamespace(),
 						},
 					},
 				}
@@ -120,92 +129,636 @@ func (m *MigrationController) SetupWithManager(mgr ctrl.Manager) error {
 }
 
 func (m *MigrationController) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
-	if req.Namespace == utils.GetDefaultNamespace() {
-		// create managedserviceaccount
-		migration := &migrationv1alpha1.ManagedClusterMigration{}
-		err := m.Get(ctx, req.NamespacedName, migration)
-		if err != nil {
-			if apierrors.IsNotFound(err) {
-				// If the custom resource is not found then it usually means that it was deleted or not created
-				// In this way, we will stop the reconciliation
-				migrationLog.Info("managedclustermigration resource not found. Ignoring since object must be deleted")
-				return ctrl.Result{}, nil
-			}
-			// Error reading the object - requeue the request.
-			migrationLog.Error(err, "failed to get managedclustermigration")
-			return ctrl.Result{}, err
+	migration := &migrationv1alpha1.ManagedClusterMigration{}
+	err := m.Get(ctx, req.NamespacedName, migration)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			// If the custom resource is not found then it usually means that it was deleted or not created
+			// In this way, we will stop the reconciliation
+			migrationLog.Info("managedclustermigration resource not found. Ignoring since object must be deleted")
+			return ctrl.Result{}, nil
 		}
+		// Error reading the object - requeue the request.
+		migrationLog.Error(err, "failed to get managedclustermigration")
+		return ctrl.Result{}, err
+	}
 
-		if migration.DeletionTimestamp.IsZero() {
-			if !controllerutil.ContainsFinalizer(migration, constants.ManagedClusterMigrationFinalizer) {
-				controllerutil.AddFinalizer(migration, constants.ManagedClusterMigrationFinalizer)
-				return ctrl.Result{}, m.Update(ctx, migration)
-			}
-		} else {
-			// The migration object is being deleted
-			if controllerutil.ContainsFinalizer(migration, constants.ManagedClusterMigrationFinalizer) {
-				if err := m.deleteManagedServiceAccount(ctx, migration); err != nil {
+	if migration.DeletionTimestamp.IsZero() {
+		if !controllerutil.ContainsFinalizer(migration, constants.ManagedClusterMigrationFinalizer) {
+			controllerutil.AddFinalizer(migration, constants.ManagedClusterMigrationFinalizer)
+			return ctrl.Result{}, m.Update(ctx, migration)
+		}
+	} else {
+		// The migration object is being deleted
+		if controllerutil.ContainsFinalizer(migration, constants.ManagedClusterMigrationFinalizer) {
+			// the clusters handed off to the target hub are rolled back if the migration is stopped
+			if migration.Status.Phase == migrationv1alpha1.PhaseRegistering {
+				if err := m.rollback(ctx, migration, unregisteredClusters(migration)); err != nil {
 					return ctrl.Result{}, err
 				}
-
-				controllerutil.RemoveFinalizer(migration, constants.ManagedClusterMigrationFinalizer)
-				if err := m.Update(ctx, migration); err != nil {
+			}
+			for _, hub := range migrationHubs(migration) {
+				if err := m.deleteManagedServiceAccount(ctx, migration, hub); err != nil {
 					return ctrl.Result{}, err
 				}
 			}
-			return ctrl.Result{}, nil
+
+			controllerutil.RemoveFinalizer(migration, constants.ManagedClusterMigrationFinalizer)
+			if err := m.Update(ctx, migration); err != nil {
+				return ctrl.Result{}, err
+			}
+		}
+		return ctrl.Result{}, nil
+	}
+
+	if migration.Status.Phase == migrationv1alpha1.PhaseCompleted ||
+		migration.Status.Phase == migrationv1alpha1.PhaseFailed {
+		return ctrl.Result{}, nil
+	}
+
+	if migration.Spec.To != "" {
+		for _, hub := range migrationHubs(migration) {
+			if err := m.ensureManagedServiceAccount(ctx, migration, hub); err != nil {
+				return ctrl.Result{}, err
+			}
 		}
+	}
+
+	originalStatus := migration.Status.DeepCopy()
+	var requeueAfter time.Duration
+	switch migration.Status.Phase {
+	case "", migrationv1alpha1.PhaseValidating:
+		requeueAfter, err = m.validate(ctx, migration)
+	case migrationv1alpha1.PhaseInitializing:
+		requeueAfter, err = m.initialize(ctx, migration)
+	case migrationv1alpha1.PhaseDeploying:
+		err = m.deploy(ctx, migration)
+	case migrationv1alpha1.PhaseRegistering:
+		requeueAfter, err = m.register(ctx, migration)
+	case migrationv1alpha1.PhaseRollingBack:
+		requeueAfter, err = m.rollingBack(migration)
+	case migrationv1alpha1.PhaseCleaning:
+		err = m.clean(ctx, migration)
+	}
+	if err != nil {
+		return ctrl.Result{}, err
+	}
 
-		if err := m.ensureManagedServiceAccount(ctx, migration); err != nil {
+	if !apiequality.Semantic.DeepEqual(originalStatus, &migration.Status) {
+		migrationLog.Infof("migration %s is in the phase %s", migration.Name, migration.Status.Phase)
+		if err := m.Status().Update(ctx, migration); err != nil {
 			return ctrl.Result{}, err
 		}
+	}
+	return ctrl.Result{RequeueAfter: requeueAfter}, nil
+}
 
-		// send the klusterletaddonconfig to the target cluster
-		return ctrl.Result{}, m.syncMigrationTo(ctx, migration)
-	} else {
-		// check if the secret is created by managedserviceaccount, if not, requeue after 1 second
-		desiredSecret := &corev1.Secret{}
-		if err := m.Client.Get(ctx, types.NamespacedName{
-			Name:      req.Name,
-			Namespace: req.Namespace,
-		}, desiredSecret); err != nil {
+// validate resolves the managed clusters and their source hubs. The migration fails if the spec is invalid, and waits
+// for the managed clusters which are not reported to the global hub yet.
+func (m *MigrationController) validate(ctx context.Context,
+	migration *migrationv1alpha1.ManagedClusterMigration,
+) (time.Duration, error) {
+	if migration.Spec.To == "" {
+		setFailed(migration, migrationv1alpha1.ConditionTypeValidated, "InvalidSpec", "the target hub is required")
+		return 0, nil
+	}
+	if migration.Spec.From == migration.Spec.To {
+		setFailed(migration, migrationv1alpha1.ConditionTypeValidated, "InvalidSpec",
+			fmt.Sprintf("the managed clusters can't be migrated to the source hub %s", migration.Spec.From))
+		return 0, nil
+	}
+	if len(migration.Spec.IncludedManagedClusters) == 0 && migration.Spec.ManagedClusterSelector == nil {
+		setFailed(migration, migrationv1alpha1.ConditionTypeValidated, "InvalidSpec",
+			"either includedManagedClusters or managedClusterSelector is required")
+		return 0, nil
+	}
+	var selector labels.Selector
+	if migration.Spec.ManagedClusterSelector != nil {
+		var err error
+		selector, err = metav1.LabelSelectorAsSelector(migration.Spec.ManagedClusterSelector)
+		if err != nil {
+			setFailed(migration, migrationv1alpha1.ConditionTypeValidated, "InvalidSpec",
+				fmt.Sprintf("invalid managedClusterSelector: %v", err))
+			return 0, nil
+		}
+	}
+
+	clusterHubs, err := m.selectManagedClusters(migration, selector)
+	if err != nil {
+		return 0, err
+	}
+
+	waiting := []string{}
+	for _, name := range migration.Spec.IncludedManagedClusters {
+		if _, ok := clusterHubs[name]; !ok {
+			waiting = append(waiting, name)
+		}
+	}
+	if len(waiting) > 0 || len(clusterHubs) == 0 {
+		message := "no managed cluster is selected"
+		if len(waiting) > 0 {
+			message = fmt.Sprintf("the managed clusters %v are not found", waiting)
+		}
+		migration.Status.Phase = migrationv1alpha1.PhaseValidating
+		meta.SetStatusCondition(&migration.Status.Conditions, metav1.Condition{
+			Type:    migrationv1alpha1.ConditionTypeValidated,
+			Status:  metav1.ConditionFalse,
+			Reason:  "ClusterNotFound",
+			Message: message,
+		})
+		return waitingInterval, nil
+	}
+
+	names := make([]string, 0, len(clusterHubs))
+	for name, hub := range clusterHubs {
+		if hub == migration.Spec.To {
+			setFailed(migration, migrationv1alpha1.ConditionTypeValidated, "InvalidSpec",
+				fmt.Sprintf("the managed cluster %s is already in the target hub %s", name, hub))
+			return 0, nil
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	migration.Status.Clusters = nil
+	for _, name := range names {
+		migration.Status.Clusters = append(migration.Status.Clusters, migrationv1alpha1.ClusterMigrationStatus{
+			Name:               name,
+			From:               clusterHubs[name],
+			Phase:              migrationv1alpha1.ClusterPhasePending,
+			LastTransitionTime: metav1.Now(),
+		})
+	}
+	migration.Status.Phase = migrationv1alpha1.PhaseInitializing
+	meta.SetStatusCondition(&migration.Status.Conditions, metav1.Condition{
+		Type:    migrationv1alpha1.ConditionTypeValidated,
+		Status:  metav1.ConditionTrue,
+		Reason:  "ResourceValidated",
+		Message: fmt.Sprintf("%d managed clusters are migrated to %s", len(names), migration.Spec.To),
+	})
+	return 0, nil
+}
+
+// selectManagedClusters returns the source hub of the included and selected managed clusters
+func (m *MigrationController) selectManagedClusters(migration *migrationv1alpha1.ManagedClusterMigration,
+	selector labels.Selector,
+) (map[string]string, error) {
+	clusterHubs := map[string]string{}
+	// the included managed clusters are from the specified hub
+	if migration.Spec.From != "" {
+		for _, name := range migration.Spec.IncludedManagedClusters {
+			clusterHubs[name] = migration.Spec.From
+		}
+		if selector == nil {
+			return clusterHubs, nil
+		}
+	}
+
+	tx := database.GetGorm().Table("status.managed_clusters").
+		Select("leaf_hub_name, cluster_name, payload -> 'metadata' -> 'labels' AS labels").
+		Where("deleted_at IS NULL")
+	if migration.Spec.From != "" {
+		tx = tx.Where("leaf_hub_name = ?", migration.Spec.From)
+	}
+	if selector == nil {
+		tx = tx.Where("cluster_name IN (?)", migration.Spec.IncludedManagedClusters)
+	}
+	rows, err := tx.Rows()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get leaf hub name and managed clusters - %w", err)
+	}
+	defer rows.Close()
+
+	included := sets.New(migration.Spec.IncludedManagedClusters...)
+	for rows.Next() {
+		var leafHubName, clusterName string
+		var clusterLabels []byte
+		if err := rows.Scan(&leafHubName, &clusterName, &clusterLabels); err != nil {
+			return nil, fmt.Errorf("failed to scan leaf hub name and managed cluster name - %w", err)
+		}
+		if included.Has(clusterName) {
+			clusterHubs[clusterName] = leafHubName
+			continue
+		}
+		labelSet := labels.Set{}
+		if len(clusterLabels) > 0 {
+			if err := json.Unmarshal(clusterLabels, &labelSet); err != nil {
+				return nil, fmt.Errorf("failed to unmarshal the labels of managed cluster %s - %w", clusterName, err)
+			}
+		}
+		if selector != nil && selector.Matches(labelSet) {
+			clusterHubs[clusterName] = leafHubName
+		}
+	}
+	return clusterHubs, nil
+}
+
+// initialize prepares the target hub and the source hubs with the managed service account to accept the managed
+// clusters, the source hubs accept the managed clusters which are rolled back
+func (m *MigrationController) initialize(ctx context.Context,
+	migration *migrationv1alpha1.ManagedClusterMigration,
+) (time.Duration, error) {
+	hubs := migrationHubs(migration)
+	// check if the secret is created by managedserviceaccount, if not, requeue after 1 second
+	for _, hub := range hubs {
+		tokenSecret := &corev1.Secret{}
+		if err := m.Get(ctx, types.NamespacedName{
+			Name:      migration.Name,
+			Namespace: hub,
+		}, tokenSecret); err != nil {
 			if apierrors.IsNotFound(err) {
-				return ctrl.Result{Requeue: true, RequeueAfter: time.Second}, nil
+				meta.SetStatusCondition(&migration.Status.Conditions, metav1.Condition{
+					Type:   migrationv1alpha1.ConditionTypeInitialized,
+					Status: metav1.ConditionFalse,
+					Reason: "Waiting",
+					Message: fmt.Sprintf("waiting for the token of the managedserviceaccount %s on %s",
+						migration.Name, hub),
+				})
+				return time.Second, nil
 			}
-			return ctrl.Result{}, err
+			return 0, err
 		}
+	}
 
-		// create kubeconfig based on the secret of managedserviceaccount
-		kubeconfig, err := m.generateKubeconfig(ctx, req)
+	// send the managedserviceaccount to the hubs, and the klusterletaddonconfig to the target hub
+	for _, hub := range hubs {
+		if err := m.syncMigrationTo(ctx, migration, hub); err != nil {
+			return 0, err
+		}
+	}
+
+	migration.Status.Phase = migrationv1alpha1.PhaseDeploying
+	meta.SetStatusCondition(&migration.Status.Conditions, metav1.Condition{
+		Type:    migrationv1alpha1.ConditionTypeInitialized,
+		Status:  metav1.ConditionTrue,
+		Reason:  "ResourceInitialized",
+		Message: fmt.Sprintf("the target hub %s is initialized", migration.Spec.To),
+	})
+	return 0, nil
+}
+
+// deploy sends the bootstrap kubeconfig of the target hub to the source hubs, which hand off the managed clusters.
+// The bootstrap kubeconfig of the source hub is deployed as well, so that the klusterlet can turn back to the source
+// hub if it fails to register to the target hub.
+func (m *MigrationController) deploy(ctx context.Context,
+	migration *migrationv1alpha1.ManagedClusterMigration,
+) error {
+	// create kubeconfig based on the secret of managedserviceaccount
+	kubeconfig, err := m.generateKubeconfig(ctx, migration.Name, migration.Spec.To)
+	if err != nil {
+		return err
+	}
+	m.BootstrapSecret, err = m.generateBootstrapSecret(kubeconfig, migration.Spec.To)
+	if err != nil {
+		return err
+	}
+
+	clusters := clustersInPhase(migration, migrationv1alpha1.ClusterPhasePending)
+	for _, fromHub := range sortedHubs(clusters) {
+		sourceKubeconfig, err := m.generateKubeconfig(ctx, migration.Name, fromHub)
 		if err != nil {
-			return ctrl.Result{}, err
+			return err
 		}
-		m.BootstrapSecret, err = m.generateBootstrapSecret(kubeconfig, req)
+		sourceBootstrapSecret, err := m.generateBootstrapSecret(sourceKubeconfig, fromHub)
 		if err != nil {
-			return ctrl.Result{}, err
+			return err
 		}
-		// generate klusterletconfig
-		klusterletConfig := m.generateKlusterletConfig(req)
-		// send the kubeconfig to managedclustermigration.Spec.From
-		migration := &migrationv1alpha1.ManagedClusterMigration{}
-		if err = m.Get(ctx, types.NamespacedName{
-			Name:      req.Name,
-			Namespace: utils.GetDefaultNamespace(),
-		}, migration); err != nil {
-			migrationLog.Error(err, "failed to get managedclustermigration")
-			return ctrl.Result{}, err
+		if err := m.syncMigrationFrom(ctx, fromHub, &bundleevent.ManagedClusterMigrationFromEvent{
+			Stage:                 bundleevent.MigrationStageDeploying,
+			ManagedClusters:       clusters[fromHub],
+			BootstrapSecret:       m.BootstrapSecret,
+			SourceBootstrapSecret: sourceBootstrapSecret,
+			KlusterletConfig:      m.generateKlusterletConfig(migration.Spec.To, fromHub),
+		}); err != nil {
+			return err
 		}
-		if err := m.syncMigration(ctx, migration, klusterletConfig); err != nil {
-			return ctrl.Result{}, err
+	}
+
+	setClusterPhase(migration, migrationv1alpha1.ClusterPhasePending, migrationv1alpha1.ClusterPhaseRegistering,
+		fmt.Sprintf("waiting for the managed cluster to register to %s", migration.Spec.To))
+	migration.Status.Phase = migrationv1alpha1.PhaseRegistering
+	meta.SetStatusCondition(&migration.Status.Conditions, metav1.Condition{
+		Type:    migrationv1alpha1.ConditionTypeDeployed,
+		Status:  metav1.ConditionTrue,
+		Reason:  "ResourceDeployed",
+		Message: fmt.Sprintf("the bootstrap kubeconfig of %s is deployed to the managed clusters", migration.Spec.To),
+	})
+	meta.SetStatusCondition(&migration.Status.Conditions, metav1.Condition{
+		Type:    migrationv1alpha1.ConditionTypeRegistered,
+		Status:  metav1.ConditionFalse,
+		Reason:  "Waiting",
+		Message: fmt.Sprintf("waiting for the managed clusters to register to %s", migration.Spec.To),
+	})
+	return nil
+}
+
+// register waits for the managed clusters to be available on the target hub, the managed clusters that are not
+// registered within the registration timeout are rolled back to the source hub
+func (m *MigrationController) register(ctx context.Context,
+	migration *migrationv1alpha1.ManagedClusterMigration,
+) (time.Duration, error) {
+	registered, err := registeredClusters(migration.Spec.To, unregisteredClusters(migration), time.Time{})
+	if err != nil {
+		return 0, err
+	}
+	for i := range migration.Status.Clusters {
+		cluster := &migration.Status.Clusters[i]
+		if cluster.Phase == migrationv1alpha1.ClusterPhaseRegistering && registered.Has(cluster.Name) {
+			cluster.Phase = migrationv1alpha1.ClusterPhaseRegistered
+			cluster.Message = fmt.Sprintf("the managed cluster is available on %s", migration.Spec.To)
+			cluster.LastTransitionTime = metav1.Now()
 		}
 	}
-	return ctrl.Result{}, nil
+
+	unregistered := unregisteredClusters(migration)
+	if len(unregistered) == 0 {
+		migration.Status.Phase = migrationv1alpha1.PhaseCleaning
+		meta.SetStatusCondition(&migration.Status.Conditions, metav1.Condition{
+			Type:    migrationv1alpha1.ConditionTypeRegistered,
+			Status:  metav1.ConditionTrue,
+			Reason:  "ClusterRegistered",
+			Message: fmt.Sprintf("all the managed clusters are registered to %s", migration.Spec.To),
+		})
+		return 0, nil
+	}
+
+	timeout := registrationTimeout(migration)
+	deadline := time.Now().Add(timeout)
+	if deployed := meta.FindStatusCondition(migration.Status.Conditions,
+		migrationv1alpha1.ConditionTypeDeployed); deployed != nil {
+		deadline = deployed.LastTransitionTime.Add(timeout)
+	}
+	if time.Now().Before(deadline) {
+		return min(registeringInterval, time.Until(deadline)), nil
+	}
+
+	migrationLog.Infof("migration %s timed out, rolling back the managed clusters %v", migration.Name, unregistered)
+	if err := m.rollback(ctx, migration, unregistered); err != nil {
+		return 0, err
+	}
+	setClusterPhase(migration, migrationv1alpha1.ClusterPhaseRegistering, migrationv1alpha1.ClusterPhaseRollingBack,
+		fmt.Sprintf("the managed cluster isn't registered to %s in %s, rolling back to the source hub",
+			migration.Spec.To, timeout))
+	migration.Status.Phase = migrationv1alpha1.PhaseRollingBack
+	meta.SetStatusCondition(&migration.Status.Conditions, metav1.Condition{
+		Type:    migrationv1alpha1.ConditionTypeRegistered,
+		Status:  metav1.ConditionFalse,
+		Reason:  "Timeout",
+		Message: fmt.Sprintf("the managed clusters %v are not registered to %s in %s", unregistered, migration.Spec.To, timeout),
+	})
+	meta.SetStatusCondition(&migration.Status.Conditions, metav1.Condition{
+		Type:    migrationv1alpha1.ConditionTypeRolledBack,
+		Status:  metav1.ConditionFalse,
+		Reason:  "Waiting",
+		Message: fmt.Sprintf("waiting for the managed clusters %v to register back to the source hubs", unregistered),
+	})
+	return 0, nil
+}
+
+// rollingBack waits for the rolled back managed clusters to be accepted and available on the source hubs again. The
+// klusterlet falls back to the source hub after the hub connection timeout, so the managed clusters that aren't back
+// within the registration timeout plus the hub connection timeout are failed.
+func (m *MigrationController) rollingBack(migration *migrationv1alpha1.ManagedClusterMigration) (time.Duration, error) {
+	since := time.Now()
+	if rolledBack := meta.FindStatusCondition(migration.Status.Conditions,
+		migrationv1alpha1.ConditionTypeRolledBack); rolledBack != nil {
+		since = rolledBack.LastTransitionTime.Time
+	}
+
+	clusters := clustersInPhase(migration, migrationv1alpha1.ClusterPhaseRollingBack)
+	for _, fromHub := range sortedHubs(clusters) {
+		registered, err := registeredClusters(fromHub, clusters[fromHub], since)
+		if err != nil {
+			return 0, err
+		}
+		for i := range migration.Status.Clusters {
+			cluster := &migration.Status.Clusters[i]
+			if cluster.Phase == migrationv1alpha1.ClusterPhaseRollingBack && cluster.From == fromHub &&
+				registered.Has(cluster.Name) {
+				cluster.Phase = migrationv1alpha1.ClusterPhaseRolledBack
+				cluster.Message = fmt.Sprintf("the managed cluster is available on %s again", fromHub)
+				cluster.LastTransitionTime = metav1.Now()
+			}
+		}
+	}
+
+	// the registered clusters are still cleaned up from the source hubs
+	if len(clustersInPhase(migration, migrationv1alpha1.ClusterPhaseRollingBack)) == 0 {
+		migration.Status.Phase = migrationv1alpha1.PhaseCleaning
+		meta.SetStatusCondition(&migration.Status.Conditions, metav1.Condition{
+			Type:    migrationv1alpha1.ConditionTypeRolledBack,
+			Status:  metav1.ConditionTrue,
+			Reason:  "ClusterRolledBack",
+			Message: "the unregistered managed clusters are registered back to the source hubs",
+		})
+		return 0, nil
+	}
+
+	timeout := registrationTimeout(migration) + hubConnectionTimeout
+	deadline := since.Add(timeout)
+	if time.Now().Before(deadline) {
+		return min(registeringInterval, time.Until(deadline)), nil
+	}
+
+	failed := unrolledBackClusters(migration)
+	migrationLog.Infof("migration %s failed to roll back the managed clusters %v", migration.Name, failed)
+	setClusterPhase(migration, migrationv1alpha1.ClusterPhaseRollingBack, migrationv1alpha1.ClusterPhaseFailed,
+		fmt.Sprintf("the managed cluster isn't registered back to the source hub in %s", timeout))
+	migration.Status.Phase = migrationv1alpha1.PhaseCleaning
+	meta.SetStatusCondition(&migration.Status.Conditions, metav1.Condition{
+		Type:    migrationv1alpha1.ConditionTypeRolledBack,
+		Status:  metav1.ConditionFalse,
+		Reason:  "Timeout",
+		Message: fmt.Sprintf("the managed clusters %v are not registered back to the source hubs in %s", failed, timeout),
+	})
+	return 0, nil
+}
+
+func registrationTimeout(migration *migrationv1alpha1.ManagedClusterMigration) time.Duration {
+	if migration.Spec.RegistrationTimeout.Duration == 0 {
+		return DefaultRegistrationTimeout
+	}
+	return migration.Spec.RegistrationTimeout.Duration
+}
+
+// rollback re-points the klusterlet of the managed clusters to the source hubs, and removes the partially registered
+// managed clusters from the target hub
+func (m *MigrationController) rollback(ctx context.Context,
+	migration *migrationv1alpha1.ManagedClusterMigration, managedClusters []string,
+) error {
+	if len(managedClusters) == 0 {
+		return nil
+	}
+	rollbackClusters := sets.New(managedClusters...)
+	clusters := map[string][]string{}
+	for _, cluster := range migration.Status.Clusters {
+		if rollbackClusters.Has(cluster.Name) {
+			clusters[cluster.From] = append(clusters[cluster.From], cluster.Name)
+		}
+	}
+	for _, fromHub := range sortedHubs(clusters) {
+		if err := m.syncMigrationFrom(ctx, fromHub, &bundleevent.ManagedClusterMigrationFromEvent{
+			Stage:           bundleevent.MigrationStageRollingBack,
+			ManagedClusters: clusters[fromHub],
+		}); err != nil {
+			return err
+		}
+	}
+
+	payloadBytes, err := json.Marshal(&bundleevent.ManagedClusterMigrationToEvent{
+		Stage:                     bundleevent.MigrationStageRollingBack,
+		ManagedClusters:           managedClusters,
+		ManagedServiceAccountName: migration.Name,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal managed cluster migration to event - %w", err)
+	}
+	return m.sendEvent(ctx, constants.CloudEventTypeMigrationTo, migration.Spec.To, payloadBytes)
+}
+
+// clean removes the registered managed clusters from the source hubs
+func (m *MigrationController) clean(ctx context.Context,
+	migration *migrationv1alpha1.ManagedClusterMigration,
+) error {
+	clusters := clustersInPhase(migration, migrationv1alpha1.ClusterPhaseRegistered)
+	for _, fromHub := range sortedHubs(clusters) {
+		if err := m.syncMigrationFrom(ctx, fromHub, &bundleevent.ManagedClusterMigrationFromEvent{
+			Stage:           bundleevent.MigrationStageCleaning,
+			ManagedClusters: clusters[fromHub],
+		}); err != nil {
+			return err
+		}
+	}
+	setClusterPhase(migration, migrationv1alpha1.ClusterPhaseRegistered, migrationv1alpha1.ClusterPhaseCompleted,
+		fmt.Sprintf("the managed cluster is migrated to %s", migration.Spec.To))
+	meta.SetStatusCondition(&migration.Status.Conditions, metav1.Condition{
+		Type:    migrationv1alpha1.ConditionTypeCleaned,
+		Status:  metav1.ConditionTrue,
+		Reason:  "ResourceCleaned",
+		Message: "the migrated managed clusters are removed from the source hubs",
+	})
+
+	migration.Status.Phase = migrationv1alpha1.PhaseCompleted
+	if len(clustersInPhase(migration, migrationv1alpha1.ClusterPhaseRolledBack)) > 0 ||
+		len(clustersInPhase(migration, migrationv1alpha1.ClusterPhaseFailed)) > 0 {
+		migration.Status.Phase = migrationv1alpha1.PhaseFailed
+	}
+	return nil
+}
+
+// registeredClusters returns the managed clusters which are reported as available by the hub. If the since time is set,
+// the managed clusters must be accepted by the hub after it, so that a stale report before the rollback is ignored.
+func registeredClusters(hub string, managedClusters []string, since time.Time) (sets.Set[string], error) {
+	registered := sets.New[string]()
+	if len(managedClusters) == 0 {
+		return registered, nil
+	}
+	rows, err := database.GetGorm().Raw(`SELECT cluster_name, payload FROM status.managed_clusters
+		WHERE leaf_hub_name = ? AND cluster_name IN (?) AND deleted_at IS NULL`, hub, managedClusters).Rows()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the managed clusters of hub %s - %w", hub, err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var clusterName string
+		var payload []byte
+		if err := rows.Scan(&clusterName, &payload); err != nil {
+			return nil, fmt.Errorf("failed to scan the managed cluster - %w", err)
+		}
+		cluster := &clusterv1.ManagedCluster{}
+		if err := json.Unmarshal(payload, cluster); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal the managed cluster %s - %w", clusterName, err)
+		}
+		if !meta.IsStatusConditionTrue(cluster.Status.Conditions, clusterv1.ManagedClusterConditionAvailable) {
+			continue
+		}
+		if !since.IsZero() {
+			accepted := meta.FindStatusCondition(cluster.Status.Conditions, clusterv1.ManagedClusterConditionHubAccepted)
+			if accepted == nil || accepted.Status != metav1.ConditionTrue || accepted.LastTransitionTime.Time.Before(since) {
+				continue
+			}
+		}
+		registered.Insert(clusterName)
+	}
+	return registered, nil
+}
+
+func setFailed(migration *migrationv1alpha1.ManagedClusterMigration, conditionType, reason, message string) {
+	migration.Status.Phase = migrationv1alpha1.PhaseFailed
+	meta.SetStatusCondition(&migration.Status.Conditions, metav1.Condition{
+		Type:    conditionType,
+		Status:  metav1.ConditionFalse,
+		Reason:  reason,
+		Message: message,
+	})
+}
+
+// clustersInPhase groups the managed clusters in the phase by their source hubs
+func clustersInPhase(migration *migrationv1alpha1.ManagedClusterMigration, phase string) map[string][]string {
+	clusters := map[string][]string{}
+	for _, cluster := range migration.Status.Clusters {
+		if cluster.Phase == phase {
+			clusters[cluster.From] = append(clusters[cluster.From], cluster.Name)
+		}
+	}
+	return clusters
+}
+
+func unregisteredClusters(migration *migrationv1alpha1.ManagedClusterMigration) []string {
+	clusters := []string{}
+	for _, cluster := range migration.Status.Clusters {
+		if cluster.Phase == migrationv1alpha1.ClusterPhaseRegistering {
+			clusters = append(clusters, cluster.Name)
+		}
+	}
+	return clusters
+}
+
+func unrolledBackClusters(migration *migrationv1alpha1.ManagedClusterMigration) []string {
+	clusters := []string{}
+	for _, cluster := range migration.Status.Clusters {
+		if cluster.Phase == migrationv1alpha1.ClusterPhaseRollingBack {
+			clusters = append(clusters, cluster.Name)
+		}
+	}
+	return clusters
+}
+
+func setClusterPhase(migration *migrationv1alpha1.ManagedClusterMigration, from, to, message string) {
+	for i := range migration.Status.Clusters {
+		cluster := &migration.Status.Clusters[i]
+		if cluster.Phase == from {
+			cluster.Phase = to
+			cluster.Message = message
+			cluster.LastTransitionTime = metav1.Now()
+		}
+	}
+}
+
+// migrationHubs returns the target hub and the source hubs of the migration
+func migrationHubs(migration *migrationv1alpha1.ManagedClusterMigration) []string {
+	sourceHubs := sets.New[string]()
+	for _, cluster := range migration.Status.Clusters {
+		sourceHubs.Insert(cluster.From)
+	}
+	return append([]string{migration.Spec.To}, sets.List(sourceHubs)...)
+}
+
+func sortedHubs(clusters map[string][]string) []string {
+	hubs := make([]string, 0, len(clusters))
+	for hub := range clusters {
+		hubs = append(hubs, hub)
+	}
+	sort.Strings(hubs)
+	return hubs
 }
 
-func (m *MigrationController) generateKlusterletConfig(req ctrl.Request) *klusterletv1alpha1.KlusterletConfig {
+// generateKlusterletConfig points the klusterlet to the target hub, the klusterlet falls back to the source hub if it
+// can't connect to the target hub
+func (m *MigrationController) generateKlusterletConfig(toHub, fromHub string) *klusterletv1alpha1.KlusterletConfig {
 	return &klusterletv1alpha1.KlusterletConfig{
 		ObjectMeta: metav1.ObjectMeta{
-			Name: klusterletConfigNamePrefix + req.Namespace,
+			Name: klusterletConfigNamePrefix + toHub,
 		},
 		Spec: klusterletv1alpha1.KlusterletConfigSpec{
 			BootstrapKubeConfigs: operatorv1.BootstrapKubeConfigs{
@@ -213,9 +766,13 @@ func (m *MigrationController) generateKlusterletConfig(req ctrl.Request) *kluste
 				LocalSecrets: operatorv1.LocalSecretsConfig{
 					KubeConfigSecrets: []operatorv1.KubeConfigSecret{
 						{
-							Name: bootstrapSecretNamePrefix + req.Namespace,
+							Name: bootstrapSecretNamePrefix + toHub,
+						},
+						{
+							Name: bootstrapSecretNamePrefix + fromHub,
 						},
 					},
+					HubConnectionTimeoutSeconds: int32(hubConnectionTimeout.Seconds()),
 				},
 			},
 		},
@@ -223,7 +780,7 @@ func (m *MigrationController) generateKlusterletConfig(req ctrl.Request) *kluste
 }
 
 func (m *MigrationController) generateBootstrapSecret(kubeconfig *clientcmdapi.Config,
-	req ctrl.Request,
+	hub string,
 ) (*corev1.Secret, error) {
 	// Serialize the kubeconfig to YAML
 	kubeconfigBytes, err := clientcmd.Write(*kubeconfig)
@@ -232,7 +789,7 @@ func (m *MigrationController) generateBootstrapSecret(kubeconfig *clientcmdapi.C
 	}
 	return &corev1.Secret{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      bootstrapSecretNamePrefix + req.Namespace,
+			Name:      bootstrapSecretNamePrefix + hub,
 			Namespace: "multicluster-engine",
 		},
 		Data: map[string][]byte{
@@ -241,25 +798,31 @@ func (m *MigrationController) generateBootstrapSecret(kubeconfig *clientcmdapi.C
 	}, nil
 }
 
-func (m *MigrationController) generateKubeconfig(ctx context.Context, req ctrl.Request) (*clientcmdapi.Config, error) {
+// generateKubeconfig creates the kubeconfig of the hub with the token of the managed service account
+func (m *MigrationController) generateKubeconfig(ctx context.Context,
+	msaName, hub string,
+) (*clientcmdapi.Config, error) {
 	// get the secret which is generated by msa
 	desiredSecret := &corev1.Secret{}
 	if err := m.Client.Get(ctx, types.NamespacedName{
-		Name:      req.Name,
-		Namespace: req.Namespace,
+		Name:      msaName,
+		Namespace: hub,
 	}, desiredSecret); err != nil {
 		return nil, err
 	}
 	// fetch the managed cluster to get url
 	managedcluster := &clusterv1.ManagedCluster{}
 	if err := m.Client.Get(ctx, types.NamespacedName{
-		Name: req.Namespace,
+		Name: hub,
 	}, managedcluster); err != nil {
 		return nil, err
 	}
+	if len(managedcluster.Spec.ManagedClusterClientConfigs) == 0 {
+		return nil, fmt.Errorf("the url of the managed hub %s is not found", hub)
+	}
 
 	config := clientcmdapi.NewConfig()
-	config.Clusters[req.Namespace] = &clientcmdapi.Cluster{
+	config.Clusters[hub] = &clientcmdapi.Cluster{
 		Server:                   managedcluster.Spec.ManagedClusterClientConfigs[0].URL,
 		CertificateAuthorityData: desiredSecret.Data["ca.crt"],
 	}
@@ -267,7 +830,7 @@ func (m *MigrationController) generateKubeconfig(ctx context.Context, req ctrl.R
 		Token: string(desiredSecret.Data["token"]),
 	}
 	config.Contexts["default-context"] = &clientcmdapi.Context{
-		Cluster:  req.Namespace,
+		Cluster:  hub,
 		AuthInfo: "user",
 	}
 	config.CurrentContext = "default-context"
@@ -275,14 +838,16 @@ func (m *MigrationController) generateKubeconfig(ctx context.Context, req ctrl.R
 	return config, nil
 }
 
+// ensureManagedServiceAccount creates the managed service account on the hub, its token is used by the bootstrap
+// kubeconfig of the hub
 func (m *MigrationController) ensureManagedServiceAccount(ctx context.Context,
-	migration *migrationv1alpha1.ManagedClusterMigration,
+	migration *migrationv1alpha1.ManagedClusterMigration, hub string,
 ) error {
 	// create a desired msa
 	desiredMSA := &v1beta1.ManagedServiceAccount{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      migration.GetName(),
-			Namespace: migration.Spec.To,
+			Namespace: hub,
 			Labels: map[string]string{
 				"owner": strings.ToLower(constants.ManagedClusterMigrationKind),
 			},
@@ -300,7 +865,7 @@ func (m *MigrationController) ensureManagedServiceAccount(ctx context.Context,
 	existingMSA := &v1beta1.ManagedServiceAccount{}
 	err := m.Client.Get(ctx, types.NamespacedName{
 		Name:      migration.GetName(),
-		Namespace: migration.Spec.To,
+		Namespace: hub,
 	}, existingMSA)
 	if err != nil {
 		if apierrors.IsNotFound(err) {
@@ -312,12 +877,12 @@ func (m *MigrationController) ensureManagedServiceAccount(ctx context.Context,
 }
 
 func (m *MigrationController) deleteManagedServiceAccount(ctx context.Context,
-	migration *migrationv1alpha1.ManagedClusterMigration,
+	migration *migrationv1alpha1.ManagedClusterMigration, hub string,
 ) error {
 	msa := &v1beta1.ManagedServiceAccount{}
 	if err := m.Get(ctx, types.NamespacedName{
 		Name:      migration.Name,
-		Namespace: migration.Spec.To,
+		Namespace: hub,
 	}, msa); err != nil {
 		if apierrors.IsNotFound(err) {
 			return nil
@@ -327,74 +892,30 @@ func (m *MigrationController) deleteManagedServiceAccount(ctx context.Context,
 	return m.Delete(ctx, msa)
 }
 
-func (m *MigrationController) syncMigration(ctx context.Context,
-	migration *migrationv1alpha1.ManagedClusterMigration,
-	klusterletConfig *klusterletv1alpha1.KlusterletConfig,
+func (m *MigrationController) syncMigrationFrom(ctx context.Context, fromHub string,
+	managedClusterMigrationFromEvent *bundleevent.ManagedClusterMigrationFromEvent,
 ) error {
-	if migration.Spec.From != "" {
-		// send the migration event to migration.from managed hub
-		if err := m.syncMigrationFrom(ctx, migration.Spec.From,
-			migration.Spec.IncludedManagedClusters, klusterletConfig); err != nil {
-			return err
-		}
-	} else {
-		db := database.GetGorm()
-		managedClusterMap := make(map[string][]string)
-		rows, err := db.Raw(`SELECT leaf_hub_name, cluster_name FROM status.managed_clusters
-			WHERE cluster_name IN (?)`,
-			migration.Spec.IncludedManagedClusters).Rows()
-		if err != nil {
-			return fmt.Errorf("failed to get leaf hub name and managed clusters - %w", err)
-		}
-		defer rows.Close()
-		for rows.Next() {
-			var leafHubName, managedClusterName string
-			if err := rows.Scan(&leafHubName, &managedClusterName); err != nil {
-				return fmt.Errorf("failed to scan leaf hub name and managed cluster name - %w", err)
-			}
-			managedClusterMap[leafHubName] = append(managedClusterMap[leafHubName], managedClusterName)
-		}
-
-		// send the migration event to migration.from managed hub(s)
-		for leafHubName, managedClusters := range managedClusterMap {
-			if err := m.syncMigrationFrom(ctx, leafHubName, managedClusters, klusterletConfig); err != nil {
-				return err
-			}
-		}
-	}
-
-	// send the migration event to migration.to managed hub
-	return m.syncMigrationTo(ctx, migration)
-}
-
-func (m *MigrationController) syncMigrationFrom(ctx context.Context,
-	fromHub string, managedClusters []string,
-	klusterletConfig *klusterletv1alpha1.KlusterletConfig,
-) error {
-	managedClusterMigrationFromEvent := &bundleevent.ManagedClusterMigrationFromEvent{
-		ManagedClusters:  managedClusters,
-		BootstrapSecret:  m.BootstrapSecret,
-		KlusterletConfig: klusterletConfig,
-	}
-
 	payloadBytes, err := json.Marshal(managedClusterMigrationFromEvent)
 	if err != nil {
 		return fmt.Errorf("failed to marshal managed cluster migration from event(%v) - %w",
 			managedClusterMigrationFromEvent, err)
 	}
+	return m.sendEvent(ctx, constants.CloudEventTypeMigrationFrom, fromHub, payloadBytes)
+}
 
-	eventType := constants.CloudEventTypeMigrationFrom
-	evt := utils.ToCloudEvent(eventType, constants.CloudEventSourceGlobalHub, fromHub, payloadBytes)
+func (m *MigrationController) sendEvent(ctx context.Context, eventType, hub string, payload []byte) error {
+	evt := utils.ToCloudEvent(eventType, constants.CloudEventSourceGlobalHub, hub, payload)
 	if err := m.Producer.SendEvent(ctx, evt); err != nil {
 		return fmt.Errorf("failed to sync managedclustermigration event(%s) from source(%s) to destination(%s) - %w",
-			eventType, constants.CloudEventSourceGlobalHub, fromHub, err)
+			eventType, constants.CloudEventSourceGlobalHub, hub, err)
 	}
-
 	return nil
 }
 
+// syncMigrationTo authorizes the managed service account to register the managed clusters to the hub, the
+// klusterletaddonconfig is only sent to the target hub
 func (m *MigrationController) syncMigrationTo(ctx context.Context,
-	migration *migrationv1alpha1.ManagedClusterMigration,
+	migration *migrationv1alpha1.ManagedClusterMigration, hub string,
 ) error {
 	// default managedserviceaccount addon namespace
 	msaNamespace := "open-cluster-management-agent-addon"
@@ -408,12 +929,13 @@ func (m *MigrationController) syncMigrationTo(ctx context.Context,
 		msaNamespace = val
 	}
 	managedClusterMigrationToEvent := &bundleevent.ManagedClusterMigrationToEvent{
+		Stage:                                 bundleevent.MigrationStageDeploying,
 		ManagedServiceAccountName:             migration.Name,
 		ManagedServiceAccountInstallNamespace: msaNamespace,
 	}
 	// append klusterletAddonConfig if exists
 	klusterletAddonConfigStr, exists := migration.Annotations[constants.KlusterletAddonConfigAnnotation]
-	if exists {
+	if exists && hub == migration.Spec.To {
 		klusterletAddonConfig := &addonv1.KlusterletAddonConfig{}
 		if err := json.Unmarshal([]byte(klusterletAddonConfigStr), klusterletAddonConfig); err != nil {
 			return err
@@ -427,13 +949,6 @@ func (m *MigrationController) syncMigrationTo(ctx context.Context,
 			managedClusterMigrationToEvent, err)
 	}
 
-	// send the event to the destination managed hub
-	eventType := constants.CloudEventTypeMigrationTo
-	evt := utils.ToCloudEvent(eventType, constants.CloudEventSourceGlobalHub, migration.Spec.To, payloadToBytes)
-	if err := m.Producer.SendEvent(ctx, evt); err != nil {
-		return fmt.Errorf("failed to sync managedclustermigration event(%s) from source(%s) to destination(%s) - %w",
-			eventType, constants.CloudEventSourceGlobalHub, migration.Spec.To, err)
-	}
-
-	return nil
+	// send the event to the managed hub
+	return m.sendEvent(ctx, constants.CloudEventTypeMigrationTo, hub, payloadToBytes)
 }
@@ -0,0 +1,102 @@
+package archive
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"gorm.io/gorm"
+
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/configs"
+)
+
+const (
+	// FormatNDJSON is the gzip compressed newline-delimited JSON, each line is a row of the partition
+	FormatNDJSON = "ndjson"
+	// FormatParquet is the parquet file, each column of the partition is stored as an optional string
+	FormatParquet = "parquet"
+)
+
+// Store saves the exported partitions
+type Store interface {
+	// Put saves the local file as the object with the name
+	Put(ctx context.Context, name, file string) error
+}
+
+// Archiver exports the partition tables to the store before they're dropped
+type Archiver struct {
+	format string
+	store  Store
+}
+
+// NewArchiver returns nil if the archive isn't configured, the partitions are dropped without exporting
+func NewArchiver(config *configs.ArchiveConfig) (*Archiver, error) {
+	if config == nil || config.Format == "" {
+		return nil, nil
+	}
+	if config.Format != FormatNDJSON && config.Format != FormatParquet {
+		return nil, fmt.Errorf("unsupported archive format %s, it must be %s or %s", config.Format,
+			FormatNDJSON, FormatParquet)
+	}
+
+	var store Store
+	var err error
+	switch {
+	case config.S3Bucket != "":
+		store, err = NewS3Store(config)
+		if err != nil {
+			return nil, err
+		}
+	case config.Dir != "":
+		store = NewFileStore(config.Dir)
+	default:
+		return nil, fmt.Errorf("either the archive directory or the S3 bucket is required")
+	}
+	return &Archiver{format: config.Format, store: store}, nil
+}
+
+// Archive exports the partition, e.g. "event.managed_clusters_2024_01", and returns the name of the saved object,
+// e.g. "event/managed_clusters_2024_01.ndjson.gz"
+func (a *Archiver) Archive(ctx context.Context, db *gorm.DB, partition string) (string, int64, error) {
+	schema, table, ok := strings.Cut(partition, ".")
+	if !ok {
+		return "", 0, fmt.Errorf("invalid partition %s: it must be <schema>.<table>", partition)
+	}
+	name := schema + "/" + table + a.extension()
+
+	file, err := os.CreateTemp("", table+"-*")
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to create the temporary file: %w", err)
+	}
+	defer os.Remove(file.Name())
+
+	count, err := a.export(ctx, db, pgx.Identifier{schema, table}.Sanitize(), file)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to export the partition %s: %w", partition, err)
+	}
+
+	if err := a.store.Put(ctx, name, file.Name()); err != nil {
+		return "", 0, fmt.Errorf("failed to save the partition %s as %s: %w", partition, name, err)
+	}
+	return name, count, nil
+}
+
+func (a *Archiver) export(ctx context.Context, db *gorm.DB, table string, w io.Writer) (int64, error) {
+	if a.format == FormatParquet {
+		return exportParquet(ctx, db, table, w)
+	}
+	return exportNDJSON(ctx, db, table, w)
+}
+
+func (a *Archiver) extension() string {
+	if a.format == FormatParquet {
+		return ".parquet"
+	}
+	return ".ndjson.gz"
+}
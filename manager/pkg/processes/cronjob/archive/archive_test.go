@@ -0,0 +1,54 @@
+package archive
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/configs"
+)
+
+func TestNewArchiver(t *testing.T) {
+	archiver, err := NewArchiver(&configs.ArchiveConfig{})
+	require.NoError(t, err)
+	assert.Nil(t, archiver)
+
+	_, err = NewArchiver(&configs.ArchiveConfig{Format: "csv", Dir: t.TempDir()})
+	assert.Error(t, err)
+
+	_, err = NewArchiver(&configs.ArchiveConfig{Format: FormatParquet})
+	assert.Error(t, err)
+
+	archiver, err = NewArchiver(&configs.ArchiveConfig{Format: FormatParquet, Dir: t.TempDir()})
+	require.NoError(t, err)
+	assert.Equal(t, ".parquet", archiver.extension())
+	assert.IsType(t, &FileStore{}, archiver.store)
+
+	archiver, err = NewArchiver(&configs.ArchiveConfig{
+		Format:     FormatNDJSON,
+		S3Endpoint: "localhost:9000",
+		S3Bucket:   "global-hub",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, ".ndjson.gz", archiver.extension())
+	assert.IsType(t, &S3Store{}, archiver.store)
+}
+
+func TestFileStorePut(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "partition")
+	require.NoError(t, os.WriteFile(file, []byte("{}\n"), 0o600))
+
+	dir := t.TempDir()
+	store := NewFileStore(dir)
+	require.NoError(t, store.Put(context.Background(), "event/managed_clusters_2024_01.ndjson.gz", file))
+
+	content, err := os.ReadFile(filepath.Join(dir, "event", "managed_clusters_2024_01.ndjson.gz"))
+	require.NoError(t, err)
+	assert.Equal(t, "{}\n", string(content))
+	_, err = os.Stat(filepath.Join(dir, "event", "managed_clusters_2024_01.ndjson.gz.tmp"))
+	assert.True(t, os.IsNotExist(err))
+}
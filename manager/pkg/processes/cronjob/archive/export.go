@@ -0,0 +1,111 @@
+package archive
+
+import (
+	"compress/gzip"
+	"context"
+	"database/sql"
+	"fmt"
+	"io"
+
+	"github.com/parquet-go/parquet-go"
+	"gorm.io/gorm"
+)
+
+// the rows are written to the parquet file in batches
+const parquetBatchSize = 1000
+
+// exportNDJSON writes the rows of the table as the gzip compressed newline-delimited JSON, the JSON is encoded by the
+// database so that the column types, e.g. jsonb, are kept
+func exportNDJSON(ctx context.Context, db *gorm.DB, table string, w io.Writer) (int64, error) {
+	rows, err := db.WithContext(ctx).Raw(fmt.Sprintf("SELECT row_to_json(t)::text FROM %s t", table)).Rows()
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+
+	gz := gzip.NewWriter(w)
+	count := int64(0)
+	for rows.Next() {
+		var line []byte
+		if err := rows.Scan(&line); err != nil {
+			return count, err
+		}
+		if _, err := gz.Write(append(line, '\n')); err != nil {
+			return count, err
+		}
+		count++
+	}
+	if err := rows.Err(); err != nil {
+		return count, err
+	}
+	return count, gz.Close()
+}
+
+// exportParquet writes the rows of the table as the parquet file, the columns are stored as the optional strings in
+// the text representation of the database
+func exportParquet(ctx context.Context, db *gorm.DB, table string, w io.Writer) (int64, error) {
+	rows, err := db.WithContext(ctx).Raw(fmt.Sprintf("SELECT * FROM %s", table)).Rows()
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return 0, err
+	}
+	group := parquet.Group{}
+	for _, column := range columns {
+		group[column] = parquet.Optional(parquet.String())
+	}
+	schema := parquet.NewSchema(table, group)
+
+	// the columns of the parquet schema are sorted by the names
+	indexes := make([]int, len(columns))
+	for i, column := range columns {
+		leaf, ok := schema.Lookup(column)
+		if !ok {
+			return 0, fmt.Errorf("the column %s isn't found in the parquet schema", column)
+		}
+		indexes[i] = leaf.ColumnIndex
+	}
+
+	writer := parquet.NewWriter(w, schema, parquet.Compression(&parquet.Zstd))
+	values := make([]sql.NullString, len(columns))
+	dest := make([]any, len(columns))
+	for i := range values {
+		dest[i] = &values[i]
+	}
+
+	count := int64(0)
+	batch := make([]parquet.Row, 0, parquetBatchSize)
+	for rows.Next() {
+		if err := rows.Scan(dest...); err != nil {
+			return count, err
+		}
+		row := make(parquet.Row, len(columns))
+		for i, value := range values {
+			index := indexes[i]
+			if value.Valid {
+				row[index] = parquet.ByteArrayValue([]byte(value.String)).Level(0, 1, index)
+			} else {
+				row[index] = parquet.Value{}.Level(0, 0, index)
+			}
+		}
+		batch = append(batch, row)
+		if len(batch) == parquetBatchSize {
+			if _, err := writer.WriteRows(batch); err != nil {
+				return count, err
+			}
+			batch = batch[:0]
+		}
+		count++
+	}
+	if err := rows.Err(); err != nil {
+		return count, err
+	}
+	if _, err := writer.WriteRows(batch); err != nil {
+		return count, err
+	}
+	return count, writer.Close()
+}
@@ -0,0 +1,79 @@
+package archive
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/configs"
+)
+
+// FileStore saves the exported partitions into the local directory, e.g. the mount path of a persistent volume
+type FileStore struct {
+	dir string
+}
+
+func NewFileStore(dir string) *FileStore {
+	return &FileStore{dir: dir}
+}
+
+func (s *FileStore) Put(ctx context.Context, name, file string) error {
+	target := filepath.Join(s.dir, filepath.FromSlash(name))
+	if err := os.MkdirAll(filepath.Dir(target), 0o750); err != nil {
+		return err
+	}
+
+	src, err := os.Open(file)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	// write to a temporary file first, so that the partial file isn't left with the target name
+	tmp := target + ".tmp"
+	dst, err := os.OpenFile(tmp, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o640)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(dst, src)
+	if closeErr := dst.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = os.Remove(tmp)
+		return err
+	}
+	return os.Rename(tmp, target)
+}
+
+// S3Store uploads the exported partitions to the S3-compatible object store
+type S3Store struct {
+	client *minio.Client
+	bucket string
+	prefix string
+}
+
+func NewS3Store(config *configs.ArchiveConfig) (*S3Store, error) {
+	client, err := minio.New(config.S3Endpoint, &minio.Options{
+		Creds:  credentials.NewStaticV4(config.S3AccessKeyID, config.S3SecretAccessKey, ""),
+		Secure: !config.S3Insecure,
+		Region: config.S3Region,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the S3 client: %w", err)
+	}
+	return &S3Store{client: client, bucket: config.S3Bucket, prefix: config.S3Prefix}, nil
+}
+
+func (s *S3Store) Put(ctx context.Context, name, file string) error {
+	_, err := s.client.FPutObject(ctx, s.bucket, path.Join(s.prefix, name), file, minio.PutObjectOptions{
+		ContentType: "application/octet-stream",
+	})
+	return err
+}
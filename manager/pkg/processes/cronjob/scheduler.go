@@ -9,6 +9,7 @@ import (
 	ctrl "sigs.k8s.io/controller-runtime"
 
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/configs"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/processes/cronjob/archive"
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/processes/cronjob/task"
 	"github.com/stolostron/multicluster-global-hub/pkg/logger"
 )
@@ -43,21 +44,7 @@ func AddSchedulerToManager(ctx context.Context, mgr ctrl.Manager,
 	// The cluster may be in a different timezones, Here we choose to be consistent with the local GH timezone.
 	scheduler := gocron.NewScheduler(time.Local)
 
-	switch managerConfig.SchedulerInterval {
-	case EveryMonth:
-		scheduler = scheduler.Every(1).Month(1)
-	case EveryWeek:
-		scheduler = scheduler.Every(1).Week()
-	case EveryHour:
-		scheduler = scheduler.Every(1).Hour()
-	case EveryMinute:
-		scheduler = scheduler.Every(1).Minute()
-	case EverySecond:
-		scheduler = scheduler.Every(1).Second()
-	default:
-		scheduler = scheduler.Every(1).Day().At("00:00")
-	}
-	complianceHistoryJob, err := scheduler.
+	complianceHistoryJob, err := every(scheduler, managerConfig.SchedulerInterval).
 		Tag(task.LocalComplianceTaskName).
 		DoWithJobDetails(task.LocalComplianceHistory, ctx)
 	if err != nil {
@@ -65,10 +52,34 @@ func AddSchedulerToManager(ctx context.Context, mgr ctrl.Manager,
 	}
 	log.Infow("set SyncLocalCompliance job", "scheduleAt", complianceHistoryJob.ScheduledAtTime())
 
+	// the compliance of the global policies is only synced when the global resource is enabled
+	if managerConfig.EnableGlobalResource {
+		globalComplianceHistoryJob, err := every(scheduler, managerConfig.SchedulerInterval).
+			Tag(task.ComplianceTaskName).
+			DoWithJobDetails(task.ComplianceHistory, ctx)
+		if err != nil {
+			return err
+		}
+		log.Infow("set SyncCompliance job", "scheduleAt", globalComplianceHistoryJob.ScheduledAtTime())
+	}
+
+	archiver, err := archive.NewArchiver(&managerConfig.DatabaseConfig.Archive)
+	if err != nil {
+		return err
+	}
+	retentionPolicy := &task.RetentionPolicy{
+		Months:      managerConfig.DatabaseConfig.DataRetention,
+		TableMonths: managerConfig.DatabaseConfig.TableRetentions,
+		Archiver:    archiver,
+	}
+	for _, tableName := range retentionPolicy.UnknownTables() {
+		log.Warnw("the retention of the table is ignored, it isn't a partitioned or soft deleted table",
+			"table", tableName)
+	}
 	dataRetentionJob, err := scheduler.
 		Every(1).Month(1, 15, 28).At("00:00").
 		Tag(task.RetentionTaskName).
-		DoWithJobDetails(task.DataRetention, ctx, managerConfig.DatabaseConfig.DataRetention)
+		DoWithJobDetails(task.DataRetention, ctx, retentionPolicy)
 	if err != nil {
 		return err
 	}
@@ -82,11 +93,30 @@ func AddSchedulerToManager(ctx context.Context, mgr ctrl.Manager,
 		strings.Split(managerConfig.LaunchJobNames, ",")))
 }
 
+// every starts the schedule of a job with the interval, the job is scheduled daily at midnight by default
+func every(scheduler *gocron.Scheduler, interval string) *gocron.Scheduler {
+	switch interval {
+	case EveryMonth:
+		return scheduler.Every(1).Month(1)
+	case EveryWeek:
+		return scheduler.Every(1).Week()
+	case EveryHour:
+		return scheduler.Every(1).Hour()
+	case EveryMinute:
+		return scheduler.Every(1).Minute()
+	case EverySecond:
+		return scheduler.Every(1).Second()
+	default:
+		return scheduler.Every(1).Day().At("00:00")
+	}
+}
+
 func (s *GlobalHubJobScheduler) Start(ctx context.Context) error {
 	log.Infow("start job scheduler")
 	// Set the status of the job to 0 (success) when the job is started.
 	task.GlobalHubCronJobGaugeVec.WithLabelValues(task.RetentionTaskName).Set(0)
 	task.GlobalHubCronJobGaugeVec.WithLabelValues(task.LocalComplianceTaskName).Set(0)
+	task.GlobalHubCronJobGaugeVec.WithLabelValues(task.ComplianceTaskName).Set(0)
 	s.scheduler.StartAsync()
 	if err := s.ExecJobs(); err != nil {
 		return err
@@ -0,0 +1,70 @@
+package task
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-co-op/gocron"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
+	"github.com/stolostron/multicluster-global-hub/pkg/logger"
+)
+
+var ComplianceTaskName = "compliance-history"
+
+// the template of syncing a batch of status.compliance to history.compliance with the limit and offset
+const complianceSyncSQL = `
+	INSERT INTO history.compliance (
+		policy_id,
+		cluster_name,
+		leaf_hub_name,
+		cluster_id,
+		compliance,
+		compliance_date
+	)
+	(
+		SELECT
+			policy_id,
+			cluster_name,
+			leaf_hub_name,
+			cluster_id,
+			compliance,
+			CURRENT_DATE
+		FROM
+			status.compliance
+		ORDER BY policy_id, leaf_hub_name, cluster_name
+		LIMIT %d
+		OFFSET %d
+	)
+	ON CONFLICT (
+		leaf_hub_name,
+		policy_id,
+		cluster_name,
+		compliance_date
+	) DO NOTHING;
+`
+
+// ComplianceHistory snapshots the compliance of the global policies into history.compliance, so that each cluster
+// has a record for the day even if its compliance doesn't change. The changes within the day are recorded into
+// event.compliance_changes and merged into the history by the triggers of the database.
+func ComplianceHistory(ctx context.Context, job gocron.Job) {
+	start := time.Now()
+	taskLog := logger.ZapLogger(ComplianceTaskName).With("date", start.Format(DateFormat))
+	taskLog.Infow("start running", "currentRun", job.LastRun().Format(TimeFormat))
+
+	err := (&complianceSnapshot{
+		name:      ComplianceTaskName,
+		model:     &models.StatusCompliance{},
+		syncSQL:   complianceSyncSQL,
+		startTime: start,
+		log:       taskLog,
+	}).run(ctx)
+	if err != nil {
+		GlobalHubCronJobGaugeVec.WithLabelValues(ComplianceTaskName).Set(1)
+		taskLog.Error(err, "sync from status.compliance to history.compliance failed")
+		return
+	}
+	GlobalHubCronJobGaugeVec.WithLabelValues(ComplianceTaskName).Set(0)
+
+	taskLog.Infow("finish running", "nextRun", job.NextRun().Format(TimeFormat))
+}
@@ -3,11 +3,15 @@ package task
 import (
 	"context"
 	"fmt"
+	"slices"
+	"sort"
 	"strings"
 	"time"
 
 	"github.com/go-co-op/gocron"
+	"gorm.io/gorm/schema"
 
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/processes/cronjob/archive"
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/processes/hubmanagement"
 	"github.com/stolostron/multicluster-global-hub/pkg/database"
 	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
@@ -17,7 +21,8 @@ import (
 var (
 	// The main tasks of this job are:
 	// 1. create partition tables for days in the future, the partition table for the next month is created
-	// 2. delete partition tables that are no longer needed, the partition table for the previous 18 month is deleted
+	// 2. delete partition tables that are no longer needed, the partition tables before the retention of the table
+	//    (18 months by default) are deleted, they're exported by the archiver before deletion if it's configured
 	// 3. completely delete the soft deleted records from database after retainedMonths
 	RetentionTaskName = "data-retention"
 
@@ -39,11 +44,48 @@ var (
 		"event.local_root_policies",
 		"history.local_compliance",
 		"event.managed_clusters",
+		// the tables of the global policies only exist if the global resource is enabled
+		"event.compliance_changes",
+		"history.compliance",
 	}
 	retentionLog = logger.ZapLogger(RetentionTaskName)
 )
 
-func DataRetention(ctx context.Context, retentionMonth int, job gocron.Job) {
+// RetentionPolicy defines how long the data of the tables is kept in the database
+type RetentionPolicy struct {
+	// Months is the retention of the tables which aren't in the TableMonths
+	Months      int
+	TableMonths map[string]int
+	// Archiver exports the expired partitions before they're dropped, they're dropped directly if it's nil
+	Archiver *archive.Archiver
+}
+
+// months returns the retention months of the table, it should at least be 1 month, otherwise the current month
+// partition and records will be deleted
+func (p *RetentionPolicy) months(tableName string) int {
+	months := p.Months
+	if tableMonths, ok := p.TableMonths[tableName]; ok {
+		months = tableMonths
+	}
+	if months < 1 {
+		months = 1
+	}
+	return months
+}
+
+// UnknownTables returns the tables in the TableMonths which aren't managed by the data retention
+func (p *RetentionPolicy) UnknownTables() []string {
+	unknown := []string{}
+	for tableName := range p.TableMonths {
+		if !slices.Contains(PartitionTables, tableName) && !slices.Contains(RetentionTables, tableName) {
+			unknown = append(unknown, tableName)
+		}
+	}
+	sort.Strings(unknown)
+	return unknown
+}
+
+func DataRetention(ctx context.Context, policy *RetentionPolicy, job gocron.Job) {
 	now := time.Now()
 	currentMonth := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
 
@@ -66,9 +108,19 @@ func DataRetention(ctx context.Context, retentionMonth int, job gocron.Job) {
 	}()
 
 	createMonth := currentMonth.AddDate(0, 1, 0)
-	deleteMonth := currentMonth.AddDate(0, -(retentionMonth + 1), 0)
 	for _, tableName := range PartitionTables {
-		err = updatePartitionTables(tableName, createMonth, deleteMonth)
+		exists, e := tableExists(tableName)
+		if e != nil {
+			err = e
+			retentionLog.Error(err, "failed to check the partition table")
+			return
+		}
+		if !exists {
+			retentionLog.Info("skip the partition table which doesn't exist", "table", tableName)
+			continue
+		}
+		deleteMonth := currentMonth.AddDate(0, -(policy.months(tableName) + 1), 0)
+		err = updatePartitionTables(ctx, tableName, createMonth, deleteMonth, policy.Archiver)
 		if e := traceDataRetentionLog(tableName, currentMonth, err, true); e != nil {
 			retentionLog.Error(e, "failed to trace data retention log")
 		}
@@ -79,8 +131,8 @@ func DataRetention(ctx context.Context, retentionMonth int, job gocron.Job) {
 	}
 
 	// delete the soft deleted records from database
-	minTime := currentMonth.AddDate(0, -retentionMonth, 0)
 	for _, tableName := range RetentionTables {
+		minTime := currentMonth.AddDate(0, -policy.months(tableName), 0)
 		err = deleteExpiredRecords(tableName, minTime)
 		if e := traceDataRetentionLog(tableName, currentMonth, err, false); e != nil {
 			retentionLog.Error(e, "failed to trace data retention log")
@@ -90,16 +142,47 @@ func DataRetention(ctx context.Context, retentionMonth int, job gocron.Job) {
 			return
 		}
 	}
+	minTime := currentMonth.AddDate(0, -policy.months(""), 0)
 	err = db.Where("last_timestamp < ? AND status = ?", minTime, hubmanagement.HubInactive).
 		Delete(&models.LeafHubHeartbeat{}).Error
 	if err != nil {
 		retentionLog.Error(err, "failed to delete the expired leaf hub heartbeat")
 		return
 	}
+
+	// the events and the dead letters are only appended, the tables created by the migrations don't exist until the
+	// migrations are applied
+	for _, event := range []schema.Tabler{&models.LeafHubEvent{}, &models.SpecAudit{}, &models.DeadLetterEvent{}} {
+		exists, err := tableExists(event.TableName())
+		if err != nil {
+			retentionLog.Errorw("failed to check the event table", "table", event.TableName(), "error", err)
+			return
+		}
+		if !exists {
+			continue
+		}
+		if err = db.Where("created_at < ?", minTime).Delete(event).Error; err != nil {
+			retentionLog.Errorw("failed to delete the expired events", "table", event.TableName(), "error", err)
+			return
+		}
+	}
 	retentionLog.Info("finish running", "nextRun", job.NextRun().Format(TimeFormat))
 }
 
-func updatePartitionTables(tableName string, createTime, deleteTime time.Time) error {
+func tableExists(tableName string) (bool, error) {
+	var exists bool
+	err := database.GetGorm().Raw("SELECT to_regclass(?) IS NOT NULL", tableName).Scan(&exists).Error
+	if err != nil {
+		return false, fmt.Errorf("failed to check the table %s: %w", tableName, err)
+	}
+	return exists, nil
+}
+
+// updatePartitionTables creates the partition table for the createTime, and drops the partition tables of the
+// deleteTime and the months before. The expired partition is kept if it fails to be archived.
+func updatePartitionTables(ctx context.Context, tableName string, createTime, deleteTime time.Time,
+	archiver *archive.Archiver,
+) error {
 	db := database.GetGorm()
 
 	// create the partition tables for the next month
@@ -116,15 +199,62 @@ func updatePartitionTables(tableName string, createTime, deleteTime time.Time) e
 		"end", endTime.Format(DateFormat))
 
 	// delete the partition tables that are expired
-	deletePartitionTableName := fmt.Sprintf("%s_%s", tableName, deleteTime.Format(PartitionDateFormat))
-	deletionSql := fmt.Sprintf("DROP TABLE IF EXISTS %s", deletePartitionTableName)
-	if result := db.Exec(deletionSql); result.Error != nil {
-		return fmt.Errorf("failed to delete partition table %s: %w", tableName, result.Error)
+	partitions, err := expiredPartitions(tableName, deleteTime)
+	if err != nil {
+		return err
+	}
+	for _, deletePartitionTableName := range partitions {
+		if archiver != nil {
+			name, count, err := archiver.Archive(ctx, db, deletePartitionTableName)
+			if err != nil {
+				return err
+			}
+			retentionLog.Info("archive partition table", "table", deletePartitionTableName, "object", name,
+				"rows", count)
+		}
+		deletionSql := fmt.Sprintf("DROP TABLE IF EXISTS %s", deletePartitionTableName)
+		if result := db.Exec(deletionSql); result.Error != nil {
+			return fmt.Errorf("failed to delete partition table %s: %w", tableName, result.Error)
+		}
+		retentionLog.Info("delete partition table", "table", deletePartitionTableName)
 	}
-	retentionLog.Info("delete partition table", "table", deletePartitionTableName)
 	return nil
 }
 
+// expiredPartitions returns the partition tables, e.g. "event.managed_clusters_2024_01", of the deleteTime and the
+// months before
+func expiredPartitions(tableName string, deleteTime time.Time) ([]string, error) {
+	tables, err := listPartitions(tableName)
+	if err != nil {
+		return nil, err
+	}
+	deleteMonth := time.Date(deleteTime.Year(), deleteTime.Month(), 1, 0, 0, 0, 0, time.UTC)
+	expired := []string{}
+	for _, table := range tables {
+		month, ok := partitionMonth(table.Table)
+		if !ok {
+			retentionLog.Info("skip the partition table without the month suffix", "table", table.Table)
+			continue
+		}
+		if !month.After(deleteMonth) {
+			expired = append(expired, fmt.Sprintf("%s.%s", table.Schema, table.Table))
+		}
+	}
+	return expired, nil
+}
+
+// partitionMonth parses the month from the suffix of the partition table, e.g. "managed_clusters_2024_01"
+func partitionMonth(partition string) (time.Time, bool) {
+	if len(partition) <= len(PartitionDateFormat) {
+		return time.Time{}, false
+	}
+	month, err := time.Parse(PartitionDateFormat, partition[len(partition)-len(PartitionDateFormat):])
+	if err != nil {
+		return time.Time{}, false
+	}
+	return month, true
+}
+
 func deleteExpiredRecords(tableName string, minDate time.Time) error {
 	sql := fmt.Sprintf("DELETE FROM %s WHERE deleted_at < '%s'", tableName, minDate.Format(DateFormat))
 	db := database.GetGorm()
@@ -164,11 +294,24 @@ func traceDataRetentionLog(tableName string, startTime time.Time, err error, par
 }
 
 func getMinMaxPartitions(tableName string) (string, string, error) {
+	tables, err := listPartitions(tableName)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to get min/max partition table: %w", err)
+	}
+	if len(tables) < 1 {
+		retentionLog.Info("no partition table found", "table", tableName)
+		return "", "", nil
+	}
+	return tables[0].Table, tables[len(tables)-1].Table, nil
+}
+
+// listPartitions returns the partition tables of the table in order of the name
+func listPartitions(tableName string) ([]models.Table, error) {
 	db := database.GetGorm()
 
 	schemaTable := strings.Split(tableName, ".")
 	if len(schemaTable) != 2 {
-		return "", "", fmt.Errorf("invalid table name: %s", tableName)
+		return nil, fmt.Errorf("invalid table name: %s", tableName)
 	}
 	sql := fmt.Sprintf(`
 		SELECT
@@ -190,13 +333,9 @@ func getMinMaxPartitions(tableName string) (string, string, error) {
 	var tables []models.Table
 	result := db.Raw(sql).Find(&tables)
 	if result.Error != nil {
-		return "", "", fmt.Errorf("failed to get min/max partition table: %w", result.Error)
-	}
-	if len(tables) < 1 {
-		retentionLog.Info("no partition table found", "table", tableName)
-		return "", "", nil
+		return nil, fmt.Errorf("failed to list the partition tables of %s: %w", tableName, result.Error)
 	}
-	return tables[0].Table, tables[len(tables)-1].Table, nil
+	return tables, nil
 }
 
 func getMinDeletionTime(tableName string) (time.Time, error) {
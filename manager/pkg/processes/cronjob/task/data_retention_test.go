@@ -0,0 +1,38 @@
+package task
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRetentionPolicy(t *testing.T) {
+	policy := &RetentionPolicy{
+		Months: 18,
+		TableMonths: map[string]int{
+			"event.managed_clusters":   3,
+			"history.local_compliance": 24,
+			"status.managed_clusters":  0,
+			"event.unknown":            6,
+		},
+	}
+
+	assert.Equal(t, 3, policy.months("event.managed_clusters"))
+	assert.Equal(t, 24, policy.months("history.local_compliance"))
+	assert.Equal(t, 18, policy.months("event.local_policies"))
+	// keep at least the current month
+	assert.Equal(t, 1, policy.months("status.managed_clusters"))
+	assert.Equal(t, []string{"event.unknown"}, policy.UnknownTables())
+}
+
+func TestPartitionMonth(t *testing.T) {
+	month, ok := partitionMonth("managed_clusters_2024_01")
+	assert.True(t, ok)
+	assert.Equal(t, time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC), month)
+
+	_, ok = partitionMonth("managed_clusters_default")
+	assert.False(t, ok)
+	_, ok = partitionMonth("2024_01")
+	assert.False(t, ok)
+}
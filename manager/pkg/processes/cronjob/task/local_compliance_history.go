@@ -44,7 +44,13 @@ func LocalComplianceHistory(ctx context.Context, job gocron.Job) {
 		}
 	}()
 
-	err = snapshotLocalComplianceToHistory(ctx)
+	err = (&complianceSnapshot{
+		name:      LocalComplianceTaskName,
+		model:     &models.LocalStatusCompliance{},
+		syncSQL:   localComplianceSyncSQL,
+		startTime: startTime,
+		log:       log,
+	}).run(ctx)
 	if err != nil {
 		log.Error(err, "sync from local_status.compliance to history.local_compliance failed")
 		return
@@ -53,75 +59,86 @@ func LocalComplianceHistory(ctx context.Context, job gocron.Job) {
 	log.Infow("finish running", "nextRun", job.NextRun().Format(TimeFormat))
 }
 
-func snapshotLocalComplianceToHistory(ctx context.Context) (err error) {
+// the template of syncing a batch of local_status.compliance to history.local_compliance with the limit and offset
+const localComplianceSyncSQL = `
+	INSERT INTO history.local_compliance (
+		policy_id, 
+		cluster_id, 
+		leaf_hub_name, 
+		compliance, 
+		compliance_date
+	) 
+	(
+		SELECT 
+			policy_id, 
+			cluster_id, 
+			leaf_hub_name, 
+			compliance, 
+			(CURRENT_DATE - INTERVAL '0 day') 
+		FROM 
+				local_status.compliance
+		ORDER BY policy_id, cluster_id
+		LIMIT %d 
+		OFFSET %d
+	)
+	ON CONFLICT (
+			leaf_hub_name, 
+			policy_id, 
+			cluster_id, 
+			compliance_date
+	) DO NOTHING;
+`
+
+// complianceSnapshot syncs the compliance table of the model to its history table in batches
+type complianceSnapshot struct {
+	name  string
+	model interface{}
+	// syncSQL is the template of syncing a batch, the arguments are the limit and offset
+	syncSQL   string
+	startTime time.Time
+	log       *zap.SugaredLogger
+}
+
+func (s *complianceSnapshot) run(ctx context.Context) (err error) {
 	db := database.GetGorm()
 	var totalCount int64
-	err = db.Model(&models.LocalStatusCompliance{}).Count(&totalCount).Error
+	err = db.Model(s.model).Count(&totalCount).Error
 	if err != nil {
 		return err
 	}
-	log.Infow("The number of compliance need to be synchronized", "count", totalCount)
+	s.log.Infow("The number of compliance need to be synchronized", "count", totalCount)
 
 	insertedCount := int64(0)
 	for offset := int64(0); offset < totalCount; offset += batchSize {
-		batchInsertedCount, err := batchSync(ctx, totalCount, offset)
+		batchInsertedCount, err := s.batchSync(ctx, totalCount, offset)
 		if err != nil {
 			return err
 		}
 		insertedCount += batchInsertedCount
 	}
-	log.Infow("The number of compliance has been synchronized", "insertedCount", insertedCount)
+	s.log.Infow("The number of compliance has been synchronized", "insertedCount", insertedCount)
 	return nil
 }
 
-func batchSync(ctx context.Context, totalCount, offset int64) (int64, error) {
+func (s *complianceSnapshot) batchSync(ctx context.Context, totalCount, offset int64) (int64, error) {
 	batchInsert := int64(0)
 	var err error
 	defer func() {
-		e := traceComplianceHistoryLog(LocalComplianceTaskName, totalCount, offset, offset+batchInsert, startTime, err)
+		e := traceComplianceHistoryLog(s.name, totalCount, offset, offset+batchInsert, s.startTime, err)
 		if e != nil {
-			log.Info("trace local compliance job failed, retrying", "error", e)
+			s.log.Info("trace compliance job failed, retrying", "error", e)
 		}
 	}()
 	err = wait.PollUntilContextTimeout(ctx, 5*time.Second, 10*time.Minute, true,
 		func(ctx context.Context) (done bool, err error) {
-			batchSyncSQLTemplate := `
-				INSERT INTO history.local_compliance (
-					policy_id, 
-					cluster_id, 
-					leaf_hub_name, 
-					compliance, 
-					compliance_date
-				) 
-				(
-					SELECT 
-						policy_id, 
-						cluster_id, 
-						leaf_hub_name, 
-						compliance, 
-						(CURRENT_DATE - INTERVAL '0 day') 
-					FROM 
-							local_status.compliance
-					ORDER BY policy_id, cluster_id
-					LIMIT %d 
-					OFFSET %d
-				)
-				ON CONFLICT (
-						leaf_hub_name, 
-						policy_id, 
-						cluster_id, 
-						compliance_date
-				) DO NOTHING;
-			`
-
 			db := database.GetGorm()
-			ret := db.Exec(fmt.Sprintf(batchSyncSQLTemplate, batchSize, offset))
+			ret := db.Exec(fmt.Sprintf(s.syncSQL, batchSize, offset))
 			if ret.Error != nil {
-				log.Info("exec failed, retrying", "error", ret.Error)
+				s.log.Info("exec failed, retrying", "error", ret.Error)
 				return false, nil
 			}
 			batchInsert = ret.RowsAffected
-			log.Infow("sync compliance to history", "batch", batchSize, "batchInsert", batchInsert, "offset", offset)
+			s.log.Infow("sync compliance to history", "batch", batchSize, "batchInsert", batchInsert, "offset", offset)
 			return true, nil
 		})
 	return batchInsert, err
@@ -65,7 +65,7 @@ func (c *managerClusterAddonController) Reconcile(ctx context.Context, request c
 		log.Infof("inactive the agent when the global hub addon(%s) is deleted", request.Namespace)
 		err := hubStatusManager.inactive(ctx, []models.LeafHubHeartbeat{{
 			Name: request.Namespace,
-		}})
+		}}, ReasonAddonDeleted)
 		return ctrl.Result{}, err
 	} else if err != nil {
 		return ctrl.Result{Requeue: true, RequeueAfter: 5 * time.Second}, fmt.Errorf("failed to get addon: %w", err)
@@ -75,7 +75,7 @@ func (c *managerClusterAddonController) Reconcile(ctx context.Context, request c
 		log.Infof("inactive the agent when the global hub addon(%s) is deleting", addon.Namespace)
 		err := hubStatusManager.inactive(ctx, []models.LeafHubHeartbeat{{
 			Name: request.Namespace,
-		}})
+		}}, ReasonAddonDeleted)
 		if err != nil {
 			return ctrl.Result{}, err
 		}
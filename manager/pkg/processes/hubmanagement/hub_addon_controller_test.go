@@ -20,7 +20,7 @@ import (
 // mockHubManagement implements a mock hub management process for testing.
 type mockHubManagement struct{}
 
-func (m *mockHubManagement) inactive(ctx context.Context, hubs []models.LeafHubHeartbeat) error {
+func (m *mockHubManagement) inactive(ctx context.Context, hubs []models.LeafHubHeartbeat, reason string) error {
 	return nil
 }
 
@@ -11,9 +11,12 @@ import (
 
 	"go.uber.org/zap"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
 	"k8s.io/apimachinery/pkg/util/wait"
 	ctrl "sigs.k8s.io/controller-runtime"
 
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/spec/specversion"
 	"github.com/stolostron/multicluster-global-hub/pkg/constants"
 	"github.com/stolostron/multicluster-global-hub/pkg/database"
 	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
@@ -24,18 +27,31 @@ import (
 )
 
 const (
-	HubActive   = "active"
+	HubActive = "active"
+	// HubUnreachable means the heartbeat of the hub is missing for the ActiveTimeout, the data of the hub is kept but
+	// it's stale until the hub is active again or the grace period is expired
+	HubUnreachable = "unreachable"
+	// HubInactive means the hub is unreachable over the grace period or the agent addon is deleted, the data of the
+	// hub is removed
 	HubInactive = "inactive"
 
+	// the reasons of the hub status transitions
+	ReasonHeartbeatTimeout   = "HeartbeatTimeout"
+	ReasonGracePeriodExpired = "GracePeriodExpired"
+	ReasonHeartbeatResumed   = "HeartbeatResumed"
+	ReasonAddonDeleted       = "AddonDeleted"
+
 	// heartbeatInterval = 1 * time.Minute
-	ActiveTimeout = 5 * time.Minute // if heartbeat < (now - ActiveTimeout), then status = inactive, vice versa
+	ActiveTimeout = 5 * time.Minute // if heartbeat < (now - ActiveTimeout), then status = unreachable, vice versa
 	ProbeDuration = 2 * time.Minute // the duration to detect run the updating
+	// DefaultGracePeriod is how long the hub stays unreachable before it's inactive and its data is removed
+	DefaultGracePeriod = 30 * time.Minute
 )
 
 var hubStatusManager HubStatusManager
 
 type HubStatusManager interface {
-	inactive(ctx context.Context, hubs []models.LeafHubHeartbeat) error
+	inactive(ctx context.Context, hubs []models.LeafHubHeartbeat, reason string) error
 	reactive(ctx context.Context, hubs []models.LeafHubHeartbeat) error
 }
 
@@ -45,22 +61,27 @@ type HubManagement struct {
 	producer      transport.Producer
 	probeDuration time.Duration
 	activeTimeout time.Duration
+	gracePeriod   time.Duration
 }
 
-func NewHubManagement(producer transport.Producer, probeDuration, activeTimeout time.Duration) *HubManagement {
+// NewHubManagement creates the hub management, the hub without heartbeat for the activeTimeout is unreachable, and
+// it's inactive after being unreachable for the gracePeriod. The hub is inactive directly if the gracePeriod is 0.
+func NewHubManagement(producer transport.Producer, probeDuration, activeTimeout, gracePeriod time.Duration,
+) *HubManagement {
 	return &HubManagement{
 		log:           logger.DefaultZapLogger(),
 		producer:      producer,
 		probeDuration: probeDuration,
 		activeTimeout: activeTimeout,
+		gracePeriod:   gracePeriod,
 	}
 }
 
-func AddHubManagement(mgr ctrl.Manager, producer transport.Producer) error {
+func AddHubManagement(mgr ctrl.Manager, producer transport.Producer, gracePeriod time.Duration) error {
 	if hubStatusManager != nil {
 		return nil
 	}
-	instance := NewHubManagement(producer, ProbeDuration, ActiveTimeout)
+	instance := NewHubManagement(producer, ProbeDuration, ActiveTimeout, gracePeriod)
 	if err := mgr.Add(instance); err != nil {
 		return err
 	}
@@ -100,18 +121,35 @@ func (h *HubManagement) Start(ctx context.Context) error {
 func (h *HubManagement) update(ctx context.Context) error {
 	thresholdTime := time.Now().Add(-h.activeTimeout)
 	db := database.GetGorm()
+
+	if h.gracePeriod > 0 {
+		var unreachableHubs []models.LeafHubHeartbeat
+		if err := db.Where("last_timestamp < ? AND status = ?", thresholdTime, HubActive).
+			Find(&unreachableHubs).Error; err != nil {
+			return err
+		}
+		if err := h.unreachable(unreachableHubs); err != nil {
+			return fmt.Errorf("failed to mark hubs unreachable %v", err)
+		}
+	}
+
+	// the hub is inactive once the heartbeat is missing for the active timeout and the grace period
 	var expiredHubs []models.LeafHubHeartbeat
-	if err := db.Where("last_timestamp < ? AND status = ?", thresholdTime, HubActive).
-		Find(&expiredHubs).Error; err != nil {
+	if err := db.Where("last_timestamp < ? AND status IN ?", thresholdTime.Add(-h.gracePeriod),
+		[]string{HubActive, HubUnreachable}).Find(&expiredHubs).Error; err != nil {
 		return err
 	}
-	if err := h.inactive(ctx, expiredHubs); err != nil {
+	reason := ReasonGracePeriodExpired
+	if h.gracePeriod == 0 {
+		reason = ReasonHeartbeatTimeout
+	}
+	if err := h.inactive(ctx, expiredHubs, reason); err != nil {
 		return fmt.Errorf("failed to inactive hubs %v", err)
 	}
 
 	var reactiveHubs []models.LeafHubHeartbeat
-	if err := db.Where("last_timestamp > ? AND status = ?", thresholdTime, HubInactive).
-		Find(&reactiveHubs).Error; err != nil {
+	if err := db.Where("last_timestamp > ? AND status IN ?", thresholdTime,
+		[]string{HubUnreachable, HubInactive}).Find(&reactiveHubs).Error; err != nil {
 		return err
 	}
 	if err := h.reactive(ctx, reactiveHubs); err != nil {
@@ -120,11 +158,39 @@ func (h *HubManagement) update(ctx context.Context) error {
 	return nil
 }
 
-func (h *HubManagement) inactive(ctx context.Context, hubs []models.LeafHubHeartbeat) error {
+// unreachable marks the hubs unreachable without removing their data, so that a short outage of the hub doesn't
+// remove its data from the dashboards.
+func (h *HubManagement) unreachable(hubs []models.LeafHubHeartbeat) error {
+	db := database.GetGorm()
+	for _, hub := range hubs {
+		err := db.Transaction(func(tx *gorm.DB) error {
+			err := transition(tx, hub.Name, HubUnreachable, ReasonHeartbeatTimeout,
+				fmt.Sprintf("no heartbeat since %s, the data is kept for the grace period %s",
+					hub.LastUpdateAt.Format(time.RFC3339), h.gracePeriod))
+			if err != nil {
+				return err
+			}
+			now := time.Now()
+			return markStale(tx, hub.Name, &now)
+		})
+		if err != nil {
+			return err
+		}
+		h.log.Infow("the hub is unreachable", "name", hub.Name, "lastHeartbeat", hub.LastUpdateAt)
+	}
+	return nil
+}
+
+func (h *HubManagement) inactive(ctx context.Context, hubs []models.LeafHubHeartbeat, reason string) error {
 	for _, hub := range hubs {
+		message := "the agent addon is deleted, the data of the hub is removed"
+		if reason != ReasonAddonDeleted {
+			message = fmt.Sprintf("no heartbeat since %s, the data of the hub is removed",
+				hub.LastUpdateAt.Format(time.RFC3339))
+		}
 		err := wait.PollUntilContextTimeout(ctx, 2*time.Second, 5*time.Minute, true,
 			func(ctx context.Context) (bool, error) {
-				if e := h.cleanup(hub.Name); e != nil {
+				if e := h.cleanup(hub.Name, reason, message); e != nil {
 					h.log.Infow("cleanup the hub resource failed, retrying...", "name", hub.Name, "err", e.Error())
 					return false, nil
 				}
@@ -133,11 +199,13 @@ func (h *HubManagement) inactive(ctx context.Context, hubs []models.LeafHubHeart
 		if err != nil {
 			return err
 		}
+		// the inactive hub receives the full spec bundles once it's reactive
+		specversion.Delete(hub.Name)
 	}
 	return nil
 }
 
-func (h *HubManagement) cleanup(hubName string) error {
+func (h *HubManagement) cleanup(hubName, reason, message string) error {
 	db := database.GetGorm()
 	return db.Transaction(func(tx *gorm.DB) error {
 		// soft delete the cluster
@@ -171,7 +239,7 @@ func (h *HubManagement) cleanup(hubName string) error {
 		}
 
 		// inactive the hub status
-		return tx.Model(&models.LeafHubHeartbeat{}).Where("leaf_hub_name = ?", hubName).Update("status", HubInactive).Error
+		return transition(tx, hubName, HubInactive, reason, message)
 	})
 }
 
@@ -186,7 +254,14 @@ func (h *HubManagement) reactive(ctx context.Context, hubs []models.LeafHubHeart
 					return false, nil
 				}
 				// reactive the batch hub status
-				e := db.Model(&models.LeafHubHeartbeat{}).Where("leaf_hub_name = ?", hub.Name).Update("status", HubActive).Error
+				e := db.Transaction(func(tx *gorm.DB) error {
+					err := transition(tx, hub.Name, HubActive, ReasonHeartbeatResumed,
+						fmt.Sprintf("the heartbeat is resumed at %s", hub.LastUpdateAt.Format(time.RFC3339)))
+					if err != nil {
+						return err
+					}
+					return markStale(tx, hub.Name, nil)
+				})
 				if e != nil {
 					h.log.Info("fail to reactive the hub, retrying...", "name", hub.Name, "err", e.Error())
 					return false, nil
@@ -200,6 +275,60 @@ func (h *HubManagement) reactive(ctx context.Context, hubs []models.LeafHubHeart
 	return nil
 }
 
+// transition updates the status of the hub with the reason, and records the transition as the hub event. Nothing is
+// recorded if the hub is already in the status.
+func transition(tx *gorm.DB, hubName, status, reason, message string) error {
+	var hubs []models.LeafHubHeartbeat
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("leaf_hub_name = ?", hubName).
+		Find(&hubs).Error; err != nil {
+		return err
+	}
+	if len(hubs) == 0 || hubs[0].Status == status {
+		return nil
+	}
+
+	now := time.Now()
+	err := tx.Model(&models.LeafHubHeartbeat{}).Where("leaf_hub_name = ?", hubName).Updates(map[string]any{
+		"status":               status,
+		"reason":               reason,
+		"last_transition_time": now,
+	}).Error
+	if err != nil {
+		return err
+	}
+	return tx.Create(&models.LeafHubEvent{
+		LeafHubName:    hubName,
+		PreviousStatus: hubs[0].Status,
+		Status:         status,
+		Reason:         reason,
+		Message:        message,
+		LastHeartbeat:  hubs[0].LastUpdateAt,
+		CreatedAt:      now,
+	}).Error
+}
+
+// markStale flags the managed clusters, policies and compliance of the hub as stale since the time, or clears the flag
+// if the time is nil. Only the rows whose flag changes are updated, so the triggers of the tables aren't fired for the
+// others.
+func markStale(tx *gorm.DB, hubName string, staleAt *time.Time) error {
+	condition := "stale_at IS NULL"
+	if staleAt == nil {
+		condition = "stale_at IS NOT NULL"
+	}
+	for _, table := range []schema.Tabler{
+		&models.ManagedCluster{},
+		&models.LocalSpecPolicy{},
+		&models.LocalStatusCompliance{},
+	} {
+		err := tx.Table(table.TableName()).Where("leaf_hub_name = ? AND "+condition, hubName).
+			Update("stale_at", staleAt).Error
+		if err != nil {
+			return fmt.Errorf("failed to update the stale flag of %s: %w", table.TableName(), err)
+		}
+	}
+	return nil
+}
+
 func (h *HubManagement) resync(ctx context.Context, hubName string) error {
 	resyncResources := []string{
 		string(enum.HubClusterInfoType),
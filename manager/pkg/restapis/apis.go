@@ -9,16 +9,25 @@ import (
 	"fmt"
 	"net/http"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+	policyv1 "open-cluster-management.io/governance-policy-propagator/api/v1"
+	appsv1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1"
 	ctrl "sigs.k8s.io/controller-runtime"
 
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/restapis/authentication"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/restapis/authorization"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/restapis/deadletters"
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/restapis/managedclusters"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/restapis/managedhubs"
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/restapis/policies"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/restapis/resources"
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/restapis/subscriptions"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/restapis/watcher"
 	"github.com/stolostron/multicluster-global-hub/pkg/logger"
 )
 
@@ -30,6 +39,15 @@ type RestApiServerConfig struct {
 	ClusterAPIURL          string
 	ClusterAPICABundlePath string
 	ServerBasePath         string
+	// RBACConfigPath is the file mapping the users and groups to the resources, the SubjectAccessReview of the global
+	// hub cluster is used if it's empty
+	RBACConfigPath string
+	// Authorizer checks the routes for the authenticated user, the routes aren't authorized if it's nil
+	Authorizer authorization.Authorizer
+	// WatchRetention is how long the resource changes are kept for resuming the watches
+	WatchRetention time.Duration
+	// WatchBroker wakes up the watches on the database notifications, the watches only resync periodically if it's nil
+	WatchBroker *watcher.Broker
 }
 
 // NeedLeaderElection implements the LeaderElectionRunnable interface, which indicates
@@ -61,11 +79,30 @@ func readCertificateAuthority(nonK8sAPIServerConfig *RestApiServerConfig) ([]byt
 
 // AddRestApiServer adds the non-k8s-api-server to the Manager.
 func AddRestApiServer(mgr ctrl.Manager, restApiConfig *RestApiServerConfig) error {
+	if restApiConfig.Authorizer == nil && restApiConfig.ClusterAPIURL != "" {
+		listHubs := authorization.NewHubLister(mgr.GetClient())
+		if restApiConfig.RBACConfigPath != "" {
+			rbacConfig, err := authorization.LoadRBACConfig(restApiConfig.RBACConfigPath)
+			if err != nil {
+				return err
+			}
+			restApiConfig.Authorizer = authorization.NewRBACAuthorizer(rbacConfig, listHubs)
+		} else {
+			restApiConfig.Authorizer = authorization.NewSubjectAccessReviewAuthorizer(mgr.GetClient(), listHubs)
+		}
+	}
+
 	router, err := SetupRouter(restApiConfig)
 	if err != nil {
 		return err
 	}
 
+	if restApiConfig.WatchBroker != nil {
+		if err := mgr.Add(restApiConfig.WatchBroker); err != nil {
+			return fmt.Errorf("failed to add the watch broker to the manager: %w", err)
+		}
+	}
+
 	err = mgr.Add(&restApiServer{
 		log: logger.ZapLogger("restapi-server"),
 		svr: &http.Server{
@@ -110,14 +147,45 @@ func SetupRouter(nonK8sAPIServerConfig *RestApiServerConfig) (*gin.Engine, error
 		router.Use(authentication.Authentication(nonK8sAPIServerConfig.ClusterAPIURL, clusterAPICABundle))
 	}
 
+	// authorize the routes for the authenticated user
+	authorize := func(group, resource, verb string, hubScoped bool) gin.HandlerFunc {
+		if nonK8sAPIServerConfig.Authorizer == nil || nonK8sAPIServerConfig.ClusterAPIURL == "" {
+			return func(ginCtx *gin.Context) { ginCtx.Next() }
+		}
+		resource, subresource, _ := strings.Cut(resource, "/")
+		return authorization.Authorization(nonK8sAPIServerConfig.Authorizer, authorization.Attributes{
+			Group:       group,
+			Resource:    resource,
+			Subresource: subresource,
+			Verb:        verb,
+			HubScoped:   hubScoped,
+		})
+	}
+	clusterGroup := clusterv1.GroupName
+	policyGroup := policyv1.GroupVersion.Group
+	appsGroup := appsv1.SchemeGroupVersion.Group
+
 	routerGroup := router.Group(nonK8sAPIServerConfig.ServerBasePath)
-	routerGroup.GET("/managedclusters", managedclusters.ListManagedClusters())
-	routerGroup.PATCH("/managedcluster/:clusterID",
+	routerGroup.GET("/managedclusters", authorize(clusterGroup, "managedclusters", "list", true),
+		managedclusters.ListManagedClusters(nonK8sAPIServerConfig.WatchBroker))
+	routerGroup.PATCH("/managedcluster/:clusterID", authorize(clusterGroup, "managedclusters", "patch", true),
 		managedclusters.PatchManagedCluster())
-	routerGroup.GET("/policies", policies.ListPolicies())
-	routerGroup.GET("/policy/:policyID/status", policies.GetPolicyStatus())
-	routerGroup.GET("/subscriptions", subscriptions.ListSubscriptions())
-	routerGroup.GET("/subscriptionreport/:subscriptionID", subscriptions.GetSubscriptionReport())
+	routerGroup.GET("/managedhubs", authorize(authorization.GlobalHubGroup, "managedhubs", "list", true),
+		managedhubs.ListManagedHubs())
+	routerGroup.GET("/policies", authorize(policyGroup, "policies", "list", true),
+		policies.ListPolicies(nonK8sAPIServerConfig.WatchBroker))
+	routerGroup.GET("/policy/:policyID/status", authorize(policyGroup, "policies", "get", true),
+		policies.GetPolicyStatus())
+	routerGroup.GET("/subscriptions", authorize(appsGroup, "subscriptions", "list", true),
+		subscriptions.ListSubscriptions())
+	routerGroup.GET("/subscriptionreport/:subscriptionID",
+		authorize(appsGroup, "subscriptionreports", "get", true), subscriptions.GetSubscriptionReport())
+	routerGroup.GET("/deadletters", authorize(authorization.GlobalHubGroup, "deadletters", "list", true),
+		deadletters.ListDeadLetters())
+	routerGroup.POST("/deadletter/:deadLetterID/replay",
+		authorize(authorization.GlobalHubGroup, "deadletters/replay", "create", true), deadletters.ReplayDeadLetter())
+	routerGroup.GET("/resources", authorize(authorization.GlobalHubGroup, "resources", "list", true),
+		resources.ListResources())
 
 	return router, nil
 }
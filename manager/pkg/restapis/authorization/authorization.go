@@ -0,0 +1,134 @@
+// Copyright (c) 2025 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package authorization
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/restapis/authentication"
+)
+
+const (
+	// HubScopeKey - the key for the leaf hubs which the caller can see in context.
+	HubScopeKey = "hubScope"
+	// GlobalHubGroup is the API group of the resources which only exist in the global hub, e.g. the dead letters
+	GlobalHubGroup = "global-hub.open-cluster-management.io"
+)
+
+// Attributes is the resource and verb checked for a route.
+type Attributes struct {
+	Group       string
+	Resource    string
+	Subresource string
+	Verb        string
+	// HubScoped means the results of the route belong to the leaf hubs, so the caller who can only see some of the
+	// hubs is allowed and the results are filtered by the hubs
+	HubScoped bool
+}
+
+// User is the authenticated user resolved by the authentication middleware.
+type User struct {
+	Name   string
+	Groups []string
+}
+
+// Authorizer decides whether the user can access the resource.
+type Authorizer interface {
+	// Authorize returns true if the user can perform the verb on the resource of all the leaf hubs
+	Authorize(ctx context.Context, user User, attrs Attributes) (bool, error)
+	// VisibleHubs returns the leaf hubs which the user can see, by the hubs or the cluster sets of the hubs
+	VisibleHubs(ctx context.Context, user User, attrs Attributes) ([]string, error)
+}
+
+// HubScope is the leaf hubs which the caller can see, the nil scope means all the hubs.
+type HubScope map[string]bool
+
+// Contains returns whether the caller can see the leaf hub.
+func (s HubScope) Contains(leafHubName string) bool {
+	return s == nil || s[leafHubName]
+}
+
+// Hubs returns the visible leaf hubs, it's nil if the caller can see all the hubs.
+func (s HubScope) Hubs() []string {
+	if s == nil {
+		return nil
+	}
+	hubs := make([]string, 0, len(s))
+	for hub := range s {
+		hubs = append(hubs, hub)
+	}
+	sort.Strings(hubs)
+	return hubs
+}
+
+// GetHubScope returns the leaf hubs which the caller can see, all the hubs are visible if the authorization is
+// disabled.
+func GetHubScope(ginCtx *gin.Context) HubScope {
+	if scope, ok := ginCtx.Get(HubScopeKey); ok {
+		return scope.(HubScope)
+	}
+	return nil
+}
+
+// Authorization middleware checks the route with the authorizer, it must follow the authentication middleware. The
+// "list" verb is checked as "watch" if the caller watches the resources.
+func Authorization(authorizer Authorizer, attrs Attributes) gin.HandlerFunc {
+	return func(ginCtx *gin.Context) {
+		user, ok := authenticatedUser(ginCtx)
+		if !ok {
+			ginCtx.AbortWithStatus(http.StatusUnauthorized)
+			return
+		}
+		if _, watch := ginCtx.GetQuery("watch"); watch && attrs.Verb == "list" {
+			attrs.Verb = "watch"
+		}
+
+		ctx := ginCtx.Request.Context()
+		allowed, err := authorizer.Authorize(ctx, user, attrs)
+		if err != nil {
+			fmt.Fprintf(gin.DefaultWriter, "failed to authorize the user %s: %v\n", user.Name, err)
+			ginCtx.AbortWithStatus(http.StatusInternalServerError)
+			return
+		}
+		if allowed {
+			ginCtx.Set(HubScopeKey, HubScope(nil))
+			ginCtx.Next()
+			return
+		}
+
+		if attrs.HubScoped {
+			hubs, err := authorizer.VisibleHubs(ctx, user, attrs)
+			if err != nil {
+				fmt.Fprintf(gin.DefaultWriter, "failed to get the visible hubs of the user %s: %v\n", user.Name, err)
+				ginCtx.AbortWithStatus(http.StatusInternalServerError)
+				return
+			}
+			if len(hubs) > 0 {
+				scope := HubScope{}
+				for _, hub := range hubs {
+					scope[hub] = true
+				}
+				ginCtx.Set(HubScopeKey, scope)
+				ginCtx.Next()
+				return
+			}
+		}
+
+		fmt.Fprintf(gin.DefaultWriter, "the user %s is forbidden to %s the %s\n", user.Name, attrs.Verb, attrs.Resource)
+		ginCtx.AbortWithStatus(http.StatusForbidden)
+	}
+}
+
+func authenticatedUser(ginCtx *gin.Context) (User, bool) {
+	name := ginCtx.GetString(authentication.UserKey)
+	if name == "" {
+		return User{}, false
+	}
+	return User{Name: name, Groups: ginCtx.GetStringSlice(authentication.GroupsKey)}, true
+}
@@ -0,0 +1,174 @@
+// Copyright (c) 2025 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package authorization
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+	clusterv1beta2 "open-cluster-management.io/api/cluster/v1beta2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/restapis/authentication"
+)
+
+var listHubs HubLister = func(ctx context.Context) (map[string]string, error) {
+	return map[string]string{"hub1": "team-a", "hub2": "team-b", "hub3": ""}, nil
+}
+
+var clusterListAttrs = Attributes{Group: clusterv1.GroupName, Resource: "managedclusters", Verb: "list", HubScoped: true}
+
+func TestAuthorization(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	authorizer := NewRBACAuthorizer(&RBACConfig{Rules: []RBACRule{
+		{Users: []string{"admin"}, Resources: []string{Wildcard}, Verbs: []string{Wildcard}},
+		{Groups: []string{"team-a"}, Resources: []string{"managedclusters"}, Verbs: []string{"list"}, ClusterSets: []string{"team-a"}},
+	}}, listHubs)
+
+	serve := func(user string, groups []string, attrs Attributes, target string) (int, HubScope) {
+		var scope HubScope
+		router := gin.New()
+		router.GET("/", func(ginCtx *gin.Context) {
+			if user != "" {
+				ginCtx.Set(authentication.UserKey, user)
+				ginCtx.Set(authentication.GroupsKey, groups)
+			}
+			ginCtx.Next()
+		}, Authorization(authorizer, attrs), func(ginCtx *gin.Context) {
+			scope = GetHubScope(ginCtx)
+			ginCtx.Status(http.StatusOK)
+		})
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, target, nil))
+		return recorder.Code, scope
+	}
+
+	code, scope := serve("admin", nil, clusterListAttrs, "/")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Nil(t, scope)
+
+	// the results are filtered by the hubs of the cluster set
+	code, scope = serve("alice", []string{"team-a"}, clusterListAttrs, "/")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, []string{"hub1"}, scope.Hubs())
+	assert.False(t, scope.Contains("hub2"))
+
+	// the watch verb isn't granted
+	code, _ = serve("alice", []string{"team-a"}, clusterListAttrs, "/?watch")
+	assert.Equal(t, http.StatusForbidden, code)
+
+	// the route which isn't scoped by the hubs requires the permission of all the hubs
+	policyAttrs := Attributes{Group: "policy.open-cluster-management.io", Resource: "policies", Verb: "list"}
+	code, _ = serve("alice", []string{"team-a"}, policyAttrs, "/")
+	assert.Equal(t, http.StatusForbidden, code)
+
+	code, _ = serve("", nil, clusterListAttrs, "/")
+	assert.Equal(t, http.StatusUnauthorized, code)
+}
+
+func TestSubjectAccessReviewAuthorizer(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, authorizationv1.AddToScheme(scheme))
+	require.NoError(t, clusterv1.AddToScheme(scheme))
+
+	// the user can list the managed clusters of hub2 and the hubs in the cluster set team-a
+	allowed := map[string]bool{"managedclusters/hub2": true, "managedclustersets/team-a": true}
+	reviews := 0
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithInterceptorFuncs(interceptor.Funcs{
+		Create: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+			review := obj.(*authorizationv1.SubjectAccessReview)
+			attrs := review.Spec.ResourceAttributes
+			reviews++
+			review.Status.Allowed = review.Spec.User == "alice" && allowed[attrs.Resource+"/"+attrs.Name]
+			return nil
+		},
+	}).WithObjects(
+		&clusterv1.ManagedCluster{ObjectMeta: metav1.ObjectMeta{
+			Name: "hub1", Labels: map[string]string{clusterv1beta2.ClusterSetLabel: "team-a"},
+		}},
+		&clusterv1.ManagedCluster{ObjectMeta: metav1.ObjectMeta{Name: "hub2"}},
+		&clusterv1.ManagedCluster{ObjectMeta: metav1.ObjectMeta{
+			Name: "hub3", Labels: map[string]string{clusterv1beta2.ClusterSetLabel: "team-b"},
+		}},
+		&clusterv1.ManagedCluster{ObjectMeta: metav1.ObjectMeta{Name: "local-cluster"}},
+	).Build()
+
+	authorizer := NewSubjectAccessReviewAuthorizer(fakeClient, NewHubLister(fakeClient))
+	user := User{Name: "alice", Groups: []string{"team-a"}}
+	ok, err := authorizer.Authorize(context.Background(), user, clusterListAttrs)
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	hubs, err := authorizer.VisibleHubs(context.Background(), user, clusterListAttrs)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"hub1", "hub2"}, hubs)
+	// 1 for authorizing, 3 for the hubs and 2 for the cluster sets of the hubs which aren't allowed by name
+	assert.Equal(t, 6, reviews)
+
+	// the visible hubs are cached for the user
+	hubs, err = authorizer.VisibleHubs(context.Background(), user, clusterListAttrs)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"hub1", "hub2"}, hubs)
+	assert.Equal(t, 6, reviews)
+
+	// the user with the different groups isn't served by the cache
+	hubs, err = authorizer.VisibleHubs(context.Background(), User{Name: "alice", Groups: []string{"team-a,team-b"}},
+		clusterListAttrs)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"hub1", "hub2"}, hubs)
+	assert.Equal(t, 11, reviews)
+
+	// the hubs are reviewed again once the cache expires
+	sar := authorizer.(*subjectAccessReviewAuthorizer)
+	sar.now = func() time.Time { return time.Now().Add(VisibleHubsCacheTTL + time.Second) }
+	_, err = authorizer.VisibleHubs(context.Background(), user, clusterListAttrs)
+	require.NoError(t, err)
+	assert.Equal(t, 16, reviews)
+}
+
+func TestLoadRBACConfig(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "rbac.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(`
+rules:
+- groups: ["team-a"]
+  resources: ["managedclusters", "deadletters/replay"]
+  verbs: ["list", "create"]
+  leafHubs: ["hub3"]
+  clusterSets: ["team-a"]
+`), 0o600))
+	config, err := LoadRBACConfig(path)
+	require.NoError(t, err)
+
+	authorizer := NewRBACAuthorizer(config, listHubs)
+	user := User{Name: "bob", Groups: []string{"team-a"}}
+	hubs, err := authorizer.VisibleHubs(context.Background(), user, clusterListAttrs)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"hub1", "hub3"}, hubs)
+
+	hubs, err = authorizer.VisibleHubs(context.Background(), user, Attributes{
+		Group: GlobalHubGroup, Resource: "deadletters", Subresource: "replay", Verb: "create", HubScoped: true,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"hub1", "hub3"}, hubs)
+
+	// the rule must have the subjects
+	require.NoError(t, os.WriteFile(path, []byte(`rules: [{resources: ["*"], verbs: ["*"]}]`), 0o600))
+	_, err = LoadRBACConfig(path)
+	assert.Error(t, err)
+}
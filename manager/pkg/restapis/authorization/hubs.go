@@ -0,0 +1,37 @@
+// Copyright (c) 2025 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package authorization
+
+import (
+	"context"
+	"fmt"
+
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+	clusterv1beta2 "open-cluster-management.io/api/cluster/v1beta2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/constants"
+)
+
+// HubLister returns the cluster set of the leaf hubs by the hub name, the cluster set is empty if the hub doesn't
+// belong to any cluster set.
+type HubLister func(ctx context.Context) (map[string]string, error)
+
+// NewHubLister lists the leaf hubs from the managed clusters of the global hub cluster.
+func NewHubLister(c client.Client) HubLister {
+	return func(ctx context.Context) (map[string]string, error) {
+		clusters := &clusterv1.ManagedClusterList{}
+		if err := c.List(ctx, clusters); err != nil {
+			return nil, fmt.Errorf("failed to list the managed hubs: %w", err)
+		}
+		hubs := map[string]string{}
+		for _, cluster := range clusters.Items {
+			if cluster.Name == constants.LocalClusterName || cluster.Labels[constants.LocalClusterName] == "true" {
+				continue
+			}
+			hubs[cluster.Name] = cluster.Labels[clusterv1beta2.ClusterSetLabel]
+		}
+		return hubs, nil
+	}
+}
@@ -0,0 +1,126 @@
+// Copyright (c) 2025 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package authorization
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"slices"
+
+	"sigs.k8s.io/yaml"
+)
+
+// Wildcard matches all the resources or verbs in the RBAC rule
+const Wildcard = "*"
+
+// RBACConfig maps the users and groups to the resources they can access, it's used instead of the
+// SubjectAccessReview if the API server isn't the source of the permissions, e.g.
+//
+//	rules:
+//	- groups: ["team-a"]
+//	  resources: ["managedclusters"]
+//	  verbs: ["list", "watch"]
+//	  clusterSets: ["team-a"]
+type RBACConfig struct {
+	Rules []RBACRule `json:"rules"`
+}
+
+// RBACRule allows the users or groups to perform the verbs on the resources. The resources of all the leaf hubs are
+// allowed if neither the leaf hubs nor the cluster sets are specified.
+type RBACRule struct {
+	Users       []string `json:"users,omitempty"`
+	Groups      []string `json:"groups,omitempty"`
+	Resources   []string `json:"resources"`
+	Verbs       []string `json:"verbs"`
+	LeafHubs    []string `json:"leafHubs,omitempty"`
+	ClusterSets []string `json:"clusterSets,omitempty"`
+}
+
+func (r *RBACRule) matches(user User, attrs Attributes) bool {
+	resource := attrs.Resource
+	if attrs.Subresource != "" {
+		resource = attrs.Resource + "/" + attrs.Subresource
+	}
+	subjectMatched := slices.Contains(r.Users, user.Name) ||
+		slices.ContainsFunc(r.Groups, func(group string) bool { return slices.Contains(user.Groups, group) })
+	return subjectMatched &&
+		(slices.Contains(r.Resources, Wildcard) || slices.Contains(r.Resources, resource)) &&
+		(slices.Contains(r.Verbs, Wildcard) || slices.Contains(r.Verbs, attrs.Verb))
+}
+
+func (r *RBACRule) allHubs() bool {
+	return len(r.LeafHubs) == 0 && len(r.ClusterSets) == 0
+}
+
+// LoadRBACConfig reads the RBAC config from the YAML or JSON file.
+func LoadRBACConfig(path string) (*RBACConfig, error) {
+	data, err := os.ReadFile(path) // #nosec G304
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the RBAC config %s: %w", path, err)
+	}
+	config := &RBACConfig{}
+	if err := yaml.UnmarshalStrict(data, config); err != nil {
+		return nil, fmt.Errorf("failed to parse the RBAC config %s: %w", path, err)
+	}
+	for i, rule := range config.Rules {
+		if len(rule.Users) == 0 && len(rule.Groups) == 0 {
+			return nil, fmt.Errorf("the rule %d of the RBAC config %s has no users or groups", i, path)
+		}
+	}
+	return config, nil
+}
+
+type rbacAuthorizer struct {
+	config   *RBACConfig
+	listHubs HubLister
+}
+
+// NewRBACAuthorizer creates the authorizer with the RBAC config.
+func NewRBACAuthorizer(config *RBACConfig, listHubs HubLister) Authorizer {
+	return &rbacAuthorizer{config: config, listHubs: listHubs}
+}
+
+func (a *rbacAuthorizer) Authorize(ctx context.Context, user User, attrs Attributes) (bool, error) {
+	for _, rule := range a.config.Rules {
+		if rule.allHubs() && rule.matches(user, attrs) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (a *rbacAuthorizer) VisibleHubs(ctx context.Context, user User, attrs Attributes) ([]string, error) {
+	visibleHubs, clusterSets := map[string]bool{}, map[string]bool{}
+	for _, rule := range a.config.Rules {
+		if !rule.matches(user, attrs) {
+			continue
+		}
+		for _, hub := range rule.LeafHubs {
+			visibleHubs[hub] = true
+		}
+		for _, clusterSet := range rule.ClusterSets {
+			clusterSets[clusterSet] = true
+		}
+	}
+
+	if len(clusterSets) > 0 {
+		hubs, err := a.listHubs(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for hub, clusterSet := range hubs {
+			if clusterSets[clusterSet] {
+				visibleHubs[hub] = true
+			}
+		}
+	}
+
+	hubs := make([]string, 0, len(visibleHubs))
+	for hub := range visibleHubs {
+		hubs = append(hubs, hub)
+	}
+	slices.Sort(hubs)
+	return hubs, nil
+}
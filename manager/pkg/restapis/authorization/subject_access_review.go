@@ -0,0 +1,157 @@
+// Copyright (c) 2025 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package authorization
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// VisibleHubsCacheTTL is how long the visible hubs of the user are cached, each of the hubs and their cluster sets is
+// reviewed once it expires.
+const VisibleHubsCacheTTL = 30 * time.Second
+
+// subjectAccessReviewAuthorizer delegates the decisions to the RBAC of the global hub cluster. The caller can see a
+// leaf hub if the verb on the resource named by the hub, or on the managed cluster set of the hub is allowed.
+type subjectAccessReviewAuthorizer struct {
+	client   client.Client
+	listHubs HubLister
+
+	cacheTTL   time.Duration
+	cacheLock  sync.Mutex
+	cachedHubs map[visibleHubsKey]*visibleHubs
+	now        func() time.Time
+}
+
+// visibleHubsKey identifies the user and the route of the cached visible hubs, the groups are quoted so that the
+// different groups can't be joined into the same key
+type visibleHubsKey struct {
+	user   string
+	groups string
+	attrs  Attributes
+}
+
+type visibleHubs struct {
+	hubs       []string
+	expiration time.Time
+}
+
+// NewSubjectAccessReviewAuthorizer creates the authorizer with the SubjectAccessReview of the global hub cluster.
+func NewSubjectAccessReviewAuthorizer(c client.Client, listHubs HubLister) Authorizer {
+	return &subjectAccessReviewAuthorizer{
+		client:     c,
+		listHubs:   listHubs,
+		cacheTTL:   VisibleHubsCacheTTL,
+		cachedHubs: map[visibleHubsKey]*visibleHubs{},
+		now:        time.Now,
+	}
+}
+
+func (a *subjectAccessReviewAuthorizer) Authorize(ctx context.Context, user User, attrs Attributes) (bool, error) {
+	return a.review(ctx, user, &authorizationv1.ResourceAttributes{
+		Group:       attrs.Group,
+		Resource:    attrs.Resource,
+		Subresource: attrs.Subresource,
+		Verb:        attrs.Verb,
+	})
+}
+
+// VisibleHubs returns the cached visible hubs of the user, the hubs are reviewed if they're expired
+func (a *subjectAccessReviewAuthorizer) VisibleHubs(ctx context.Context, user User, attrs Attributes) (
+	[]string, error,
+) {
+	groups := slices.Clone(user.Groups)
+	slices.Sort(groups)
+	key := visibleHubsKey{user: user.Name, groups: fmt.Sprintf("%q", groups), attrs: attrs}
+
+	a.cacheLock.Lock()
+	now := a.now()
+	for cachedKey, cached := range a.cachedHubs {
+		if now.After(cached.expiration) {
+			delete(a.cachedHubs, cachedKey)
+		}
+	}
+	cached, found := a.cachedHubs[key]
+	a.cacheLock.Unlock()
+	if found {
+		return cached.hubs, nil
+	}
+
+	hubs, err := a.reviewHubs(ctx, user, attrs)
+	if err != nil {
+		return nil, err
+	}
+
+	a.cacheLock.Lock()
+	a.cachedHubs[key] = &visibleHubs{hubs: hubs, expiration: now.Add(a.cacheTTL)}
+	a.cacheLock.Unlock()
+	return hubs, nil
+}
+
+func (a *subjectAccessReviewAuthorizer) reviewHubs(ctx context.Context, user User, attrs Attributes) (
+	[]string, error,
+) {
+	hubs, err := a.listHubs(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	clusterSets := map[string]bool{} // the cluster sets which have been reviewed
+	visibleHubs := []string{}
+	for hub, clusterSet := range hubs {
+		allowed, err := a.review(ctx, user, &authorizationv1.ResourceAttributes{
+			Group:       attrs.Group,
+			Resource:    attrs.Resource,
+			Subresource: attrs.Subresource,
+			Verb:        attrs.Verb,
+			Name:        hub,
+		})
+		if err != nil {
+			return nil, err
+		}
+		if !allowed && clusterSet != "" {
+			reviewed, found := clusterSets[clusterSet]
+			if !found {
+				reviewed, err = a.review(ctx, user, &authorizationv1.ResourceAttributes{
+					Group:    clusterv1.GroupName,
+					Resource: "managedclustersets",
+					Verb:     attrs.Verb,
+					Name:     clusterSet,
+				})
+				if err != nil {
+					return nil, err
+				}
+				clusterSets[clusterSet] = reviewed
+			}
+			allowed = reviewed
+		}
+		if allowed {
+			visibleHubs = append(visibleHubs, hub)
+		}
+	}
+	return visibleHubs, nil
+}
+
+func (a *subjectAccessReviewAuthorizer) review(ctx context.Context, user User,
+	resourceAttributes *authorizationv1.ResourceAttributes,
+) (bool, error) {
+	review := &authorizationv1.SubjectAccessReview{
+		Spec: authorizationv1.SubjectAccessReviewSpec{
+			User:               user.Name,
+			Groups:             user.Groups,
+			ResourceAttributes: resourceAttributes,
+		},
+	}
+	if err := a.client.Create(ctx, review); err != nil {
+		return false, fmt.Errorf("failed to create the subject access review: %w", err)
+	}
+	return review.Status.Allowed, nil
+}
@@ -0,0 +1,73 @@
+// Copyright (c) 2025 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package deadletters
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/restapis/authorization"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/status/deadletter"
+)
+
+const serverInternalErrorMsg = "internal error"
+
+// ListDeadLetters godoc
+// @summary list dead letter events
+// @description list the status events which aren't handled by the manager within the retry budget
+// @accept json
+// @produce json
+// @param        hub         query     string  false  "list the dead letter events of the managed hub"
+// @param        replayed    query     bool    false  "include the replayed dead letter events"
+// @param        limit       query     int     false  "maximum dead letter event number to receive"
+// @success      200  {array}     models.DeadLetterEvent
+// @failure      400
+// @failure      401
+// @failure      403
+// @failure      500
+// @security     ApiKeyAuth
+// @router /deadletters [get]
+func ListDeadLetters() gin.HandlerFunc {
+	return func(ginCtx *gin.Context) {
+		includeReplayed := false
+		if replayed := ginCtx.Query("replayed"); replayed != "" {
+			var err error
+			if includeReplayed, err = strconv.ParseBool(replayed); err != nil {
+				ginCtx.String(http.StatusBadRequest, "invalid replayed: %s", replayed)
+				return
+			}
+		}
+
+		limit := 0
+		if limitStr := ginCtx.Query("limit"); limitStr != "" {
+			var err error
+			if limit, err = strconv.Atoi(limitStr); err != nil || limit < 0 {
+				ginCtx.String(http.StatusBadRequest, "invalid limit: %s", limitStr)
+				return
+			}
+		}
+
+		// only the dead letter events of the visible hubs are listed
+		scope := authorization.GetHubScope(ginCtx)
+		leafHubNames := scope.Hubs()
+		if hub := ginCtx.Query("hub"); hub != "" {
+			if !scope.Contains(hub) {
+				ginCtx.String(http.StatusForbidden, "the hub %s isn't visible", hub)
+				return
+			}
+			leafHubNames = []string{hub}
+		}
+
+		deadLetters, err := deadletter.List(ginCtx.Request.Context(), leafHubNames, includeReplayed, limit)
+		if err != nil {
+			fmt.Fprintf(gin.DefaultWriter, "failed to list the dead letter events: %v\n", err)
+			ginCtx.String(http.StatusInternalServerError, serverInternalErrorMsg)
+			return
+		}
+		ginCtx.JSON(http.StatusOK, deadLetters)
+	}
+}
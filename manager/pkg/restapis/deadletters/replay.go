@@ -0,0 +1,66 @@
+// Copyright (c) 2025 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package deadletters
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/restapis/authorization"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/status/conflator"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/status/deadletter"
+)
+
+// ReplayDeadLetter godoc
+// @summary replay dead letter event
+// @description handle the dead letter event again with the registered status handler
+// @accept json
+// @produce json
+// @param        deadLetterID    path    int    true    "Dead Letter Event ID"
+// @success      200
+// @failure      400
+// @failure      401
+// @failure      403
+// @failure      404
+// @failure      409
+// @failure      500
+// @failure      503
+// @security     ApiKeyAuth
+// @router /deadletter/{deadLetterID}/replay [post]
+func ReplayDeadLetter() gin.HandlerFunc {
+	return func(ginCtx *gin.Context) {
+		id, err := strconv.ParseInt(ginCtx.Param("deadLetterID"), 10, 64)
+		if err != nil {
+			ginCtx.String(http.StatusBadRequest, "invalid dead letter event id: %s", ginCtx.Param("deadLetterID"))
+			return
+		}
+
+		// the dead letter event of the hub which isn't visible is treated as not found
+		deadLetter, err := deadletter.Get(ginCtx.Request.Context(), id)
+		if err == nil && !authorization.GetHubScope(ginCtx).Contains(deadLetter.LeafHubName) {
+			err = gorm.ErrRecordNotFound
+		}
+		if err == nil {
+			err = deadletter.Replay(ginCtx.Request.Context(), id)
+		}
+		switch {
+		case err == nil:
+			ginCtx.String(http.StatusOK, "the dead letter event %d is replayed", id)
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			ginCtx.String(http.StatusNotFound, "the dead letter event %d isn't found", id)
+		case errors.Is(err, deadletter.ErrAlreadyReplayed), errors.Is(err, conflator.ErrEventSuperseded):
+			ginCtx.String(http.StatusConflict, err.Error())
+		case errors.Is(err, deadletter.ErrReplayUnavailable):
+			ginCtx.String(http.StatusServiceUnavailable, err.Error())
+		default:
+			fmt.Fprintf(gin.DefaultWriter, "failed to replay the dead letter event %d: %v\n", id, err)
+			ginCtx.String(http.StatusInternalServerError, err.Error())
+		}
+	}
+}
@@ -8,11 +8,9 @@ import (
 	"database/sql"
 	"encoding/json"
 	"fmt"
-	"io"
 	"net/http"
-	"time"
+	"strconv"
 
-	set "github.com/deckarep/golang-set"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	corev1 "k8s.io/api/core/v1"
@@ -21,16 +19,16 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
-	"k8s.io/apimachinery/pkg/runtime/schema"
 	clusterv1 "open-cluster-management.io/api/cluster/v1"
 
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/restapis/authorization"
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/restapis/util"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/restapis/watcher"
 	"github.com/stolostron/multicluster-global-hub/pkg/database"
 )
 
 const (
 	serverInternalErrorMsg                      = "internal error"
-	syncIntervalInSeconds                       = 4
 	onlyPatchOfLabelsIsImplemented              = "only patch of labels is currently implemented"
 	onlyAddOrRemoveAreImplemented               = "only add or remove operations are currently implemented"
 	noRowsAffectedByOptimisticConcurrencyUpdate = "no rows were affected by an optimistic-concurrency update query"
@@ -46,36 +44,36 @@ const (
 // @param        labelSelector    query     string  false  "list managed clusters by label selector"
 // @param        limit            query     int     false  "maximum managed cluster number to receive"
 // @param        continue         query     string  false  "continue token to request next request"
+// @param        watch            query     string  false  "watch the changes of the managed clusters"
+// @param        resourceVersion  query     string  false  "resource version to resume the watch from"
 // @success      200  {object}    clusterv1.ManagedClusterList
 // @failure      400
 // @failure      401
 // @failure      403
 // @failure      404
+// @failure      410
 // @failure      500
 // @failure      503
 // @security     ApiKeyAuth
 // @router /managedclusters [get]
-func ListManagedClusters() gin.HandlerFunc {
+func ListManagedClusters(broker *watcher.Broker) gin.HandlerFunc {
 	customResourceColumnDefinitions := util.GetCustomResourceColumnDefinitions(crdName,
 		clusterv1.GroupVersion.Version)
 
 	return func(ginCtx *gin.Context) {
-		labelSelector := ginCtx.Query("labelSelector")
-
-		selectorInSql := ""
-		var err error
-
-		if labelSelector != "" {
-			selectorInSql, err = util.ParseLabelSelector(labelSelector)
-			if err != nil {
-				fmt.Fprintf(gin.DefaultWriter, "failed to parse label selector: %s\n", err.Error())
-				return
-			}
+		selector, err := util.ParseLabelSelector(ginCtx.Query("labelSelector"))
+		if err != nil {
+			ginCtx.String(http.StatusBadRequest, err.Error())
+			fmt.Fprintf(gin.DefaultWriter, "failed to parse label selector: %s\n", err.Error())
+			return
 		}
 
-		fmt.Fprintf(gin.DefaultWriter, "parsed selector: %s\n", selectorInSql)
-
-		limit := ginCtx.Query("limit")
+		limit, err := util.ParseLimit(ginCtx.Query("limit"))
+		if err != nil {
+			ginCtx.String(http.StatusBadRequest, err.Error())
+			fmt.Fprintf(gin.DefaultWriter, "failed to parse limit: %s\n", err.Error())
+			return
+		}
 		fmt.Fprintf(gin.DefaultWriter, "limit: %v\n", limit)
 
 		lastManagedClusterName := ""
@@ -101,27 +99,24 @@ func ListManagedClusters() gin.HandlerFunc {
 			lastManagedClusterName,
 			lastManagedClusterUID)
 
-		// build query condition for paging
-		LastResourceCompareCondition := fmt.Sprintf(
-			"(payload -> 'metadata' ->> 'name', cluster_id) > ('%s', '%s') ",
-			lastManagedClusterName,
-			lastManagedClusterUID)
-
-		// managed cluster list query order by name and uid with limit if set
-		managedClusterListQuery := "SELECT payload FROM status.managed_clusters WHERE deleted_at is NULL AND " +
-			LastResourceCompareCondition +
-			selectorInSql +
-			" ORDER BY (payload -> 'metadata' ->> 'name', cluster_id)"
-
-		// add limit
-		if limit != "" {
-			managedClusterListQuery += fmt.Sprintf(" LIMIT %s", limit)
-		}
+		// managed cluster list query order by name and uid with limit if set, the paging starts after the last
+		// returned managed cluster
+		managedClusterListQuery, managedClusterListArgs := util.NewListQuery(
+			"SELECT payload FROM status.managed_clusters WHERE deleted_at is NULL",
+			"(payload -> 'metadata' ->> 'name', cluster_id)").
+			After(lastManagedClusterName, lastManagedClusterUID.String()).
+			WithSelector(selector).
+			WithLeafHubs(authorization.GetHubScope(ginCtx).Hubs()).
+			WithLimit(limit).
+			Build()
 
 		fmt.Fprintf(gin.DefaultWriter, "managedcluster list query: %v\n", managedClusterListQuery)
 
 		if _, watch := ginCtx.GetQuery("watch"); watch {
-			handleRowsForWatch(ginCtx, managedClusterListQuery)
+			watcher.Serve(ginCtx, broker, &managedClusterSource{
+				selector: selector,
+				hubScope: authorization.GetHubScope(ginCtx),
+			})
 			return
 		}
 
@@ -129,125 +124,29 @@ func ListManagedClusters() gin.HandlerFunc {
 		lastManagedClusterQuery := "SELECT payload FROM status.managed_clusters WHERE deleted_at is NULL " +
 			"ORDER BY (payload -> 'metadata' ->> 'name', cluster_id) DESC LIMIT 1"
 
-		handleRows(ginCtx, managedClusterListQuery, lastManagedClusterQuery,
+		handleRows(ginCtx, managedClusterListQuery, managedClusterListArgs, lastManagedClusterQuery,
 			customResourceColumnDefinitions)
 	}
 }
 
-func handleRowsForWatch(ginCtx *gin.Context, managedClusterListQuery string) {
-	writer := ginCtx.Writer
-	header := writer.Header()
-	header.Set("Transfer-Encoding", "chunked")
-	header.Set("Content-Type", "application/json")
-	writer.WriteHeader(http.StatusOK)
-
-	ticker := time.NewTicker(syncIntervalInSeconds * time.Second)
-
-	_, cancelContext := context.WithCancel(context.Background())
-	defer cancelContext()
-
-	// instead of holding the previously added managed clusters by memory
-	// and calculating the deleted clusters
-	preAddedManagedClusterNames := set.NewSet()
-
-	for {
-		select {
-		case <-writer.CloseNotify():
-			ticker.Stop()
-			cancelContext()
-
-			return
-		case <-ticker.C:
-			if ginCtx.Err() != nil || ginCtx.IsAborted() {
-				ticker.Stop()
-				cancelContext()
-
-				return
-			}
-
-			doHandleRowsForWatch(writer, managedClusterListQuery, preAddedManagedClusterNames)
-		}
-	}
-}
-
-func doHandleRowsForWatch(writer io.Writer, managedClusterListQuery string,
-	preAddedManagedClusterNames set.Set,
+func handleRows(ginCtx *gin.Context, managedClusterListQuery string, managedClusterListArgs []interface{},
+	lastManagedClusterQuery string, customResourceColumnDefinitions []apiextensionsv1.CustomResourceColumnDefinition,
 ) {
 	db := database.GetGorm()
-	rows, err := db.Raw(managedClusterListQuery).Rows()
-	if err != nil {
-		fmt.Fprintf(gin.DefaultWriter, "error in quering managed cluster list: %v\n", err)
-	}
-
-	addedManagedClusterNames := set.NewSet()
-	for rows.Next() {
-		managedCluster := &clusterv1.ManagedCluster{}
-
-		err := rows.Scan(managedCluster)
-		if err != nil {
-			continue
-		}
-
-		addedManagedClusterNames.Add(managedCluster.GetName())
-		if err := util.SendWatchEvent(&metav1.WatchEvent{
-			Type:   "ADDED",
-			Object: runtime.RawExtension{Object: managedCluster},
-		}, writer); err != nil {
-			fmt.Fprintf(gin.DefaultWriter, "error in sending watch event: %v\n", err)
-		}
-	}
-
-	managedClusterNamesToDelete := preAddedManagedClusterNames.Difference(addedManagedClusterNames)
-
-	managedClusterNamesToDeleteIterator := managedClusterNamesToDelete.Iterator()
-	for managedClusterNameToDelete := range managedClusterNamesToDeleteIterator.C {
-		managedClusterNameToDeleteAsString, ok := managedClusterNameToDelete.(string)
-		if !ok {
-			continue
-		}
 
-		preAddedManagedClusterNames.Remove(managedClusterNameToDeleteAsString)
-
-		managedClusterToDelete := &clusterv1.ManagedCluster{}
-		managedClusterToDelete.SetGroupVersionKind(schema.GroupVersionKind{
-			Group:   clusterv1.GroupVersion.Group,
-			Version: clusterv1.GroupVersion.Version,
-			Kind:    "ManagedCluster",
-		})
-		managedClusterToDelete.SetName(managedClusterNameToDeleteAsString)
-		if err := util.SendWatchEvent(&metav1.WatchEvent{
-			Type:   "DELETED",
-			Object: runtime.RawExtension{Object: managedClusterToDelete},
-		}, writer); err != nil {
-			fmt.Fprintf(gin.DefaultWriter, "error in sending watch event: %v\n", err)
-		}
-	}
-
-	managedClusterNamesToAdd := addedManagedClusterNames.Difference(preAddedManagedClusterNames)
-
-	managedClusterNamesToAddIterator := managedClusterNamesToAdd.Iterator()
-	for managedClusterNameToAdd := range managedClusterNamesToAddIterator.C {
-		managedClusterNameToAddAsString, ok := managedClusterNameToAdd.(string)
-		if !ok {
-			continue
-		}
-
-		preAddedManagedClusterNames.Add(managedClusterNameToAddAsString)
+	// the resource version of the list is read first, so the watch from it sends the changes during the listing
+	resourceVersion, err := watcher.CurrentVersion(ginCtx.Request.Context())
+	if err != nil {
+		ginCtx.String(http.StatusInternalServerError, serverInternalErrorMsg)
+		fmt.Fprintf(gin.DefaultWriter, "%v\n", err)
+		return
 	}
 
-	writer.(http.Flusher).Flush()
-}
-
-func handleRows(ginCtx *gin.Context, managedClusterListQuery, lastManagedClusterQuery string,
-	customResourceColumnDefinitions []apiextensionsv1.CustomResourceColumnDefinition,
-) {
-	db := database.GetGorm()
-
 	// load the lastManaged cluster
 	lastManagedCluster := &clusterv1.ManagedCluster{}
 
 	var payload []byte
-	err := db.Raw(lastManagedClusterQuery).Row().Scan(&payload)
+	err = db.Raw(lastManagedClusterQuery).Row().Scan(&payload)
 	if err != nil && err != sql.ErrNoRows {
 		ginCtx.String(http.StatusInternalServerError, serverInternalErrorMsg)
 		fmt.Fprintf(gin.DefaultWriter, "error in querying row: %v\n", err)
@@ -262,7 +161,7 @@ func handleRows(ginCtx *gin.Context, managedClusterListQuery, lastManagedCluster
 	}
 
 	// get hte managed cluster list
-	rows, err := db.Raw(managedClusterListQuery).Rows()
+	rows, err := db.Raw(managedClusterListQuery, managedClusterListArgs...).Rows()
 	if err != nil {
 		ginCtx.String(http.StatusInternalServerError, serverInternalErrorMsg)
 		fmt.Fprintf(gin.DefaultWriter, "error in querying managed clusters: %v\n", err)
@@ -274,7 +173,8 @@ func handleRows(ginCtx *gin.Context, managedClusterListQuery, lastManagedCluster
 			Kind:       "ManagedClusterList",
 			APIVersion: "cluster.open-cluster-management.io/v1",
 		},
-		Items: []clusterv1.ManagedCluster{},
+		ListMeta: metav1.ListMeta{ResourceVersion: strconv.FormatInt(resourceVersion, 10)},
+		Items:    []clusterv1.ManagedCluster{},
 	}
 	lastManagedClusterName, lastManagedClusterUID := "", ""
 	for rows.Next() {
@@ -13,6 +13,7 @@ import (
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
 
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/restapis/authorization"
 	"github.com/stolostron/multicluster-global-hub/pkg/database"
 	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
 )
@@ -62,6 +63,12 @@ func PatchManagedCluster() gin.HandlerFunc {
 		fmt.Fprintf(gin.DefaultWriter, "patch for managed cluster: %s -leaf hub: %s\n",
 			managedClusterName, leafHubName)
 
+		if !authorization.GetHubScope(ginCtx).Contains(leafHubName) {
+			ginCtx.String(http.StatusForbidden, "the managed cluster %s of the hub %s isn't visible",
+				managedClusterName, leafHubName)
+			return
+		}
+
 		var patches []patch
 
 		err := ginCtx.BindJSON(&patches)
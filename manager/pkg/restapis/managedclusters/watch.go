@@ -0,0 +1,96 @@
+// Copyright (c) 2025 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package managedclusters
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/types"
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/restapis/authorization"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/restapis/util"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/restapis/watcher"
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+)
+
+// managedClusterSource is the managed clusters matching the label selector in the visible hubs of the watcher
+type managedClusterSource struct {
+	selector *util.LabelSelector
+	hubScope authorization.HubScope
+}
+
+var _ watcher.Source = &managedClusterSource{}
+
+func (s *managedClusterSource) Table() string {
+	return "status.managed_clusters"
+}
+
+func (s *managedClusterSource) List(ctx context.Context) ([]watcher.Resource, error) {
+	query, args := util.NewListQuery(
+		"SELECT cluster_id, payload FROM status.managed_clusters WHERE deleted_at is NULL",
+		"(payload -> 'metadata' ->> 'name', cluster_id)").
+		WithSelector(s.selector).
+		WithLeafHubs(s.hubScope.Hubs()).
+		Build()
+	rows, err := database.GetGorm().WithContext(ctx).Raw(query, args...).Rows()
+	if err != nil {
+		return nil, fmt.Errorf("error in querying managed clusters: %w", err)
+	}
+	defer rows.Close()
+
+	resources := []watcher.Resource{}
+	for rows.Next() {
+		var clusterID string
+		var payload []byte
+		if err := rows.Scan(&clusterID, &payload); err != nil {
+			return nil, fmt.Errorf("error in scanning a managed cluster: %w", err)
+		}
+		managedCluster := &clusterv1.ManagedCluster{}
+		if err := json.Unmarshal(payload, managedCluster); err != nil {
+			return nil, fmt.Errorf("error to unmarshal payload to managedCluster: %w", err)
+		}
+		resources = append(resources, watcher.Resource{ID: clusterID, Object: managedCluster})
+	}
+	return resources, nil
+}
+
+func (s *managedClusterSource) Get(ctx context.Context, id string) (client.Object, bool, error) {
+	condition, args := s.selector.SQLCondition()
+	query := "SELECT leaf_hub_name, payload, (deleted_at IS NULL" + condition + ") " +
+		"FROM status.managed_clusters WHERE cluster_id = ?"
+
+	var leafHubName string
+	var payload []byte
+	var matched bool
+	err := database.GetGorm().WithContext(ctx).Raw(query, append(args, id)...).Row().
+		Scan(&leafHubName, &payload, &matched)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("error in querying the managed cluster %s: %w", id, err)
+	}
+	if !s.hubScope.Contains(leafHubName) {
+		return nil, false, nil
+	}
+
+	managedCluster := &clusterv1.ManagedCluster{}
+	if err := json.Unmarshal(payload, managedCluster); err != nil {
+		return nil, false, fmt.Errorf("error to unmarshal payload to managedCluster: %w", err)
+	}
+	return managedCluster, matched, nil
+}
+
+func (s *managedClusterSource) Tombstone(name types.NamespacedName) client.Object {
+	managedCluster := &clusterv1.ManagedCluster{}
+	managedCluster.SetGroupVersionKind(clusterv1.GroupVersion.WithKind("ManagedCluster"))
+	managedCluster.SetName(name.Name)
+	return managedCluster
+}
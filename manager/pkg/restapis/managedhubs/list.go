@@ -0,0 +1,73 @@
+// Copyright (c) 2025 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package managedhubs
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/restapis/authorization"
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
+)
+
+const serverInternalErrorMsg = "internal error"
+
+// ManagedHub is the lifecycle state of the managed hub. The data of the unreachable hub is kept but stale until the
+// hub is active again or it's inactive.
+type ManagedHub struct {
+	Name               string     `json:"name"`
+	Status             string     `json:"status"`
+	Reason             string     `json:"reason,omitempty"`
+	LastHeartbeat      time.Time  `json:"lastHeartbeat"`
+	LastTransitionTime *time.Time `json:"lastTransitionTime,omitempty"`
+}
+
+// ListManagedHubs godoc
+// @summary list managed hubs
+// @description list the managed hubs with the status of them: active, unreachable or inactive
+// @accept json
+// @produce json
+// @param        status      query     string  false  "list the managed hubs in the status"
+// @success      200  {array}     ManagedHub
+// @failure      401
+// @failure      403
+// @failure      500
+// @security     ApiKeyAuth
+// @router /managedhubs [get]
+func ListManagedHubs() gin.HandlerFunc {
+	return func(ginCtx *gin.Context) {
+		db := database.GetGorm().WithContext(ginCtx.Request.Context())
+
+		// only the visible hubs are listed
+		if leafHubNames := authorization.GetHubScope(ginCtx).Hubs(); leafHubNames != nil {
+			db = db.Where("leaf_hub_name IN ?", leafHubNames)
+		}
+		if status := ginCtx.Query("status"); status != "" {
+			db = db.Where("status = ?", status)
+		}
+
+		var heartbeats []models.LeafHubHeartbeat
+		if err := db.Order("leaf_hub_name").Find(&heartbeats).Error; err != nil {
+			fmt.Fprintf(gin.DefaultWriter, "failed to list the managed hubs: %v\n", err)
+			ginCtx.String(http.StatusInternalServerError, serverInternalErrorMsg)
+			return
+		}
+
+		hubs := make([]ManagedHub, 0, len(heartbeats))
+		for _, heartbeat := range heartbeats {
+			hubs = append(hubs, ManagedHub{
+				Name:               heartbeat.Name,
+				Status:             heartbeat.Status,
+				Reason:             heartbeat.Reason,
+				LastHeartbeat:      heartbeat.LastUpdateAt,
+				LastTransitionTime: heartbeat.LastTransitionTime,
+			})
+		}
+		ginCtx.JSON(http.StatusOK, hubs)
+	}
+}
@@ -15,6 +15,8 @@ const (
 	policyQuery           = `SELECT payload FROM spec.policies WHERE deleted = FALSE AND id = ?`
 	policyComplianceQuery = `SELECT cluster_name,leaf_hub_name,compliance FROM status.compliance
 		WHERE policy_id = ? ORDER BY leaf_hub_name, cluster_name`
+	// the policies are visible to the caller if they're propagated to the clusters of the visible hubs
+	policyOfHubsQuery  = `SELECT 1 FROM status.compliance WHERE policy_id = spec.policies.id`
 	policyMappingQuery = `SELECT p.payload -> 'metadata' ->> 'name' AS policy,
 								 pb.payload -> 'metadata' ->> 'name' AS binding,
 								 pr.payload -> 'metadata' ->> 'name' AS placementrule
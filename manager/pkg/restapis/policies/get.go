@@ -5,7 +5,9 @@ package policies
 
 import (
 	"context"
+	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"time"
@@ -19,6 +21,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	policyv1 "open-cluster-management.io/governance-policy-propagator/api/v1"
 
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/restapis/authorization"
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/restapis/util"
 	"github.com/stolostron/multicluster-global-hub/pkg/database"
 )
@@ -46,18 +49,36 @@ func GetPolicyStatus() gin.HandlerFunc {
 		fmt.Fprintf(gin.DefaultWriter, "policy compliance query with policy ID: %v\n", policyComplianceQuery)
 		fmt.Fprintf(gin.DefaultWriter, "policy&placementbinding&placementrule mapping query: %v\n", policyMappingQuery)
 
+		// the policy which isn't propagated to the clusters of the visible hubs is treated as not found
+		hubScope := authorization.GetHubScope(ginCtx)
+		if hubScope != nil {
+			var visible bool
+			err := database.GetGorm().Raw("SELECT EXISTS ("+policyOfHubsQuery+" AND leaf_hub_name IN ?) "+
+				"FROM spec.policies WHERE id = ?", hubScope.Hubs(), policyID).Row().Scan(&visible)
+			if err != nil && !errors.Is(err, sql.ErrNoRows) {
+				ginCtx.String(http.StatusInternalServerError, ServerInternalErrorMsg)
+				fmt.Fprintf(gin.DefaultWriter, QueryPolicyComplianceFailureFormatMsg, err)
+				return
+			}
+			if !visible {
+				ginCtx.String(http.StatusNotFound, "the policy %s isn't found", policyID)
+				return
+			}
+		}
+
 		if _, watch := ginCtx.GetQuery("watch"); watch {
 			handlePolicyForWatch(ginCtx, policyID, policyQuery,
-				policyMappingQuery, policyComplianceQuery)
+				policyMappingQuery, policyComplianceQuery, hubScope)
 			return
 		}
 
 		handlePolicy(ginCtx, policyID, policyQuery, policyMappingQuery, policyComplianceQuery,
-			customResourceColumnDefinitions)
+			customResourceColumnDefinitions, hubScope)
 	}
 }
 
 func handlePolicyForWatch(ginCtx *gin.Context, policyID, policyQuery, policyMappingQuery, policyComplianceQuery string,
+	hubScope authorization.HubScope,
 ) {
 	writer := ginCtx.Writer
 	header := writer.Header()
@@ -70,7 +91,8 @@ func handlePolicyForWatch(ginCtx *gin.Context, policyID, policyQuery, policyMapp
 	ctx, cancelContext := context.WithCancel(context.Background())
 	defer cancelContext()
 
-	preUnstrPolicy, err := queryPolicyStatus(policyID, policyQuery, policyMappingQuery, policyComplianceQuery)
+	preUnstrPolicy, err := queryPolicyStatus(policyID, policyQuery, policyMappingQuery, policyComplianceQuery,
+		hubScope)
 	if err != nil {
 		ginCtx.String(http.StatusInternalServerError, ServerInternalErrorMsg)
 	}
@@ -102,15 +124,17 @@ func handlePolicyForWatch(ginCtx *gin.Context, policyID, policyQuery, policyMapp
 			}
 
 			doHandlePolicyForWatch(ctx, writer, policyID, policyQuery, policyMappingQuery,
-				policyComplianceQuery, preUnstrPolicy)
+				policyComplianceQuery, preUnstrPolicy, hubScope)
 		}
 	}
 }
 
 func doHandlePolicyForWatch(ctx context.Context, writer gin.ResponseWriter, policyID,
 	policyQuery, policyMappingQuery, policyComplianceQuery string, preUnstrPolicy *unstructured.Unstructured,
+	hubScope authorization.HubScope,
 ) {
-	curUnstrPolicy, err := queryPolicyStatus(policyID, policyQuery, policyMappingQuery, policyComplianceQuery)
+	curUnstrPolicy, err := queryPolicyStatus(policyID, policyQuery, policyMappingQuery, policyComplianceQuery,
+		hubScope)
 	if err != nil {
 		fmt.Fprintf(gin.DefaultWriter, "error in getting policy status with policy ID(%s): %v", policyID, err)
 	}
@@ -137,9 +161,10 @@ func doHandlePolicyForWatch(ctx context.Context, writer gin.ResponseWriter, poli
 
 func handlePolicy(ginCtx *gin.Context, policyID, policyQuery, policyMappingQuery,
 	policyComplianceQuery string, customResourceColumnDefinitions []apiextensionsv1.CustomResourceColumnDefinition,
+	hubScope authorization.HubScope,
 ) {
 	unstrPolicy, err := queryPolicyStatus(policyID,
-		policyQuery, policyMappingQuery, policyComplianceQuery)
+		policyQuery, policyMappingQuery, policyComplianceQuery, hubScope)
 	if err != nil {
 		ginCtx.String(http.StatusInternalServerError, ServerInternalErrorMsg)
 	}
@@ -173,7 +198,7 @@ func handlePolicy(ginCtx *gin.Context, policyID, policyQuery, policyMappingQuery
 }
 
 func queryPolicyStatus(policyID, policyQuery, policyMappingQuery,
-	policyComplianceQuery string,
+	policyComplianceQuery string, hubScope authorization.HubScope,
 ) (*unstructured.Unstructured, error) {
 	var err error
 	policy := &policyv1.Policy{}
@@ -197,7 +222,7 @@ func queryPolicyStatus(policyID, policyQuery, policyMappingQuery,
 	}
 
 	compliancePerClusterStatuses, hasNonCompliantClusters, err := getComplianceStatus(
-		policyComplianceQuery, policyID)
+		policyComplianceQuery, policyID, hubScope)
 	if err != nil {
 		fmt.Fprintf(gin.DefaultWriter, QueryPolicyComplianceFailureFormatMsg, err)
 		return &unstructured.Unstructured{}, err
@@ -8,12 +8,9 @@ import (
 	"database/sql"
 	"encoding/json"
 	"fmt"
-	"io"
 	"net/http"
-	"strings"
-	"time"
+	"strconv"
 
-	set "github.com/deckarep/golang-set"
 	"github.com/gin-gonic/gin"
 	corev1 "k8s.io/api/core/v1"
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
@@ -21,10 +18,11 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
-	"k8s.io/apimachinery/pkg/runtime/schema"
 	policyv1 "open-cluster-management.io/governance-policy-propagator/api/v1"
 
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/restapis/authorization"
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/restapis/util"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/restapis/watcher"
 	"github.com/stolostron/multicluster-global-hub/pkg/database"
 	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
 )
@@ -58,32 +56,33 @@ var (
 // @param        labelSelector    query     string  false  "list policies by label selector"
 // @param        limit            query     int     false  "maximum policy number to receive"
 // @param        continue         query     string  false  "continue token to request next request"
+// @param        watch            query     string  false  "watch the changes of the policies and their compliance"
+// @param        resourceVersion  query     string  false  "resource version to resume the watch from"
 // @success      200  {object}    policyv1.PolicyList
 // @failure      400
 // @failure      401
 // @failure      403
 // @failure      404
+// @failure      410
 // @failure      500
 // @failure      503
 // @security     ApiKeyAuth
 // @router /policies [get]
-func ListPolicies() gin.HandlerFunc {
+func ListPolicies(broker *watcher.Broker) gin.HandlerFunc {
 	return func(ginCtx *gin.Context) {
-		labelSelector := ginCtx.Query("labelSelector")
-
-		selectorInSql := ""
-		if labelSelector != "" {
-			var err error
-			selectorInSql, err = util.ParseLabelSelector(labelSelector)
-			if err != nil {
-				fmt.Fprintf(gin.DefaultWriter, "failed to parse label selector: %s\n", err.Error())
-				return
-			}
+		selector, err := util.ParseLabelSelector(ginCtx.Query("labelSelector"))
+		if err != nil {
+			ginCtx.String(http.StatusBadRequest, err.Error())
+			fmt.Fprintf(gin.DefaultWriter, "failed to parse label selector: %s\n", err.Error())
+			return
 		}
 
-		fmt.Fprintf(gin.DefaultWriter, "parsed selector: %s\n", selectorInSql)
-
-		limit := ginCtx.Query("limit")
+		limit, err := util.ParseLimit(ginCtx.Query("limit"))
+		if err != nil {
+			ginCtx.String(http.StatusBadRequest, err.Error())
+			fmt.Fprintf(gin.DefaultWriter, "failed to parse limit: %s\n", err.Error())
+			return
+		}
 		fmt.Fprintf(gin.DefaultWriter, "limit: %v\n", limit)
 
 		continueToken := ginCtx.Query("continue")
@@ -92,7 +91,6 @@ func ListPolicies() gin.HandlerFunc {
 		if continueToken != "" {
 			fmt.Fprintf(gin.DefaultWriter, "continue: %v\n", continueToken)
 
-			var err error
 			lastPolicyName, lastPolicyUID, err = util.DecodeContinue(continueToken)
 			if err != nil {
 				fmt.Fprintf(gin.DefaultWriter, "failed to decode continue token: %s\n", err.Error())
@@ -105,22 +103,16 @@ func ListPolicies() gin.HandlerFunc {
 			lastPolicyName,
 			lastPolicyUID)
 
-		// build query condition for paging
-		LastResourceCompareCondition := fmt.Sprintf(
-			"(payload -> 'metadata' ->> 'name', payload -> 'metadata' ->> 'uid') > ('%s', '%s') ",
-			lastPolicyName,
-			lastPolicyUID)
-
-		// policy list query order by name and uid
-		policyListQuery := "SELECT id, payload FROM spec.policies WHERE deleted = FALSE AND " +
-			LastResourceCompareCondition +
-			selectorInSql +
-			" ORDER BY (payload -> 'metadata' ->> 'name', payload -> 'metadata' ->> 'uid')"
-
-		// add limit
-		if limit != "" {
-			policyListQuery += fmt.Sprintf(" LIMIT %s", limit)
-		}
+		// policy list query order by name and uid, the paging starts after the last returned policy
+		hubScope := authorization.GetHubScope(ginCtx)
+		policyListQuery, policyListArgs := util.NewListQuery(
+			"SELECT id, payload FROM spec.policies WHERE deleted = FALSE",
+			"(payload -> 'metadata' ->> 'name', payload -> 'metadata' ->> 'uid')").
+			After(lastPolicyName, lastPolicyUID).
+			WithSelector(selector).
+			WithStatusOfLeafHubs(policyOfHubsQuery, hubScope.Hubs()).
+			WithLimit(limit).
+			Build()
 
 		// last policy order by name and uid query
 		lastPolicyQuery := "SELECT id, payload FROM spec.policies WHERE deleted = FALSE " +
@@ -132,175 +124,33 @@ func ListPolicies() gin.HandlerFunc {
 		fmt.Fprintf(gin.DefaultWriter, "policy&placementbinding&placementrule mapping query: %v\n", policyMappingQuery)
 
 		if _, watch := ginCtx.GetQuery("watch"); watch {
-			handlePoliciesForWatch(ginCtx, policyListQuery, policyMappingQuery, policyComplianceQuery)
+			watcher.Serve(ginCtx, broker, &policySource{selector: selector, hubScope: hubScope})
 			return
 		}
 
-		handlePolicies(ginCtx, policyListQuery, lastPolicyQuery, policyMappingQuery,
-			policyComplianceQuery, customResourceColumnDefinitions)
-	}
-}
-
-func handlePoliciesForWatch(ginCtx *gin.Context, policyListQuery, policyMappingQuery,
-	policyComplianceQuery string,
-) {
-	writer := ginCtx.Writer
-	header := writer.Header()
-	header.Set("Transfer-Encoding", "chunked")
-	header.Set("Content-Type", "application/json")
-	writer.WriteHeader(http.StatusOK)
-
-	ticker := time.NewTicker(syncIntervalInSeconds * time.Second)
-
-	ctx, cancelContext := context.WithCancel(context.Background())
-	defer cancelContext()
-
-	preAddedPolicies := set.NewSet()
-
-	for {
-		select {
-		case <-writer.CloseNotify():
-			ticker.Stop()
-			cancelContext()
-
-			return
-		case <-ticker.C:
-			if ginCtx.Err() != nil || ginCtx.IsAborted() {
-				ticker.Stop()
-				cancelContext()
-
-				return
-			}
-
-			doHandlePoliciesForWatch(ctx, writer, policyListQuery, policyMappingQuery,
-				policyComplianceQuery, preAddedPolicies)
-		}
+		handlePolicies(ginCtx, policyListQuery, policyListArgs, lastPolicyQuery, policyMappingQuery,
+			policyComplianceQuery, customResourceColumnDefinitions, hubScope)
 	}
 }
 
-func doHandlePoliciesForWatch(ctx context.Context, writer gin.ResponseWriter,
-	policyListQuery, policyMappingQuery, policyComplianceQuery string, preAddedPolicies set.Set,
+func handlePolicies(ginCtx *gin.Context, policyListQuery string, policyListArgs []interface{}, lastPolicyQuery,
+	policyMappingQuery, policyComplianceQuery string,
+	customResourceColumnDefinitions []apiextensionsv1.CustomResourceColumnDefinition, hubScope authorization.HubScope,
 ) {
-	var err error
-	policyMatches, err = getPolicyMatches(policyMappingQuery)
-	if err != nil {
-		fmt.Fprintf(gin.DefaultWriter, QueryPolicyMappingFailureFormatMsg, err)
-	}
 	db := database.GetGorm()
-	policyRows, err := db.Raw(policyListQuery).Rows()
-	if err != nil {
-		fmt.Fprintf(gin.DefaultWriter, QueryPoliciesFailureFormatMsg, err)
-	}
-
-	defer policyRows.Close()
-
-	addedPolicies := set.NewSet()
-	for policyRows.Next() {
-		policyID, policy := "", &policyv1.Policy{}
-		var payload []byte
-		if err := policyRows.Scan(&policyID, &payload); err != nil {
-			fmt.Fprintf(gin.DefaultWriter, "error in scanning a policy: %v\n", err)
-			continue
-		}
-
-		if err := json.Unmarshal(payload, policy); err != nil {
-			fmt.Fprintf(gin.DefaultWriter, "error in scanning a policyPayload: %v\n", err)
-			continue
-		}
 
-		addedPolicies.Add(policyID + "/" + policy.GetName())
-		if err := sendPolicyWatchEvent(writer, policy, "ADDED",
-			policyComplianceQuery, policyID); err != nil {
-			fmt.Fprintf(gin.DefaultWriter, "error in sending watch event: %v\n", err)
-		}
-	}
-
-	policiesToDelete := preAddedPolicies.Difference(addedPolicies)
-
-	policiesToDeleteIterator := policiesToDelete.Iterator()
-	for policyToDelete := range policiesToDeleteIterator.C {
-		policyToDeleteAsString, ok := policyToDelete.(string)
-		if !ok {
-			continue
-		}
-
-		preAddedPolicies.Remove(policyToDeleteAsString)
-		policyToDeleteAsStrList := strings.Split(policyToDeleteAsString, "/")
-		policyID := policyToDeleteAsStrList[0]
-		policyNameName := policyToDeleteAsStrList[1]
-
-		policyInstanceToDelete := &policyv1.Policy{}
-		policyInstanceToDelete.SetGroupVersionKind(schema.GroupVersionKind{
-			Group:   policyv1.GroupVersion.Group,
-			Version: policyv1.GroupVersion.Version,
-			Kind:    "Policy",
-		})
-		policyInstanceToDelete.SetName(policyNameName)
-		if err := sendPolicyWatchEvent(writer, policyInstanceToDelete, "DELETED",
-			policyComplianceQuery, policyID); err != nil {
-			fmt.Fprintf(gin.DefaultWriter, "error in sending watch event: %v\n", err)
-		}
-	}
-
-	policiesToAdd := addedPolicies.Difference(preAddedPolicies)
-
-	policiesToAddIterator := policiesToAdd.Iterator()
-	for policyToAdd := range policiesToAddIterator.C {
-		policyToAddAsString, ok := policyToAdd.(string)
-		if !ok {
-			continue
-		}
-
-		preAddedPolicies.Add(policyToAddAsString)
-	}
-
-	writer.(http.Flusher).Flush()
-}
-
-func sendPolicyWatchEvent(writer io.Writer, policy *policyv1.Policy, eventType,
-	policyComplianceQuery, policyID string,
-) error {
-	// add policy placement
-	policy.Status.Placement = []*policyv1.Placement{}
-	for _, pm := range policyMatches {
-		if pm.policy == policy.GetName() {
-			policy.Status.Placement = append(policy.Status.Placement, &policyv1.Placement{
-				PlacementRule:    pm.placementrule,
-				PlacementBinding: pm.placementbinding,
-			})
-		}
-	}
-
-	compliancePerClusterStatuses, hasNonCompliantClusters, err := getComplianceStatus(
-		policyComplianceQuery, policyID)
+	// the resource version of the list is read first, so the watch from it sends the changes during the listing
+	resourceVersion, err := watcher.CurrentVersion(ginCtx.Request.Context())
 	if err != nil {
-		return fmt.Errorf("error in querying compliance status of a policy with UID: %s - %w", policyID, err)
-	}
-
-	policy.Status.Status = compliancePerClusterStatuses
-	policy.Status.ComplianceState = ""
-
-	if hasNonCompliantClusters {
-		policy.Status.ComplianceState = policyv1.NonCompliant
-	} else if len(compliancePerClusterStatuses) > 0 {
-		policy.Status.ComplianceState = policyv1.Compliant
+		ginCtx.String(http.StatusInternalServerError, ServerInternalErrorMsg)
+		fmt.Fprintf(gin.DefaultWriter, "%v\n", err)
+		return
 	}
 
-	return util.SendWatchEvent(&metav1.WatchEvent{
-		Type:   eventType,
-		Object: runtime.RawExtension{Object: policy},
-	}, writer)
-}
-
-func handlePolicies(ginCtx *gin.Context, policyListQuery, lastPolicyQuery,
-	policyMappingQuery, policyComplianceQuery string,
-	customResourceColumnDefinitions []apiextensionsv1.CustomResourceColumnDefinition,
-) {
-	db := database.GetGorm()
 	lastPolicy := &policyv1.Policy{}
 	lastPolicyID := ""
 	var lastPolicyPayload []byte
-	err := db.Raw(lastPolicyQuery).Row().Scan(&lastPolicyID, &lastPolicyPayload)
+	err = db.Raw(lastPolicyQuery).Row().Scan(&lastPolicyID, &lastPolicyPayload)
 	if err != nil && err != sql.ErrNoRows {
 		ginCtx.String(http.StatusInternalServerError, ServerInternalErrorMsg)
 		fmt.Fprintf(gin.DefaultWriter, "error in querying last policy: %v\n", err)
@@ -320,7 +170,7 @@ func handlePolicies(ginCtx *gin.Context, policyListQuery, lastPolicyQuery,
 		fmt.Fprintf(gin.DefaultWriter, QueryPolicyMappingFailureFormatMsg, err)
 	}
 
-	policyRows, err := db.Raw(policyListQuery).Rows()
+	policyRows, err := db.Raw(policyListQuery, policyListArgs...).Rows()
 	if err != nil {
 		ginCtx.String(http.StatusInternalServerError, ServerInternalErrorMsg)
 		fmt.Fprintf(gin.DefaultWriter, QueryPoliciesFailureFormatMsg, err)
@@ -334,6 +184,7 @@ func handlePolicies(ginCtx *gin.Context, policyListQuery, lastPolicyQuery,
 		},
 		Items: []unstructured.Unstructured{},
 	}
+	unstrPolicyList.SetResourceVersion(strconv.FormatInt(resourceVersion, 10))
 	policyName, policyUID := "", ""
 	for policyRows.Next() {
 		var policyPayload []byte
@@ -347,7 +198,8 @@ func handlePolicies(ginCtx *gin.Context, policyListQuery, lastPolicyQuery,
 			continue
 		}
 
-		compliancePerClusterStatuses, hasNonCompliantClusters, err := getComplianceStatus(policyComplianceQuery, policyUID)
+		compliancePerClusterStatuses, hasNonCompliantClusters, err := getComplianceStatus(policyComplianceQuery, policyUID,
+			hubScope)
 		if err != nil {
 			fmt.Fprintf(gin.DefaultWriter, QueryPolicyComplianceFailureFormatMsg, err)
 			continue
@@ -437,9 +289,9 @@ func getPolicyMatches(policyMappingQuery string) ([]*policyMatch, error) {
 	return policyMatches, nil
 }
 
-// getComplianceStatus returns array of CompliancePerClusterStatus,
+// getComplianceStatus returns array of CompliancePerClusterStatus of the visible hubs,
 // whether the policy has any NonCompliant cluster, and error.
-func getComplianceStatus(policyComplianceQuery, policyID string,
+func getComplianceStatus(policyComplianceQuery, policyID string, hubScope authorization.HubScope,
 ) ([]*policyv1.CompliancePerClusterStatus, bool, error) {
 	compliancePerClusterStatuses := []*policyv1.CompliancePerClusterStatus{}
 	hasNonCompliantClusters := false
@@ -466,6 +318,9 @@ func getComplianceStatus(policyComplianceQuery, policyID string,
 		if err := policyComplianceRows.Scan(&clusterName, &leafHubName, &complianceInDB); err != nil {
 			return []*policyv1.CompliancePerClusterStatus{}, false, err
 		}
+		if !hubScope.Contains(leafHubName) {
+			continue
+		}
 
 		compliance := dbEnumToPolicyComplianceStateMap[complianceInDB]
 		if compliance == policyv1.NonCompliant {
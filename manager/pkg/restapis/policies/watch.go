@@ -0,0 +1,146 @@
+// Copyright (c) 2025 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package policies
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/types"
+	policyv1 "open-cluster-management.io/governance-policy-propagator/api/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/restapis/authorization"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/restapis/util"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/restapis/watcher"
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+)
+
+// policySource is the policies matching the label selector in the visible hubs of the watcher, the compliance changes
+// are recorded as the changes of the policies
+type policySource struct {
+	selector *util.LabelSelector
+	hubScope authorization.HubScope
+}
+
+var _ watcher.Source = &policySource{}
+
+func (s *policySource) Table() string {
+	return "spec.policies"
+}
+
+func (s *policySource) List(ctx context.Context) ([]watcher.Resource, error) {
+	matches, err := getPolicyMatches(policyMappingQuery)
+	if err != nil {
+		return nil, err
+	}
+
+	query, args := util.NewListQuery(
+		"SELECT id, payload FROM spec.policies WHERE deleted = FALSE",
+		"(payload -> 'metadata' ->> 'name', payload -> 'metadata' ->> 'uid')").
+		WithSelector(s.selector).
+		WithStatusOfLeafHubs(policyOfHubsQuery, s.hubScope.Hubs()).
+		Build()
+	rows, err := database.GetGorm().WithContext(ctx).Raw(query, args...).Rows()
+	if err != nil {
+		return nil, fmt.Errorf("error in querying policies: %w", err)
+	}
+	defer rows.Close()
+
+	resources := []watcher.Resource{}
+	for rows.Next() {
+		var policyID string
+		var payload []byte
+		if err := rows.Scan(&policyID, &payload); err != nil {
+			return nil, fmt.Errorf("error in scanning a policy: %w", err)
+		}
+		policy := &policyv1.Policy{}
+		if err := json.Unmarshal(payload, policy); err != nil {
+			return nil, fmt.Errorf("error in scanning a policyPayload: %w", err)
+		}
+		if err := setPolicyStatus(policy, policyID, matches, s.hubScope); err != nil {
+			return nil, err
+		}
+		resources = append(resources, watcher.Resource{ID: policyID, Object: policy})
+	}
+	return resources, nil
+}
+
+func (s *policySource) Get(ctx context.Context, id string) (client.Object, bool, error) {
+	condition, args := s.selector.SQLCondition()
+	query := "SELECT payload, (deleted = FALSE" + condition + ") FROM spec.policies WHERE id = ?"
+
+	var payload []byte
+	var matched bool
+	err := database.GetGorm().WithContext(ctx).Raw(query, append(args, id)...).Row().Scan(&payload, &matched)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("error in querying the policy %s: %w", id, err)
+	}
+
+	policy := &policyv1.Policy{}
+	if err := json.Unmarshal(payload, policy); err != nil {
+		return nil, false, fmt.Errorf("error in scanning a policyPayload: %w", err)
+	}
+	if !matched {
+		return policy, false, nil
+	}
+
+	matches, err := getPolicyMatches(policyMappingQuery)
+	if err != nil {
+		return nil, false, err
+	}
+	if err := setPolicyStatus(policy, id, matches, s.hubScope); err != nil {
+		return nil, false, err
+	}
+	// the policy isn't visible if it isn't propagated to the clusters of the visible hubs
+	if s.hubScope != nil && len(policy.Status.Status) == 0 {
+		return policy, false, nil
+	}
+	return policy, true, nil
+}
+
+func (s *policySource) Tombstone(name types.NamespacedName) client.Object {
+	policy := &policyv1.Policy{}
+	policy.SetGroupVersionKind(policyv1.GroupVersion.WithKind("Policy"))
+	policy.SetNamespace(name.Namespace)
+	policy.SetName(name.Name)
+	return policy
+}
+
+// setPolicyStatus sets the placements and the compliance of the clusters in the visible hubs to the policy status
+func setPolicyStatus(policy *policyv1.Policy, policyID string, policyMatches []*policyMatch,
+	hubScope authorization.HubScope,
+) error {
+	policy.Status.Placement = []*policyv1.Placement{}
+	for _, pm := range policyMatches {
+		if pm.policy == policy.GetName() {
+			policy.Status.Placement = append(policy.Status.Placement, &policyv1.Placement{
+				PlacementRule:    pm.placementrule,
+				PlacementBinding: pm.placementbinding,
+			})
+		}
+	}
+
+	compliancePerClusterStatuses, hasNonCompliantClusters, err := getComplianceStatus(
+		policyComplianceQuery, policyID, hubScope)
+	if err != nil {
+		return fmt.Errorf("error in querying compliance status of a policy with UID: %s - %w", policyID, err)
+	}
+
+	policy.Status.Status = compliancePerClusterStatuses
+	policy.Status.ComplianceState = ""
+
+	if hasNonCompliantClusters {
+		policy.Status.ComplianceState = policyv1.NonCompliant
+	} else if len(compliancePerClusterStatuses) > 0 {
+		policy.Status.ComplianceState = policyv1.Compliant
+	}
+	return nil
+}
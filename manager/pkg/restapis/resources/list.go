@@ -0,0 +1,128 @@
+// Copyright (c) 2025 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package resources
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/restapis/authorization"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/restapis/util"
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
+)
+
+const (
+	serverInternalErrorMsg = "internal error"
+	defaultListLimit       = 100
+)
+
+// ResourceList is a page of the resources, the next page is listed with the continue token in the metadata.
+type ResourceList struct {
+	metav1.ListMeta `json:"metadata"`
+	Items           []models.Resource `json:"items"`
+}
+
+// ListResources godoc
+// @summary list resources
+// @description list the resources collected from the managed hubs by the declarations of the agent configuration
+// @accept json
+// @produce json
+// @param        hub            query     string  false  "list the resources of the managed hub"
+// @param        apiVersion     query     string  false  "list the resources of the api version, e.g. hive.openshift.io/v1"
+// @param        kind           query     string  false  "list the resources of the kind, e.g. ClusterDeployment"
+// @param        namespace      query     string  false  "list the resources in the namespace"
+// @param        labelSelector  query     string  false  "list the resources by label selector"
+// @param        limit          query     int     false  "maximum resource number to receive, 100 by default"
+// @param        continue       query     string  false  "continue token to request next request"
+// @success      200  {object}    ResourceList
+// @failure      400
+// @failure      401
+// @failure      403
+// @failure      500
+// @security     ApiKeyAuth
+// @router /resources [get]
+func ListResources() gin.HandlerFunc {
+	return func(ginCtx *gin.Context) {
+		selector, err := util.ParseLabelSelector(ginCtx.Query("labelSelector"))
+		if err != nil {
+			ginCtx.String(http.StatusBadRequest, err.Error())
+			return
+		}
+
+		limit, err := util.ParseLimit(ginCtx.Query("limit"))
+		if err != nil {
+			ginCtx.String(http.StatusBadRequest, err.Error())
+			return
+		}
+		if limit == 0 {
+			limit = defaultListLimit
+		}
+
+		lastName, lastID := "", uuid.Nil.String()
+		if continueToken := ginCtx.Query("continue"); continueToken != "" {
+			lastName, lastID, err = util.DecodeContinue(continueToken)
+			if err == nil {
+				_, err = uuid.Parse(lastID)
+			}
+			if err != nil {
+				ginCtx.String(http.StatusBadRequest, "invalid continue token: %v", err)
+				return
+			}
+		}
+
+		// only the resources of the visible hubs are listed
+		scope := authorization.GetHubScope(ginCtx)
+		leafHubNames := scope.Hubs()
+		if hub := ginCtx.Query("hub"); hub != "" {
+			if !scope.Contains(hub) {
+				ginCtx.String(http.StatusForbidden, "the hub %s isn't visible", hub)
+				return
+			}
+			leafHubNames = []string{hub}
+		}
+
+		// the resources are listed order by name and id, the paging starts after the last returned resource
+		listQuery := util.NewListQuery("SELECT * FROM status.resources WHERE TRUE", "(name, id)").
+			After(lastName, lastID).
+			WithSelector(selector).
+			WithLeafHubs(leafHubNames).
+			WithLimit(limit)
+		for _, filter := range []struct{ column, param string }{
+			{"api_version", "apiVersion"},
+			{"kind", "kind"},
+			{"namespace", "namespace"},
+		} {
+			if value := ginCtx.Query(filter.param); value != "" {
+				listQuery.WithCondition(" AND "+filter.column+" = ?", value)
+			}
+		}
+		query, args := listQuery.Build()
+
+		resourceList := &ResourceList{Items: []models.Resource{}}
+		err = database.GetGorm().WithContext(ginCtx.Request.Context()).Raw(query, args...).
+			Scan(&resourceList.Items).Error
+		if err != nil {
+			fmt.Fprintf(gin.DefaultWriter, "failed to list the resources: %v\n", err)
+			ginCtx.String(http.StatusInternalServerError, serverInternalErrorMsg)
+			return
+		}
+
+		// a full page might be followed by more resources
+		if len(resourceList.Items) == limit {
+			last := resourceList.Items[len(resourceList.Items)-1]
+			resourceList.Continue, err = util.EncodeContinue(last.Name, last.ID)
+			if err != nil {
+				fmt.Fprintf(gin.DefaultWriter, "failed to encode the continue token: %v\n", err)
+				ginCtx.String(http.StatusInternalServerError, serverInternalErrorMsg)
+				return
+			}
+		}
+		ginCtx.JSON(http.StatusOK, resourceList)
+	}
+}
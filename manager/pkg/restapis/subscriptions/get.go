@@ -17,6 +17,7 @@ import (
 	appsv1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1"
 	appsv1alpha1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1alpha1"
 
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/restapis/authorization"
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/restapis/util"
 	"github.com/stolostron/multicluster-global-hub/pkg/database"
 )
@@ -26,7 +27,7 @@ const (
 	subscriptionStatusCRDName = "subscriptionstatuses.apps.open-cluster-management.io"
 	subscriptionQuery         = `SELECT payload->'metadata'->>'name', payload->'metadata'->>'namespace' 
 		FROM spec.subscriptions WHERE deleted = FALSE AND id = ?`
-	subscriptionReportQuery = `SELECT payload FROM status.subscription_reports
+	subscriptionReportQuery = `SELECT leaf_hub_name, payload FROM status.subscription_reports
 		WHERE payload->'metadata'->>'name'= ? AND payload->'metadata'->>'namespace' = ?`
 )
 
@@ -58,19 +59,26 @@ func GetSubscriptionReport() gin.HandlerFunc {
 
 		handleSubscriptionReport(ginCtx, subscriptionID,
 			subscriptionQuery, subscriptionReportQuery,
-			subReportCustomResourceColumnDefinitions)
+			subReportCustomResourceColumnDefinitions, authorization.GetHubScope(ginCtx))
 	}
 }
 
 func handleSubscriptionReport(ginCtx *gin.Context, subscriptionID, subscriptionQuery,
 	subscriptionReportQuery string, customResourceColumnDefinitions []apiextensionsv1.CustomResourceColumnDefinition,
+	hubScope authorization.HubScope,
 ) {
 	subscriptionReport, err := getAggregatedSubscriptionReport(subscriptionID,
-		subscriptionQuery, subscriptionReportQuery)
+		subscriptionQuery, subscriptionReportQuery, hubScope)
 	if err != nil {
 		ginCtx.String(http.StatusInternalServerError, serverInternalErrorMsg)
 	}
 
+	// the subscription which isn't reported by the visible hubs is treated as not found
+	if err == nil && subscriptionReport == nil && hubScope != nil {
+		ginCtx.String(http.StatusNotFound, "the subscription report of %s isn't found", subscriptionID)
+		return
+	}
+
 	if util.ShouldReturnAsTable(ginCtx) {
 		fmt.Fprintf(gin.DefaultWriter, "returning subscription as table...\n")
 
@@ -96,9 +104,9 @@ func handleSubscriptionReport(ginCtx *gin.Context, subscriptionID, subscriptionQ
 	ginCtx.JSON(http.StatusOK, subscriptionReport)
 }
 
-// returns aggregated SubscriptionReport and error.
+// returns aggregated SubscriptionReport of the visible hubs and error.
 func getAggregatedSubscriptionReport(subscriptionID, subscriptionQuery,
-	subscriptionReportQuery string,
+	subscriptionReportQuery string, hubScope authorization.HubScope,
 ) (*appsv1alpha1.SubscriptionReport, error) {
 	var subscriptionReport *appsv1alpha1.SubscriptionReport
 	var subName, subNamespace string
@@ -118,10 +126,14 @@ func getAggregatedSubscriptionReport(subscriptionID, subscriptionQuery,
 
 	for rows.Next() {
 		leafHubSubscriptionReport := appsv1alpha1.SubscriptionReport{}
+		var leafHubName string
 		var payload []byte
-		if err := rows.Scan(&payload); err != nil {
+		if err := rows.Scan(&leafHubName, &payload); err != nil {
 			return nil, fmt.Errorf("error getting subscription report payload for leaf hub: %v\n", err)
 		}
+		if !hubScope.Contains(leafHubName) {
+			continue
+		}
 
 		if err = json.Unmarshal(payload, &leafHubSubscriptionReport); err != nil {
 			return nil, fmt.Errorf("error getting subscription report for leaf hub: %v\n", err)
@@ -23,6 +23,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	appsv1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1"
 
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/restapis/authorization"
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/restapis/util"
 	"github.com/stolostron/multicluster-global-hub/pkg/database"
 )
@@ -31,6 +32,10 @@ const (
 	serverInternalErrorMsg = "internal error"
 	syncIntervalInSeconds  = 4
 	crdName                = "subscriptions.apps.open-cluster-management.io"
+	// the subscriptions are visible to the caller if they're reported by the visible hubs
+	subscriptionOfHubsQuery = `SELECT 1 FROM status.subscription_reports
+		WHERE payload -> 'metadata' ->> 'name' = spec.subscriptions.payload -> 'metadata' ->> 'name'
+		AND payload -> 'metadata' ->> 'namespace' = spec.subscriptions.payload -> 'metadata' ->> 'namespace'`
 )
 
 var customResourceColumnDefinitions = util.GetCustomResourceColumnDefinitions(crdName,
@@ -55,22 +60,19 @@ var customResourceColumnDefinitions = util.GetCustomResourceColumnDefinitions(cr
 // @router /subscriptions [get]
 func ListSubscriptions() gin.HandlerFunc {
 	return func(ginCtx *gin.Context) {
-		labelSelector := ginCtx.Query("labelSelector")
-
-		selectorInSql := ""
-
-		if labelSelector != "" {
-			var err error
-			selectorInSql, err = util.ParseLabelSelector(labelSelector)
-			if err != nil {
-				fmt.Fprintf(gin.DefaultWriter, "failed to parse label selector: %s\n", err.Error())
-				return
-			}
+		selector, err := util.ParseLabelSelector(ginCtx.Query("labelSelector"))
+		if err != nil {
+			ginCtx.String(http.StatusBadRequest, err.Error())
+			fmt.Fprintf(gin.DefaultWriter, "failed to parse label selector: %s\n", err.Error())
+			return
 		}
 
-		fmt.Fprintf(gin.DefaultWriter, "parsed selector: %s\n", selectorInSql)
-
-		limit := ginCtx.Query("limit")
+		limit, err := util.ParseLimit(ginCtx.Query("limit"))
+		if err != nil {
+			ginCtx.String(http.StatusBadRequest, err.Error())
+			fmt.Fprintf(gin.DefaultWriter, "failed to parse limit: %s\n", err.Error())
+			return
+		}
 		fmt.Fprintf(gin.DefaultWriter, "limit: %v\n", limit)
 
 		lastSubscriptionName, lastSubscriptionUID := "", ""
@@ -79,7 +81,6 @@ func ListSubscriptions() gin.HandlerFunc {
 		if continueToken != "" {
 			fmt.Fprintf(gin.DefaultWriter, "continue: %v\n", continueToken)
 
-			var err error
 			lastSubscriptionName, lastSubscriptionUID, err = util.DecodeContinue(continueToken)
 			if err != nil {
 				fmt.Fprintf(gin.DefaultWriter, "failed to decode continue token: %s\n", err.Error())
@@ -92,39 +93,35 @@ func ListSubscriptions() gin.HandlerFunc {
 			lastSubscriptionName,
 			lastSubscriptionUID)
 
-		// build query condition for paging
-		LastResourceCompareCondition := fmt.Sprintf(
-			"(payload -> 'metadata' ->> 'name', payload -> 'metadata' ->> 'uid') > ('%s', '%s') ",
-			lastSubscriptionName,
-			lastSubscriptionUID)
-
 		// the last subscription query order by subscription name and uid
 		lastSubscriptionQuery := "SELECT payload FROM spec.subscriptions WHERE deleted = FALSE " +
 			"ORDER BY (payload -> 'metadata' ->> 'name', payload -> 'metadata' ->> 'uid') DESC LIMIT 1"
 
-		// subscrition list query
-		subscriptionListQuery := "SELECT payload FROM spec.subscriptions WHERE deleted = FALSE AND " +
-			LastResourceCompareCondition +
-			selectorInSql +
-			" ORDER BY (payload -> 'metadata' ->> 'name', payload -> 'metadata' ->> 'uid')"
-
-		// add limit
-		if limit != "" {
-			subscriptionListQuery += fmt.Sprintf(" LIMIT %s", limit)
-		}
+		// subscription list query order by name and uid, the paging starts after the last returned subscription
+		subscriptionListQuery, subscriptionListArgs := util.NewListQuery(
+			"SELECT payload FROM spec.subscriptions WHERE deleted = FALSE",
+			"(payload -> 'metadata' ->> 'name', payload -> 'metadata' ->> 'uid')").
+			After(lastSubscriptionName, lastSubscriptionUID).
+			WithSelector(selector).
+			WithStatusOfLeafHubs(subscriptionOfHubsQuery, authorization.GetHubScope(ginCtx).Hubs()).
+			WithLimit(limit).
+			Build()
 
 		fmt.Fprintf(gin.DefaultWriter, "subscription list query: %v\n", subscriptionListQuery)
 
 		if _, watch := ginCtx.GetQuery("watch"); watch {
-			handleSubscriptionListForWatch(ginCtx, subscriptionListQuery)
+			handleSubscriptionListForWatch(ginCtx, subscriptionListQuery, subscriptionListArgs)
 			return
 		}
 
-		handleRows(ginCtx, subscriptionListQuery, lastSubscriptionQuery, customResourceColumnDefinitions)
+		handleRows(ginCtx, subscriptionListQuery, subscriptionListArgs, lastSubscriptionQuery,
+			customResourceColumnDefinitions)
 	}
 }
 
-func handleSubscriptionListForWatch(ginCtx *gin.Context, subscriptionListQuery string) {
+func handleSubscriptionListForWatch(ginCtx *gin.Context, subscriptionListQuery string,
+	subscriptionListArgs []interface{},
+) {
 	writer := ginCtx.Writer
 	header := writer.Header()
 
@@ -154,16 +151,16 @@ func handleSubscriptionListForWatch(ginCtx *gin.Context, subscriptionListQuery s
 				return
 			}
 
-			doHandleRowsForWatch(ctx, writer, subscriptionListQuery, preAddedSubscriptions)
+			doHandleRowsForWatch(ctx, writer, subscriptionListQuery, subscriptionListArgs, preAddedSubscriptions)
 		}
 	}
 }
 
 func doHandleRowsForWatch(ctx context.Context, writer io.Writer, subscriptionListQuery string,
-	preAddedSubscriptions set.Set,
+	subscriptionListArgs []interface{}, preAddedSubscriptions set.Set,
 ) {
 	db := database.GetGorm()
-	rows, err := db.Raw(subscriptionListQuery).Rows()
+	rows, err := db.Raw(subscriptionListQuery, subscriptionListArgs...).Rows()
 	if err != nil {
 		fmt.Fprintf(gin.DefaultWriter, "error in quering subscription list: %v\n", err)
 	}
@@ -226,8 +223,8 @@ func doHandleRowsForWatch(ctx context.Context, writer io.Writer, subscriptionLis
 	writer.(http.Flusher).Flush()
 }
 
-func handleRows(ginCtx *gin.Context, subscriptionListQuery, lastSubscriptionQuery string,
-	customResourceColumnDefinitions []apiextensionsv1.CustomResourceColumnDefinition,
+func handleRows(ginCtx *gin.Context, subscriptionListQuery string, subscriptionListArgs []interface{},
+	lastSubscriptionQuery string, customResourceColumnDefinitions []apiextensionsv1.CustomResourceColumnDefinition,
 ) {
 	db := database.GetGorm()
 	lastSubscription := &appsv1.Subscription{}
@@ -248,7 +245,7 @@ func handleRows(ginCtx *gin.Context, subscriptionListQuery, lastSubscriptionQuer
 		}
 	}
 
-	rows, err := db.Raw(subscriptionListQuery).Rows()
+	rows, err := db.Raw(subscriptionListQuery, subscriptionListArgs...).Rows()
 	if err != nil {
 		ginCtx.String(http.StatusInternalServerError, serverInternalErrorMsg)
 		fmt.Fprintf(gin.DefaultWriter, "error in querying subscriptions: %v\n", err)
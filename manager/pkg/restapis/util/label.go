@@ -6,83 +6,92 @@ package util
 import (
 	"fmt"
 	"strings"
+
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
 )
 
 const (
-	invalidLabelSelectorFormatMsg = "invalid equality based label selector: %s\n"
+	invalidLabelSelectorFormatMsg = "invalid label selector %q: %w"
+	// payloadLabels is the labels of the resource in the payload column
+	payloadLabels = "payload -> 'metadata' -> 'labels' ->> ?"
 )
 
-func ParseLabelSelector(labelSelector string) (string, error) {
-	selectorInSql := ""
-	selectors := strings.Split(labelSelector, ",")
-	for _, selector := range selectors {
-		switch {
-		case strings.Contains(selector, "!="):
-			keyValPair := strings.Split(selector, "!=")
-			if len(keyValPair) != 2 {
-				return "", fmt.Errorf(invalidLabelSelectorFormatMsg, selector)
-			}
-
-			key, val := strings.TrimSpace(keyValPair[0]), strings.TrimSpace(keyValPair[1])
-			selectorInSql += fmt.Sprintf(" AND NOT (payload -> 'metadata' -> 'labels' @> '{\"%s\": \"%s\"}')", key, val)
-		case strings.Contains(selector, "==") || strings.Contains(selector, "="):
-			var keyValPair []string
-			if strings.Contains(selector, "==") {
-				keyValPair = strings.Split(selector, "=")
-			} else if strings.Contains(selector, "=") {
-				keyValPair = strings.Split(selector, "=")
-			}
-
-			if len(keyValPair) != 2 {
-				return "", fmt.Errorf(invalidLabelSelectorFormatMsg, selector)
-			}
+// LabelSelector is the label selector parsed with the Kubernetes grammar, e.g. "env in (prod,qa),!canary,tier". It's
+// compiled into the parameterized condition on the labels of the payload, the user input is never formatted into
+// the query.
+type LabelSelector struct {
+	requirements labels.Requirements
+}
 
-			key, val := strings.TrimSpace(keyValPair[0]), strings.TrimSpace(keyValPair[1])
-			selectorInSql += fmt.Sprintf(" AND payload -> 'metadata' -> 'labels' @> '{\"%s\": \"%s\"}'", key, val)
-		// case strings.Contains(selector, "notin"):
-		// 	keyValSetPair := strings.Split(selector, "notin")
-		// 	if len(keyValSetPair) != 2 {
-		// 		return "", fmt.Errorf(invalidLabelSelectorFormatMsg, selector)
-		// 	}
+// ParseLabelSelector parses the equality based and set based label selector. The "gt" and "lt" operators aren't
+// supported since the label values aren't indexed as numbers.
+func ParseLabelSelector(labelSelector string) (*LabelSelector, error) {
+	selector, err := labels.Parse(labelSelector)
+	if err != nil {
+		return nil, fmt.Errorf(invalidLabelSelectorFormatMsg, labelSelector, err)
+	}
+	requirements, _ := selector.Requirements()
+	for _, requirement := range requirements {
+		switch requirement.Operator() {
+		case selection.GreaterThan, selection.LessThan:
+			return nil, fmt.Errorf(invalidLabelSelectorFormatMsg, labelSelector,
+				fmt.Errorf("the operator %q isn't supported", requirement.Operator()))
+		}
+	}
+	return &LabelSelector{requirements: requirements}, nil
+}
 
-		// 	key, valSet := strings.TrimSpace(keyValSetPair[0]), strings.TrimSpace(keyValSetPair[1])
-		// 	valSet = strings.TrimPrefix(valSet, "(")
-		// 	valSet = strings.TrimSuffix(valSet, ")")
-		// 	for i, valStr := range strings.Split(valSet, ",") {
-		// 		val := strings.TrimSpace(valStr)
-		// 		if i == 0 {
-		// 			selectorInSql += fmt.Sprintf(" AND (NOT (payload -> 'metadata' -> 'labels' @> '{\"%s\": \"%s\"}')", key, val)
-		// 		} else {
-		// 			selectorInSql += fmt.Sprintf(" OR NOT (payload -> 'metadata' -> 'labels' @> '{\"%s\": \"%s\"}')", key, val)
-		// 		}
-		// 	}
-		// 	selectorInSql += ")"
-		// case strings.Contains(selector, "in"):
-		// 	keyValSetPair := strings.Split(selector, "in")
-		// 	if len(keyValSetPair) != 2 {
-		// 		return "", fmt.Errorf(invalidLabelSelectorFormatMsg, selector)
-		// 	}
+// Requirements returns the parsed requirements of the selector.
+func (s *LabelSelector) Requirements() labels.Requirements {
+	if s == nil {
+		return nil
+	}
+	return s.requirements
+}
 
-		// 	key, valSet := strings.TrimSpace(keyValSetPair[0]), strings.TrimSpace(keyValSetPair[1])
-		// 	valSet = strings.TrimPrefix(valSet, "(")
-		// 	valSet = strings.TrimSuffix(valSet, ")")
-		// 	for i, valStr := range strings.Split(valSet, ",") {
-		// 		val := strings.TrimSpace(valStr)
-		// 		if i == 0 {
-		// 			selectorInSql += fmt.Sprintf(" AND (payload -> 'metadata' -> 'labels' @> '{\"%s\": \"%s\"}'", key, val)
-		// 		} else {
-		// 			selectorInSql += fmt.Sprintf(" OR payload -> 'metadata' -> 'labels' @> '{\"%s\": \"%s\"}'", key, val)
-		// 		}
-		// 	}
-		// 	selectorInSql += ")"
-		case strings.HasPrefix(selector, "!"):
-			key := strings.TrimSpace(strings.TrimPrefix(selector, "!"))
-			selectorInSql += fmt.Sprintf(" AND NOT (payload -> 'metadata' -> 'labels' ? '%s')", key)
-		default:
-			key := strings.TrimSpace(selector)
-			selectorInSql += fmt.Sprintf(" AND payload -> 'metadata' -> 'labels' ? '%s'", key)
+// SQLCondition compiles the selector into the conditions joined with "AND", the args are bound to the "?"
+// placeholders in order. It's empty if the selector has no requirements.
+func (s *LabelSelector) SQLCondition() (string, []interface{}) {
+	var condition strings.Builder
+	args := []interface{}{}
+	for _, requirement := range s.Requirements() {
+		key := requirement.Key()
+		values := requirement.Values().List() // sorted
+		switch requirement.Operator() {
+		case selection.Equals, selection.DoubleEquals:
+			condition.WriteString(" AND " + payloadLabels + " = ?")
+			args = append(args, key, values[0])
+		case selection.NotEquals:
+			// the resources without the key are matched, as the Kubernetes selector does
+			condition.WriteString(" AND " + payloadLabels + " IS DISTINCT FROM ?")
+			args = append(args, key, values[0])
+		case selection.In:
+			condition.WriteString(" AND " + payloadLabels + " IN (" + placeholders(len(values)) + ")")
+			args = append(append(args, key), toArgs(values)...)
+		case selection.NotIn:
+			condition.WriteString(" AND (" + payloadLabels + " IS NULL OR " + payloadLabels +
+				" NOT IN (" + placeholders(len(values)) + "))")
+			args = append(append(args, key, key), toArgs(values)...)
+		case selection.Exists:
+			condition.WriteString(" AND " + payloadLabels + " IS NOT NULL")
+			args = append(args, key)
+		case selection.DoesNotExist:
+			condition.WriteString(" AND " + payloadLabels + " IS NULL")
+			args = append(args, key)
 		}
 	}
+	return condition.String(), args
+}
 
-	return selectorInSql, nil
+func placeholders(count int) string {
+	return strings.TrimSuffix(strings.Repeat("?, ", count), ", ")
+}
+
+func toArgs(values []string) []interface{} {
+	args := make([]interface{}, 0, len(values))
+	for _, value := range values {
+		args = append(args, value)
+	}
+	return args
 }
@@ -0,0 +1,97 @@
+// Copyright (c) 2025 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package util
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseLabelSelector(t *testing.T) {
+	cases := []struct {
+		name      string
+		selector  string
+		condition string
+		args      []interface{}
+	}{
+		{
+			name:     "empty selector",
+			selector: "",
+			args:     []interface{}{},
+		},
+		{
+			name:     "equality based selector",
+			selector: "env=prod,tier==web,vendor!=OpenShift",
+			condition: " AND payload -> 'metadata' -> 'labels' ->> ? = ?" +
+				" AND payload -> 'metadata' -> 'labels' ->> ? = ?" +
+				" AND payload -> 'metadata' -> 'labels' ->> ? IS DISTINCT FROM ?",
+			args: []interface{}{"env", "prod", "tier", "web", "vendor", "OpenShift"},
+		},
+		{
+			name:     "set based selector",
+			selector: "env in (qa, prod),region notin (us),canary,!deprecated",
+			condition: " AND payload -> 'metadata' -> 'labels' ->> ? IS NOT NULL" +
+				" AND payload -> 'metadata' -> 'labels' ->> ? IS NULL" +
+				" AND payload -> 'metadata' -> 'labels' ->> ? IN (?, ?)" +
+				" AND (payload -> 'metadata' -> 'labels' ->> ? IS NULL" +
+				" OR payload -> 'metadata' -> 'labels' ->> ? NOT IN (?))",
+			args: []interface{}{"canary", "deprecated", "env", "prod", "qa", "region", "region", "us"},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			selector, err := ParseLabelSelector(tc.selector)
+			require.NoError(t, err)
+			condition, args := selector.SQLCondition()
+			assert.Equal(t, tc.condition, condition)
+			assert.Equal(t, tc.args, args)
+		})
+	}
+
+	for _, invalid := range []string{"env='prod' OR 1=1", "env in (prod", "replicas>1", "a=b=c"} {
+		_, err := ParseLabelSelector(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestListQuery(t *testing.T) {
+	selector, err := ParseLabelSelector("env=prod")
+	require.NoError(t, err)
+
+	query, args := NewListQuery("SELECT payload FROM spec.policies WHERE deleted = FALSE",
+		"(payload -> 'metadata' ->> 'name', payload -> 'metadata' ->> 'uid')").
+		After("policy1", "uid1").WithSelector(selector).WithLimit(2).Build()
+	assert.Equal(t, "SELECT payload FROM spec.policies WHERE deleted = FALSE"+
+		" AND (payload -> 'metadata' ->> 'name', payload -> 'metadata' ->> 'uid') > (?, ?)"+
+		" AND payload -> 'metadata' -> 'labels' ->> ? = ?"+
+		" ORDER BY (payload -> 'metadata' ->> 'name', payload -> 'metadata' ->> 'uid') LIMIT ?", query)
+	assert.Equal(t, []interface{}{"policy1", "uid1", "env", "prod", 2}, args)
+
+	// the global resources are filtered by the status of the visible hubs
+	query, args = NewListQuery("SELECT id, payload FROM spec.policies WHERE deleted = FALSE", "id").
+		WithStatusOfLeafHubs("SELECT 1 FROM status.compliance WHERE policy_id = spec.policies.id",
+			[]string{"hub1", "hub2"}).Build()
+	assert.Equal(t, "SELECT id, payload FROM spec.policies WHERE deleted = FALSE"+
+		" AND EXISTS (SELECT 1 FROM status.compliance WHERE policy_id = spec.policies.id"+
+		" AND leaf_hub_name IN (?, ?)) ORDER BY id", query)
+	assert.Equal(t, []interface{}{"hub1", "hub2"}, args)
+
+	// the resources are filtered by the columns and the visible hubs
+	query, args = NewListQuery("SELECT * FROM status.resources WHERE TRUE", "(name, id)").
+		After("node1", "uid1").WithCondition(" AND kind = ?", "Node").WithLeafHubs([]string{"hub1"}).
+		WithLimit(10).Build()
+	assert.Equal(t, "SELECT * FROM status.resources WHERE TRUE AND (name, id) > (?, ?) AND kind = ?"+
+		" AND leaf_hub_name IN (?) ORDER BY (name, id) LIMIT ?", query)
+	assert.Equal(t, []interface{}{"node1", "uid1", "Node", "hub1", 10}, args)
+
+	limit, err := ParseLimit("")
+	require.NoError(t, err)
+	assert.Equal(t, 0, limit)
+	_, err = ParseLimit("1; DROP TABLE spec.policies")
+	assert.Error(t, err)
+	_, err = ParseLimit("-1")
+	assert.Error(t, err)
+}
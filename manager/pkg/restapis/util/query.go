@@ -0,0 +1,108 @@
+// Copyright (c) 2025 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package util
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// ListQuery builds the parameterized query of the list handlers, the resources are listed page by page in order of
+// the name and uid.
+type ListQuery struct {
+	// statement selects the resources, e.g. "SELECT payload FROM spec.policies WHERE deleted = FALSE"
+	statement string
+	// orderBy is the tuple of the name and uid, e.g. "(payload -> 'metadata' ->> 'name', cluster_id)"
+	orderBy    string
+	conditions string
+	args       []interface{}
+	limit      int
+}
+
+// NewListQuery creates the list query with the select statement which has the WHERE clause.
+func NewListQuery(statement, orderBy string) *ListQuery {
+	return &ListQuery{statement: statement, orderBy: orderBy, args: []interface{}{}}
+}
+
+// After lists the resources after the last returned one of the previous page.
+func (q *ListQuery) After(lastName, lastUID string) *ListQuery {
+	q.conditions += fmt.Sprintf(" AND %s > (?, ?)", q.orderBy)
+	q.args = append(q.args, lastName, lastUID)
+	return q
+}
+
+// WithSelector lists the resources matching the label selector.
+func (q *ListQuery) WithSelector(selector *LabelSelector) *ListQuery {
+	condition, args := selector.SQLCondition()
+	q.conditions += condition
+	q.args = append(q.args, args...)
+	return q
+}
+
+// WithCondition lists the resources matching the condition, e.g. " AND kind = ?".
+func (q *ListQuery) WithCondition(condition string, args ...interface{}) *ListQuery {
+	q.conditions += condition
+	q.args = append(q.args, args...)
+	return q
+}
+
+// WithLeafHubs lists the resources of the leaf hubs, the resources of all the hubs are listed if the hubs are nil.
+func (q *ListQuery) WithLeafHubs(leafHubNames []string) *ListQuery {
+	if leafHubNames == nil {
+		return q
+	}
+	if len(leafHubNames) == 0 {
+		q.conditions += " AND FALSE"
+		return q
+	}
+	q.conditions += " AND leaf_hub_name IN (" + placeholders(len(leafHubNames)) + ")"
+	q.args = append(q.args, toArgs(leafHubNames)...)
+	return q
+}
+
+// WithStatusOfLeafHubs lists the global resources whose status is reported by the leaf hubs, the status rows are
+// selected by the statement correlated with the resource, e.g.
+// "SELECT 1 FROM status.compliance WHERE policy_id = spec.policies.id". They're all listed if the hubs are nil.
+func (q *ListQuery) WithStatusOfLeafHubs(statusStatement string, leafHubNames []string) *ListQuery {
+	if leafHubNames == nil {
+		return q
+	}
+	if len(leafHubNames) == 0 {
+		q.conditions += " AND FALSE"
+		return q
+	}
+	q.conditions += " AND EXISTS (" + statusStatement + " AND leaf_hub_name IN (" + placeholders(len(leafHubNames)) +
+		"))"
+	q.args = append(q.args, toArgs(leafHubNames)...)
+	return q
+}
+
+// WithLimit lists the limited number of the resources, it isn't limited if the limit is 0.
+func (q *ListQuery) WithLimit(limit int) *ListQuery {
+	q.limit = limit
+	return q
+}
+
+// Build returns the query and the args bound to the "?" placeholders.
+func (q *ListQuery) Build() (string, []interface{}) {
+	query := q.statement + q.conditions + " ORDER BY " + q.orderBy
+	args := append([]interface{}{}, q.args...)
+	if q.limit > 0 {
+		query += " LIMIT ?"
+		args = append(args, q.limit)
+	}
+	return query, args
+}
+
+// ParseLimit parses the limit of the list request, it's 0 if the limit is empty.
+func ParseLimit(limit string) (int, error) {
+	if limit == "" {
+		return 0, nil
+	}
+	val, err := strconv.Atoi(limit)
+	if err != nil || val < 0 {
+		return 0, fmt.Errorf("invalid limit %q: it must be a non-negative integer", limit)
+	}
+	return val, nil
+}
@@ -0,0 +1,153 @@
+// Copyright (c) 2025 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package watcher
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/lib/pq"
+	"go.uber.org/zap"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
+	"github.com/stolostron/multicluster-global-hub/pkg/logger"
+)
+
+const (
+	// NotificationChannel is the channel notified by the database triggers, the payload is the changed table
+	NotificationChannel = "resource_changes"
+	// DefaultRetention is how long the changes are kept for resuming the watches
+	DefaultRetention = 1 * time.Hour
+	pruneInterval    = 5 * time.Minute
+)
+
+// Broker listens to the change notifications of the database and wakes up the watchers of the changed tables. The
+// notifications only carry the table name, the watchers read the changes from the status.resource_changes table, so
+// the notifications lost while reconnecting are recovered by waking up all the watchers.
+type Broker struct {
+	log       *zap.SugaredLogger
+	config    *database.DatabaseConfig
+	retention time.Duration
+
+	mutex       sync.Mutex
+	subscribers map[string]map[chan struct{}]struct{}
+}
+
+// NewBroker creates the broker with the database config of the LISTEN connection, the changes older than the
+// retention are pruned.
+func NewBroker(config *database.DatabaseConfig, retention time.Duration) *Broker {
+	if retention <= 0 {
+		retention = DefaultRetention
+	}
+	return &Broker{
+		log:         logger.ZapLogger("restapi-watch-broker"),
+		config:      config,
+		retention:   retention,
+		subscribers: map[string]map[chan struct{}]struct{}{},
+	}
+}
+
+// NeedLeaderElection implements the LeaderElectionRunnable interface, the watches are served by all the replicas.
+func (b *Broker) NeedLeaderElection() bool {
+	return false
+}
+
+// Subscribe returns the channel signaled when the table is changed, the cancel function must be called once the
+// watch ends. It's safe to subscribe to the nil broker, the watchers only resync periodically then.
+func (b *Broker) Subscribe(table string) (<-chan struct{}, func()) {
+	wakeup := make(chan struct{}, 1)
+	if b == nil {
+		return wakeup, func() {}
+	}
+
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	if b.subscribers[table] == nil {
+		b.subscribers[table] = map[chan struct{}]struct{}{}
+	}
+	b.subscribers[table][wakeup] = struct{}{}
+	return wakeup, func() {
+		b.mutex.Lock()
+		defer b.mutex.Unlock()
+		delete(b.subscribers[table], wakeup)
+	}
+}
+
+// notify wakes up the watchers of the table, or all the watchers if the table is empty
+func (b *Broker) notify(table string) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	for subscribedTable, wakeups := range b.subscribers {
+		if table != "" && table != subscribedTable {
+			continue
+		}
+		for wakeup := range wakeups {
+			// the pending signal already covers the change
+			select {
+			case wakeup <- struct{}{}:
+			default:
+			}
+		}
+	}
+}
+
+// Start listens to the notifications until the context is done.
+func (b *Broker) Start(ctx context.Context) error {
+	listener, err := database.NewListener(b.config, func(event pq.ListenerEventType, err error) {
+		if err != nil {
+			b.log.Warnw("the watch notification listener is interrupted", "event", event, "error", err)
+		}
+	})
+	if err != nil {
+		return err
+	}
+	go func() {
+		<-ctx.Done()
+		if err := listener.Close(); err != nil {
+			b.log.Warnw("failed to close the watch notification listener", "error", err)
+		}
+	}()
+	// it blocks until the connection is established
+	if err := listener.Listen(NotificationChannel); err != nil {
+		if ctx.Err() != nil {
+			return nil
+		}
+		return err
+	}
+	b.log.Infof("listening to the channel %s", NotificationChannel)
+
+	ticker := time.NewTicker(pruneInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case notification, ok := <-listener.Notify:
+			if !ok {
+				return nil
+			}
+			// the nil notification means the connection is re-established, the notifications might be lost
+			if notification == nil {
+				b.notify("")
+				continue
+			}
+			b.notify(notification.Extra)
+		case <-ticker.C:
+			if err := b.prune(ctx); err != nil {
+				b.log.Warnw("failed to prune the resource changes", "error", err)
+			}
+		}
+	}
+}
+
+// prune deletes the changes older than the retention, the latest change is kept to tell whether the resource version
+// to resume is expired.
+func (b *Broker) prune(ctx context.Context) error {
+	db := database.GetGorm().WithContext(ctx)
+	return db.Where("created_at < now() - ? * interval '1 second' AND resource_version < (?)", b.retention.Seconds(),
+		database.GetGorm().Model(&models.ResourceChange{}).Select("MAX(resource_version)")).
+		Delete(&models.ResourceChange{}).Error
+}
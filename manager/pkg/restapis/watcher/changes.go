@@ -0,0 +1,103 @@
+// Copyright (c) 2025 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package watcher
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
+)
+
+// gapTimeout is how long the watcher waits for the missing resource version. The resource versions are allocated
+// before the transactions commit, so the later version might be visible before the earlier one, and the versions of
+// the rolled back transactions never show up.
+const gapTimeout = 2 * time.Second
+
+// ErrResourceVersionExpired means the changes after the resource version are pruned, the client has to list again.
+var ErrResourceVersionExpired = errors.New("too old resource version")
+
+// CurrentVersion returns the resource version of the latest change, it's 0 if nothing is changed.
+func CurrentVersion(ctx context.Context) (int64, error) {
+	var version int64
+	err := database.GetGorm().WithContext(ctx).Model(&models.ResourceChange{}).
+		Select("COALESCE(MAX(resource_version), 0)").Scan(&version).Error
+	if err != nil {
+		return 0, fmt.Errorf("failed to get the current resource version: %w", err)
+	}
+	return version, nil
+}
+
+// ParseResourceVersion parses the resource version supplied by the client, the empty or "0" version means the watch
+// starts from the current resources.
+func ParseResourceVersion(resourceVersion string) (int64, error) {
+	if resourceVersion == "" {
+		return 0, nil
+	}
+	version, err := strconv.ParseInt(resourceVersion, 10, 64)
+	if err != nil || version < 0 {
+		return 0, fmt.Errorf("invalid resource version %q: it must be a non-negative integer", resourceVersion)
+	}
+	return version, nil
+}
+
+// checkExpired returns ErrResourceVersionExpired if the changes after the version are pruned.
+func checkExpired(ctx context.Context, version int64) error {
+	var oldest int64
+	err := database.GetGorm().WithContext(ctx).Model(&models.ResourceChange{}).
+		Select("COALESCE(MIN(resource_version), 0)").Scan(&oldest).Error
+	if err != nil {
+		return fmt.Errorf("failed to get the oldest resource version: %w", err)
+	}
+	if oldest > version+1 {
+		return ErrResourceVersionExpired
+	}
+	return nil
+}
+
+// listChanges returns the changes of all the tables after the version in order, the changes of the other tables are
+// needed to tell the missing versions.
+func listChanges(ctx context.Context, after int64, limit int) ([]models.ResourceChange, error) {
+	changes := []models.ResourceChange{}
+	err := database.GetGorm().WithContext(ctx).Where("resource_version > ?", after).
+		Order("resource_version").Limit(limit).Find(&changes).Error
+	return changes, err
+}
+
+// sequencer delivers the changes in order of the resource version without skipping the uncommitted ones.
+type sequencer struct {
+	// last is the version of the last delivered change
+	last int64
+	// gapSince is when the version after the last one is found missing, it's zero if there is no gap
+	gapSince time.Time
+}
+
+// next returns the changes which can be delivered, the changes after a missing version are held until the missing
+// one shows up or the gap times out. The pending is true if some changes are held.
+func (s *sequencer) next(changes []models.ResourceChange, now time.Time) (delivered []models.ResourceChange,
+	pending bool,
+) {
+	for _, change := range changes {
+		if change.ResourceVersion <= s.last {
+			continue
+		}
+		if change.ResourceVersion != s.last+1 {
+			if s.gapSince.IsZero() {
+				s.gapSince = now
+			}
+			if now.Sub(s.gapSince) < gapTimeout {
+				return delivered, true
+			}
+			// the missing versions are rolled back
+		}
+		delivered = append(delivered, change)
+		s.last = change.ResourceVersion
+		s.gapSince = time.Time{}
+	}
+	return delivered, false
+}
@@ -0,0 +1,222 @@
+// Copyright (c) 2025 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package watcher
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/watch"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/restapis/util"
+)
+
+const (
+	// resyncInterval is how often the watcher reads the changes without the notification
+	resyncInterval  = 30 * time.Second
+	changeBatchSize = 500
+)
+
+// Resource is the object of the watch with its id in the table.
+type Resource struct {
+	ID     string
+	Object client.Object
+}
+
+// Source loads the watched resources of a table for a watcher, it applies the filters of the watch request, e.g. the
+// label selector and the visible hubs.
+type Source interface {
+	// Table is the table of the changes, e.g. "status.managed_clusters"
+	Table() string
+	// List returns the current resources matching the filters
+	List(ctx context.Context) ([]Resource, error)
+	// Get returns the resource of the id and whether it matches the filters, the resource is nil if it doesn't exist
+	// or the watcher can't see it
+	Get(ctx context.Context, id string) (client.Object, bool, error)
+	// Tombstone returns the object with the name only, it's the object of the DELETED event of the removed resource
+	Tombstone(name types.NamespacedName) client.Object
+}
+
+// Serve streams the changes of the source to the client as the watch events until the client disconnects. The watch
+// starts from the "resourceVersion" of the request, or sends the current resources as ADDED events first if it's
+// empty. The resource version of the events is the version of the change, the client resumes the watch with it.
+func Serve(ginCtx *gin.Context, broker *Broker, source Source) {
+	ctx := ginCtx.Request.Context()
+	version, err := ParseResourceVersion(ginCtx.Query("resourceVersion"))
+	if err != nil {
+		ginCtx.String(http.StatusBadRequest, err.Error())
+		fmt.Fprintf(gin.DefaultWriter, "failed to parse resource version: %s\n", err.Error())
+		return
+	}
+
+	w := newWatch(source, version > 0)
+	if w.resumed {
+		if err := checkExpired(ctx, version); err != nil {
+			if errors.Is(err, ErrResourceVersionExpired) {
+				ginCtx.JSON(http.StatusGone, &metav1.Status{
+					TypeMeta: metav1.TypeMeta{Kind: "Status", APIVersion: "v1"},
+					Status:   metav1.StatusFailure,
+					Message:  fmt.Sprintf("the resource version %d is too old", version),
+					Reason:   metav1.StatusReasonExpired,
+					Code:     http.StatusGone,
+				})
+				return
+			}
+			ginCtx.String(http.StatusInternalServerError, "internal error")
+			fmt.Fprintf(gin.DefaultWriter, "failed to check the resource version: %v\n", err)
+			return
+		}
+	} else if version, err = CurrentVersion(ctx); err != nil {
+		ginCtx.String(http.StatusInternalServerError, "internal error")
+		fmt.Fprintf(gin.DefaultWriter, "%v\n", err)
+		return
+	}
+
+	// subscribe before listing, so the changes during the listing are sent later
+	wakeup, cancel := broker.Subscribe(source.Table())
+	defer cancel()
+
+	writer := ginCtx.Writer
+	header := writer.Header()
+	header.Set("Transfer-Encoding", "chunked")
+	header.Set("Content-Type", "application/json")
+	writer.WriteHeader(http.StatusOK)
+
+	if !w.resumed {
+		resources, err := source.List(ctx)
+		if err != nil {
+			fmt.Fprintf(gin.DefaultWriter, "failed to list the resources of %s: %v\n", source.Table(), err)
+			return
+		}
+		for _, resource := range resources {
+			w.sent[resource.ID] = client.ObjectKeyFromObject(resource.Object)
+			if err := sendEvent(writer, watch.Added, resource.Object, version); err != nil {
+				fmt.Fprintf(gin.DefaultWriter, "error in sending watch event: %v\n", err)
+				return
+			}
+		}
+		writer.Flush()
+	}
+
+	resync := time.NewTicker(resyncInterval)
+	defer resync.Stop()
+	seq := &sequencer{last: version}
+	for {
+		pending, err := w.sendChanges(ctx, writer, seq)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			fmt.Fprintf(gin.DefaultWriter, "failed to send the changes of %s: %v\n", source.Table(), err)
+		}
+
+		var retry <-chan time.Time
+		if pending {
+			retry = time.After(gapTimeout / 2)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-wakeup:
+		case <-resync.C:
+		case <-retry:
+		}
+	}
+}
+
+// watchState tracks the resources sent to the watcher.
+type watchState struct {
+	source Source
+	// resumed is true if the watch starts from the resource version of the client, the resources which the client
+	// has are unknown then
+	resumed bool
+	// sent is the resources which the client has
+	sent map[string]types.NamespacedName
+	// deleted is the resources which are sent as deleted while resuming the watch
+	deleted map[string]bool
+}
+
+func newWatch(source Source, resumed bool) *watchState {
+	return &watchState{
+		source:  source,
+		resumed: resumed,
+		sent:    map[string]types.NamespacedName{},
+		deleted: map[string]bool{},
+	}
+}
+
+// sendChanges sends the changes after the last version, it returns true if some changes are held by the missing
+// version.
+func (w *watchState) sendChanges(ctx context.Context, writer gin.ResponseWriter, seq *sequencer) (bool, error) {
+	defer writer.Flush()
+	for {
+		changes, err := listChanges(ctx, seq.last, changeBatchSize)
+		if err != nil {
+			return false, err
+		}
+		delivered, pending := seq.next(changes, time.Now())
+		for _, change := range delivered {
+			if change.ResourceTable != w.source.Table() {
+				continue
+			}
+			obj, matched, err := w.source.Get(ctx, change.ResourceID)
+			if err != nil {
+				return false, err
+			}
+			eventType, obj := w.event(change.ResourceID, change.ChangeType, obj, matched)
+			if eventType == "" {
+				continue
+			}
+			if err := sendEvent(writer, eventType, obj, change.ResourceVersion); err != nil {
+				return false, err
+			}
+		}
+		if pending || len(changes) < changeBatchSize {
+			return pending, nil
+		}
+	}
+}
+
+// event returns the event type and the object of the change for the watcher, the event type is empty if the watcher
+// needn't know the change.
+func (w *watchState) event(id, changeType string, obj client.Object, matched bool) (watch.EventType, client.Object) {
+	name, sent := w.sent[id]
+	switch {
+	case obj != nil && matched:
+		w.sent[id] = client.ObjectKeyFromObject(obj)
+		delete(w.deleted, id)
+		if sent || (w.resumed && changeType != string(watch.Added)) {
+			return watch.Modified, obj
+		}
+		return watch.Added, obj
+	case sent:
+		delete(w.sent, id)
+		if obj == nil {
+			obj = w.source.Tombstone(name)
+		}
+		return watch.Deleted, obj
+	case obj != nil && w.resumed && !w.deleted[id]:
+		// the client might have the resource before it's deleted or stops matching the filters
+		w.deleted[id] = true
+		return watch.Deleted, obj
+	}
+	return "", nil
+}
+
+func sendEvent(writer gin.ResponseWriter, eventType watch.EventType, obj client.Object, version int64) error {
+	obj.SetResourceVersion(strconv.FormatInt(version, 10))
+	return util.SendWatchEvent(&metav1.WatchEvent{
+		Type:   string(eventType),
+		Object: runtime.RawExtension{Object: obj},
+	}, writer)
+}
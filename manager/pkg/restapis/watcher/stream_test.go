@@ -0,0 +1,111 @@
+// Copyright (c) 2025 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package watcher
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/watch"
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
+)
+
+type fakeSource struct{}
+
+func (s *fakeSource) Table() string { return "status.managed_clusters" }
+
+func (s *fakeSource) List(ctx context.Context) ([]Resource, error) { return nil, nil }
+
+func (s *fakeSource) Get(ctx context.Context, id string) (client.Object, bool, error) { return nil, false, nil }
+
+func (s *fakeSource) Tombstone(name types.NamespacedName) client.Object {
+	cluster := &clusterv1.ManagedCluster{}
+	cluster.SetName(name.Name)
+	return cluster
+}
+
+func cluster(name string) client.Object {
+	cluster := &clusterv1.ManagedCluster{}
+	cluster.SetName(name)
+	return cluster
+}
+
+func TestSequencer(t *testing.T) {
+	changes := func(versions ...int64) []models.ResourceChange {
+		result := []models.ResourceChange{}
+		for _, version := range versions {
+			result = append(result, models.ResourceChange{ResourceVersion: version})
+		}
+		return result
+	}
+	versions := func(changes []models.ResourceChange) []int64 {
+		result := []int64{}
+		for _, change := range changes {
+			result = append(result, change.ResourceVersion)
+		}
+		return result
+	}
+
+	now := time.Now()
+	seq := &sequencer{last: 10}
+	delivered, pending := seq.next(changes(11, 12, 14, 15), now)
+	assert.Equal(t, []int64{11, 12}, versions(delivered))
+	assert.True(t, pending)
+
+	// the missing version is committed later
+	delivered, pending = seq.next(changes(13, 14, 15), now.Add(time.Second))
+	assert.Equal(t, []int64{13, 14, 15}, versions(delivered))
+	assert.False(t, pending)
+
+	// the missing version is rolled back
+	delivered, pending = seq.next(changes(17), now.Add(2*time.Second))
+	assert.Empty(t, delivered)
+	assert.True(t, pending)
+	delivered, pending = seq.next(changes(17, 18), now.Add(2*time.Second+gapTimeout))
+	assert.Equal(t, []int64{17, 18}, versions(delivered))
+	assert.False(t, pending)
+	assert.Equal(t, int64(18), seq.last)
+}
+
+func TestWatchEvent(t *testing.T) {
+	w := newWatch(&fakeSource{}, false)
+	w.sent["1"] = types.NamespacedName{Name: "mc1"}
+
+	eventType, _ := w.event("1", "MODIFIED", cluster("mc1"), true)
+	assert.Equal(t, watch.Modified, eventType)
+
+	// the resource stops matching the label selector
+	eventType, obj := w.event("1", "MODIFIED", cluster("mc1"), false)
+	assert.Equal(t, watch.Deleted, eventType)
+	assert.Equal(t, "mc1", obj.GetName())
+	eventType, _ = w.event("1", "MODIFIED", cluster("mc1"), false)
+	assert.Empty(t, eventType)
+
+	eventType, _ = w.event("1", "MODIFIED", cluster("mc1"), true)
+	assert.Equal(t, watch.Added, eventType)
+
+	// the resource is removed from the table or isn't visible to the watcher
+	eventType, obj = w.event("1", "DELETED", nil, false)
+	assert.Equal(t, watch.Deleted, eventType)
+	assert.Equal(t, "mc1", obj.GetName())
+	eventType, _ = w.event("2", "ADDED", nil, false)
+	assert.Empty(t, eventType)
+
+	// the resources which the client has are unknown for the resumed watch
+	w = newWatch(&fakeSource{}, true)
+	eventType, _ = w.event("1", "MODIFIED", cluster("mc1"), true)
+	assert.Equal(t, watch.Modified, eventType)
+	eventType, _ = w.event("2", "ADDED", cluster("mc2"), true)
+	assert.Equal(t, watch.Added, eventType)
+	eventType, _ = w.event("3", "DELETED", cluster("mc3"), false)
+	assert.Equal(t, watch.Deleted, eventType)
+	eventType, _ = w.event("3", "MODIFIED", cluster("mc3"), false)
+	assert.Empty(t, eventType)
+}
@@ -17,6 +17,8 @@ func NewBaseObjectsBundle() ObjectsBundle {
 type baseObjectsBundle struct {
 	Objects        []metav1.Object `json:"objects"`
 	DeletedObjects []metav1.Object `json:"deletedObjects"`
+	Version        int64           `json:"version,omitempty"`
+	BaseVersion    int64           `json:"baseVersion,omitempty"`
 }
 
 // AddObject adds an object to the bundle.
@@ -30,6 +32,12 @@ func (b *baseObjectsBundle) AddDeletedObject(object metav1.Object) {
 	b.DeletedObjects = append(b.DeletedObjects, object)
 }
 
+// SetVersion sets the version of the bundle, and the base version if it's a delta bundle.
+func (b *baseObjectsBundle) SetVersion(version, baseVersion int64) {
+	b.Version = version
+	b.BaseVersion = baseVersion
+}
+
 // setMetaDataAnnotation sets metadata annotation on the given object.
 func setMetaDataAnnotation(object metav1.Object, key string, value string) {
 	annotations := object.GetAnnotations()
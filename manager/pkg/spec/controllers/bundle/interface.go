@@ -17,4 +17,6 @@ type ObjectsBundle interface {
 	AddObject(object metav1.Object, objectUID string)
 	// AddDeletedObject adds a deleted object to the bundle.
 	AddDeletedObject(object metav1.Object)
+	// SetVersion sets the version of the bundle, and the base version if it's a delta bundle.
+	SetVersion(version, baseVersion int64)
 }
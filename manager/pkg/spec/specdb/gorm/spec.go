@@ -116,6 +116,19 @@ func (p *gormSpecDB) DeleteSpecObject(ctx context.Context, tableName, name, name
 // GetObjectsBundle returns a bundle of objects from a specific table.
 func (p *gormSpecDB) GetObjectsBundle(ctx context.Context, tableName string, createObjFunc bundle.CreateObjectFunction,
 	intoBundle bundle.ObjectsBundle,
+) (*time.Time, error) {
+	return p.getObjectsBundle(ctx, tableName, nil, createObjFunc, intoBundle)
+}
+
+// GetUpdatedObjectsBundle returns a bundle of objects updated or deleted after the timestamp from a specific table.
+func (p *gormSpecDB) GetUpdatedObjectsBundle(ctx context.Context, tableName string, since time.Time,
+	createObjFunc bundle.CreateObjectFunction, intoBundle bundle.ObjectsBundle,
+) (*time.Time, error) {
+	return p.getObjectsBundle(ctx, tableName, &since, createObjFunc, intoBundle)
+}
+
+func (p *gormSpecDB) getObjectsBundle(ctx context.Context, tableName string, since *time.Time,
+	createObjFunc bundle.CreateObjectFunction, intoBundle bundle.ObjectsBundle,
 ) (*time.Time, error) {
 	timestamp, err := p.GetLastUpdateTimestamp(ctx, tableName, true)
 	if err != nil {
@@ -124,9 +137,15 @@ func (p *gormSpecDB) GetObjectsBundle(ctx context.Context, tableName string, cre
 
 	db := database.GetGorm()
 
-	rows, err := db.Raw(fmt.Sprintf(`SELECT id,payload,deleted FROM spec.%s WHERE
+	query := fmt.Sprintf(`SELECT id,payload,deleted FROM spec.%s WHERE
 		payload->'metadata'->'labels'->'global-hub.open-cluster-management.io/global-resource' IS NOT NULL`,
-		tableName)).Rows()
+		tableName)
+	args := []interface{}{}
+	if since != nil {
+		query += " AND updated_at > ?"
+		args = append(args, *since)
+	}
+	rows, err := db.Raw(query, args...).Rows()
 	if err != nil {
 		return nil, fmt.Errorf(errQueryTableFailedTemplate, tableName, err)
 	}
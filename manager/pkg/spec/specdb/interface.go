@@ -30,4 +30,7 @@ type ObjectsSpecDB interface {
 	// GetObjectsBundle returns a bundle of objects from a specific table.
 	GetObjectsBundle(ctx context.Context, tableName string, createObjFunc bundle.CreateObjectFunction,
 		intoBundle bundle.ObjectsBundle) (*time.Time, error)
+	// GetUpdatedObjectsBundle returns a bundle of objects updated or deleted after the timestamp from a specific table.
+	GetUpdatedObjectsBundle(ctx context.Context, tableName string, since time.Time,
+		createObjFunc bundle.CreateObjectFunction, intoBundle bundle.ObjectsBundle) (*time.Time, error)
 }
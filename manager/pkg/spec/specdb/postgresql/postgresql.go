@@ -134,15 +134,34 @@ func (p *PostgreSQL) DeleteSpecObject(ctx context.Context, tableName, name, name
 // GetObjectsBundle returns a bundle of objects from a specific table.
 func (p *PostgreSQL) GetObjectsBundle(ctx context.Context, tableName string, createObjFunc bundle.CreateObjectFunction,
 	intoBundle bundle.ObjectsBundle,
+) (*time.Time, error) {
+	return p.getObjectsBundle(ctx, tableName, nil, createObjFunc, intoBundle)
+}
+
+// GetUpdatedObjectsBundle returns a bundle of objects updated or deleted after the timestamp from a specific table.
+func (p *PostgreSQL) GetUpdatedObjectsBundle(ctx context.Context, tableName string, since time.Time,
+	createObjFunc bundle.CreateObjectFunction, intoBundle bundle.ObjectsBundle,
+) (*time.Time, error) {
+	return p.getObjectsBundle(ctx, tableName, &since, createObjFunc, intoBundle)
+}
+
+func (p *PostgreSQL) getObjectsBundle(ctx context.Context, tableName string, since *time.Time,
+	createObjFunc bundle.CreateObjectFunction, intoBundle bundle.ObjectsBundle,
 ) (*time.Time, error) {
 	timestamp, err := p.GetLastUpdateTimestamp(ctx, tableName, true)
 	if err != nil {
 		return nil, err
 	}
 
-	rows, err := p.conn.Query(ctx, fmt.Sprintf(`SELECT id,payload,deleted FROM spec.%s WHERE
+	query := fmt.Sprintf(`SELECT id,payload,deleted FROM spec.%s WHERE
 		payload->'metadata'->'labels'->'global-hub.open-cluster-management.io/global-resource' IS NOT NULL`,
-		tableName))
+		tableName)
+	args := []interface{}{}
+	if since != nil {
+		query += " AND updated_at > $1"
+		args = append(args, *since)
+	}
+	rows, err := p.conn.Query(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf(failedQueryMsg, tableName, err)
 	}
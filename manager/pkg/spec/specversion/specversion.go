@@ -0,0 +1,60 @@
+package specversion
+
+import (
+	"sync"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/spec"
+)
+
+// the spec bundle versions applied by the managed hubs, the spec syncers send the delta bundles based on them
+var (
+	mutex sync.RWMutex
+	// appliedVersions is the applied versions by the hub name and event type
+	appliedVersions = map[string]map[string]spec.AppliedSpecVersion{}
+	// gapGeneration is increased once any hub reports a gap, then the spec syncers resend the bundles
+	gapGeneration uint64
+)
+
+// Update replaces the applied versions of the hub with the reported ones
+func Update(hubName string, versionBundle *spec.SpecVersionBundle) {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	versions := map[string]spec.AppliedSpecVersion{}
+	gap := false
+	for eventType, version := range versionBundle.Versions {
+		if version == nil {
+			continue
+		}
+		versions[eventType] = *version
+		if version.Gap {
+			gap = true
+		}
+	}
+	appliedVersions[hubName] = versions
+	if gap {
+		gapGeneration++
+	}
+}
+
+// Delete removes the applied versions of the hub, e.g. the hub is detached
+func Delete(hubName string) {
+	mutex.Lock()
+	defer mutex.Unlock()
+	delete(appliedVersions, hubName)
+}
+
+// Applied returns the version of the event type applied by the hub
+func Applied(hubName, eventType string) (spec.AppliedSpecVersion, bool) {
+	mutex.RLock()
+	defer mutex.RUnlock()
+	version, found := appliedVersions[hubName][eventType]
+	return version, found
+}
+
+// GapGeneration returns the generation of the gaps reported by the hubs
+func GapGeneration() uint64 {
+	mutex.RLock()
+	defer mutex.RUnlock()
+	return gapGeneration
+}
@@ -27,13 +27,14 @@ func AddApplicationsDBToTransportSyncer(mgr ctrl.Manager, specDB specdb.SpecDB,
 ) error {
 	createObjFunc := func() metav1.Object { return &applicationv1beta1.Application{} }
 	lastSyncTimestampPtr := &time.Time{}
+	router := newHubRouter(mgr.GetClient())
 
 	if err := mgr.Add(&genericDBToTransportSyncer{
 		log:            logger.ZapLogger("db-to-transport-syncer-application"),
 		intervalPolicy: interval.NewExponentialBackoffPolicy(specSyncInterval),
 		syncBundleFunc: func(ctx context.Context) (bool, error) {
 			return syncObjectsBundle(ctx, producer, applicationsMsgKey, specDB, applicationsTableName,
-				createObjFunc, bundle.NewBaseObjectsBundle, lastSyncTimestampPtr)
+				createObjFunc, bundle.NewBaseObjectsBundle, lastSyncTimestampPtr, router)
 		},
 	}); err != nil {
 		return fmt.Errorf("failed to add applications db to transport syncer - %w", err)
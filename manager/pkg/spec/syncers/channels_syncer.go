@@ -27,13 +27,14 @@ func AddChannelsDBToTransportSyncer(mgr ctrl.Manager, specDB specdb.SpecDB, prod
 ) error {
 	createObjFunc := func() metav1.Object { return &channelv1.Channel{} }
 	lastSyncTimestampPtr := &time.Time{}
+	router := newHubRouter(mgr.GetClient())
 
 	if err := mgr.Add(&genericDBToTransportSyncer{
 		log:            logger.ZapLogger("db-to-transport-syncer-channels"),
 		intervalPolicy: interval.NewExponentialBackoffPolicy(specSyncInterval),
 		syncBundleFunc: func(ctx context.Context) (bool, error) {
 			return syncObjectsBundle(ctx, producer, channelsMsgKey, specDB, channelsTableName,
-				createObjFunc, bundle.NewBaseObjectsBundle, lastSyncTimestampPtr)
+				createObjFunc, bundle.NewBaseObjectsBundle, lastSyncTimestampPtr, router)
 		},
 	}); err != nil {
 		return fmt.Errorf("failed to add channels db to transport syncer - %w", err)
@@ -34,7 +34,7 @@ func AddHoHConfigDBToTransportSyncer(mgr ctrl.Manager, specDB specdb.SpecDB, pro
 		intervalPolicy: interval.NewExponentialBackoffPolicy(specSyncInterval),
 		syncBundleFunc: func(ctx context.Context) (bool, error) {
 			return syncObjectsBundle(ctx, producer, configMsgKey, specDB, configTableName,
-				createObjFunc, bundle.NewBaseObjectsBundle, lastSyncTimestampPtr)
+				createObjFunc, bundle.NewBaseObjectsBundle, lastSyncTimestampPtr, nil)
 		},
 	}); err != nil {
 		return fmt.Errorf("failed to add config db to transport syncer - %w", err)
@@ -7,9 +7,11 @@ import (
 	"time"
 
 	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/util/sets"
 
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/spec/controllers/bundle"
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/spec/specdb"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/spec/specversion"
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/spec/syncers/interval"
 	"github.com/stolostron/multicluster-global-hub/pkg/constants"
 	"github.com/stolostron/multicluster-global-hub/pkg/transport"
@@ -79,42 +81,112 @@ func (syncer *genericDBToTransportSyncer) periodicSync(ctx context.Context) {
 	}
 }
 
+// deltaOverlap is subtracted from the base version when querying the delta objects, so that the objects committed by
+// the concurrent transactions with an earlier update timestamp aren't missed
+const deltaOverlap = 10 * time.Second
+
 // syncObjectsBundle performs the actual sync logic and returns true if bundle was committed to transport,
-// otherwise false.
+// otherwise false. The objects are delivered to the hubs resolved by the router, or broadcasted if the router is nil.
+// With the router, only the objects changed after the version applied by all the hubs are sent in a delta bundle.
 func syncObjectsBundle(ctx context.Context, producer transport.Producer, eventType string,
 	specDB specdb.SpecDB, dbTableName string, createObjFunc bundle.CreateObjectFunction,
-	createBundleFunc bundle.CreateBundleFunction, lastSyncTimestampPtr *time.Time,
+	createBundleFunc bundle.CreateBundleFunction, lastSyncTimestampPtr *time.Time, router *hubRouter,
 ) (bool, error) {
 	lastUpdateTimestamp, err := specDB.GetLastUpdateTimestamp(ctx, dbTableName, true) // filter local resources
 	if err != nil {
 		return false, fmt.Errorf("unable to sync bundle - %w", err)
 	}
 
-	if !lastUpdateTimestamp.After(*lastSyncTimestampPtr) { // sync only if something has changed
+	// the objects are resynced if the hubs or the cluster set bindings are changed
+	var topology *hubTopology
+	fingerprint := ""
+	if router != nil {
+		if topology, err = getHubTopology(ctx, router.client); err != nil {
+			return false, fmt.Errorf("unable to sync bundle - %w", err)
+		}
+		if fingerprint, err = topology.fingerprint(); err != nil {
+			return false, fmt.Errorf("unable to sync bundle - %w", err)
+		}
+	}
+
+	// sync only if something has changed, or any hub reports a gap of the applied versions
+	gapGeneration := specversion.GapGeneration()
+	if !lastUpdateTimestamp.After(*lastSyncTimestampPtr) && (router == nil ||
+		(fingerprint == router.lastFingerprint && gapGeneration == router.lastGapGeneration)) {
 		return false, nil
 	}
 
-	// if we got here, then the last update timestamp from db is after what we have in memory.
-	// this means something has changed in db, syncing all the objects to transport.
-	bundleResult := createBundleFunc()
-	lastUpdateTimestamp, err = specDB.GetObjectsBundle(ctx, dbTableName, createObjFunc, bundleResult)
+	// all the objects are sent if the topology is changed, or no hub applies the bundle yet
+	baseVersion := int64(0)
+	if router != nil && fingerprint == router.lastFingerprint {
+		baseVersion = appliedBaseVersion(topology, eventType)
+	}
+
+	var bundleResult bundle.ObjectsBundle = &routedObjectsBundle{}
+	if router == nil {
+		bundleResult = createBundleFunc()
+	}
+	if baseVersion > 0 {
+		lastUpdateTimestamp, err = specDB.GetUpdatedObjectsBundle(ctx, dbTableName,
+			time.UnixMicro(baseVersion).Add(-deltaOverlap), createObjFunc, bundleResult)
+	} else {
+		lastUpdateTimestamp, err = specDB.GetObjectsBundle(ctx, dbTableName, createObjFunc, bundleResult)
+	}
 	if err != nil {
 		return false, fmt.Errorf("unable to sync bundle - %w", err)
 	}
 
-	// send message to transport
-	payloadBytes, err := json.Marshal(bundleResult)
-	if err != nil {
-		return false, fmt.Errorf("failed to sync marshal bundle(%s)", eventType)
+	destinationBundles := map[string]bundle.ObjectsBundle{transport.Broadcast: bundleResult}
+	var targets map[string]sets.Set[string]
+	if router != nil {
+		destinationBundles, targets = router.route(topology, bundleResult.(*routedObjectsBundle), createObjFunc,
+			createBundleFunc)
 	}
 
-	evt := utils.ToCloudEvent(eventType, constants.CloudEventSourceGlobalHub, transport.Broadcast, payloadBytes)
-	if err := producer.SendEvent(ctx, evt); err != nil {
-		return false, fmt.Errorf("failed to sync message(%s) from table(%s) to destination(%s) - %w",
-			eventType, dbTableName, transport.Broadcast, err)
+	// send message to transport
+	for _, destination := range sortedDestinations(destinationBundles) {
+		destinationBundles[destination].SetVersion(lastUpdateTimestamp.UnixMicro(), baseVersion)
+		payloadBytes, err := json.Marshal(destinationBundles[destination])
+		if err != nil {
+			return false, fmt.Errorf("failed to sync marshal bundle(%s)", eventType)
+		}
+
+		evt := utils.ToCloudEvent(eventType, constants.CloudEventSourceGlobalHub, destination, payloadBytes)
+		if err := producer.SendEvent(ctx, evt); err != nil {
+			return false, fmt.Errorf("failed to sync message(%s) from table(%s) to destination(%s) - %w",
+				eventType, dbTableName, destination, err)
+		}
 	}
 
 	// updating value to retain same ptr between calls
 	*lastSyncTimestampPtr = *lastUpdateTimestamp
+	if router != nil {
+		if baseVersion > 0 {
+			router.commitDelta(bundleResult.(*routedObjectsBundle), targets)
+		} else {
+			router.commit(fingerprint, targets)
+		}
+		router.lastGapGeneration = gapGeneration
+	}
 	return true, nil
 }
+
+// appliedBaseVersion returns the min version of the event type applied by the hubs. The hubs which don't report the
+// applied version yet are excluded, they report a gap if the delta bundle isn't based on their applied version. It's 0
+// if no hub reports the version, or any hub reports it doesn't apply the event type, e.g. it requests the full bundle.
+func appliedBaseVersion(topology *hubTopology, eventType string) int64 {
+	baseVersion := int64(0)
+	for hub := range topology.Hubs {
+		applied, found := specversion.Applied(hub, eventType)
+		if !found {
+			continue
+		}
+		if applied.Version <= 0 {
+			return 0
+		}
+		if baseVersion == 0 || applied.Version < baseVersion {
+			baseVersion = applied.Version
+		}
+	}
+	return baseVersion
+}
@@ -0,0 +1,40 @@
+package syncers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/spec/specversion"
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/spec"
+)
+
+func TestAppliedBaseVersion(t *testing.T) {
+	topology := &hubTopology{Hubs: map[string]map[string]string{"hub1": {}, "hub2": {}}}
+	defer specversion.Delete("hub1")
+	defer specversion.Delete("hub2")
+
+	// the full bundle is required if no hub reports the applied version
+	assert.Equal(t, int64(0), appliedBaseVersion(topology, "policies"))
+
+	// the hub which doesn't report the applied version is excluded
+	specversion.Update("hub1", &spec.SpecVersionBundle{Versions: map[string]*spec.AppliedSpecVersion{
+		"policies": {Version: 200},
+	}})
+	assert.Equal(t, int64(200), appliedBaseVersion(topology, "policies"))
+
+	// the delta bundle is based on the min version applied by the hubs
+	gapGeneration := specversion.GapGeneration()
+	specversion.Update("hub2", &spec.SpecVersionBundle{Versions: map[string]*spec.AppliedSpecVersion{
+		"policies": {Version: 100, Gap: true},
+	}})
+	assert.Equal(t, int64(100), appliedBaseVersion(topology, "policies"))
+	assert.Equal(t, gapGeneration+1, specversion.GapGeneration())
+	assert.Equal(t, int64(0), appliedBaseVersion(topology, "placements"))
+
+	// the full bundle is required if any hub reports a gap without the applied version
+	specversion.Update("hub2", &spec.SpecVersionBundle{Versions: map[string]*spec.AppliedSpecVersion{
+		"policies": {Version: 0, Gap: true},
+	}})
+	assert.Equal(t, int64(0), appliedBaseVersion(topology, "policies"))
+}
@@ -0,0 +1,313 @@
+package syncers
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"go.uber.org/zap"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+	clusterv1beta2 "open-cluster-management.io/api/cluster/v1beta2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/spec/controllers/bundle"
+	"github.com/stolostron/multicluster-global-hub/pkg/constants"
+	"github.com/stolostron/multicluster-global-hub/pkg/logger"
+	"github.com/stolostron/multicluster-global-hub/pkg/transport"
+)
+
+// hubTopology is the snapshot of the managed hubs and the cluster sets bound to the namespaces on the global hub
+type hubTopology struct {
+	// Hubs is the labels of the managed hub clusters by the hub name
+	Hubs map[string]map[string]string `json:"hubs"`
+	// Bindings is the bound cluster sets by the namespace
+	Bindings map[string][]string `json:"bindings"`
+	// ClusterSets is the selector of the clusters by the cluster set name
+	ClusterSets map[string]*metav1.LabelSelector `json:"clusterSets"`
+}
+
+// fingerprint identifies the topology, the objects are resynced once it's changed
+func (t *hubTopology) fingerprint() (string, error) {
+	data, err := json.Marshal(t) // the keys of the map are sorted by the marshaling
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// targetHubs returns the managed hubs which the object should be delivered to, the nil result means the object is
+// delivered to all the hubs. The hubs are selected by the hub selector annotation of the object, otherwise by the
+// cluster sets bound to the namespace of the object.
+func (t *hubTopology) targetHubs(obj metav1.Object) (sets.Set[string], error) {
+	if hubSelector, found := obj.GetAnnotations()[constants.HubSelectorAnnotation]; found {
+		selector, err := labels.Parse(hubSelector)
+		if err != nil {
+			return sets.New[string](), fmt.Errorf("invalid hub selector %q: %w", hubSelector, err)
+		}
+		return t.selectHubs(selector), nil
+	}
+
+	clusterSets := t.Bindings[obj.GetNamespace()]
+	if obj.GetNamespace() == "" || len(clusterSets) == 0 {
+		return nil, nil
+	}
+	targets := sets.New[string]()
+	for _, clusterSet := range clusterSets {
+		selector, err := t.clusterSetSelector(clusterSet)
+		if err != nil {
+			return sets.New[string](), err
+		}
+		targets = targets.Union(t.selectHubs(selector))
+	}
+	return targets, nil
+}
+
+// clusterSetSelector returns the selector of the clusters in the cluster set, the exclusive cluster set label is used
+// if the cluster set doesn't specify the label selector
+func (t *hubTopology) clusterSetSelector(clusterSet string) (labels.Selector, error) {
+	labelSelector, found := t.ClusterSets[clusterSet]
+	if !found || labelSelector == nil {
+		return labels.SelectorFromSet(labels.Set{clusterv1beta2.ClusterSetLabel: clusterSet}), nil
+	}
+	selector, err := metav1.LabelSelectorAsSelector(labelSelector)
+	if err != nil {
+		return nil, fmt.Errorf("invalid selector of the cluster set %s: %w", clusterSet, err)
+	}
+	return selector, nil
+}
+
+func (t *hubTopology) selectHubs(selector labels.Selector) sets.Set[string] {
+	hubs := sets.New[string]()
+	for hub, hubLabels := range t.Hubs {
+		if selector.Matches(labels.Set(hubLabels)) {
+			hubs.Insert(hub)
+		}
+	}
+	return hubs
+}
+
+// getHubTopology builds the topology from the managed clusters, cluster sets and cluster set bindings of the global
+// hub cluster
+func getHubTopology(ctx context.Context, c client.Client) (*hubTopology, error) {
+	topology := &hubTopology{
+		Hubs:        map[string]map[string]string{},
+		Bindings:    map[string][]string{},
+		ClusterSets: map[string]*metav1.LabelSelector{},
+	}
+
+	clusters := &clusterv1.ManagedClusterList{}
+	if err := c.List(ctx, clusters); err != nil {
+		return nil, fmt.Errorf("failed to list the managed hubs: %w", err)
+	}
+	for _, cluster := range clusters.Items {
+		if cluster.Name == constants.LocalClusterName || cluster.Labels[constants.LocalClusterName] == "true" {
+			continue
+		}
+		topology.Hubs[cluster.Name] = cluster.Labels
+	}
+
+	bindings := &clusterv1beta2.ManagedClusterSetBindingList{}
+	if err := c.List(ctx, bindings); err != nil {
+		return nil, fmt.Errorf("failed to list the managed cluster set bindings: %w", err)
+	}
+	for _, binding := range bindings.Items {
+		topology.Bindings[binding.Namespace] = append(topology.Bindings[binding.Namespace], binding.Spec.ClusterSet)
+	}
+	for namespace := range topology.Bindings {
+		sort.Strings(topology.Bindings[namespace])
+	}
+
+	clusterSets := &clusterv1beta2.ManagedClusterSetList{}
+	if err := c.List(ctx, clusterSets); err != nil {
+		return nil, fmt.Errorf("failed to list the managed cluster sets: %w", err)
+	}
+	for _, clusterSet := range clusterSets.Items {
+		if clusterSet.Spec.ClusterSelector.SelectorType == clusterv1beta2.LabelSelector {
+			selector := clusterSet.Spec.ClusterSelector.LabelSelector
+			if selector == nil {
+				// the empty label selector selects all the clusters, e.g. the "global" cluster set
+				selector = &metav1.LabelSelector{}
+			}
+			topology.ClusterSets[clusterSet.Name] = selector
+		}
+	}
+	return topology, nil
+}
+
+// routedObject is the object collected from the spec table
+type routedObject struct {
+	object metav1.Object
+	uid    string
+}
+
+// routedObjectsBundle collects the objects from the spec table, then the hubRouter routes them into the bundles of
+// the target hubs
+type routedObjectsBundle struct {
+	objects        []routedObject
+	deletedObjects []metav1.Object
+}
+
+func (b *routedObjectsBundle) AddObject(object metav1.Object, objectUID string) {
+	b.objects = append(b.objects, routedObject{object: object, uid: objectUID})
+}
+
+func (b *routedObjectsBundle) AddDeletedObject(object metav1.Object) {
+	b.deletedObjects = append(b.deletedObjects, object)
+}
+
+// SetVersion is a no-op, the version is set to the bundles of the destinations after routing
+func (b *routedObjectsBundle) SetVersion(version, baseVersion int64) {}
+
+// hubRouter delivers the spec objects to the hubs that need them, instead of broadcasting them to all the hubs. It
+// remembers the hubs which the objects were delivered to, so that the object is deleted from the hubs which aren't
+// targeted anymore.
+type hubRouter struct {
+	log    *zap.SugaredLogger
+	client client.Client
+	// lastTargets is the hubs which the objects were delivered to by the object key, the nil value means all the hubs
+	lastTargets map[string]sets.Set[string]
+	// lastFingerprint is the fingerprint of the topology in the latest sync
+	lastFingerprint string
+	// lastGapGeneration is the generation of the gaps reported by the hubs in the latest sync
+	lastGapGeneration uint64
+}
+
+func newHubRouter(c client.Client) *hubRouter {
+	return &hubRouter{
+		log:         logger.ZapLogger("spec-hub-router"),
+		client:      c,
+		lastTargets: map[string]sets.Set[string]{},
+	}
+}
+
+// route splits the collected objects into the bundles by the destination, the destination is transport.Broadcast if
+// all the objects are delivered to all the hubs. The returned targets should be committed once the bundles are sent.
+func (r *hubRouter) route(topology *hubTopology, collected *routedObjectsBundle,
+	createObjFunc bundle.CreateObjectFunction, createBundleFunc bundle.CreateBundleFunction,
+) (map[string]bundle.ObjectsBundle, map[string]sets.Set[string]) {
+	targets := map[string]sets.Set[string]{}
+	broadcast := true
+	for _, obj := range collected.objects {
+		hubs := r.targetHubs(topology, obj.object)
+		targets[objectKey(obj.object)] = hubs
+		if hubs != nil {
+			broadcast = false
+		}
+	}
+	for _, targetHubs := range r.lastTargets {
+		if targetHubs != nil {
+			broadcast = false
+		}
+	}
+
+	if broadcast {
+		broadcastBundle := createBundleFunc()
+		for _, obj := range collected.objects {
+			broadcastBundle.AddObject(obj.object, obj.uid)
+		}
+		for _, obj := range collected.deletedObjects {
+			broadcastBundle.AddDeletedObject(obj)
+		}
+		return map[string]bundle.ObjectsBundle{transport.Broadcast: broadcastBundle}, targets
+	}
+
+	allHubs := sets.KeySet(topology.Hubs)
+	hubBundles := map[string]bundle.ObjectsBundle{}
+	for hub := range allHubs {
+		hubBundles[hub] = createBundleFunc()
+	}
+	// resolve the nil targets to all the hubs
+	resolve := func(hubs sets.Set[string], found bool) sets.Set[string] {
+		if !found || hubs == nil {
+			return allHubs
+		}
+		return hubs
+	}
+
+	for _, obj := range collected.objects {
+		key := objectKey(obj.object)
+		currentHubs := resolve(targets[key], true)
+		for hub := range currentHubs.Intersection(allHubs) {
+			hubBundles[hub].AddObject(obj.object, obj.uid)
+		}
+		// the object isn't known by the router after restarting, it might be delivered to all the hubs before
+		lastHubs, found := r.lastTargets[key]
+		for hub := range resolve(lastHubs, found).Difference(currentHubs).Intersection(allHubs) {
+			hubBundles[hub].AddDeletedObject(deletionStub(obj.object, createObjFunc))
+		}
+	}
+
+	for _, obj := range collected.deletedObjects {
+		key := objectKey(obj)
+		hubs := resolve(r.targetHubs(topology, obj), true)
+		if lastHubs, found := r.lastTargets[key]; found {
+			hubs = hubs.Union(resolve(lastHubs, true))
+		}
+		for hub := range hubs.Intersection(allHubs) {
+			hubBundles[hub].AddDeletedObject(obj)
+		}
+	}
+	return hubBundles, targets
+}
+
+func (r *hubRouter) targetHubs(topology *hubTopology, obj metav1.Object) sets.Set[string] {
+	hubs, err := topology.targetHubs(obj)
+	if err != nil {
+		r.log.Warnw("the object isn't delivered to any hub", "namespace", obj.GetNamespace(), "name", obj.GetName(),
+			"error", err)
+	}
+	return hubs
+}
+
+// commit records the targets and topology once the bundles are delivered
+func (r *hubRouter) commit(fingerprint string, targets map[string]sets.Set[string]) {
+	r.lastTargets = targets
+	r.lastFingerprint = fingerprint
+}
+
+// commitDelta merges the targets of the objects in the delta bundle into the ones of the latest sync, the other
+// objects aren't changed so their targets are kept
+func (r *hubRouter) commitDelta(collected *routedObjectsBundle, targets map[string]sets.Set[string]) {
+	for _, obj := range collected.deletedObjects {
+		delete(r.lastTargets, objectKey(obj))
+	}
+	for key, hubs := range targets {
+		r.lastTargets[key] = hubs
+	}
+}
+
+func objectKey(obj metav1.Object) string {
+	return fmt.Sprintf("%s/%s", obj.GetNamespace(), obj.GetName())
+}
+
+// deletionStub only carries the type and name of the object, so that the hub which isn't targeted anymore can delete
+// it without seeing the content of it
+func deletionStub(obj metav1.Object, createObjFunc bundle.CreateObjectFunction) metav1.Object {
+	stub := createObjFunc()
+	stub.SetName(obj.GetName())
+	stub.SetNamespace(obj.GetNamespace())
+	runtimeObj, ok := obj.(runtime.Object)
+	runtimeStub, stubOk := stub.(runtime.Object)
+	if ok && stubOk {
+		runtimeStub.GetObjectKind().SetGroupVersionKind(runtimeObj.GetObjectKind().GroupVersionKind())
+	}
+	return stub
+}
+
+// sortedDestinations returns the destinations of the bundles in order
+func sortedDestinations(bundles map[string]bundle.ObjectsBundle) []string {
+	destinations := make([]string, 0, len(bundles))
+	for destination := range bundles {
+		destinations = append(destinations, destination)
+	}
+	sort.Strings(destinations)
+	return destinations
+}
@@ -0,0 +1,129 @@
+package syncers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+	clusterv1beta2 "open-cluster-management.io/api/cluster/v1beta2"
+	policyv1 "open-cluster-management.io/governance-policy-propagator/api/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/spec/controllers/bundle"
+	"github.com/stolostron/multicluster-global-hub/pkg/constants"
+	"github.com/stolostron/multicluster-global-hub/pkg/transport"
+)
+
+func newHub(name string, hubLabels map[string]string) *clusterv1.ManagedCluster {
+	return &clusterv1.ManagedCluster{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: hubLabels}}
+}
+
+func newPolicy(namespace, name string, annotations map[string]string) *policyv1.Policy {
+	return &policyv1.Policy{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "policy.open-cluster-management.io/v1", Kind: "Policy"},
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name, Annotations: annotations},
+	}
+}
+
+// bundledObjects returns the names of the objects and the deleted objects in the bundle
+func bundledObjects(b bundle.ObjectsBundle) ([]string, []string) {
+	objects, deletedObjects := []string{}, []string{}
+	routed := b.(*routedObjectsBundle)
+	for _, obj := range routed.objects {
+		objects = append(objects, obj.object.GetName())
+	}
+	for _, obj := range routed.deletedObjects {
+		deletedObjects = append(deletedObjects, obj.GetName())
+	}
+	return objects, deletedObjects
+}
+
+func TestHubRouter(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, clusterv1.AddToScheme(scheme))
+	require.NoError(t, clusterv1beta2.AddToScheme(scheme))
+
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		newHub("hub1", map[string]string{clusterv1beta2.ClusterSetLabel: "tenant-a", "env": "prod"}),
+		newHub("hub2", map[string]string{clusterv1beta2.ClusterSetLabel: "tenant-b", "env": "dev"}),
+		newHub(constants.LocalClusterName, map[string]string{constants.LocalClusterName: "true"}),
+		&clusterv1beta2.ManagedClusterSetBinding{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns-a", Name: "tenant-a"},
+			Spec:       clusterv1beta2.ManagedClusterSetBindingSpec{ClusterSet: "tenant-a"},
+		},
+		&clusterv1beta2.ManagedClusterSetBinding{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns-global", Name: "global"},
+			Spec:       clusterv1beta2.ManagedClusterSetBindingSpec{ClusterSet: "global"},
+		},
+		&clusterv1beta2.ManagedClusterSet{
+			ObjectMeta: metav1.ObjectMeta{Name: "global"},
+			Spec: clusterv1beta2.ManagedClusterSetSpec{
+				ClusterSelector: clusterv1beta2.ManagedClusterSelector{
+					SelectorType:  clusterv1beta2.LabelSelector,
+					LabelSelector: &metav1.LabelSelector{},
+				},
+			},
+		},
+	).Build()
+
+	topology, err := getHubTopology(context.Background(), fakeClient)
+	require.NoError(t, err)
+	assert.Len(t, topology.Hubs, 2)
+
+	createObjFunc := func() metav1.Object { return &policyv1.Policy{} }
+	createBundleFunc := func() bundle.ObjectsBundle { return &routedObjectsBundle{} }
+	router := newHubRouter(fakeClient)
+
+	// broadcast the objects which aren't bound to any cluster set
+	collected := &routedObjectsBundle{}
+	collected.AddObject(newPolicy("default", "policy-default", nil), "1")
+	bundles, targets := router.route(topology, collected, createObjFunc, createBundleFunc)
+	require.Len(t, bundles, 1)
+	require.NotNil(t, bundles[transport.Broadcast])
+	router.commit("1", targets)
+
+	// deliver the objects to the hubs by the cluster set bindings and hub selector
+	collected = &routedObjectsBundle{}
+	collected.AddObject(newPolicy("default", "policy-default", nil), "1")
+	collected.AddObject(newPolicy("ns-a", "policy-a", nil), "2")
+	collected.AddObject(newPolicy("ns-global", "policy-global", nil), "3")
+	collected.AddObject(newPolicy("default", "policy-dev", map[string]string{
+		constants.HubSelectorAnnotation: "env=dev",
+	}), "4")
+	collected.AddDeletedObject(newPolicy("ns-a", "policy-a-deleted", nil))
+	bundles, targets = router.route(topology, collected, createObjFunc, createBundleFunc)
+	require.Len(t, bundles, 2)
+
+	objects, deletedObjects := bundledObjects(bundles["hub1"])
+	assert.ElementsMatch(t, []string{"policy-default", "policy-a", "policy-global"}, objects)
+	// the unknown objects might be broadcasted before, so they are deleted from the hubs which aren't targeted
+	assert.ElementsMatch(t, []string{"policy-a-deleted", "policy-dev"}, deletedObjects)
+
+	objects, deletedObjects = bundledObjects(bundles["hub2"])
+	assert.ElementsMatch(t, []string{"policy-default", "policy-global", "policy-dev"}, objects)
+	assert.ElementsMatch(t, []string{"policy-a"}, deletedObjects)
+	router.commit("2", targets)
+
+	// retarget the object to the other hub
+	collected = &routedObjectsBundle{}
+	collected.AddObject(newPolicy("default", "policy-dev", map[string]string{
+		constants.HubSelectorAnnotation: "env=prod",
+	}), "4")
+	bundles, _ = router.route(topology, collected, createObjFunc, createBundleFunc)
+
+	objects, deletedObjects = bundledObjects(bundles["hub1"])
+	assert.ElementsMatch(t, []string{"policy-dev"}, objects)
+	assert.Empty(t, deletedObjects)
+
+	objects, deletedObjects = bundledObjects(bundles["hub2"])
+	assert.Empty(t, objects)
+	assert.ElementsMatch(t, []string{"policy-dev"}, deletedObjects)
+	// the deleted stub only carries the type and name
+	stub := bundles["hub2"].(*routedObjectsBundle).deletedObjects[0].(*policyv1.Policy)
+	assert.Equal(t, "Policy", stub.Kind)
+	assert.Nil(t, stub.Annotations)
+}
@@ -30,13 +30,15 @@ func AddManagedClusterSetBindingsDBToTransportSyncer(mgr ctrl.Manager, specDB sp
 		return &clusterv1beta2.ManagedClusterSetBinding{}
 	}
 	lastSyncTimestampPtr := &time.Time{}
+	router := newHubRouter(mgr.GetClient())
 
 	if err := mgr.Add(&genericDBToTransportSyncer{
 		log:            logger.ZapLogger("db-to-transport-syncer-managedclustersetbinding"),
 		intervalPolicy: interval.NewExponentialBackoffPolicy(specSyncInterval),
 		syncBundleFunc: func(ctx context.Context) (bool, error) {
 			return syncObjectsBundle(ctx, producer, managedClusterSetBindingsMsgKey, specDB,
-				managedClusterSetBindingsTableName, createObjFunc, bundle.NewBaseObjectsBundle, lastSyncTimestampPtr)
+				managedClusterSetBindingsTableName, createObjFunc, bundle.NewBaseObjectsBundle, lastSyncTimestampPtr,
+				router)
 		},
 	}); err != nil {
 		return fmt.Errorf("failed to add managed-cluster-set-bindings db to transport syncer - %w", err)
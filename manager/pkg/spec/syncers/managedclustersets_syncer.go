@@ -27,13 +27,14 @@ func AddManagedClusterSetsDBToTransportSyncer(mgr ctrl.Manager, specDB specdb.Sp
 ) error {
 	createObjFunc := func() metav1.Object { return &clusterv1beta2.ManagedClusterSet{} }
 	lastSyncTimestampPtr := &time.Time{}
+	router := newHubRouter(mgr.GetClient())
 
 	if err := mgr.Add(&genericDBToTransportSyncer{
 		log:            logger.ZapLogger("db-to-transport-syncer-managedclusterset"),
 		intervalPolicy: interval.NewExponentialBackoffPolicy(specSyncInterval),
 		syncBundleFunc: func(ctx context.Context) (bool, error) {
 			return syncObjectsBundle(ctx, producer, managedClusterSetsMsgKey, specDB, managedClusterSetsTableName,
-				createObjFunc, bundle.NewBaseObjectsBundle, lastSyncTimestampPtr)
+				createObjFunc, bundle.NewBaseObjectsBundle, lastSyncTimestampPtr, router)
 		},
 	}); err != nil {
 		return fmt.Errorf("failed to add managed-cluster-sets db to transport syncer - %w", err)
@@ -27,13 +27,14 @@ func AddPlacementBindingsDBToTransportSyncer(mgr ctrl.Manager, specDB specdb.Spe
 ) error {
 	createObjFunc := func() metav1.Object { return &policyv1.PlacementBinding{} }
 	lastSyncTimestampPtr := &time.Time{}
+	router := newHubRouter(mgr.GetClient())
 
 	if err := mgr.Add(&genericDBToTransportSyncer{
 		log:            logger.ZapLogger("db-to-transport-syncer-placementrulebiding"),
 		intervalPolicy: interval.NewExponentialBackoffPolicy(specSyncInterval),
 		syncBundleFunc: func(ctx context.Context) (bool, error) {
 			return syncObjectsBundle(ctx, producer, placementBindingsMsgKey, specDB, placementBindingsTableName,
-				createObjFunc, bundle.NewBaseObjectsBundle, lastSyncTimestampPtr)
+				createObjFunc, bundle.NewBaseObjectsBundle, lastSyncTimestampPtr, router)
 		},
 	}); err != nil {
 		return fmt.Errorf("failed to add placement bindings db to transport syncer - %w", err)
@@ -27,13 +27,14 @@ func AddPlacementRulesDBToTransportSyncer(mgr ctrl.Manager, specDB specdb.SpecDB
 ) error {
 	createObjFunc := func() metav1.Object { return &placementrulev1.PlacementRule{} }
 	lastSyncTimestampPtr := &time.Time{}
+	router := newHubRouter(mgr.GetClient())
 
 	if err := mgr.Add(&genericDBToTransportSyncer{
 		log:            logger.ZapLogger("db-to-transport-syncer-placementrule"),
 		intervalPolicy: interval.NewExponentialBackoffPolicy(specSyncInterval),
 		syncBundleFunc: func(ctx context.Context) (bool, error) {
 			return syncObjectsBundle(ctx, producer, placementRulesMsgKey, specDB, placementRulesTableName,
-				createObjFunc, bundle.NewBaseObjectsBundle, lastSyncTimestampPtr)
+				createObjFunc, bundle.NewBaseObjectsBundle, lastSyncTimestampPtr, router)
 		},
 	}); err != nil {
 		return fmt.Errorf("failed to add placement rules db to transport syncer - %w", err)
@@ -27,13 +27,14 @@ func AddSubscriptionsDBToTransportSyncer(mgr ctrl.Manager, specDB specdb.SpecDB,
 ) error {
 	createObjFunc := func() metav1.Object { return &subscriptionv1.Subscription{} }
 	lastSyncTimestampPtr := &time.Time{}
+	router := newHubRouter(mgr.GetClient())
 
 	if err := mgr.Add(&genericDBToTransportSyncer{
 		log:            logger.ZapLogger("db-to-transport-syncer-subscriptions"),
 		intervalPolicy: interval.NewExponentialBackoffPolicy(specSyncInterval),
 		syncBundleFunc: func(ctx context.Context) (bool, error) {
 			return syncObjectsBundle(ctx, producer, subscriptionMsgKey, specDB, subscriptionsTableName,
-				createObjFunc, bundle.NewBaseObjectsBundle, lastSyncTimestampPtr)
+				createObjFunc, bundle.NewBaseObjectsBundle, lastSyncTimestampPtr, router)
 		},
 	}); err != nil {
 		return fmt.Errorf("failed to add subscriptions db to transport syncer - %w", err)
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"sync"
 	"time"
 
 	"go.uber.org/zap"
@@ -27,6 +28,8 @@ type ConflationCommitter struct {
 	log                  *zap.SugaredLogger
 	retrieveMetadataFunc MetadataFunc
 	committedPositions   map[string]int64
+	// positionsLock guards the committed positions, they're read by the introspection
+	positionsLock sync.Mutex
 }
 
 func NewKafkaConflationCommitter(metadataFunc MetadataFunc) *ConflationCommitter {
@@ -66,6 +69,9 @@ func (k *ConflationCommitter) commit() error {
 
 	transPositions := metadataToCommit(transportMetadatas)
 
+	k.positionsLock.Lock()
+	defer k.positionsLock.Unlock()
+
 	databaseTransports := []models.Transport{}
 	for key, transPosition := range transPositions {
 		// skip request if already committed this offset
@@ -110,6 +116,18 @@ func (k *ConflationCommitter) commit() error {
 	return nil
 }
 
+// CommittedPositions returns a copy of the committed offsets by the topic@partition.
+func (k *ConflationCommitter) CommittedPositions() map[string]int64 {
+	k.positionsLock.Lock()
+	defer k.positionsLock.Unlock()
+
+	positions := make(map[string]int64, len(k.committedPositions))
+	for key, offset := range k.committedPositions {
+		positions[key] = offset
+	}
+	return positions
+}
+
 func metadataToCommit(metadataArray []ConflationMetadata) map[string]*transport.EventPosition {
 	// extract the lowest per partition in the pending bundles, the highest per partition in the processed bundles
 	pendingLowestMetadataMap := make(map[string]*transport.EventPosition)
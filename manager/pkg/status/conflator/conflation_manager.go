@@ -1,17 +1,26 @@
 package conflator
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"sync"
 
 	cloudevents "github.com/cloudevents/sdk-go/v2"
 	"go.uber.org/zap"
 
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/status/conflator/metadata"
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/version"
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
 	"github.com/stolostron/multicluster-global-hub/pkg/logger"
 	"github.com/stolostron/multicluster-global-hub/pkg/statistics"
 	"github.com/stolostron/multicluster-global-hub/pkg/transport/consumer"
 )
 
+// ErrEventSuperseded means a newer complete state event has been processed
+var ErrEventSuperseded = errors.New("the event is superseded")
+
 // ConflationManager implements conflation units management.
 type ConflationManager struct {
 	log             *zap.SugaredLogger
@@ -39,21 +48,81 @@ func NewConflationManager(statistics *statistics.Statistics) *ConflationManager
 	}
 }
 
-// Register registers bundle type with priority and handler function within the conflation manager.
+// Register registers bundle type with priority and handler function within the conflation manager. The dependencies
+// aren't checked until Validate is called, so the handlers can be registered in any order.
 func (cm *ConflationManager) Register(registration *ConflationRegistration) {
+	cm.lock.Lock()
+	defer cm.lock.Unlock()
+
 	cm.registrations[registration.eventType] = registration
 	cm.statistics.Register(registration.eventType)
 }
 
+// AddRegistration registers the handler at runtime, e.g. from a plugin after the conflation units are created. It's
+// rejected if the event type has been registered, or the dependency is invalid.
+func (cm *ConflationManager) AddRegistration(registration *ConflationRegistration) error {
+	cm.lock.Lock()
+	defer cm.lock.Unlock()
+
+	if _, found := cm.registrations[registration.eventType]; found {
+		return fmt.Errorf("event type %s has been registered", registration.eventType)
+	}
+	registrations := make(map[string]*ConflationRegistration, len(cm.registrations)+1)
+	for eventType, existing := range cm.registrations {
+		registrations[eventType] = existing
+	}
+	registrations[registration.eventType] = registration
+	if err := validateRegistrations(registrations); err != nil {
+		return err
+	}
+
+	cm.registrations = registrations
+	cm.statistics.Register(registration.eventType)
+	for _, conflationUnit := range cm.conflationUnits {
+		conflationUnit.addRegistrations(registrations)
+	}
+	cm.log.Infow("added the registration", "eventType", registration.eventType, "priority", registration.priority)
+	return nil
+}
+
+// Validate checks the dependencies of all the registrations, it should be called once the handlers are registered.
+func (cm *ConflationManager) Validate() error {
+	cm.lock.Lock()
+	defer cm.lock.Unlock()
+
+	return validateRegistrations(cm.registrations)
+}
+
+// Registrations returns the specs of the registrations in the processing order.
+func (cm *ConflationManager) Registrations() []RegistrationSpec {
+	cm.lock.Lock()
+	defer cm.lock.Unlock()
+
+	specs := make([]RegistrationSpec, 0, len(cm.registrations))
+	for _, registration := range orderRegistrations(cm.registrations) {
+		specs = append(specs, registration.Spec())
+	}
+	return specs
+}
+
+func (cm *ConflationManager) getRegistration(eventType string) (*ConflationRegistration, bool) {
+	cm.lock.Lock()
+	defer cm.lock.Unlock()
+
+	registration, found := cm.registrations[eventType]
+	return registration, found
+}
+
 // Insert function inserts the bundle to the appropriate conflation unit.
 func (cm *ConflationManager) Insert(evt *cloudevents.Event) {
 	// validate the event
-	if _, ok := cm.registrations[evt.Type()]; !ok {
+	registration, ok := cm.getRegistration(evt.Type())
+	if !ok {
 		cm.log.Infow("event type hasn't been registered", "type", evt.Type())
 		return
 	}
 	// metadata
-	conflationMetadata := metadata.NewThresholdMetadata(consumer.TransportID(), 3, evt)
+	conflationMetadata := metadata.NewThresholdMetadata(consumer.TransportID(), registration.retryBudget, evt)
 	if conflationMetadata == nil {
 		return
 	}
@@ -61,6 +130,49 @@ func (cm *ConflationManager) Insert(evt *cloudevents.Event) {
 	cm.getConflationUnit(evt.Source()).insert(evt, conflationMetadata)
 }
 
+// Replay handles the dead lettered event with the registered handler. The complete state event is rejected if the same
+// or a newer one of the hub has been handled, since it would override the newer state. The dead lettered events aren't
+// counted as handled, so the latest dead lettered event can be replayed.
+func (cm *ConflationManager) Replay(ctx context.Context, evt *cloudevents.Event) error {
+	registration, ok := cm.getRegistration(evt.Type())
+	if !ok {
+		return fmt.Errorf("event type %s hasn't been registered", evt.Type())
+	}
+	var eventVersion *version.Version
+	if registration.syncMode == enum.CompleteStateMode {
+		var err error
+		if eventVersion, err = versionFromEvent(evt); err != nil {
+			return err
+		}
+		handledVersion := cm.getConflationUnit(evt.Source()).lastHandledVersion(evt.Type())
+		if handledVersion != nil && !eventVersion.NewerThan(handledVersion) {
+			return fmt.Errorf("%w: the version %s of the hub %s has been handled", ErrEventSuperseded,
+				handledVersion, evt.Source())
+		}
+	}
+
+	conn := database.GetConn()
+	if err := database.Lock(conn); err != nil {
+		return err
+	}
+	defer database.Unlock(conn)
+	if err := registration.handleFunc(ctx, evt); err != nil {
+		return err
+	}
+	if eventVersion != nil {
+		cm.getConflationUnit(evt.Source()).replayed(evt.Type(), eventVersion)
+	}
+	return nil
+}
+
+func versionFromEvent(evt *cloudevents.Event) (*version.Version, error) {
+	val, ok := evt.Extensions()[version.ExtVersion].(string)
+	if !ok {
+		return nil, fmt.Errorf("the event %s from %s doesn't have a version", evt.Type(), evt.Source())
+	}
+	return version.VersionFrom(val)
+}
+
 // GetTransportMetadatas provides collections of the CU's bundle transport-metadata.
 func (cm *ConflationManager) GetMetadatas() []ConflationMetadata {
 	metadata := make([]ConflationMetadata, 0)
@@ -0,0 +1,96 @@
+package conflator
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/status/conflator/dependency"
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/version"
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
+	"github.com/stolostron/multicluster-global-hub/pkg/statistics"
+)
+
+func TestConflationManagerReplay(t *testing.T) {
+	handled := 0
+	cm := NewConflationManager(statistics.NewStatistics(&statistics.StatisticsConfig{}))
+	cm.Register(NewConflationRegistration(HubClusterHeartbeatPriority, enum.CompleteStateMode,
+		string(enum.HubClusterInfoType), func(ctx context.Context, evt *cloudevents.Event) error {
+			handled++
+			return nil
+		}).WithRetryBudget(5))
+	assert.Equal(t, 5, cm.registrations[string(enum.HubClusterInfoType)].retryBudget)
+
+	newEvent := func(eventVersion string) *cloudevents.Event {
+		evt := cloudevents.NewEvent()
+		evt.SetSource("hub1")
+		evt.SetType(string(enum.HubClusterInfoType))
+		evt.SetExtension(version.ExtVersion, eventVersion)
+		return &evt
+	}
+
+	// replay the event which is newer than the handled one
+	cu := cm.getConflationUnit("hub1")
+	cu.element(string(enum.HubClusterInfoType)).(*completeElement).lastHandledVersion = &version.Version{
+		Generation: 2, Value: 3,
+	}
+	require.NoError(t, cm.Replay(context.Background(), newEvent("3.4")))
+	assert.Equal(t, 1, handled)
+
+	// reject the complete state event which is superseded by the handled one, including the replayed one
+	err := cm.Replay(context.Background(), newEvent("1.2"))
+	assert.True(t, errors.Is(err, ErrEventSuperseded))
+	err = cm.Replay(context.Background(), newEvent("3.4"))
+	assert.True(t, errors.Is(err, ErrEventSuperseded))
+	assert.Equal(t, 1, handled)
+
+	// reject the unregistered event
+	evt := newEvent("1.1")
+	evt.SetType(string(enum.ManagedClusterType))
+	assert.Error(t, cm.Replay(context.Background(), evt))
+}
+
+func TestConflationManagerAddRegistration(t *testing.T) {
+	handle := func(ctx context.Context, evt *cloudevents.Event) error { return nil }
+	cm := NewConflationManager(statistics.NewStatistics(&statistics.StatisticsConfig{}))
+	cm.Register(NewConflationRegistration(ManagedClustersPriority, enum.CompleteStateMode,
+		string(enum.ManagedClusterType), handle))
+	cm.Register(NewConflationRegistration(HubClusterInfoPriority, enum.CompleteStateMode,
+		string(enum.HubClusterInfoType), handle))
+	require.NoError(t, cm.Validate())
+
+	cu := cm.getConflationUnit("hub1")
+	clusterElement := cu.element(string(enum.ManagedClusterType))
+	require.NotNil(t, clusterElement)
+
+	// the plugin bundle is ordered by the declared priority, rather than the shared priority list
+	pluginType := enum.EventTypePrefix + "plugin.status"
+	require.NoError(t, cm.AddRegistration(NewConflationRegistrationFromSpec(RegistrationSpec{
+		EventType: pluginType,
+		SyncMode:  enum.CompleteStateMode,
+		Priority:  HubClusterInfoPriority,
+		Dependency: &dependency.Dependency{
+			EventType: string(enum.HubClusterInfoType), DependencyType: dependency.AtLeast,
+		},
+	}, handle)))
+
+	names := []string{}
+	for _, element := range cu.ElementPriorityQueue {
+		names = append(names, element.Name())
+	}
+	assert.Equal(t, []string{string(enum.HubClusterInfoType), pluginType, string(enum.ManagedClusterType)}, names)
+	// the existing element is kept
+	assert.Same(t, clusterElement, cu.element(string(enum.ManagedClusterType)))
+	assert.Equal(t, DefaultRetryBudget, cm.Registrations()[1].RetryBudget)
+
+	// reject the registered event type and the unregistered dependency
+	assert.Error(t, cm.AddRegistration(NewConflationRegistration(HubClusterInfoPriority, enum.CompleteStateMode,
+		pluginType, handle)))
+	assert.Error(t, cm.AddRegistration(NewConflationRegistration(HubClusterInfoPriority, enum.CompleteStateMode,
+		"unknown", handle).WithDependency(dependency.NewDependency("missing", dependency.ExactMatch))))
+	assert.Len(t, cu.ElementPriorityQueue, 3)
+}
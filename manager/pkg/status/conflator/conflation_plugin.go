@@ -0,0 +1,44 @@
+package conflator
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// Plugin registers the handlers of the bundles which aren't built in, e.g. from an out-of-tree package. The plugin
+// declares the priority and dependency of the bundle by the RegistrationSpec instead of the shared priority list.
+type Plugin func(cm *ConflationManager) error
+
+var (
+	plugins     = map[string]Plugin{}
+	pluginsLock sync.Mutex
+)
+
+// RegisterPlugin adds the plugin to be loaded once the built-in handlers are registered, it's expected to be called
+// from the init function of the plugin package.
+func RegisterPlugin(name string, plugin Plugin) {
+	pluginsLock.Lock()
+	defer pluginsLock.Unlock()
+
+	plugins[name] = plugin
+}
+
+// LoadPlugins registers the handlers of the plugins in order of the name.
+func (cm *ConflationManager) LoadPlugins() error {
+	pluginsLock.Lock()
+	defer pluginsLock.Unlock()
+
+	names := make([]string, 0, len(plugins))
+	for name := range plugins {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if err := plugins[name](cm); err != nil {
+			return fmt.Errorf("failed to load the conflation plugin %s: %w", name, err)
+		}
+		cm.log.Infow("loaded the conflation plugin", "name", name)
+	}
+	return nil
+}
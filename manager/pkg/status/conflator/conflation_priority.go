@@ -1,13 +1,15 @@
 package conflator
 
-// ConflationPriority sets processing priorities of bundles.
+// ConflationPriority sets processing priorities of bundles, the lower value is processed first. The values don't have
+// to be contiguous or unique, the bundles with the same priority are ordered by the event type.
 type ConflationPriority uint8
 
-// priority list of conflation unit.
+// priority list of the built-in bundles of conflation unit.
 const (
 	HubClusterHeartbeatPriority        ConflationPriority = iota
 	HubClusterInfoPriority             ConflationPriority = iota
 	ManagedClustersPriority            ConflationPriority = iota
+	ManagedClusterDeltaPriority        ConflationPriority = iota
 	ManagedClusterEventPriority        ConflationPriority = iota
 	LocalPolicySpecPriority            ConflationPriority = iota
 	LocalCompliancePriority            ConflationPriority = iota
@@ -16,7 +18,10 @@ const (
 	LocalReplicatedPolicyEventPriority ConflationPriority = iota
 	LocalPlacementRulesSpecPriority    ConflationPriority = iota
 	SecurityAlertCountsPriority        ConflationPriority = iota
+	SecurityViolationsPriority         ConflationPriority = iota
+	SecurityVulnerabilitiesPriority    ConflationPriority = iota
 	KlusterletAddonConfigPriority      ConflationPriority = iota
+	ResourcePriority                   ConflationPriority = iota
 
 	// enable global resource
 	CompliancePriority         ConflationPriority = iota
@@ -30,4 +35,7 @@ const (
 
 	SubscriptionStatusPriority ConflationPriority = iota
 	SubscriptionReportPriority ConflationPriority = iota
+
+	HubSpecVersionPriority ConflationPriority = iota
+	HubSpecApplyPriority   ConflationPriority = iota
 )
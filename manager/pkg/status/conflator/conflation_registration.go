@@ -9,9 +9,25 @@ import (
 	"github.com/stolostron/multicluster-global-hub/pkg/enum"
 )
 
+// DefaultRetryBudget is the times to handle the event before it's moved to the dead letter table
+const DefaultRetryBudget = 3
+
 // EventHandleFunc is a function for handling a bundle.
 type EventHandleFunc func(context.Context, *cloudevents.Event) error
 
+// RegistrationSpec declares the priority and dependency of a bundle type as data, so that a handler, e.g. from an
+// out-of-tree plugin, can be registered without adding the priority to the shared list. The registrations are ordered
+// by the priority and then the event type, the lower priority is processed first.
+type RegistrationSpec struct {
+	EventType  string                 `json:"eventType"`
+	SyncMode   enum.EventSyncMode     `json:"syncMode"`
+	Priority   ConflationPriority     `json:"priority"`
+	Dependency *dependency.Dependency `json:"dependency,omitempty"`
+	// RetryBudget is the times to handle the event before it's moved to the dead letter table, the default is used if
+	// it isn't positive
+	RetryBudget int `json:"retryBudget,omitempty"`
+}
+
 // ConflationRegistration is used to register a new conflated bundle type along with its priority and handler function.
 type ConflationRegistration struct {
 	priority   ConflationPriority
@@ -19,6 +35,8 @@ type ConflationRegistration struct {
 	eventType  string
 	handleFunc EventHandleFunc
 	dependency *dependency.Dependency
+	// retryBudget is the times to handle the event before it's moved to the dead letter table
+	retryBudget int
 }
 
 // NewConflationRegistration creates a new instance of ConflationRegistration.
@@ -26,11 +44,30 @@ func NewConflationRegistration(priority ConflationPriority, syncMode enum.EventS
 	handlerFunction EventHandleFunc,
 ) *ConflationRegistration {
 	return &ConflationRegistration{
-		priority:   priority,
-		syncMode:   syncMode,
-		eventType:  eventType,
-		handleFunc: handlerFunction,
-		dependency: nil,
+		priority:    priority,
+		syncMode:    syncMode,
+		eventType:   eventType,
+		handleFunc:  handlerFunction,
+		dependency:  nil,
+		retryBudget: DefaultRetryBudget,
+	}
+}
+
+// NewConflationRegistrationFromSpec creates a new instance of ConflationRegistration from the spec.
+func NewConflationRegistrationFromSpec(spec RegistrationSpec, handlerFunction EventHandleFunc,
+) *ConflationRegistration {
+	return NewConflationRegistration(spec.Priority, spec.SyncMode, spec.EventType, handlerFunction).
+		WithDependency(spec.Dependency).WithRetryBudget(spec.RetryBudget)
+}
+
+// Spec returns the declared priority and dependency of the registration.
+func (registration *ConflationRegistration) Spec() RegistrationSpec {
+	return RegistrationSpec{
+		EventType:   registration.eventType,
+		SyncMode:    registration.syncMode,
+		Priority:    registration.priority,
+		Dependency:  registration.dependency,
+		RetryBudget: registration.retryBudget,
 	}
 }
 
@@ -39,3 +76,11 @@ func (registration *ConflationRegistration) WithDependency(val *dependency.Depen
 	registration.dependency = val
 	return registration
 }
+
+// WithRetryBudget overrides the times to handle the event before it's moved to the dead letter table.
+func (registration *ConflationRegistration) WithRetryBudget(budget int) *ConflationRegistration {
+	if budget > 0 {
+		registration.retryBudget = budget
+	}
+	return registration
+}
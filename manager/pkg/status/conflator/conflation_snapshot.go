@@ -0,0 +1,149 @@
+package conflator
+
+import (
+	"sort"
+	"time"
+
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/status/conflator/dependency"
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
+)
+
+// ConflationSnapshot is the read-only state of the conflation pipeline, it's used to diagnose the slow hubs.
+type ConflationSnapshot struct {
+	Time               time.Time          `json:"time"`
+	ReadyQueue         ReadyQueueSnapshot `json:"readyQueue"`
+	ConflationUnits    []UnitSnapshot     `json:"conflationUnits"`
+	CommittedPositions map[string]int64   `json:"committedPositions"`
+}
+
+// ReadyQueueSnapshot is the number of the conflation units and delta jobs waiting for the workers.
+type ReadyQueueSnapshot struct {
+	ConflationUnits int `json:"conflationUnits"`
+	DeltaEventJobs  int `json:"deltaEventJobs"`
+}
+
+// UnitSnapshot is the state of the conflation unit of a leaf hub.
+type UnitSnapshot struct {
+	LeafHubName  string            `json:"leafHubName"`
+	InReadyQueue bool              `json:"inReadyQueue"`
+	Elements     []ElementSnapshot `json:"elements"`
+}
+
+// ElementSnapshot is the state of the conflation element of an event type.
+type ElementSnapshot struct {
+	EventType            string             `json:"eventType"`
+	SyncMode             enum.EventSyncMode `json:"syncMode"`
+	LastProcessedVersion string             `json:"lastProcessedVersion"`
+	// PendingVersion is the version of the event which is waiting to be processed
+	PendingVersion string `json:"pendingVersion,omitempty"`
+	InProcess      bool   `json:"inProcess"`
+	// UnsatisfiedDependency is the dependency which blocks the pending event
+	UnsatisfiedDependency *DependencySnapshot `json:"unsatisfiedDependency,omitempty"`
+	LastProcessedTime     *time.Time          `json:"lastProcessedTime,omitempty"`
+	// SecondsSinceLastProcessed is absent if no event has been processed since the manager started
+	SecondsSinceLastProcessed *int64 `json:"secondsSinceLastProcessed,omitempty"`
+}
+
+// DependencySnapshot compares the dependency version required by the pending event with the processed one.
+type DependencySnapshot struct {
+	EventType        string                    `json:"eventType"`
+	DependencyType   dependency.DependencyType `json:"dependencyType"`
+	RequiredVersion  string                    `json:"requiredVersion"`
+	ProcessedVersion string                    `json:"processedVersion"`
+}
+
+// Snapshot returns the state of the conflation units, and the positions committed by the committer if it's not nil.
+func (cm *ConflationManager) Snapshot(committer *ConflationCommitter) *ConflationSnapshot {
+	now := time.Now()
+	cm.lock.Lock()
+	units := make([]*ConflationUnit, 0, len(cm.conflationUnits))
+	for _, conflationUnit := range cm.conflationUnits {
+		units = append(units, conflationUnit)
+	}
+	cm.lock.Unlock()
+	sort.Slice(units, func(i, j int) bool { return units[i].name < units[j].name })
+
+	snapshot := &ConflationSnapshot{
+		Time: now,
+		ReadyQueue: ReadyQueueSnapshot{
+			ConflationUnits: len(cm.readyQueue.ConflationUnitChan),
+			DeltaEventJobs:  len(cm.readyQueue.DeltaEventJobChan),
+		},
+		ConflationUnits:    make([]UnitSnapshot, 0, len(units)),
+		CommittedPositions: map[string]int64{},
+	}
+	for _, conflationUnit := range units {
+		snapshot.ConflationUnits = append(snapshot.ConflationUnits, conflationUnit.snapshot(now))
+	}
+	if committer != nil {
+		snapshot.CommittedPositions = committer.CommittedPositions()
+	}
+	return snapshot
+}
+
+func (cu *ConflationUnit) snapshot(now time.Time) UnitSnapshot {
+	cu.lock.Lock()
+	defer cu.lock.Unlock()
+
+	unit := UnitSnapshot{
+		LeafHubName:  cu.name,
+		InReadyQueue: cu.isInReadyQueue,
+		Elements:     make([]ElementSnapshot, 0, len(cu.ElementPriorityQueue)),
+	}
+	for _, element := range cu.ElementPriorityQueue {
+		unit.Elements = append(unit.Elements, element.Snapshot(cu, now))
+	}
+	return unit
+}
+
+func (e *completeElement) Snapshot(cu *ConflationUnit, now time.Time) ElementSnapshot {
+	snapshot := ElementSnapshot{
+		EventType:            e.eventType,
+		SyncMode:             e.syncMode,
+		LastProcessedVersion: e.lastProcessedVersion.String(),
+		InProcess:            e.isInProcess,
+	}
+	setLastProcessedTime(&snapshot, e.lastProcessedTime, now)
+	if e.event == nil || e.metadata == nil {
+		return snapshot
+	}
+
+	snapshot.PendingVersion = e.metadata.Version().String()
+	if e.dependency != nil && !e.matchDependency(cu) {
+		unsatisfied := &DependencySnapshot{
+			EventType:       e.dependency.EventType,
+			DependencyType:  e.dependency.DependencyType,
+			RequiredVersion: e.metadata.DependencyVersion().String(),
+		}
+		if completeDependency, ok := cu.element(e.dependency.EventType).(*completeElement); ok {
+			unsatisfied.ProcessedVersion = completeDependency.lastProcessedVersion.String()
+		}
+		snapshot.UnsatisfiedDependency = unsatisfied
+	}
+	return snapshot
+}
+
+func (e *deltaElement) Snapshot(cu *ConflationUnit, now time.Time) ElementSnapshot {
+	snapshot := ElementSnapshot{
+		EventType:            e.eventType,
+		SyncMode:             e.syncMode,
+		LastProcessedVersion: e.lastProcessedVersion.String(),
+		InProcess:            e.isInProcess,
+	}
+	setLastProcessedTime(&snapshot, e.lastProcessedTime, now)
+	// the delta events are queued as jobs, the metadata is the latest one inserted
+	if e.metadata != nil && !e.metadata.Processed() {
+		snapshot.PendingVersion = e.metadata.Version().String()
+	}
+	return snapshot
+}
+
+func setLastProcessedTime(snapshot *ElementSnapshot, lastProcessedTime, now time.Time) {
+	if lastProcessedTime.IsZero() {
+		return
+	}
+	processedTime := lastProcessedTime
+	seconds := int64(now.Sub(lastProcessedTime).Seconds())
+	snapshot.LastProcessedTime = &processedTime
+	snapshot.SecondsSinceLastProcessed = &seconds
+}
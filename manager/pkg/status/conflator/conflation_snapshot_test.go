@@ -0,0 +1,70 @@
+package conflator
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/status/conflator/dependency"
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/version"
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
+	"github.com/stolostron/multicluster-global-hub/pkg/statistics"
+)
+
+func TestConflationManagerSnapshot(t *testing.T) {
+	handle := func(ctx context.Context, evt *cloudevents.Event) error { return nil }
+	cm := NewConflationManager(statistics.NewStatistics(&statistics.StatisticsConfig{}))
+	cm.Register(NewConflationRegistration(HubClusterInfoPriority, enum.CompleteStateMode,
+		string(enum.HubClusterInfoType), handle))
+	cm.Register(NewConflationRegistration(ManagedClustersPriority, enum.CompleteStateMode,
+		string(enum.ManagedClusterType), handle).
+		WithDependency(dependency.NewDependency(string(enum.HubClusterInfoType), dependency.ExactMatch)))
+	require.NoError(t, cm.Validate())
+
+	evt := cloudevents.NewEvent()
+	evt.SetSource("hub1")
+	evt.SetType(string(enum.ManagedClusterType))
+	evt.SetExtension(version.ExtVersion, "1.2")
+	evt.SetExtension(version.ExtDependencyVersion, "1.1")
+	cm.Insert(&evt)
+
+	infoElement := cm.getConflationUnit("hub1").element(string(enum.HubClusterInfoType)).(*completeElement)
+	infoElement.lastProcessedTime = time.Now().Add(-time.Minute)
+
+	committer := NewKafkaConflationCommitter(cm.GetMetadatas)
+	committer.committedPositions["status@0"] = 10
+
+	snapshot := cm.Snapshot(committer)
+	assert.Equal(t, map[string]int64{"status@0": 10}, snapshot.CommittedPositions)
+	require.Len(t, snapshot.ConflationUnits, 1)
+	unit := snapshot.ConflationUnits[0]
+	assert.Equal(t, "hub1", unit.LeafHubName)
+	assert.False(t, unit.InReadyQueue)
+	require.Len(t, unit.Elements, 2)
+
+	// the dependency of the pending event hasn't been processed
+	clusterElement := unit.Elements[1]
+	assert.Equal(t, string(enum.ManagedClusterType), clusterElement.EventType)
+	assert.Equal(t, "1.2", clusterElement.PendingVersion)
+	assert.Equal(t, &DependencySnapshot{
+		EventType:        string(enum.HubClusterInfoType),
+		DependencyType:   dependency.ExactMatch,
+		RequiredVersion:  "1.1",
+		ProcessedVersion: "0.0",
+	}, clusterElement.UnsatisfiedDependency)
+	assert.Nil(t, clusterElement.SecondsSinceLastProcessed)
+
+	infoSnapshot := unit.Elements[0]
+	assert.Empty(t, infoSnapshot.PendingVersion)
+	assert.Nil(t, infoSnapshot.UnsatisfiedDependency)
+	require.NotNil(t, infoSnapshot.SecondsSinceLastProcessed)
+	assert.GreaterOrEqual(t, *infoSnapshot.SecondsSinceLastProcessed, int64(60))
+
+	_, err := json.Marshal(snapshot)
+	assert.NoError(t, err)
+}
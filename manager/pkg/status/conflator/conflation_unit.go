@@ -7,6 +7,7 @@ import (
 	cloudevents "github.com/cloudevents/sdk-go/v2"
 	"go.uber.org/zap"
 
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/version"
 	"github.com/stolostron/multicluster-global-hub/pkg/enum"
 	"github.com/stolostron/multicluster-global-hub/pkg/logger"
 	"github.com/stolostron/multicluster-global-hub/pkg/statistics"
@@ -14,9 +15,11 @@ import (
 
 // ConflationUnit abstracts the conflation of prioritized multiple bundles with dependencies between them.
 type ConflationUnit struct {
-	log                  *zap.SugaredLogger
+	log  *zap.SugaredLogger
+	name string
+	// ElementPriorityQueue is the elements ordered by the priority and then the event type of the registrations
 	ElementPriorityQueue []ConflationElement
-	eventTypeToPriority  map[string]ConflationPriority
+	eventTypeToIndex     map[string]int
 	readyQueue           *ConflationReadyQueue
 	// requireInitialDependencyChecks bool
 	isInReadyQueue bool
@@ -28,28 +31,63 @@ func newConflationUnit(name string, readyQueue *ConflationReadyQueue,
 	registrations map[string]*ConflationRegistration, statistics *statistics.Statistics,
 ) *ConflationUnit {
 	conflationUnit := &ConflationUnit{
-		log:                  logger.ZapLogger(name),
-		ElementPriorityQueue: make([]ConflationElement, len(registrations)),
-		eventTypeToPriority:  make(map[string]ConflationPriority),
-		readyQueue:           readyQueue,
+		log:        logger.ZapLogger(name),
+		name:       name,
+		readyQueue: readyQueue,
 		// requireInitialDependencyChecks: requireInitialDependencyChecks,
 		isInReadyQueue: false,
 		lock:           sync.Mutex{},
 		statistics:     statistics,
 	}
+	conflationUnit.buildElements(registrations)
+	return conflationUnit
+}
 
-	for _, registration := range registrations {
-		if registration.syncMode == enum.CompleteStateMode {
-			conflationUnit.ElementPriorityQueue[registration.priority] = NewCompleteElement(name, registration)
-		}
-		if registration.syncMode == enum.DeltaStateMode {
-			conflationUnit.ElementPriorityQueue[registration.priority] = NewDeltaElement(name, registration)
-		}
+// buildElements orders the elements by the registrations, the existing elements are kept so that their state isn't
+// lost when a new registration is added at runtime.
+func (cu *ConflationUnit) buildElements(registrations map[string]*ConflationRegistration) {
+	existing := make(map[string]ConflationElement, len(cu.ElementPriorityQueue))
+	for _, element := range cu.ElementPriorityQueue {
+		existing[element.Name()] = element
+	}
 
-		conflationUnit.eventTypeToPriority[registration.eventType] = registration.priority
+	ordered := orderRegistrations(registrations)
+	cu.ElementPriorityQueue = make([]ConflationElement, 0, len(ordered))
+	cu.eventTypeToIndex = make(map[string]int, len(ordered))
+	for _, registration := range ordered {
+		element, found := existing[registration.eventType]
+		if !found {
+			switch registration.syncMode {
+			case enum.CompleteStateMode:
+				element = NewCompleteElement(cu.name, registration)
+			case enum.DeltaStateMode:
+				element = NewDeltaElement(cu.name, registration)
+			default:
+				cu.log.Warnw("skip the registration with unknown sync mode", "eventType", registration.eventType,
+					"syncMode", registration.syncMode)
+				continue
+			}
+		}
+		cu.eventTypeToIndex[registration.eventType] = len(cu.ElementPriorityQueue)
+		cu.ElementPriorityQueue = append(cu.ElementPriorityQueue, element)
 	}
+}
 
-	return conflationUnit
+// addRegistrations adds the elements of the new registrations into the conflation unit.
+func (cu *ConflationUnit) addRegistrations(registrations map[string]*ConflationRegistration) {
+	cu.lock.Lock()
+	defer cu.lock.Unlock()
+
+	cu.buildElements(registrations)
+}
+
+// element returns the conflation element of the event type, nil if it isn't registered
+func (cu *ConflationUnit) element(eventType string) ConflationElement {
+	index, found := cu.eventTypeToIndex[eventType]
+	if !found {
+		return nil
+	}
+	return cu.ElementPriorityQueue[index]
 }
 
 // insert is an internal function, new bundles are inserted only via conflation manager.
@@ -57,8 +95,7 @@ func (cu *ConflationUnit) insert(event *cloudevents.Event, eventMetadata Conflat
 	cu.lock.Lock()
 	defer cu.lock.Unlock()
 
-	priority := cu.eventTypeToPriority[event.Type()]
-	conflationElement := cu.ElementPriorityQueue[priority]
+	conflationElement := cu.element(event.Type())
 	if conflationElement == nil {
 		cu.log.Infow("the conflationElement hasn't been registered to conflation unit", "eventType", event.Type())
 		return
@@ -105,8 +142,12 @@ func (cu *ConflationUnit) ReportResult(metadata ConflationMetadata, err error) {
 	cu.lock.Lock()
 	defer cu.lock.Unlock()
 
-	priority := cu.eventTypeToPriority[metadata.EventType()] // priority of the bundle that was processed
-	conflationElement := cu.ElementPriorityQueue[priority]
+	conflationElement := cu.element(metadata.EventType()) // element of the bundle that was processed
+	if conflationElement == nil {
+		cu.log.Infow("the conflationElement hasn't been registered to conflation unit", "eventType",
+			metadata.EventType())
+		return
+	}
 
 	conflationElement.PostProcess(metadata, err)
 
@@ -115,6 +156,28 @@ func (cu *ConflationUnit) ReportResult(metadata ConflationMetadata, err error) {
 	}
 }
 
+// lastHandledVersion returns the version of the latest handled event of the complete state type, nil if it isn't
+// registered as the complete state type
+func (cu *ConflationUnit) lastHandledVersion(eventType string) *version.Version {
+	cu.lock.Lock()
+	defer cu.lock.Unlock()
+
+	if element, ok := cu.element(eventType).(*completeElement); ok {
+		return element.lastHandledVersion
+	}
+	return nil
+}
+
+// replayed records the version of the replayed complete state event as handled
+func (cu *ConflationUnit) replayed(eventType string, eventVersion *version.Version) {
+	cu.lock.Lock()
+	defer cu.lock.Unlock()
+
+	if element, ok := cu.element(eventType).(*completeElement); ok {
+		element.handled(eventVersion)
+	}
+}
+
 func (cu *ConflationUnit) addCUToReadyQueueIfNeeded() {
 	if cu.isInReadyQueue {
 		return // allow CU to appear only once in RQ/processing
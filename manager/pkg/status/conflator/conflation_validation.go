@@ -0,0 +1,64 @@
+package conflator
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/status/conflator/dependency"
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
+)
+
+// orderRegistrations sorts the registrations by the priority and then the event type, it's the order of the elements
+// in the conflation unit.
+func orderRegistrations(registrations map[string]*ConflationRegistration) []*ConflationRegistration {
+	ordered := make([]*ConflationRegistration, 0, len(registrations))
+	for _, registration := range registrations {
+		ordered = append(ordered, registration)
+	}
+	sort.Slice(ordered, func(i, j int) bool {
+		if ordered[i].priority != ordered[j].priority {
+			return ordered[i].priority < ordered[j].priority
+		}
+		return ordered[i].eventType < ordered[j].eventType
+	})
+	return ordered
+}
+
+// validateRegistrations checks the dependencies of the registrations: the dependency must be a registered complete
+// state bundle, and the dependencies can't form a cycle, otherwise the element never becomes ready to process.
+func validateRegistrations(registrations map[string]*ConflationRegistration) error {
+	for _, registration := range orderRegistrations(registrations) {
+		dep := registration.dependency
+		if dep == nil {
+			continue
+		}
+		if dep.DependencyType != dependency.ExactMatch && dep.DependencyType != dependency.AtLeast {
+			return fmt.Errorf("the dependency type %q of %s is invalid", dep.DependencyType, registration.eventType)
+		}
+		depRegistration, found := registrations[dep.EventType]
+		if !found {
+			return fmt.Errorf("the dependency %s of %s isn't registered", dep.EventType, registration.eventType)
+		}
+		if depRegistration.syncMode != enum.CompleteStateMode {
+			return fmt.Errorf("the dependency %s of %s isn't in the complete state mode", dep.EventType,
+				registration.eventType)
+		}
+	}
+
+	// a bundle depends on one other bundle at most, so a cycle is found once the chain visits a bundle again
+	for _, registration := range orderRegistrations(registrations) {
+		chain := []string{registration.eventType}
+		visited := map[string]bool{registration.eventType: true}
+		for current := registration; current.dependency != nil; {
+			next := current.dependency.EventType
+			chain = append(chain, next)
+			if visited[next] {
+				return fmt.Errorf("the dependencies form a cycle: %s", strings.Join(chain, " -> "))
+			}
+			visited[next] = true
+			current = registrations[next]
+		}
+	}
+	return nil
+}
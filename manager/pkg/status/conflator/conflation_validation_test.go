@@ -0,0 +1,83 @@
+package conflator
+
+import (
+	"context"
+	"testing"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/status/conflator/dependency"
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
+)
+
+func TestValidateRegistrations(t *testing.T) {
+	handle := func(ctx context.Context, evt *cloudevents.Event) error { return nil }
+	newRegistration := func(eventType string, syncMode enum.EventSyncMode, dependsOn string) *ConflationRegistration {
+		registration := NewConflationRegistration(0, syncMode, eventType, handle)
+		if dependsOn != "" {
+			registration.WithDependency(dependency.NewDependency(dependsOn, dependency.ExactMatch))
+		}
+		return registration
+	}
+	toMap := func(registrations ...*ConflationRegistration) map[string]*ConflationRegistration {
+		result := map[string]*ConflationRegistration{}
+		for _, registration := range registrations {
+			result[registration.eventType] = registration
+		}
+		return result
+	}
+
+	cases := []struct {
+		name          string
+		registrations map[string]*ConflationRegistration
+		errMessage    string
+	}{
+		{
+			name: "valid dependency chain",
+			registrations: toMap(
+				newRegistration("a", enum.CompleteStateMode, ""),
+				newRegistration("b", enum.CompleteStateMode, "a"),
+				newRegistration("c", enum.DeltaStateMode, "b"),
+			),
+		},
+		{
+			name:          "unregistered dependency",
+			registrations: toMap(newRegistration("a", enum.CompleteStateMode, "b")),
+			errMessage:    "the dependency b of a isn't registered",
+		},
+		{
+			name: "delta state dependency",
+			registrations: toMap(
+				newRegistration("a", enum.DeltaStateMode, ""),
+				newRegistration("b", enum.CompleteStateMode, "a"),
+			),
+			errMessage: "the dependency a of b isn't in the complete state mode",
+		},
+		{
+			name:          "self dependency",
+			registrations: toMap(newRegistration("a", enum.CompleteStateMode, "a")),
+			errMessage:    "the dependencies form a cycle: a -> a",
+		},
+		{
+			name: "dependency cycle",
+			registrations: toMap(
+				newRegistration("a", enum.CompleteStateMode, "c"),
+				newRegistration("b", enum.CompleteStateMode, "a"),
+				newRegistration("c", enum.CompleteStateMode, "b"),
+			),
+			errMessage: "the dependencies form a cycle: a -> c -> b -> a",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := validateRegistrations(tc.registrations)
+			if tc.errMessage == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, tc.errMessage)
+		})
+	}
+}
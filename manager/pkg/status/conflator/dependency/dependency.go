@@ -10,6 +10,6 @@ func NewDependency(eventType string, dependencyType DependencyType) *Dependency
 
 // Dependency represents the dependency between different bundles. a bundle can depend only on one other bundle.
 type Dependency struct {
-	EventType      string
-	DependencyType DependencyType
+	EventType      string         `json:"eventType"`
+	DependencyType DependencyType `json:"dependencyType"`
 }
@@ -3,6 +3,7 @@ package conflator
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	cloudevents "github.com/cloudevents/sdk-go/v2"
 	"github.com/go-logr/logr"
@@ -22,6 +23,11 @@ type completeElement struct {
 	dependency           *dependency.Dependency
 	isInProcess          bool
 	lastProcessedVersion *version.Version
+	// lastHandledVersion is the version of the latest event handled successfully, rather than moved to the dead letter
+	// table, the dead lettered events which are newer than it can be replayed
+	lastHandledVersion *version.Version
+	// lastProcessedTime is the time when the latest event is processed successfully
+	lastProcessedTime time.Time
 
 	// payload
 	event    *cloudevents.Event
@@ -39,6 +45,7 @@ func NewCompleteElement(leafHubName string, registration *ConflationRegistration
 		dependency:           registration.dependency, // nil if there is no dependency
 		isInProcess:          false,
 		lastProcessedVersion: version.NewVersion(),
+		lastHandledVersion:   version.NewVersion(),
 	}
 }
 
@@ -60,6 +67,7 @@ func (e *completeElement) Predicate(eventVersion *version.Version) bool {
 	// 2. reset the bundleInfo version to 0 (add the resetBundleVersion() function to bundleInfo interface)
 	if eventVersion.InitGen() {
 		e.lastProcessedVersion = version.NewVersion()
+		e.lastHandledVersion = version.NewVersion()
 		if e.metadata != nil {
 			e.metadata.Version().Reset()
 		}
@@ -116,6 +124,10 @@ func (e *completeElement) PostProcess(metadata ConflationMetadata, err error) {
 	// update state: lastProcessedVersion
 	if metadata.Processed() && metadata.Version().NewerThan(e.lastProcessedVersion) {
 		e.lastProcessedVersion = metadata.Version()
+		e.lastProcessedTime = time.Now()
+	}
+	if metadata.Processed() && !metadata.DeadLettered() {
+		e.handled(metadata.Version())
 	}
 
 	// update state: update the payload
@@ -126,6 +138,13 @@ func (e *completeElement) PostProcess(metadata ConflationMetadata, err error) {
 	}
 }
 
+// handled records the version of the event which is handled by the worker or replayed.
+func (e *completeElement) handled(eventVersion *version.Version) {
+	if eventVersion.NewerThan(e.lastHandledVersion) {
+		e.lastHandledVersion = eventVersion
+	}
+}
+
 // isCurrentOrAnyDependencyInProcess checks if current element or any dependency from dependency chain is in process.
 func (e *completeElement) isCurrentOrAnyDependencyInProcess(cu *ConflationUnit) bool {
 	if e.isInProcess { // current conflation element is in process
@@ -136,8 +155,7 @@ func (e *completeElement) isCurrentOrAnyDependencyInProcess(cu *ConflationUnit)
 		return false
 	}
 
-	dependencyIndex := cu.eventTypeToPriority[e.dependency.EventType]
-	dependencyElement := cu.ElementPriorityQueue[dependencyIndex]
+	dependencyElement := cu.element(e.dependency.EventType)
 
 	completeDependency, ok := dependencyElement.(*completeElement)
 	if !ok {
@@ -155,8 +173,7 @@ func (e *completeElement) matchDependency(cu *ConflationUnit) bool {
 		return true // bundle in this conflation element has no dependency
 	}
 
-	dependencyIndex := cu.eventTypeToPriority[e.dependency.EventType]
-	dependencyElement := cu.ElementPriorityQueue[dependencyIndex]
+	dependencyElement := cu.element(e.dependency.EventType)
 
 	completeDependency, ok := dependencyElement.(*completeElement)
 	if !ok {
@@ -3,6 +3,7 @@ package conflator
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	cloudevents "github.com/cloudevents/sdk-go/v2"
 	"go.uber.org/zap"
@@ -22,6 +23,8 @@ type deltaElement struct {
 	dependency           *dependency.Dependency
 	isInProcess          bool
 	lastProcessedVersion *version.Version
+	// lastProcessedTime is the time when the latest event is processed successfully
+	lastProcessedTime time.Time
 
 	// the metadata of the event
 	metadata ConflationMetadata
@@ -76,5 +79,6 @@ func (e *deltaElement) PostProcess(metadata ConflationMetadata, err error) {
 	// update state: lastProcessedVersion
 	if metadata.Processed() && metadata.Version().NewerThan(e.lastProcessedVersion) {
 		e.lastProcessedVersion = metadata.Version()
+		e.lastProcessedTime = time.Now()
 	}
 }
@@ -1,6 +1,8 @@
 package conflator
 
 import (
+	"time"
+
 	cloudevents "github.com/cloudevents/sdk-go/v2"
 
 	"github.com/stolostron/multicluster-global-hub/pkg/bundle/version"
@@ -16,6 +18,11 @@ type ConflationMetadata interface {
 	Processed() bool
 	// MarkAsUnprocessed function that marks the metadata as unprocessed.
 	MarkAsUnprocessed()
+	// MarkAsDeadLettered marks the metadata as processed, but the event is moved to the dead letter table rather than
+	// handled.
+	MarkAsDeadLettered()
+	// DeadLettered returns whether the event was moved to the dead letter table.
+	DeadLettered() bool
 	// the event version
 	Version() *version.Version
 	// the event dependencyVersion
@@ -51,4 +58,7 @@ type ConflationElement interface {
 
 	// PostProcess is to update the conflation element state after processing the event
 	PostProcess(metadata ConflationMetadata, err error)
+
+	// Snapshot returns the state of the element for the introspection, the lock of the conflation unit is held
+	Snapshot(cu *ConflationUnit, now time.Time) ElementSnapshot
 }
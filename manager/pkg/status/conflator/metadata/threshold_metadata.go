@@ -19,8 +19,9 @@ import (
 // 1, 2, 3 ... - the retry times of current bundle has been failed processed
 // -1 means it processed successfully
 type ThresholdMetadata struct {
-	maxRetry int
-	count    int
+	maxRetry     int
+	count        int
+	deadLettered bool
 
 	// transport position
 	kafkaPosition *transport.EventPosition
@@ -105,6 +106,17 @@ func (s *ThresholdMetadata) MarkAsUnprocessed() {
 	s.count++
 }
 
+// MarkAsDeadLettered marks the metadata as processed since the event is moved to the dead letter table.
+func (s *ThresholdMetadata) MarkAsDeadLettered() {
+	s.count = -1
+	s.deadLettered = true
+}
+
+// DeadLettered returns whether the event was moved to the dead letter table.
+func (s *ThresholdMetadata) DeadLettered() bool {
+	return s.deadLettered
+}
+
 func (s *ThresholdMetadata) TransportPosition() *transport.EventPosition {
 	return s.kafkaPosition
 }
@@ -7,10 +7,14 @@ import (
 	"k8s.io/apimachinery/pkg/util/wait"
 
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/status/conflator"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/status/deadletter"
 	"github.com/stolostron/multicluster-global-hub/pkg/database"
 	"github.com/stolostron/multicluster-global-hub/pkg/statistics"
 )
 
+// persistDeadLetter moves the event which exhausts the retry budget to the dead letter table
+var persistDeadLetter = deadletter.Persist
+
 // NewWorker creates a new instance of DBWorker.
 // jobsQueue is initialized with capacity of 1. this is done in order to make sure dispatcher isn't blocked when calling
 // to RunAsync, otherwise it will yield cpu to other go routines.
@@ -72,23 +76,30 @@ func (worker *Worker) handleJob(ctx context.Context, job *conflator.ConflationJo
 		return
 	}
 
-	// handle the event until it's metadata is marked as processed
+	// handle the event until it's metadata is marked as processed, or the retry budget is exhausted
 	err = wait.PollUntilContextTimeout(ctx, 5*time.Second, 5*time.Minute, true,
 		func(ctx context.Context) (bool, error) {
 			err = job.Handle(ctx, job.Event) // db connection released to pool when done
-			if err != nil {
-				job.Metadata.MarkAsUnprocessed()
-				log.Warnf("failed to handle event (%s): %v", job.Event.Type(), err)
-			} else {
+			if err == nil {
 				job.Metadata.MarkAsProcessed()
+				return true, nil
 			}
-			// retrying
-			if !job.Metadata.Processed() || err != nil {
+			job.Metadata.MarkAsUnprocessed()
+			log.Warnf("failed to handle event (%s): %v", job.Event.Type(), err)
+			if !job.Metadata.Processed() {
 				log.Info("retrying to handle the above")
 				return false, nil
 			}
-			// success or up to retry threshold
-			return job.Metadata.Processed(), nil
+			// the retry budget is exhausted, move the event to the dead letter table so that the offset can be
+			// committed and the following events of the hub aren't blocked
+			if deadLetterErr := persistDeadLetter(ctx, job.Event, err); deadLetterErr != nil {
+				log.Warnf("failed to move the event (%s) to the dead letter table: %v", job.Event.Type(), deadLetterErr)
+				return false, nil
+			}
+			log.Infow("moved the event to the dead letter table", "LF", job.Event.Source(), "type", job.Event.Type(),
+				"version", job.Metadata.Version(), "error", err)
+			job.Metadata.MarkAsDeadLettered()
+			return true, nil
 		})
 
 	worker.statistics.AddDatabaseMetrics(job.Event, time.Since(startTime), err)
@@ -0,0 +1,62 @@
+package workerpool
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/status/conflator"
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/version"
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
+	"github.com/stolostron/multicluster-global-hub/pkg/statistics"
+)
+
+func TestReplayDeadLetteredEvent(t *testing.T) {
+	deadLettered := []*cloudevents.Event{}
+	persist := persistDeadLetter
+	defer func() { persistDeadLetter = persist }()
+	persistDeadLetter = func(ctx context.Context, evt *cloudevents.Event, handleErr error) error {
+		deadLettered = append(deadLettered, evt)
+		return nil
+	}
+
+	handleErr := errors.New("failed to handle")
+	handled := 0
+	stats := statistics.NewStatistics(&statistics.StatisticsConfig{})
+	cm := conflator.NewConflationManager(stats)
+	cm.Register(conflator.NewConflationRegistration(conflator.HubClusterInfoPriority, enum.CompleteStateMode,
+		string(enum.HubClusterInfoType), func(ctx context.Context, evt *cloudevents.Event) error {
+			if handleErr != nil {
+				return handleErr
+			}
+			handled++
+			return nil
+		}).WithRetryBudget(1))
+
+	evt := cloudevents.NewEvent()
+	evt.SetSource("hub1")
+	evt.SetType(string(enum.HubClusterInfoType))
+	evt.SetExtension(version.ExtVersion, "1.2")
+
+	// the handler fails and the event is moved to the dead letter table
+	cm.Insert(&evt)
+	cu := <-cm.GetReadyQueue().ConflationUnitChan
+	job, err := cu.GetNext()
+	require.NoError(t, err)
+	worker := NewWorker(1, make(chan *Worker, 1), stats)
+	worker.handleJob(context.Background(), job)
+	require.Len(t, deadLettered, 1)
+	assert.True(t, job.Metadata.DeadLettered())
+
+	// the dead lettered event is replayed once the handler is fixed, and it can't be replayed twice
+	handleErr = nil
+	require.NoError(t, cm.Replay(context.Background(), deadLettered[0]))
+	assert.Equal(t, 1, handled)
+	err = cm.Replay(context.Background(), deadLettered[0])
+	assert.True(t, errors.Is(err, conflator.ErrEventSuperseded))
+	assert.Equal(t, 1, handled)
+}
@@ -0,0 +1,152 @@
+package deadletter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	eventversion "github.com/stolostron/multicluster-global-hub/pkg/bundle/version"
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
+)
+
+// DefaultListLimit is the max number of the dead lettered events returned by List if the limit isn't specified
+const DefaultListLimit = 100
+
+// ReplayFunc handles the dead lettered event again, it's registered by the conflation manager
+type ReplayFunc func(ctx context.Context, evt *cloudevents.Event) error
+
+var (
+	// ErrReplayUnavailable means the status handlers aren't started, e.g. the transport isn't ready
+	ErrReplayUnavailable = errors.New("the status handlers aren't ready to replay the event")
+	// ErrAlreadyReplayed means the event has been replayed successfully
+	ErrAlreadyReplayed = errors.New("the event has been replayed")
+
+	replayFunc ReplayFunc
+
+	deadLetterEventsCounter = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "multicluster_global_hub_status_dead_letter_events_total",
+		Help: "The number of the status events moved to the dead letter table after the retry budget is exhausted.",
+	}, []string{"type"})
+)
+
+// RegisterMetrics will register the dead letter metrics with the global prometheus registry
+func RegisterMetrics() {
+	metrics.Registry.MustRegister(deadLetterEventsCounter)
+}
+
+// RegisterReplayFunc sets the function to handle the replayed events
+func RegisterReplayFunc(fn ReplayFunc) {
+	replayFunc = fn
+}
+
+// Persist saves the event with the error of the last attempt to the dead letter table
+func Persist(ctx context.Context, evt *cloudevents.Event, handleErr error) error {
+	deadLetter := FromEvent(evt, handleErr)
+	if err := database.GetGorm().WithContext(ctx).Create(deadLetter).Error; err != nil {
+		return fmt.Errorf("failed to persist the dead letter event(%s) from %s: %w", evt.Type(), evt.Source(), err)
+	}
+	deadLetterEventsCounter.WithLabelValues(evt.Type()).Inc()
+	return nil
+}
+
+// List returns the dead lettered events of the hubs in the descending order of the creation, all the hubs are listed
+// if the hub names are nil
+func List(ctx context.Context, leafHubNames []string, includeReplayed bool, limit int) (
+	[]models.DeadLetterEvent, error,
+) {
+	if limit <= 0 {
+		limit = DefaultListLimit
+	}
+	db := database.GetGorm().WithContext(ctx)
+	if leafHubNames != nil {
+		db = db.Where("leaf_hub_name IN ?", leafHubNames)
+	}
+	if !includeReplayed {
+		db = db.Where("replayed_at IS NULL")
+	}
+	deadLetters := []models.DeadLetterEvent{}
+	if err := db.Order("created_at DESC, id DESC").Limit(limit).Find(&deadLetters).Error; err != nil {
+		return nil, fmt.Errorf("failed to list the dead letter events: %w", err)
+	}
+	return deadLetters, nil
+}
+
+// Get returns the dead lettered event by the id
+func Get(ctx context.Context, id int64) (*models.DeadLetterEvent, error) {
+	deadLetter := &models.DeadLetterEvent{}
+	if err := database.GetGorm().WithContext(ctx).First(deadLetter, id).Error; err != nil {
+		return nil, fmt.Errorf("failed to get the dead letter event %d: %w", id, err)
+	}
+	return deadLetter, nil
+}
+
+// Replay handles the dead lettered event again with the registered handler, then marks it as replayed
+func Replay(ctx context.Context, id int64) error {
+	if replayFunc == nil {
+		return ErrReplayUnavailable
+	}
+
+	deadLetter, err := Get(ctx, id)
+	if err != nil {
+		return err
+	}
+	if deadLetter.ReplayedAt != nil {
+		return ErrAlreadyReplayed
+	}
+
+	evt, err := ToEvent(deadLetter)
+	if err != nil {
+		return err
+	}
+	if err := replayFunc(ctx, evt); err != nil {
+		return fmt.Errorf("failed to replay the dead letter event %d: %w", id, err)
+	}
+	return database.GetGorm().WithContext(ctx).Model(deadLetter).Update("replayed_at", time.Now()).Error
+}
+
+// FromEvent converts the event into the dead letter record
+func FromEvent(evt *cloudevents.Event, handleErr error) *models.DeadLetterEvent {
+	deadLetter := &models.DeadLetterEvent{
+		LeafHubName: evt.Source(),
+		EventType:   evt.Type(),
+		EventID:     evt.ID(),
+		Payload:     evt.Data(),
+	}
+	if handleErr != nil {
+		deadLetter.Error = handleErr.Error()
+	}
+	if val, ok := evt.Extensions()[eventversion.ExtVersion].(string); ok {
+		deadLetter.Version = val
+	}
+	if val, ok := evt.Extensions()[eventversion.ExtDependencyVersion].(string); ok {
+		deadLetter.DependencyVersion = val
+	}
+	return deadLetter
+}
+
+// ToEvent rebuilds the event from the dead letter record
+func ToEvent(deadLetter *models.DeadLetterEvent) (*cloudevents.Event, error) {
+	evt := cloudevents.NewEvent()
+	evt.SetID(deadLetter.EventID)
+	if deadLetter.EventID == "" {
+		evt.SetID(fmt.Sprintf("dead-letter-%d", deadLetter.ID))
+	}
+	evt.SetSource(deadLetter.LeafHubName)
+	evt.SetType(deadLetter.EventType)
+	if deadLetter.Version != "" {
+		evt.SetExtension(eventversion.ExtVersion, deadLetter.Version)
+	}
+	if deadLetter.DependencyVersion != "" {
+		evt.SetExtension(eventversion.ExtDependencyVersion, deadLetter.DependencyVersion)
+	}
+	if err := evt.SetData(cloudevents.ApplicationJSON, deadLetter.Payload); err != nil {
+		return nil, fmt.Errorf("failed to set the payload of the dead letter event %d: %w", deadLetter.ID, err)
+	}
+	return &evt, nil
+}
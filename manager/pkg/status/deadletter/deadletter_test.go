@@ -0,0 +1,36 @@
+package deadletter
+
+import (
+	"errors"
+	"testing"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	eventversion "github.com/stolostron/multicluster-global-hub/pkg/bundle/version"
+)
+
+func TestDeadLetterEvent(t *testing.T) {
+	evt := cloudevents.NewEvent()
+	evt.SetID("1234")
+	evt.SetSource("hub1")
+	evt.SetType("io.open-cluster-management.operator.multiclusterglobalhubs.managedhub.info")
+	evt.SetExtension(eventversion.ExtVersion, "1.2")
+	require.NoError(t, evt.SetData(cloudevents.ApplicationJSON, []byte(`{"consoleURL":`)))
+
+	deadLetter := FromEvent(&evt, errors.New("unexpected end of JSON input"))
+	assert.Equal(t, "hub1", deadLetter.LeafHubName)
+	assert.Equal(t, "1.2", deadLetter.Version)
+	assert.Empty(t, deadLetter.DependencyVersion)
+	assert.Equal(t, "unexpected end of JSON input", deadLetter.Error)
+	assert.Equal(t, []byte(`{"consoleURL":`), deadLetter.Payload)
+
+	replayed, err := ToEvent(deadLetter)
+	require.NoError(t, err)
+	assert.Equal(t, evt.ID(), replayed.ID())
+	assert.Equal(t, evt.Source(), replayed.Source())
+	assert.Equal(t, evt.Type(), replayed.Type())
+	assert.Equal(t, "1.2", replayed.Extensions()[eventversion.ExtVersion])
+	assert.Equal(t, evt.Data(), replayed.Data())
+}
@@ -0,0 +1,65 @@
+package status
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync"
+
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/status/conflator"
+)
+
+// ConflationDebugPath is the path of the conflation introspection endpoint on the metrics server
+const ConflationDebugPath = "/debug/conflation"
+
+var (
+	debugLock       sync.RWMutex
+	debugConflation *conflator.ConflationManager
+	debugCommitter  *conflator.ConflationCommitter
+)
+
+func setDebugTargets(conflationManager *conflator.ConflationManager, committer *conflator.ConflationCommitter) {
+	debugLock.Lock()
+	defer debugLock.Unlock()
+
+	debugConflation = conflationManager
+	debugCommitter = committer
+}
+
+// ConflationDebugHandler dumps the state of the conflation units and the committed positions as JSON. It's read-only,
+// and responds with 503 until the status syncers are started by the transport.
+func ConflationDebugHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.Header().Set("Allow", http.MethodGet)
+			http.Error(w, "only GET is allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		debugLock.RLock()
+		conflationManager, committer := debugConflation, debugCommitter
+		debugLock.RUnlock()
+		if conflationManager == nil {
+			http.Error(w, "the status syncers haven't been started", http.StatusServiceUnavailable)
+			return
+		}
+
+		snapshot := conflationManager.Snapshot(committer)
+		if r.URL.Query().Has("hub") {
+			hub := r.URL.Query().Get("hub")
+			units := []conflator.UnitSnapshot{}
+			for _, unit := range snapshot.ConflationUnits {
+				if unit.LeafHubName == hub {
+					units = append(units, unit)
+				}
+			}
+			snapshot.ConflationUnits = units
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(snapshot); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	})
+}
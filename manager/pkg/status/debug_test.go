@@ -0,0 +1,39 @@
+package status
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/status/conflator"
+	"github.com/stolostron/multicluster-global-hub/pkg/statistics"
+)
+
+func TestConflationDebugHandler(t *testing.T) {
+	handler := ConflationDebugHandler()
+	serve := func(method, target string) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest(method, target, nil))
+		return recorder
+	}
+
+	setDebugTargets(nil, nil)
+	assert.Equal(t, http.StatusServiceUnavailable, serve(http.MethodGet, ConflationDebugPath).Code)
+
+	conflationManager := conflator.NewConflationManager(statistics.NewStatistics(&statistics.StatisticsConfig{}))
+	setDebugTargets(conflationManager, conflator.NewKafkaConflationCommitter(conflationManager.GetMetadatas))
+	defer setDebugTargets(nil, nil)
+
+	assert.Equal(t, http.StatusMethodNotAllowed, serve(http.MethodPost, ConflationDebugPath).Code)
+
+	recorder := serve(http.MethodGet, ConflationDebugPath+"?hub=hub1")
+	require.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "application/json", recorder.Header().Get("Content-Type"))
+	snapshot := &conflator.ConflationSnapshot{}
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), snapshot))
+	assert.Empty(t, snapshot.ConflationUnits)
+}
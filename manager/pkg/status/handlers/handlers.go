@@ -3,6 +3,7 @@ package handlers
 import (
 	"fmt"
 
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	clustersv1beta1 "open-cluster-management.io/api/cluster/v1beta1"
 	placementrulev1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/placementrule/v1"
 	appsv1alpha1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1alpha1"
@@ -25,6 +26,7 @@ func RegisterHandlers(mgr ctrl.Manager, cmr *conflator.ConflationManager, enable
 
 	// managed cluster
 	managedcluster.RegisterManagedClusterHandler(cmr)
+	managedcluster.RegisterManagedClusterDeltaHandler(cmr)
 	managedcluster.RegisterManagedClusterEventHandler(cmr)
 	managedcluster.RegisterKlusterletAddonConfigHandler(mgr, cmr)
 
@@ -43,8 +45,18 @@ func RegisterHandlers(mgr ctrl.Manager, cmr *conflator.ConflationManager, enable
 		enum.CompleteStateMode,
 		fmt.Sprintf("%s.%s", database.LocalSpecSchema, database.PlacementRulesTableName))
 
+	// the resources declared in the agent config
+	generic.RegisterGenericHandler[*unstructured.Unstructured](
+		cmr,
+		string(enum.ResourceType),
+		conflator.ResourcePriority,
+		enum.CompleteStateMode,
+		fmt.Sprintf("%s.%s", database.StatusSchema, database.ResourcesTableName))
+
 	// security
 	security.RegisterSecurityAlertCountsHandler(cmr)
+	security.RegisterSecurityViolationsHandler(cmr)
+	security.RegisterSecurityVulnerabilitiesHandler(cmr)
 
 	if enableGlobalResource {
 		// global policy
@@ -88,5 +100,10 @@ func RegisterHandlers(mgr ctrl.Manager, cmr *conflator.ConflationManager, enable
 			conflator.SubscriptionStatusPriority,
 			enum.CompleteStateMode,
 			fmt.Sprintf("%s.%s", database.StatusSchema, database.SubscriptionStatusesTableName))
+
+		// the applied versions of the spec bundles
+		managedhub.RegisterHubSpecVersionHandler(cmr)
+		// the results of applying the spec objects
+		managedhub.RegisterHubSpecApplyHandler(cmr)
 	}
 }
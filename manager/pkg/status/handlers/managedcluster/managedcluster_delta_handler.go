@@ -0,0 +1,107 @@
+package managedcluster
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/status/conflator"
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/cluster"
+	eventversion "github.com/stolostron/multicluster-global-hub/pkg/bundle/version"
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
+	"github.com/stolostron/multicluster-global-hub/pkg/logger"
+)
+
+// managedClusterDeltaHandler applies the changed managed clusters of the hub incrementally, the whole clusters of the
+// hub are still reconciled by the complete bundle which is sent periodically.
+type managedClusterDeltaHandler struct {
+	log           *zap.SugaredLogger
+	eventType     string
+	eventSyncMode enum.EventSyncMode
+	eventPriority conflator.ConflationPriority
+}
+
+func RegisterManagedClusterDeltaHandler(conflationManager *conflator.ConflationManager) {
+	eventType := string(enum.ManagedClusterDeltaType)
+	logName := strings.Replace(eventType, enum.EventTypePrefix, "", -1)
+	h := &managedClusterDeltaHandler{
+		log:           logger.ZapLogger(logName),
+		eventType:     eventType,
+		eventSyncMode: enum.DeltaStateMode,
+		eventPriority: conflator.ManagedClusterDeltaPriority,
+	}
+	conflationManager.Register(conflator.NewConflationRegistration(
+		h.eventPriority,
+		h.eventSyncMode,
+		h.eventType,
+		h.handleEvent,
+	))
+}
+
+func (h *managedClusterDeltaHandler) handleEvent(ctx context.Context, evt *cloudevents.Event) error {
+	version := evt.Extensions()[eventversion.ExtVersion]
+	leafHubName := evt.Source()
+	h.log.Debugw("handler start", "type", evt.Type(), "LH", evt.Source(), "version", version)
+
+	data := cluster.ManagedClusterDeltaBundle{}
+	if err := evt.DataAs(&data); err != nil {
+		return err
+	}
+
+	updatedClusters := []models.ManagedCluster{}
+	for i := range data.Update {
+		// the cluster is skipped until the cluster id is reported by the cluster claim
+		clusterId := getClusterID(&data.Update[i])
+		if clusterId == "" {
+			continue
+		}
+		payload, err := json.Marshal(data.Update[i])
+		if err != nil {
+			return err
+		}
+		updatedClusters = append(updatedClusters, models.ManagedCluster{
+			ClusterID:   clusterId,
+			LeafHubName: leafHubName,
+			Payload:     payload,
+			Error:       database.ErrorNone,
+		})
+	}
+
+	err := database.GetGorm().Transaction(func(tx *gorm.DB) error {
+		if len(updatedClusters) > 0 {
+			err := tx.Clauses(clause.OnConflict{UpdateAll: true}).CreateInBatches(updatedClusters, BatchSize).Error
+			if err != nil {
+				return err
+			}
+		}
+		for _, deleted := range data.Delete {
+			// the zero fields are ignored by the struct condition, so the empty id must not be used
+			if deleted.ClusterID == "" {
+				continue
+			}
+			err := tx.Where(&models.ManagedCluster{
+				LeafHubName: leafHubName,
+				ClusterID:   deleted.ClusterID,
+			}).Delete(&models.ManagedCluster{}).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to handle the managed cluster delta bundle - %w", err)
+	}
+
+	h.log.Debugw("handler finished", "type", evt.Type(), "LH", evt.Source(), "version", version,
+		"updated", len(updatedClusters), "deleted", len(data.Delete))
+	return nil
+}
@@ -68,13 +68,7 @@ func (h *managedClusterHandler) handleEvent(ctx context.Context, evt *cloudevent
 		cluster := object
 
 		// Initially, if the clusterID is not exist we will skip it until we get it from ClusterClaim
-		clusterId := ""
-		for _, claim := range cluster.Status.ClusterClaims {
-			if claim.Name == "id.k8s.io" {
-				clusterId = claim.Value
-				break
-			}
-		}
+		clusterId := getClusterID(&cluster)
 		if clusterId == "" {
 			continue
 		}
@@ -154,3 +148,12 @@ func getClusterIdToVersionMap(db *gorm.DB, leafHubName string) (map[string]strin
 	}
 	return nameToVersionMap, nil
 }
+
+func getClusterID(cluster *clusterv1.ManagedCluster) string {
+	for _, claim := range cluster.Status.ClusterClaims {
+		if claim.Name == "id.k8s.io" {
+			return claim.Value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,163 @@
+package managedhub
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/status/conflator"
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/spec"
+	eventversion "github.com/stolostron/multicluster-global-hub/pkg/bundle/version"
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
+	"github.com/stolostron/multicluster-global-hub/pkg/logger"
+)
+
+const specApplyBatchSize = 500
+
+type hubSpecApplyHandler struct {
+	log           *zap.SugaredLogger
+	eventType     string
+	eventSyncMode enum.EventSyncMode
+	eventPriority conflator.ConflationPriority
+}
+
+// RegisterHubSpecApplyHandler stores the results of applying the spec objects on the hubs. Every acknowledgement is
+// handled in the delta mode, since the acknowledgements of the different spec bundles shouldn't override each other.
+func RegisterHubSpecApplyHandler(conflationManager *conflator.ConflationManager) {
+	eventType := string(enum.HubSpecApplyType)
+	logName := strings.Replace(eventType, enum.EventTypePrefix, "", -1)
+	h := &hubSpecApplyHandler{
+		log:           logger.ZapLogger(logName),
+		eventType:     eventType,
+		eventSyncMode: enum.DeltaStateMode,
+		eventPriority: conflator.HubSpecApplyPriority,
+	}
+	conflationManager.Register(conflator.NewConflationRegistration(
+		h.eventPriority,
+		h.eventSyncMode,
+		h.eventType,
+		h.handleEvent,
+	))
+}
+
+func (h *hubSpecApplyHandler) handleEvent(ctx context.Context, evt *cloudevents.Event) error {
+	version := evt.Extensions()[eventversion.ExtVersion]
+	leafHubName := evt.Source()
+	h.log.Debugw("handler start", "type", evt.Type(), "LH", leafHubName, "version", version)
+
+	applyBundle := &spec.SpecApplyBundle{}
+	if err := evt.DataAs(applyBundle); err != nil {
+		return err
+	}
+	if len(applyBundle.Results) == 0 {
+		return nil
+	}
+
+	specApplies := specApplyModels(leafHubName, applyBundle)
+	specAudits, err := specAuditModels(leafHubName, evt.ID(), applyBundle)
+	if err != nil {
+		return err
+	}
+	// the audits are stored along with the results, every apply of the object is recorded, even if it's applied again
+	// with the same bundle version. The audits of the event are only stored once, so that they aren't appended again
+	// if the event is redelivered
+	err = database.GetGorm().Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "leaf_hub_name"}, {Name: "kind"}, {Name: "namespace"}, {Name: "name"}},
+			DoUpdates: clause.AssignmentColumns([]string{
+				"event_type", "operation", "version", "applied", "reason", "updated_at",
+			}),
+		}).CreateInBatches(specApplies, specApplyBatchSize).Error
+		if err != nil {
+			return fmt.Errorf("failed to store the spec apply results - %w", err)
+		}
+		if len(specAudits) == 0 {
+			return nil
+		}
+		audited := false
+		err = tx.Raw("SELECT EXISTS (SELECT 1 FROM event.spec_audits WHERE leaf_hub_name = ? AND event_id = ?)",
+			leafHubName, evt.ID()).Scan(&audited).Error
+		if err != nil {
+			return fmt.Errorf("failed to check the spec audits of the event %s - %w", evt.ID(), err)
+		}
+		if audited {
+			h.log.Debugw("the spec audits of the event are already stored", "LH", leafHubName, "id", evt.ID())
+			return nil
+		}
+		if err := tx.CreateInBatches(specAudits, specApplyBatchSize).Error; err != nil {
+			return fmt.Errorf("failed to store the spec audits - %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to handle the spec apply bundle of the hub %s - %w", leafHubName, err)
+	}
+
+	h.log.Debugw("handler finished", "type", evt.Type(), "LH", leafHubName, "version", version)
+	return nil
+}
+
+// specApplyModels converts the results into the rows, only the last result is kept if the object is applied more than
+// once in the bundle
+func specApplyModels(leafHubName string, applyBundle *spec.SpecApplyBundle) []models.SpecApply {
+	indexes := map[string]int{}
+	specApplies := []models.SpecApply{}
+	for _, result := range applyBundle.Results {
+		specApply := models.SpecApply{
+			LeafHubName: leafHubName,
+			EventType:   applyBundle.EventType,
+			Kind:        result.Kind,
+			Namespace:   result.Namespace,
+			Name:        result.Name,
+			Operation:   result.Operation,
+			Version:     applyBundle.Version,
+			Applied:     result.Applied,
+			Reason:      result.Reason,
+		}
+		key := fmt.Sprintf("%s/%s/%s", result.Kind, result.Namespace, result.Name)
+		if index, found := indexes[key]; found {
+			specApplies[index] = specApply
+			continue
+		}
+		indexes[key] = len(specApplies)
+		specApplies = append(specApplies, specApply)
+	}
+	return specApplies
+}
+
+// specAuditModels converts the audited results of the event into the records, one per applied object
+func specAuditModels(leafHubName, eventID string, applyBundle *spec.SpecApplyBundle) ([]models.SpecAudit, error) {
+	specAudits := []models.SpecAudit{}
+	for _, result := range applyBundle.Results {
+		if result.Audit == nil {
+			continue
+		}
+		groups, err := json.Marshal(result.Audit.Groups)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal the user groups - %w", err)
+		}
+		specAudits = append(specAudits, models.SpecAudit{
+			LeafHubName: leafHubName,
+			EventType:   applyBundle.EventType,
+			Version:     applyBundle.Version,
+			EventID:     eventID,
+			User:        result.Audit.User,
+			Groups:      groups,
+			Kind:        result.Kind,
+			Namespace:   result.Namespace,
+			Name:        result.Name,
+			Operation:   result.Operation,
+			Applied:     result.Applied,
+			Reason:      result.Reason,
+		})
+	}
+	return specAudits, nil
+}
@@ -0,0 +1,76 @@
+package managedhub
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/spec"
+)
+
+func TestSpecApplyModels(t *testing.T) {
+	applyBundle := &spec.SpecApplyBundle{
+		EventType: "Policies",
+		Version:   100,
+		Results: []spec.SpecApplyResult{
+			{Kind: "Policy", Namespace: "default", Name: "policy1", Operation: spec.SpecApplyOperationUpdate},
+			{Kind: "Placement", Namespace: "default", Name: "placement1", Operation: spec.SpecApplyOperationUpdate,
+				Applied: true},
+			{Kind: "Policy", Namespace: "default", Name: "policy1", Operation: spec.SpecApplyOperationDelete,
+				Reason: "forbidden"},
+		},
+	}
+
+	specApplies := specApplyModels("hub1", applyBundle)
+	assert.Len(t, specApplies, 2)
+
+	// the last result of the object is kept
+	assert.Equal(t, "hub1", specApplies[0].LeafHubName)
+	assert.Equal(t, "policy1", specApplies[0].Name)
+	assert.Equal(t, spec.SpecApplyOperationDelete, specApplies[0].Operation)
+	assert.Equal(t, "forbidden", specApplies[0].Reason)
+	assert.Equal(t, int64(100), specApplies[0].Version)
+	assert.False(t, specApplies[0].Applied)
+
+	assert.Equal(t, "placement1", specApplies[1].Name)
+	assert.True(t, specApplies[1].Applied)
+}
+
+func TestSpecAuditModels(t *testing.T) {
+	applyBundle := &spec.SpecApplyBundle{
+		EventType: "Policies",
+		Version:   100,
+		Results: []spec.SpecApplyResult{
+			{Kind: "Policy", Namespace: "default", Name: "policy1", Operation: spec.SpecApplyOperationUpdate,
+				Applied: true, Audit: &spec.SpecApplyAudit{User: "alice", Groups: []string{"admins", "devs"}}},
+			{Kind: "Placement", Namespace: "default", Name: "placement1", Operation: spec.SpecApplyOperationUpdate,
+				Applied: true},
+			{Kind: "Policy", Namespace: "default", Name: "policy2", Operation: spec.SpecApplyOperationDelete,
+				Reason: "forbidden", Audit: &spec.SpecApplyAudit{User: "bob"}},
+			{Kind: "Policy", Namespace: "default", Name: "policy2", Operation: spec.SpecApplyOperationDelete,
+				Reason: "not found", Audit: &spec.SpecApplyAudit{User: "bob"}},
+		},
+	}
+
+	specAudits, err := specAuditModels("hub1", "event1", applyBundle)
+	assert.NoError(t, err)
+
+	// the result without the audit is skipped, and every audited apply of the object is recorded
+	assert.Len(t, specAudits, 3)
+	assert.Equal(t, "alice", specAudits[0].User)
+	assert.JSONEq(t, `["admins","devs"]`, string(specAudits[0].Groups))
+	assert.Equal(t, "hub1", specAudits[0].LeafHubName)
+	assert.Equal(t, "event1", specAudits[0].EventID)
+	assert.Equal(t, int64(100), specAudits[0].Version)
+	assert.True(t, specAudits[0].Applied)
+
+	assert.Equal(t, "bob", specAudits[1].User)
+	assert.Equal(t, "policy2", specAudits[1].Name)
+	assert.Equal(t, spec.SpecApplyOperationDelete, specAudits[1].Operation)
+	assert.Equal(t, "forbidden", specAudits[1].Reason)
+	assert.False(t, specAudits[1].Applied)
+
+	assert.Equal(t, "policy2", specAudits[2].Name)
+	assert.Equal(t, "not found", specAudits[2].Reason)
+	assert.Equal(t, "event1", specAudits[2].EventID)
+}
@@ -0,0 +1,34 @@
+package managedhub
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/spec/specversion"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/status/conflator"
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/spec"
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
+)
+
+// RegisterHubSpecVersionHandler records the spec bundle versions applied by the managed hubs, then the spec syncers
+// only send the objects changed after them
+func RegisterHubSpecVersionHandler(conflationManager *conflator.ConflationManager) {
+	conflationManager.Register(conflator.NewConflationRegistration(
+		conflator.HubSpecVersionPriority,
+		enum.CompleteStateMode,
+		string(enum.HubSpecVersionType),
+		handleSpecVersionEvent,
+	))
+}
+
+func handleSpecVersionEvent(ctx context.Context, evt *cloudevents.Event) error {
+	versionBundle := spec.NewSpecVersionBundle()
+	if err := json.Unmarshal(evt.Data(), versionBundle); err != nil {
+		return fmt.Errorf("failed to unmarshal the spec versions of the hub %s: %w", evt.Source(), err)
+	}
+	specversion.Update(evt.Source(), versionBundle)
+	return nil
+}
@@ -0,0 +1,34 @@
+package security
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+)
+
+// syncFindings replaces the findings of the source in the hub with the rows. The rows are upserted with the
+// updatedAt, so that the created_at of the existing findings is kept, then the findings which aren't reported
+// anymore, e.g. the fixed violations, are deleted.
+func syncFindings[T any](ctx context.Context, hubName, source string, updatedAt time.Time, rows []T) error {
+	return database.GetGorm().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if len(rows) > 0 {
+			// the conflict target is the primary key of the table
+			err := tx.Clauses(clause.OnConflict{UpdateAll: true}).CreateInBatches(rows, 500).Error
+			if err != nil {
+				return err
+			}
+		}
+		var model T
+		return tx.Where("hub_name = ? AND source = ? AND updated_at < ?", hubName, source, updatedAt).
+			Delete(&model).Error
+	})
+}
+
+// syncTime is the updated time of the findings, it's truncated to the precision of the database timestamp.
+func syncTime() time.Time {
+	return time.Now().Truncate(time.Microsecond)
+}
@@ -0,0 +1,118 @@
+package security
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"go.uber.org/zap"
+
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/status/conflator"
+	eventversion "github.com/stolostron/multicluster-global-hub/pkg/bundle/version"
+	dbmodels "github.com/stolostron/multicluster-global-hub/pkg/database/models"
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
+	"github.com/stolostron/multicluster-global-hub/pkg/logger"
+	wiremodels "github.com/stolostron/multicluster-global-hub/pkg/wire/models"
+)
+
+type securityViolationsHandler struct {
+	log           *zap.SugaredLogger
+	eventType     string
+	eventSyncMode enum.EventSyncMode
+	eventPriority conflator.ConflationPriority
+	handle        func(ctx context.Context, leafHubName string, evt *cloudevents.Event) error
+}
+
+// RegisterSecurityViolationsHandler registers the handlers of the violations of the clusters and the deployments.
+func RegisterSecurityViolationsHandler(conflationManager *conflator.ConflationManager) {
+	for eventType, handle := range map[enum.EventType]func(context.Context, string, *cloudevents.Event) error{
+		enum.SecurityClusterViolationsType:    handleClusterViolations,
+		enum.SecurityDeploymentViolationsType: handleDeploymentViolations,
+	} {
+		h := &securityViolationsHandler{
+			log:           logger.ZapLogger(strings.Replace(string(eventType), enum.EventTypePrefix, "", -1)),
+			eventType:     string(eventType),
+			eventSyncMode: enum.CompleteStateMode,
+			eventPriority: conflator.SecurityViolationsPriority,
+			handle:        handle,
+		}
+		conflationManager.Register(conflator.NewConflationRegistration(
+			h.eventPriority,
+			h.eventSyncMode,
+			h.eventType,
+			h.handleEvent,
+		))
+	}
+}
+
+func (h *securityViolationsHandler) handleEvent(ctx context.Context, evt *cloudevents.Event) error {
+	version := evt.Extensions()[eventversion.ExtVersion]
+	h.log.Debugw("handler start", "type", evt.Type(), "LH", evt.Source(), "version", version)
+
+	if err := h.handle(ctx, evt.Source(), evt); err != nil {
+		return err
+	}
+
+	h.log.Debugw("handler finished", "type", evt.Type(), "LH", evt.Source(), "version", version)
+	return nil
+}
+
+func handleClusterViolations(ctx context.Context, leafHubName string, evt *cloudevents.Event) error {
+	wireModel := &wiremodels.SecurityClusterViolations{}
+	if err := evt.DataAs(wireModel); err != nil {
+		return err
+	}
+
+	updatedAt := syncTime()
+	rows := make([]dbmodels.SecurityClusterViolations, 0, len(wireModel.Clusters))
+	for _, cluster := range wireModel.Clusters {
+		rows = append(rows, dbmodels.SecurityClusterViolations{
+			HubName:     leafHubName,
+			Source:      wireModel.Source,
+			ClusterName: cluster.Cluster,
+			Low:         cluster.Low,
+			Medium:      cluster.Medium,
+			High:        cluster.High,
+			Critical:    cluster.Critical,
+			DetailURL:   wireModel.DetailURL,
+			UpdatedAt:   updatedAt,
+		})
+	}
+	return syncFindings(ctx, leafHubName, wireModel.Source, updatedAt, rows)
+}
+
+func handleDeploymentViolations(ctx context.Context, leafHubName string, evt *cloudevents.Event) error {
+	wireModel := &wiremodels.SecurityDeploymentViolations{}
+	if err := evt.DataAs(wireModel); err != nil {
+		return err
+	}
+
+	updatedAt := syncTime()
+	rows := make([]dbmodels.SecurityDeploymentViolations, 0, len(wireModel.Deployments))
+	for _, deployment := range wireModel.Deployments {
+		policies := deployment.Policies
+		if policies == nil {
+			policies = []string{}
+		}
+		policiesJSON, err := json.Marshal(policies)
+		if err != nil {
+			return err
+		}
+		rows = append(rows, dbmodels.SecurityDeploymentViolations{
+			HubName:        leafHubName,
+			Source:         wireModel.Source,
+			ClusterName:    deployment.Cluster,
+			Namespace:      deployment.Namespace,
+			DeploymentName: deployment.Name,
+			Low:            deployment.Low,
+			Medium:         deployment.Medium,
+			High:           deployment.High,
+			Critical:       deployment.Critical,
+			Policies:       policiesJSON,
+			DetailURL:      wireModel.DetailURL,
+			UpdatedAt:      updatedAt,
+		})
+	}
+	return syncFindings(ctx, leafHubName, wireModel.Source, updatedAt, rows)
+}
@@ -0,0 +1,95 @@
+package security
+
+import (
+	"context"
+	"strings"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"go.uber.org/zap"
+
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/status/conflator"
+	eventversion "github.com/stolostron/multicluster-global-hub/pkg/bundle/version"
+	dbmodels "github.com/stolostron/multicluster-global-hub/pkg/database/models"
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
+	"github.com/stolostron/multicluster-global-hub/pkg/logger"
+	wiremodels "github.com/stolostron/multicluster-global-hub/pkg/wire/models"
+)
+
+type securityVulnerabilitiesHandler struct {
+	log           *zap.SugaredLogger
+	eventType     string
+	eventSyncMode enum.EventSyncMode
+	eventPriority conflator.ConflationPriority
+}
+
+func RegisterSecurityVulnerabilitiesHandler(conflationManager *conflator.ConflationManager) {
+	eventType := string(enum.SecurityVulnerabilitiesType)
+	logName := strings.Replace(eventType, enum.EventTypePrefix, "", -1)
+	h := &securityVulnerabilitiesHandler{
+		log:           logger.ZapLogger(logName),
+		eventType:     eventType,
+		eventSyncMode: enum.CompleteStateMode,
+		eventPriority: conflator.SecurityVulnerabilitiesPriority,
+	}
+	conflationManager.Register(conflator.NewConflationRegistration(
+		h.eventPriority,
+		h.eventSyncMode,
+		h.eventType,
+		h.handleEvent,
+	))
+}
+
+func (h *securityVulnerabilitiesHandler) handleEvent(ctx context.Context, evt *cloudevents.Event) error {
+	version := evt.Extensions()[eventversion.ExtVersion]
+	leafHubName := evt.Source()
+	h.log.Debugw("handler start", "type", evt.Type(), "LH", evt.Source(), "version", version)
+
+	wireModel := &wiremodels.SecurityVulnerabilities{}
+	if err := evt.DataAs(wireModel); err != nil {
+		return err
+	}
+
+	updatedAt := syncTime()
+	deployments := make([]dbmodels.SecurityDeploymentVulnerabilities, 0, len(wireModel.Deployments))
+	for _, deployment := range wireModel.Deployments {
+		deployments = append(deployments, dbmodels.SecurityDeploymentVulnerabilities{
+			HubName:        leafHubName,
+			Source:         wireModel.Source,
+			ClusterName:    deployment.Cluster,
+			Namespace:      deployment.Namespace,
+			DeploymentName: deployment.Name,
+			Low:            deployment.Low,
+			Moderate:       deployment.Moderate,
+			Important:      deployment.Important,
+			Critical:       deployment.Critical,
+			Fixable:        deployment.Fixable,
+			DetailURL:      wireModel.DetailURL,
+			UpdatedAt:      updatedAt,
+		})
+	}
+	if err := syncFindings(ctx, leafHubName, wireModel.Source, updatedAt, deployments); err != nil {
+		return err
+	}
+
+	images := make([]dbmodels.SecurityImageVulnerabilities, 0, len(wireModel.Images))
+	for _, image := range wireModel.Images {
+		images = append(images, dbmodels.SecurityImageVulnerabilities{
+			HubName:   leafHubName,
+			Source:    wireModel.Source,
+			ImageName: image.Name,
+			Low:       image.Low,
+			Moderate:  image.Moderate,
+			Important: image.Important,
+			Critical:  image.Critical,
+			Fixable:   image.Fixable,
+			DetailURL: wireModel.DetailURL,
+			UpdatedAt: updatedAt,
+		})
+	}
+	if err := syncFindings(ctx, leafHubName, wireModel.Source, updatedAt, images); err != nil {
+		return err
+	}
+
+	h.log.Debugw("handler finished", "type", evt.Type(), "LH", evt.Source(), "version", version)
+	return nil
+}
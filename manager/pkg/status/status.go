@@ -7,6 +7,7 @@ import (
 
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/configs"
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/status/conflator"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/status/deadletter"
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/status/dispatcher"
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/status/handlers"
 	"github.com/stolostron/multicluster-global-hub/pkg/statistics"
@@ -30,6 +31,13 @@ func AddStatusSyncers(mgr ctrl.Manager, consumer transport.Consumer, managerConf
 	// manage all Conflation Units and handlers
 	conflationManager := conflator.NewConflationManager(stats)
 	handlers.RegisterHandlers(mgr, conflationManager, managerConfig.EnableGlobalResource)
+	if err := conflationManager.LoadPlugins(); err != nil {
+		return err
+	}
+	if err := conflationManager.Validate(); err != nil {
+		return fmt.Errorf("invalid conflation registrations: %w", err)
+	}
+	deadletter.RegisterReplayFunc(conflationManager.Replay)
 
 	// start consume message from transport to conflation manager
 	if err := dispatcher.AddTransportDispatcher(mgr, consumer, managerConfig, conflationManager, stats); err != nil {
@@ -46,6 +54,7 @@ func AddStatusSyncers(mgr ctrl.Manager, consumer transport.Consumer, managerConf
 	if err := mgr.Add(committer); err != nil {
 		return fmt.Errorf("failed to start the offset committer: %w", err)
 	}
+	setDebugTargets(conflationManager, committer)
 	statusCtrlStarted = true
 	return nil
 }
@@ -22,6 +22,8 @@ import (
 
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Phase",type="string",JSONPath=".status.phase"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
 // +operator-sdk:csv:customresourcedefinitions:resources={{Deployment,v1,multicluster-global-hub-manager}}
 // ManagedClusterMigration is a global hub resource that allows you to migrate managed clusters from one hub to another
 type ManagedClusterMigration struct {
@@ -40,6 +42,11 @@ type ManagedClusterMigrationSpec struct {
 	// +operator-sdk:csv:customresourcedefinitions:type=spec
 	IncludedManagedClusters []string `json:"includedManagedClusters,omitempty"`
 
+	// ManagedClusterSelector selects the managed clusters to migrate by their labels, the selected clusters are
+	// migrated together with the IncludedManagedClusters
+	// +optional
+	ManagedClusterSelector *metav1.LabelSelector `json:"managedClusterSelector,omitempty"`
+
 	// From defines which hub cluster the managed clusters are from
 	// +optional
 	From string `json:"from,omitempty"`
@@ -47,15 +54,92 @@ type ManagedClusterMigrationSpec struct {
 	// To defines which hub cluster the managed clusters migrate to
 	// +operator-sdk:csv:customresourcedefinitions:type=spec
 	To string `json:"to,omitempty"`
+
+	// RegistrationTimeout is how long to wait for the managed clusters to register to the target hub, the clusters
+	// that aren't registered in time are rolled back to the source hub
+	// +kubebuilder:default:="10m"
+	// +optional
+	RegistrationTimeout metav1.Duration `json:"registrationTimeout,omitempty"`
 }
 
 // ManagedClusterMigrationStatus defines the observed state of managedclustermigration
 type ManagedClusterMigrationStatus struct {
+	// Phase is the current step of the migration
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	// +optional
+	Phase string `json:"phase,omitempty"`
+
+	// Clusters represents the progress of each managed cluster in the migration
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	// +optional
+	Clusters []ClusterMigrationStatus `json:"clusters,omitempty"`
+
 	// Conditions represents the latest available observations of the current state
 	// +operator-sdk:csv:customresourcedefinitions:type=status
 	Conditions []metav1.Condition `json:"conditions,omitempty"`
 }
 
+// ClusterMigrationStatus represents the progress of a managed cluster in the migration
+type ClusterMigrationStatus struct {
+	// Name is the name of the managed cluster
+	Name string `json:"name"`
+	// From is the hub cluster the managed cluster is migrated from
+	From string `json:"from,omitempty"`
+	// Phase is the current step of the managed cluster
+	Phase string `json:"phase,omitempty"`
+	// Message describes the current step of the managed cluster
+	// +optional
+	Message string `json:"message,omitempty"`
+	// LastTransitionTime is the last time the phase of the managed cluster changed
+	// +optional
+	LastTransitionTime metav1.Time `json:"lastTransitionTime,omitempty"`
+}
+
+const (
+	// PhaseValidating resolves the managed clusters and their source hubs
+	PhaseValidating = "Validating"
+	// PhaseInitializing prepares the target hub and the source hubs to accept the managed clusters
+	PhaseInitializing = "Initializing"
+	// PhaseDeploying deploys the bootstrap kubeconfigs of the target hub and the source hub to the managed clusters
+	PhaseDeploying = "Deploying"
+	// PhaseRegistering waits for the managed clusters to register to the target hub
+	PhaseRegistering = "Registering"
+	// PhaseRollingBack waits for the managed clusters which aren't registered in time to register back to the source hubs
+	PhaseRollingBack = "RollingBack"
+	// PhaseCleaning removes the migrated managed clusters from the source hubs
+	PhaseCleaning = "Cleaning"
+	// PhaseCompleted means all the managed clusters are migrated
+	PhaseCompleted = "Completed"
+	// PhaseFailed means the migration is stopped, the unregistered managed clusters are rolled back or failed
+	PhaseFailed = "Failed"
+)
+
+const (
+	// ClusterPhasePending means the managed cluster is waiting for the target hub
+	ClusterPhasePending = "Pending"
+	// ClusterPhaseRegistering means the managed cluster is switching to the target hub
+	ClusterPhaseRegistering = "Registering"
+	// ClusterPhaseRegistered means the managed cluster is available on the target hub
+	ClusterPhaseRegistered = "Registered"
+	// ClusterPhaseCompleted means the managed cluster is removed from the source hub
+	ClusterPhaseCompleted = "Completed"
+	// ClusterPhaseRollingBack means the managed cluster is switching back to the source hub
+	ClusterPhaseRollingBack = "RollingBack"
+	// ClusterPhaseRolledBack means the managed cluster is available on the source hub again
+	ClusterPhaseRolledBack = "RolledBack"
+	// ClusterPhaseFailed means the managed cluster is neither registered to the target hub nor back to the source hub
+	ClusterPhaseFailed = "Failed"
+)
+
+const (
+	ConditionTypeValidated   = "ResourceValidated"
+	ConditionTypeInitialized = "ResourceInitialized"
+	ConditionTypeDeployed    = "ResourceDeployed"
+	ConditionTypeRegistered  = "ClusterRegistered"
+	ConditionTypeRolledBack  = "ClusterRolledBack"
+	ConditionTypeCleaned     = "ResourceCleaned"
+)
+
 // +kubebuilder:object:root=true
 // ManagedClusterMigrationList contains a list of migration
 type ManagedClusterMigrationList struct {
@@ -25,6 +25,22 @@ import (
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterMigrationStatus) DeepCopyInto(out *ClusterMigrationStatus) {
+	*out = *in
+	in.LastTransitionTime.DeepCopyInto(&out.LastTransitionTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterMigrationStatus.
+func (in *ClusterMigrationStatus) DeepCopy() *ClusterMigrationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterMigrationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ManagedClusterMigration) DeepCopyInto(out *ManagedClusterMigration) {
 	*out = *in
@@ -92,6 +108,12 @@ func (in *ManagedClusterMigrationSpec) DeepCopyInto(out *ManagedClusterMigration
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.ManagedClusterSelector != nil {
+		in, out := &in.ManagedClusterSelector, &out.ManagedClusterSelector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	out.RegistrationTimeout = in.RegistrationTimeout
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManagedClusterMigrationSpec.
@@ -107,6 +129,13 @@ func (in *ManagedClusterMigrationSpec) DeepCopy() *ManagedClusterMigrationSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ManagedClusterMigrationStatus) DeepCopyInto(out *ManagedClusterMigrationStatus) {
 	*out = *in
+	if in.Clusters != nil {
+		in, out := &in.Clusters, &out.Clusters
+		*out = make([]ClusterMigrationStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
 		*out = make([]v1.Condition, len(*in))
@@ -169,11 +169,115 @@ type PostgresSpec struct {
 	// +kubebuilder:default:="18m"
 	Retention string `json:"retention,omitempty"`
 
+	// TableRetentions overrides the retention of the specified tables, e.g. keeping "history.local_compliance" for
+	// "2y" and "event.managed_clusters" for "3m". The other tables are kept for the Retention.
+	// +optional
+	TableRetentions []TableRetention `json:"tableRetentions,omitempty"`
+
+	// Archive exports the expired partitions before they're dropped from the database
+	// +optional
+	Archive *ArchiveSpec `json:"archive,omitempty"`
+
+	// Backup runs the scheduled logical backups of the global hub database, and restores the database from a backup
+	// +optional
+	Backup *DatabaseBackupSpec `json:"backup,omitempty"`
+
 	// StorageSize specifies the size for storage
 	// +optional
 	StorageSize string `json:"storageSize,omitempty"`
 }
 
+// TableRetention defines how long to keep the data of a table in the database
+type TableRetention struct {
+	// Table is the partitioned table, such as "event.managed_clusters", or the table with the soft deleted records,
+	// such as "status.managed_clusters"
+	// +kubebuilder:validation:Required
+	Table string `json:"table"`
+
+	// Retention is a duration string in the same format as the retention of the postgres, such as "3m" or "2y"
+	// +kubebuilder:validation:Required
+	Retention string `json:"retention"`
+}
+
+// ArchiveSpec defines how and where to export the expired partitions. The partition is kept in the database until
+// it's exported successfully.
+type ArchiveSpec struct {
+	// Format is the format of the exported partitions, "ndjson" is the gzip compressed newline-delimited JSON
+	// +kubebuilder:validation:Enum=ndjson;parquet
+	// +kubebuilder:default:="ndjson"
+	Format string `json:"format,omitempty"`
+
+	// PersistentVolumeClaim is the name of the claim in the global hub namespace to store the exported partitions
+	// +optional
+	PersistentVolumeClaim string `json:"persistentVolumeClaim,omitempty"`
+
+	// S3 is the S3-compatible object store to upload the exported partitions
+	// +optional
+	S3 *S3ArchiveSpec `json:"s3,omitempty"`
+}
+
+// S3ArchiveSpec defines the S3-compatible object store of the exported partitions
+type S3ArchiveSpec struct {
+	// Endpoint is the host and optional port of the object store, such as "s3.us-east-1.amazonaws.com"
+	// +kubebuilder:validation:Required
+	Endpoint string `json:"endpoint"`
+
+	// Bucket is the bucket to upload the exported partitions
+	// +kubebuilder:validation:Required
+	Bucket string `json:"bucket"`
+
+	// Prefix is the prefix of the object names
+	// +optional
+	Prefix string `json:"prefix,omitempty"`
+
+	// Region is the region of the bucket
+	// +optional
+	Region string `json:"region,omitempty"`
+
+	// Insecure connects to the object store without TLS
+	// +optional
+	Insecure bool `json:"insecure,omitempty"`
+
+	// CredentialSecret is the secret in the global hub namespace with the "access-key-id" and "secret-access-key"
+	// +kubebuilder:validation:Required
+	CredentialSecret string `json:"credentialSecret"`
+}
+
+// DatabaseBackupSpec defines the logical backups of the global hub database. Only the data of the global hub schemas
+// is dumped, the schemas are rebuilt by the operator before the data is restored.
+type DatabaseBackupSpec struct {
+	// Schedule is the cron expression of the backups, e.g. "0 0 * * *" runs the backup at midnight every day
+	// +kubebuilder:default:="0 0 * * *"
+	Schedule string `json:"schedule,omitempty"`
+
+	// Retention is the number of the latest backups to keep, the older backups are removed after each backup
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:default:=7
+	Retention int32 `json:"retention,omitempty"`
+
+	// PersistentVolumeClaim is the name of the claim in the global hub namespace to store the backups
+	// +optional
+	PersistentVolumeClaim string `json:"persistentVolumeClaim,omitempty"`
+
+	// S3 is the S3-compatible object store to upload the backups
+	// +optional
+	S3 *S3ArchiveSpec `json:"s3,omitempty"`
+
+	// Restore reloads the data of a backup into the database. The manager is stopped while the backup is restored,
+	// and the progress is reported by the "DatabaseRestore" condition. A backup is restored at most once, the restored
+	// backup is recorded in the "databaseRestore" status. A failed restore leaves the database unchanged, and it's
+	// retried once the spec is changed. The database user must be a superuser to restore the backup.
+	// +optional
+	Restore *DatabaseRestoreSpec `json:"restore,omitempty"`
+}
+
+// DatabaseRestoreSpec defines the backup to restore
+type DatabaseRestoreSpec struct {
+	// Backup is the name of the backup, such as "globalhub-20250101000000.sql.gz"
+	// +kubebuilder:validation:Required
+	Backup string `json:"backup"`
+}
+
 // KafkaSpec defines the desired state of kafka
 type KafkaSpec struct {
 	// KafkaTopics specify the desired topics
@@ -214,6 +318,28 @@ type MulticlusterGlobalHubStatus struct {
 	// +kubebuilder:default:="Progressing"
 	// +optional
 	Phase GlobalHubPhaseType `json:"phase"`
+
+	// DatabaseRestore is the latest finished restore of the database backup
+	// +optional
+	DatabaseRestore *DatabaseRestoreStatus `json:"databaseRestore,omitempty"`
+}
+
+// DatabaseRestoreStatus is the finished restore of the database backup
+type DatabaseRestoreStatus struct {
+	// Backup is the name of the restored backup
+	Backup string `json:"backup"`
+
+	// ObservedGeneration is the generation of the MulticlusterGlobalHub when the restore is finished
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Failed is true if the restore failed, the database is left unchanged by the failed restore
+	// +optional
+	Failed bool `json:"failed,omitempty"`
+
+	// CompletionTime is the time when the restore is finished
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
 }
 type GlobalHubPhaseType string
 
@@ -68,6 +68,26 @@ func (in *AdvancedSpec) DeepCopy() *AdvancedSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ArchiveSpec) DeepCopyInto(out *ArchiveSpec) {
+	*out = *in
+	if in.S3 != nil {
+		in, out := &in.S3, &out.S3
+		*out = new(S3ArchiveSpec)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ArchiveSpec.
+func (in *ArchiveSpec) DeepCopy() *ArchiveSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ArchiveSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CommonSpec) DeepCopyInto(out *CommonSpec) {
 	*out = *in
@@ -92,7 +112,7 @@ func (in *CommonSpec) DeepCopy() *CommonSpec {
 func (in *DataLayerSpec) DeepCopyInto(out *DataLayerSpec) {
 	*out = *in
 	out.Kafka = in.Kafka
-	out.Postgres = in.Postgres
+	in.Postgres.DeepCopyInto(&out.Postgres)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataLayerSpec.
@@ -105,6 +125,65 @@ func (in *DataLayerSpec) DeepCopy() *DataLayerSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DatabaseBackupSpec) DeepCopyInto(out *DatabaseBackupSpec) {
+	*out = *in
+	if in.S3 != nil {
+		in, out := &in.S3, &out.S3
+		*out = new(S3ArchiveSpec)
+		**out = **in
+	}
+	if in.Restore != nil {
+		in, out := &in.Restore, &out.Restore
+		*out = new(DatabaseRestoreSpec)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatabaseBackupSpec.
+func (in *DatabaseBackupSpec) DeepCopy() *DatabaseBackupSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(DatabaseBackupSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DatabaseRestoreSpec) DeepCopyInto(out *DatabaseRestoreSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatabaseRestoreSpec.
+func (in *DatabaseRestoreSpec) DeepCopy() *DatabaseRestoreSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(DatabaseRestoreSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DatabaseRestoreStatus) DeepCopyInto(out *DatabaseRestoreStatus) {
+	*out = *in
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatabaseRestoreStatus.
+func (in *DatabaseRestoreStatus) DeepCopy() *DatabaseRestoreStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DatabaseRestoreStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *KafkaSpec) DeepCopyInto(out *KafkaSpec) {
 	*out = *in
@@ -212,7 +291,7 @@ func (in *MulticlusterGlobalHubSpec) DeepCopyInto(out *MulticlusterGlobalHubSpec
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
-	out.DataLayerSpec = in.DataLayerSpec
+	in.DataLayerSpec.DeepCopyInto(&out.DataLayerSpec)
 	if in.AdvancedSpec != nil {
 		in, out := &in.AdvancedSpec, &out.AdvancedSpec
 		*out = new(AdvancedSpec)
@@ -247,6 +326,11 @@ func (in *MulticlusterGlobalHubStatus) DeepCopyInto(out *MulticlusterGlobalHubSt
 			(*out)[key] = *val.DeepCopy()
 		}
 	}
+	if in.DatabaseRestore != nil {
+		in, out := &in.DatabaseRestore, &out.DatabaseRestore
+		*out = new(DatabaseRestoreStatus)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MulticlusterGlobalHubStatus.
@@ -262,6 +346,21 @@ func (in *MulticlusterGlobalHubStatus) DeepCopy() *MulticlusterGlobalHubStatus {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PostgresSpec) DeepCopyInto(out *PostgresSpec) {
 	*out = *in
+	if in.TableRetentions != nil {
+		in, out := &in.TableRetentions, &out.TableRetentions
+		*out = make([]TableRetention, len(*in))
+		copy(*out, *in)
+	}
+	if in.Archive != nil {
+		in, out := &in.Archive, &out.Archive
+		*out = new(ArchiveSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Backup != nil {
+		in, out := &in.Backup, &out.Backup
+		*out = new(DatabaseBackupSpec)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PostgresSpec.
@@ -274,6 +373,21 @@ func (in *PostgresSpec) DeepCopy() *PostgresSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *S3ArchiveSpec) DeepCopyInto(out *S3ArchiveSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new S3ArchiveSpec.
+func (in *S3ArchiveSpec) DeepCopy() *S3ArchiveSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(S3ArchiveSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *StatusCondition) DeepCopyInto(out *StatusCondition) {
 	*out = *in
@@ -289,3 +403,18 @@ func (in *StatusCondition) DeepCopy() *StatusCondition {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TableRetention) DeepCopyInto(out *TableRetention) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TableRetention.
+func (in *TableRetention) DeepCopy() *TableRetention {
+	if in == nil {
+		return nil
+	}
+	out := new(TableRetention)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -0,0 +1,81 @@
+// Copyright (c) 2025 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+// The migration command prints the pending migrations of the global hub database, and applies them with
+// "--dry-run=false". The migrations are the ones embedded in the operator, e.g.
+//
+//	go run ./operator/cmd/migration --database-url "postgres://..."
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/spf13/pflag"
+
+	"github.com/stolostron/multicluster-global-hub/operator/pkg/controllers/storage"
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	"github.com/stolostron/multicluster-global-hub/pkg/database/migration"
+)
+
+func main() {
+	if err := doMain(context.Background()); err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func doMain(ctx context.Context) error {
+	var databaseURL, caCertPath string
+	var dryRun bool
+	pflag.StringVar(&databaseURL, "database-url", "", "The URL of the global hub database.")
+	pflag.StringVar(&caCertPath, "ca-cert-path", "", "The path of CA certificate for the database server.")
+	pflag.BoolVar(&dryRun, "dry-run", true, "Only print the pending migrations without applying them.")
+	pflag.Parse()
+
+	if databaseURL == "" {
+		return fmt.Errorf("the --database-url is required")
+	}
+
+	var cert []byte
+	if caCertPath != "" {
+		var err error
+		if cert, err = os.ReadFile(caCertPath); err != nil {
+			return fmt.Errorf("failed to read the CA certificate: %w", err)
+		}
+	}
+
+	migrations, err := storage.Migrations()
+	if err != nil {
+		return err
+	}
+
+	conn, err := database.PostgresConnection(ctx, databaseURL, cert)
+	if err != nil {
+		return err
+	}
+	defer conn.Close(ctx)
+
+	migrator := migration.NewMigrator(conn, migrations)
+	if dryRun {
+		pending, err := migrator.Pending(ctx)
+		if err != nil {
+			return err
+		}
+		if len(pending) == 0 {
+			fmt.Println("no pending migrations")
+			return nil
+		}
+		for _, m := range pending {
+			fmt.Printf("-- pending migration %04d_%s.sql (sha256: %s)\n%s\n", m.Version, m.Name, m.Checksum, m.SQL)
+		}
+		return nil
+	}
+
+	applied, err := migrator.Migrate(ctx)
+	for _, m := range applied {
+		fmt.Printf("applied migration %04d_%s.sql\n", m.Version, m.Name)
+	}
+	return err
+}
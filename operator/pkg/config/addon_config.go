@@ -98,6 +98,7 @@ type ManifestsConfig struct {
 	KlusterletWorkSA          string
 	EnableGlobalResource      bool
 	TransportFailureThreshold int
+	TransportCompressionType  string
 	AgentQPS                  float32
 	AgentBurst                int
 	LogLevel                  string
@@ -106,6 +107,9 @@ type ManifestsConfig struct {
 	Resources                 *Resources
 	EnableStackroxIntegration bool
 	StackroxPollInterval      time.Duration
+	// StatusResources is the quoted JSON of the resources declared to be collected by the agent
+	StatusResources     string
+	StatusResourceRules []StatusResourceRule
 
 	ImagePullSecretName     string
 	ImagePullSecretData     string
@@ -125,6 +129,13 @@ type ManifestsConfig struct {
 	Tolerations             []corev1.Toleration
 	AggregationLevel        string
 	EnableLocalPolicies     string
+	ManagedClusterSyncMode  string
+}
+
+// StatusResourceRule grants the agent to list the declared resources of the API group.
+type StatusResourceRule struct {
+	APIGroup  string
+	Resources []string
 }
 
 type Resources struct {
@@ -3,6 +3,7 @@ package config
 import (
 	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
 	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	"k8s.io/apimachinery/pkg/fields"
@@ -90,6 +91,22 @@ func InitCache(config *rest.Config, cacheOpts cache.Options) (cache.Cache, error
 				utils.GetDefaultNamespace(): {},
 			},
 		},
+		// database backup controller: the backup cronjob, the backup and restore jobs and their pods
+		&batchv1.CronJob{}: {
+			Namespaces: map[string]cache.Config{
+				utils.GetDefaultNamespace(): {LabelSelector: labelSelector},
+			},
+		},
+		&batchv1.Job{}: {
+			Namespaces: map[string]cache.Config{
+				utils.GetDefaultNamespace(): {LabelSelector: labelSelector},
+			},
+		},
+		&corev1.Pod{}: {
+			Namespaces: map[string]cache.Config{
+				utils.GetDefaultNamespace(): {LabelSelector: labelSelector},
+			},
+		},
 		&admissionregistrationv1.MutatingWebhookConfiguration{}: {
 			Label: labelSelector,
 		},
@@ -20,6 +20,7 @@ import (
 	"context"
 	"crypto/rand"
 	"encoding/base64"
+	"encoding/json"
 	"fmt"
 	"os"
 	"reflect"
@@ -35,6 +36,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/yaml"
 
 	"github.com/stolostron/multicluster-global-hub/operator/api/operator/v1alpha1"
 	"github.com/stolostron/multicluster-global-hub/operator/api/operator/v1alpha4"
@@ -70,6 +72,9 @@ const (
 	AggregationLevel       = "full"
 	EnableLocalPolicies    = "true"
 	AgentHeartbeatInterval = "60s"
+	// the sync modes of the managed clusters sent by the agents
+	ManagedClusterSyncModeDelta    = "delta"
+	ManagedClusterSyncModeComplete = "complete"
 )
 
 var (
@@ -218,11 +223,75 @@ func GetStackroxPollInterval(mgh *v1alpha4.MulticlusterGlobalHub) time.Duration
 	return value
 }
 
+// GetStatusResources returns the declarations of the resources collected by the agents in JSON, which are specified in
+// the annotations of the given object as a YAML or JSON list. If it isn't specified or the format isn't valid, then it
+// returns nil.
+func GetStatusResources(mgh *v1alpha4.MulticlusterGlobalHub) []byte {
+	text, ok := mgh.GetAnnotations()[operatorconstants.AnnotationMGHStatusResources]
+	if !ok {
+		return nil
+	}
+	value, err := yaml.YAMLToJSON([]byte(text))
+	if err == nil {
+		err = json.Unmarshal(value, &[]map[string]interface{}{})
+	}
+	if err != nil {
+		log.Errorf(
+			"Failed to parse value '%s' of annotation '%s', will ignore it: %v",
+			text, operatorconstants.AnnotationMGHStatusResources, err,
+		)
+		return nil
+	}
+	return value
+}
+
+// GetManagedClusterSyncMode returns the sync mode of the managed clusters specified in the annotations of the given
+// object, which is either "delta" or "complete". If it isn't specified or the value isn't valid, then it returns the
+// delta mode.
+func GetManagedClusterSyncMode(mgh *v1alpha4.MulticlusterGlobalHub) string {
+	mode, ok := mgh.GetAnnotations()[operatorconstants.AnnotationMGHManagedClusterSyncMode]
+	if !ok || mode == ManagedClusterSyncModeDelta {
+		return ManagedClusterSyncModeDelta
+	}
+	if mode != ManagedClusterSyncModeComplete {
+		log.Errorf("Invalid value '%s' of annotation '%s', will use the %s mode",
+			mode, operatorconstants.AnnotationMGHManagedClusterSyncMode, ManagedClusterSyncModeDelta)
+		return ManagedClusterSyncModeDelta
+	}
+	return mode
+}
+
 // GetSchedulerInterval returns the scheduler interval for moving policy compliance history
 func GetSchedulerInterval(mgh *v1alpha4.MulticlusterGlobalHub) string {
 	return getAnnotation(mgh, operatorconstants.AnnotationMGHSchedulerInterval)
 }
 
+// GetHubGracePeriod returns the grace period of the unreachable managed hubs, or an empty string to use the default
+func GetHubGracePeriod(mgh *v1alpha4.MulticlusterGlobalHub) string {
+	return getAnnotation(mgh, operatorconstants.AnnotationMGHHubGracePeriod)
+}
+
+// GetDatabaseBackupRequest returns the request of the on-demand database backup, or an empty string if not requested
+func GetDatabaseBackupRequest(mgh *v1alpha4.MulticlusterGlobalHub) string {
+	return getAnnotation(mgh, operatorconstants.AnnotationMGHDatabaseBackup)
+}
+
+// GetPendingDatabaseRestore returns the backup to restore, or an empty string if the backup isn't requested or it's
+// already restored. The failed restore is pending again once the spec is changed. The manager is stopped while the
+// restore is pending.
+func GetPendingDatabaseRestore(mgh *v1alpha4.MulticlusterGlobalHub) string {
+	backup := mgh.Spec.DataLayerSpec.Postgres.Backup
+	if backup == nil || backup.Restore == nil || backup.Restore.Backup == "" {
+		return ""
+	}
+	restored := mgh.Status.DatabaseRestore
+	if restored == nil || restored.Backup != backup.Restore.Backup ||
+		(restored.Failed && restored.ObservedGeneration < mgh.Generation) {
+		return backup.Restore.Backup
+	}
+	return ""
+}
+
 // SkipAuth returns true to skip authenticate for non-k8s api
 func SkipAuth(mgh *v1alpha4.MulticlusterGlobalHub) bool {
 	toSkipAuth := getAnnotation(mgh, operatorconstants.AnnotationMGHSkipAuth)
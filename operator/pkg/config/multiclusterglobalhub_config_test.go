@@ -263,3 +263,69 @@ func TestStackRoxPoolNotPresent(t *testing.T) {
 		)
 	}
 }
+
+func TestGetPendingDatabaseRestore(t *testing.T) {
+	backup := "globalhub-20250101000000.sql.gz"
+	tests := []struct {
+		desc       string
+		generation int64
+		restore    *globalhubv1alpha4.DatabaseRestoreSpec
+		restored   *globalhubv1alpha4.DatabaseRestoreStatus
+		expected   string
+	}{
+		{
+			desc:     "restore isn't requested",
+			expected: "",
+		},
+		{
+			desc:     "backup isn't restored",
+			restore:  &globalhubv1alpha4.DatabaseRestoreSpec{Backup: backup},
+			expected: backup,
+		},
+		{
+			desc:     "another backup is restored",
+			restore:  &globalhubv1alpha4.DatabaseRestoreSpec{Backup: backup},
+			restored: &globalhubv1alpha4.DatabaseRestoreStatus{Backup: "globalhub-20241231000000.sql.gz"},
+			expected: backup,
+		},
+		{
+			desc:       "backup is restored in the previous generation",
+			generation: 3,
+			restore:    &globalhubv1alpha4.DatabaseRestoreSpec{Backup: backup},
+			restored:   &globalhubv1alpha4.DatabaseRestoreStatus{Backup: backup, ObservedGeneration: 2},
+			expected:   "",
+		},
+		{
+			desc:       "restore failed in the generation",
+			generation: 2,
+			restore:    &globalhubv1alpha4.DatabaseRestoreSpec{Backup: backup},
+			restored:   &globalhubv1alpha4.DatabaseRestoreStatus{Backup: backup, ObservedGeneration: 2, Failed: true},
+			expected:   "",
+		},
+		{
+			desc:       "restore failed in the previous generation",
+			generation: 3,
+			restore:    &globalhubv1alpha4.DatabaseRestoreSpec{Backup: backup},
+			restored:   &globalhubv1alpha4.DatabaseRestoreStatus{Backup: backup, ObservedGeneration: 2, Failed: true},
+			expected:   backup,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			mgh := &globalhubv1alpha4.MulticlusterGlobalHub{
+				ObjectMeta: metav1.ObjectMeta{Generation: tc.generation},
+				Spec: globalhubv1alpha4.MulticlusterGlobalHubSpec{
+					DataLayerSpec: globalhubv1alpha4.DataLayerSpec{
+						Postgres: globalhubv1alpha4.PostgresSpec{
+							Backup: &globalhubv1alpha4.DatabaseBackupSpec{Restore: tc.restore},
+						},
+					},
+				},
+				Status: globalhubv1alpha4.MulticlusterGlobalHubStatus{DatabaseRestore: tc.restored},
+			}
+			if got := GetPendingDatabaseRestore(mgh); got != tc.expected {
+				t.Fatalf("expected the pending restore %q, but got %q", tc.expected, got)
+			}
+		})
+	}
+}
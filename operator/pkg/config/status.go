@@ -114,6 +114,21 @@ const (
 	CONDITION_MESSAGE_BACKUP_DISABLED = "Backup is disabled in RHACM"
 )
 
+// NOTE: the status of the database backup and restore is Unknown when the job is running
+const (
+	CONDITION_TYPE_DATABASE_BACKUP     = "DatabaseBackup"
+	CONDITION_REASON_BACKUP_SCHEDULED  = "BackupScheduled"
+	CONDITION_REASON_BACKUP_RUNNING    = "BackupInProgress"
+	CONDITION_REASON_BACKUP_SUCCEEDED  = "BackupSucceeded"
+	CONDITION_REASON_BACKUP_FAILED     = "BackupFailed"
+	CONDITION_REASON_BACKUP_INVALID    = "BackupInvalid"
+	CONDITION_TYPE_DATABASE_RESTORE    = "DatabaseRestore"
+	CONDITION_REASON_RESTORE_PENDING   = "RestorePending"
+	CONDITION_REASON_RESTORE_RUNNING   = "RestoreInProgress"
+	CONDITION_REASON_RESTORE_SUCCEEDED = "RestoreSucceeded"
+	CONDITION_REASON_RESTORE_FAILED    = "RestoreFailed"
+)
+
 type GetComponentStatus func(ctx context.Context,
 	c client.Client,
 	namespace string,
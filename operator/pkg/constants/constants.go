@@ -54,6 +54,12 @@ const (
 	// to identify the scheduler interval for moving policy compliance history
 	// valid value can be "month, week, day, hour, minute, second"
 	AnnotationMGHSchedulerInterval = "mgh-scheduler-interval"
+	// AnnotationMGHHubGracePeriod sits in MulticlusterGlobalHub annotations to set the duration, e.g. "1h", that
+	// the managed hub without heartbeat is unreachable with its data kept before it's inactive
+	AnnotationMGHHubGracePeriod = "mgh-hub-grace-period"
+	// AnnotationMGHDatabaseBackup sits in MulticlusterGlobalHub annotations to run a database backup on demand, each
+	// distinct value, e.g. the current time, runs the backup once
+	AnnotationMGHDatabaseBackup = "mgh-database-backup"
 	// MGHOperandImagePrefix ...
 	MGHOperandImagePrefix = "RELATED_IMAGE_"
 	// AnnotationImportClusterInHosted will import a managedhub cluster in hosted mode,
@@ -75,6 +81,12 @@ const (
 	// development environments, where is is convenient to reduce the poll interval. The value should be a string
 	// that can be parsed with the time.ParseDuration function.
 	AnnotationMGHWithStackroxPollInterval = "global-hub.open-cluster-management.io/with-stackrox-poll-interval"
+	// AnnotationMGHStatusResources declares the extra resources collected from the managed hubs as a YAML or JSON
+	// list, e.g. [{"apiVersion": "v1", "kind": "Node", "fields": ["{.status.capacity}"], "interval": "5m"}].
+	AnnotationMGHStatusResources = "global-hub.open-cluster-management.io/status-resources"
+	// AnnotationMGHManagedClusterSyncMode specifies how the agents send the managed clusters: "delta" sends the changed
+	// clusters with a periodic full sync, "complete" sends all the clusters on each change. The default is "delta".
+	AnnotationMGHManagedClusterSyncMode = "global-hub.open-cluster-management.io/managed-cluster-sync-mode"
 )
 
 // hub installation constants
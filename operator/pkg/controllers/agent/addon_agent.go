@@ -136,6 +136,7 @@ func (a *GlobalHubAddonAgent) GetValues(cluster *clusterv1.ManagedCluster,
 		KlusterletWorkSA:          "klusterlet-work-sa",
 		EnableGlobalResource:      a.operatorConfig.GlobalResourceEnabled,
 		TransportFailureThreshold: a.operatorConfig.TransportFailureThreshold,
+		TransportCompressionType:  cluster.GetAnnotations()[constants.TransportCompressionTypeAnnotation],
 		AgentQPS:                  agentQPS,
 		AgentBurst:                agentBurst,
 		LogLevel:                  string(logger.GetLogLevel()),
@@ -157,8 +158,14 @@ func (a *GlobalHubAddonAgent) GetValues(cluster *clusterv1.ManagedCluster,
 	log.Debugw("rendering manifests", "pullSecret", manifestsConfig.ImagePullSecretName,
 		"image", manifestsConfig.HoHAgentImage)
 
+	if err := setStatusResources(&manifestsConfig, mgh); err != nil {
+		log.Errorw("failed to set status resources", "error", err)
+		return nil, err
+	}
+
 	manifestsConfig.AggregationLevel = config.AggregationLevel
 	manifestsConfig.EnableLocalPolicies = config.EnableLocalPolicies
+	manifestsConfig.ManagedClusterSyncMode = config.GetManagedClusterSyncMode(mgh)
 	manifestsConfig.Tolerations = mgh.Spec.Tolerations
 	manifestsConfig.NodeSelector = mgh.Spec.NodeSelector
 
@@ -0,0 +1,88 @@
+package agent
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	globalhubv1alpha4 "github.com/stolostron/multicluster-global-hub/operator/api/operator/v1alpha4"
+	"github.com/stolostron/multicluster-global-hub/operator/pkg/config"
+	"github.com/stolostron/multicluster-global-hub/pkg/utils"
+)
+
+// setStatusResources passes the resources declared in the global hub to the agent config, and grants the agent to list
+// them. The resource of a kind is guessed as the API server does, since the kind might not be served by the global hub.
+// The declarations of the sensitive kinds, or without the projected fields, are dropped, so the agent can neither list
+// nor report them.
+func setStatusResources(manifestsConfig *config.ManifestsConfig, mgh *globalhubv1alpha4.MulticlusterGlobalHub) error {
+	declarations := config.GetStatusResources(mgh)
+	if declarations == nil {
+		return nil
+	}
+
+	items := []json.RawMessage{}
+	if err := json.Unmarshal(declarations, &items); err != nil {
+		return fmt.Errorf("failed to unmarshal the status resources: %w", err)
+	}
+
+	accepted := []json.RawMessage{}
+	groupResources := map[string]map[string]bool{}
+	for _, item := range items {
+		kind := struct {
+			APIVersion string   `json:"apiVersion"`
+			Kind       string   `json:"kind"`
+			Fields     []string `json:"fields"`
+		}{}
+		if err := json.Unmarshal(item, &kind); err != nil {
+			log.Warnw("skip the invalid status resource", "declaration", string(item), "error", err)
+			continue
+		}
+		gv, err := schema.ParseGroupVersion(kind.APIVersion)
+		if err != nil || kind.Kind == "" {
+			log.Warnw("skip the invalid status resource", "apiVersion", kind.APIVersion, "kind", kind.Kind)
+			continue
+		}
+		if utils.IsSensitiveKind(gv.WithKind(kind.Kind).GroupKind()) {
+			log.Warnw("skip the sensitive status resource", "apiVersion", kind.APIVersion, "kind", kind.Kind)
+			continue
+		}
+		if len(kind.Fields) == 0 {
+			log.Warnw("skip the status resource without fields", "apiVersion", kind.APIVersion, "kind", kind.Kind)
+			continue
+		}
+		accepted = append(accepted, item)
+
+		resource, _ := meta.UnsafeGuessKindToResource(gv.WithKind(kind.Kind))
+		if groupResources[gv.Group] == nil {
+			groupResources[gv.Group] = map[string]bool{}
+		}
+		groupResources[gv.Group][resource.Resource] = true
+	}
+
+	for group, resources := range groupResources {
+		rule := config.StatusResourceRule{APIGroup: group}
+		for resource := range resources {
+			rule.Resources = append(rule.Resources, resource)
+		}
+		sort.Strings(rule.Resources)
+		manifestsConfig.StatusResourceRules = append(manifestsConfig.StatusResourceRules, rule)
+	}
+	sort.Slice(manifestsConfig.StatusResourceRules, func(i, j int) bool {
+		return manifestsConfig.StatusResourceRules[i].APIGroup < manifestsConfig.StatusResourceRules[j].APIGroup
+	})
+
+	acceptedDeclarations, err := json.Marshal(accepted)
+	if err != nil {
+		return err
+	}
+	// quote the declarations to be a string value of the config map
+	quoted, err := json.Marshal(string(acceptedDeclarations))
+	if err != nil {
+		return err
+	}
+	manifestsConfig.StatusResources = string(quoted)
+	return nil
+}
@@ -0,0 +1,57 @@
+package agent
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	globalhubv1alpha4 "github.com/stolostron/multicluster-global-hub/operator/api/operator/v1alpha4"
+	"github.com/stolostron/multicluster-global-hub/operator/pkg/config"
+	operatorconstants "github.com/stolostron/multicluster-global-hub/operator/pkg/constants"
+)
+
+func TestSetStatusResources(t *testing.T) {
+	mgh := &globalhubv1alpha4.MulticlusterGlobalHub{
+		ObjectMeta: metav1.ObjectMeta{
+			Annotations: map[string]string{
+				operatorconstants.AnnotationMGHStatusResources: `
+- apiVersion: hive.openshift.io/v1
+  kind: ClusterDeployment
+  fields: ["{.status.powerState}"]
+- apiVersion: v1
+  kind: Node
+  fields: ["{.status.capacity}"]
+- apiVersion: v1
+  kind: Secret
+  fields: ["{.data}"]
+- apiVersion: oauth.openshift.io/v1
+  kind: OAuthAccessToken
+  fields: ["{.userName}"]
+- apiVersion: argoproj.io/v1alpha1
+  kind: Application`,
+			},
+		},
+	}
+
+	manifestsConfig := &config.ManifestsConfig{}
+	require.NoError(t, setStatusResources(manifestsConfig, mgh))
+
+	// the sensitive kinds and the kinds without the projected fields are dropped
+	assert.Equal(t, []config.StatusResourceRule{
+		{APIGroup: "", Resources: []string{"nodes"}},
+		{APIGroup: "hive.openshift.io", Resources: []string{"clusterdeployments"}},
+	}, manifestsConfig.StatusResourceRules)
+
+	var declarations string
+	require.NoError(t, json.Unmarshal([]byte(manifestsConfig.StatusResources), &declarations))
+	kinds := []struct {
+		Kind string `json:"kind"`
+	}{}
+	require.NoError(t, json.Unmarshal([]byte(declarations), &kinds))
+	assert.Len(t, kinds, 2)
+	assert.Equal(t, "ClusterDeployment", kinds[0].Kind)
+	assert.Equal(t, "Node", kinds[1].Kind)
+}
@@ -0,0 +1,382 @@
+package databasebackup
+
+import (
+	"context"
+	"embed"
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"time"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/discovery/cached/memory"
+	"k8s.io/client-go/restmapper"
+	"k8s.io/client-go/util/retry"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	"github.com/stolostron/multicluster-global-hub/operator/api/operator/v1alpha4"
+	"github.com/stolostron/multicluster-global-hub/operator/pkg/config"
+	"github.com/stolostron/multicluster-global-hub/operator/pkg/deployer"
+	"github.com/stolostron/multicluster-global-hub/operator/pkg/renderer"
+	"github.com/stolostron/multicluster-global-hub/operator/pkg/utils"
+	"github.com/stolostron/multicluster-global-hub/pkg/logger"
+)
+
+// +kubebuilder:rbac:groups=operator.open-cluster-management.io,resources=multiclusterglobalhubs,verbs=get;list;watch;
+// +kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch;create;update;delete;patch
+// +kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch;create;update;delete
+// +kubebuilder:rbac:groups="",resources=pods,verbs=get;list;watch
+// +kubebuilder:rbac:groups="batch",resources=cronjobs;jobs,verbs=get;list;watch;create;update;delete;deletecollection
+
+//go:embed manifests
+var fs embed.FS
+
+// BackupName is the name of the backup cronjob, and its secret and scripts
+const BackupName = "multicluster-global-hub-database-backup"
+
+// the schemas of the global hub data, the "spec" schema only exists when the global resource is enabled
+var backupSchemas = []string{"spec", "status", "local_spec", "local_status", "event", "history", "security"}
+
+var (
+	log              = logger.DefaultZapLogger()
+	backupReconciler *DatabaseBackupReconciler
+)
+
+// DatabaseBackupReconciler runs the logical backups of the global hub database by a cronjob, and the on-demand
+// backups and the restores by the jobs created from the cronjob template. The progress of the jobs is reported by the
+// "DatabaseBackup" and "DatabaseRestore" conditions of the MulticlusterGlobalHub.
+type DatabaseBackupReconciler struct {
+	ctrl.Manager
+}
+
+func (r *DatabaseBackupReconciler) IsResourceRemoved() bool {
+	return true
+}
+
+func StartController(initOption config.ControllerOption) (config.ControllerInterface, error) {
+	if backupReconciler != nil {
+		return backupReconciler, nil
+	}
+	if initOption.MulticlusterGlobalHub.Spec.DataLayerSpec.Postgres.Backup == nil {
+		return nil, nil
+	}
+	log.Info("start database backup controller")
+
+	backupReconciler = &DatabaseBackupReconciler{Manager: initOption.Manager}
+	err := backupReconciler.SetupWithManager(initOption.Manager)
+	if err != nil {
+		backupReconciler = nil
+		return nil, err
+	}
+	log.Infof("inited database backup controller")
+	return backupReconciler, nil
+}
+
+func (r *DatabaseBackupReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).Named("databaseBackupController").
+		For(&v1alpha4.MulticlusterGlobalHub{},
+			builder.WithPredicates(config.MGHPred)).
+		Watches(&batchv1.CronJob{},
+			&handler.EnqueueRequestForObject{}, builder.WithPredicates(config.GeneralPredicate)).
+		Watches(&batchv1.Job{},
+			&handler.EnqueueRequestForObject{}, builder.WithPredicates(jobPred)).
+		Complete(r)
+}
+
+// the backup and restore jobs trigger the reconcile when they're created, and their status is changed
+var jobPred = predicate.Funcs{
+	CreateFunc: func(e event.CreateEvent) bool {
+		return e.Object.GetLabels()[JobTypeLabelKey] != ""
+	},
+	UpdateFunc: func(e event.UpdateEvent) bool {
+		return e.ObjectNew.GetLabels()[JobTypeLabelKey] != ""
+	},
+	DeleteFunc: func(e event.DeleteEvent) bool {
+		return e.Object.GetLabels()[JobTypeLabelKey] != ""
+	},
+}
+
+func (r *DatabaseBackupReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log.Debug("reconcile database backup controller")
+	mgh, err := config.GetMulticlusterGlobalHub(ctx, r.GetClient())
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if mgh == nil || config.IsPaused(mgh) || mgh.DeletionTimestamp != nil {
+		return ctrl.Result{}, nil
+	}
+
+	backup := mgh.Spec.DataLayerSpec.Postgres.Backup
+	if backup == nil {
+		return ctrl.Result{}, r.pruneResources(ctx, mgh.Namespace)
+	}
+	if backup.PersistentVolumeClaim == "" && backup.S3 == nil {
+		return ctrl.Result{}, r.updateCondition(ctx, mgh, metav1.Condition{
+			Type:    config.CONDITION_TYPE_DATABASE_BACKUP,
+			Status:  config.CONDITION_STATUS_FALSE,
+			Reason:  config.CONDITION_REASON_BACKUP_INVALID,
+			Message: "either the persistentVolumeClaim or the s3 is required to store the backups",
+		})
+	}
+
+	// the restore reloads the data after the schemas are rebuilt
+	storageConn := config.GetStorageConnection()
+	if storageConn == nil || !config.GetDatabaseReady() {
+		log.Debug("wait the database to be ready for the backup")
+		return ctrl.Result{RequeueAfter: 10 * time.Second}, nil
+	}
+
+	if err := r.deployBackup(ctx, mgh, backup, storageConn); err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to deploy the database backup: %w", err)
+	}
+	cronJob := &batchv1.CronJob{}
+	err = r.GetClient().Get(ctx, types.NamespacedName{Namespace: mgh.Namespace, Name: BackupName}, cronJob)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if request := config.GetDatabaseBackupRequest(mgh); request != "" {
+		if err := r.ensureJob(ctx, mgh, newBackupJob(cronJob, request)); err != nil {
+			return ctrl.Result{}, fmt.Errorf("failed to run the backup on demand: %w", err)
+		}
+	}
+	if err := r.updateBackupCondition(ctx, mgh, backup); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	restore := config.GetPendingDatabaseRestore(mgh)
+	if restore == "" {
+		return ctrl.Result{}, nil
+	}
+	result, err := r.restore(ctx, mgh, newRestoreJob(cronJob, restore))
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to restore the backup %s: %w", restore, err)
+	}
+	return result, nil
+}
+
+func (r *DatabaseBackupReconciler) deployBackup(ctx context.Context, mgh *v1alpha4.MulticlusterGlobalHub,
+	backup *v1alpha4.DatabaseBackupSpec, storageConn *config.PostgresConnection,
+) error {
+	hohRenderer, hohDeployer := renderer.NewHoHRenderer(fs), deployer.NewHoHDeployer(r.GetClient())
+
+	dc, err := discovery.NewDiscoveryClientForConfig(r.Manager.GetConfig())
+	if err != nil {
+		return err
+	}
+	mapper := restmapper.NewDeferredDiscoveryRESTMapper(memory.NewMemCacheClient(dc))
+
+	imagePullPolicy := corev1.PullAlways
+	if mgh.Spec.ImagePullPolicy != "" {
+		imagePullPolicy = mgh.Spec.ImagePullPolicy
+	}
+
+	backupObjects, err := hohRenderer.Render("manifests", "", func(profile string) (interface{}, error) {
+		return struct {
+			Image                 string
+			ImagePullSecret       string
+			ImagePullPolicy       string
+			Namespace             string
+			NodeSelector          map[string]string
+			Tolerations           []corev1.Toleration
+			DatabaseURI           string
+			CACert                string
+			Schedule              string
+			Retention             int32
+			Schemas               string
+			PersistentVolumeClaim string
+			S3                    *v1alpha4.S3ArchiveSpec
+		}{
+			Image:                 config.GetImage(config.PostgresImageKey),
+			ImagePullSecret:       mgh.Spec.ImagePullSecret,
+			ImagePullPolicy:       string(imagePullPolicy),
+			Namespace:             mgh.Namespace,
+			NodeSelector:          mgh.Spec.NodeSelector,
+			Tolerations:           mgh.Spec.Tolerations,
+			DatabaseURI:           base64.StdEncoding.EncodeToString([]byte(storageConn.SuperuserDatabaseURI)),
+			CACert:                base64.StdEncoding.EncodeToString(storageConn.CACert),
+			Schedule:              backup.Schedule,
+			Retention:             backup.Retention,
+			Schemas:               strings.Join(backupSchemas, " "),
+			PersistentVolumeClaim: backup.PersistentVolumeClaim,
+			S3:                    backup.S3,
+		}, nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to render the database backup objects: %v", err)
+	}
+	return utils.ManipulateGlobalHubObjects(backupObjects, mgh, hohDeployer, mapper, r.GetScheme())
+}
+
+// ensureJob creates the job if it doesn't exist, the job runs only once and it's kept for the condition
+func (r *DatabaseBackupReconciler) ensureJob(ctx context.Context, mgh *v1alpha4.MulticlusterGlobalHub,
+	job *batchv1.Job,
+) error {
+	existing := &batchv1.Job{}
+	err := r.GetClient().Get(ctx, client.ObjectKeyFromObject(job), existing)
+	if err == nil || !errors.IsNotFound(err) {
+		return err
+	}
+	if err := controllerutil.SetControllerReference(mgh, job, r.GetScheme()); err != nil {
+		return err
+	}
+	log.Infow("create the database job", "name", job.Name, "type", job.Labels[JobTypeLabelKey])
+	return r.GetClient().Create(ctx, job)
+}
+
+// restore runs the restore job once the manager is stopped, and records the finished restore in the status, so that
+// the backup isn't restored again. The finished job of the previous restore is removed to restore the backup again,
+// that's the restore failed, or another backup is restored after it.
+func (r *DatabaseBackupReconciler) restore(ctx context.Context, mgh *v1alpha4.MulticlusterGlobalHub,
+	job *batchv1.Job,
+) (ctrl.Result, error) {
+	backup := job.Annotations[RestoreBackupAnnotationKey]
+	existing := &batchv1.Job{}
+	err := r.GetClient().Get(ctx, client.ObjectKeyFromObject(job), existing)
+	if err != nil && !errors.IsNotFound(err) {
+		return ctrl.Result{}, err
+	}
+	if err == nil {
+		restored := mgh.Status.DatabaseRestore
+		if jobState(existing) != jobRunning && restored != nil &&
+			(restored.Backup == backup || finishTime(existing).Before(restored.CompletionTime)) {
+			log.Infow("remove the previous database restore job", "name", existing.Name, "backup", backup)
+			return ctrl.Result{}, r.GetClient().Delete(ctx, existing,
+				client.PropagationPolicy(metav1.DeletePropagationBackground))
+		}
+		message, err := r.terminationMessage(ctx, existing)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if err := r.updateCondition(ctx, mgh, restoreCondition(existing, message)); err != nil {
+			return ctrl.Result{}, err
+		}
+		if jobState(existing) == jobRunning {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, r.recordRestore(ctx, mgh, existing)
+	}
+
+	// the manager is scaled down by the manager reconciler while the restore is pending, the restore waits until all
+	// the manager pods are gone, so that the restored data isn't overwritten
+	pods := &corev1.PodList{}
+	err = r.GetClient().List(ctx, pods, client.InNamespace(mgh.Namespace),
+		client.MatchingLabels{"name": config.COMPONENTS_MANAGER_NAME})
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if len(pods.Items) > 0 {
+		return ctrl.Result{RequeueAfter: 10 * time.Second}, r.updateCondition(ctx, mgh, metav1.Condition{
+			Type:   config.CONDITION_TYPE_DATABASE_RESTORE,
+			Status: config.CONDITION_STATUS_UNKNOWN,
+			Reason: config.CONDITION_REASON_RESTORE_PENDING,
+			Message: fmt.Sprintf("The backup %s is restored once the %d manager pods are stopped", backup,
+				len(pods.Items)),
+		})
+	}
+	if err := r.ensureJob(ctx, mgh, job); err != nil {
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{}, r.updateCondition(ctx, mgh, restoreCondition(job, ""))
+}
+
+// recordRestore records the finished restore job in the status, the manager is started again once it's recorded
+func (r *DatabaseBackupReconciler) recordRestore(ctx context.Context, mgh *v1alpha4.MulticlusterGlobalHub,
+	job *batchv1.Job,
+) error {
+	return retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+		current := &v1alpha4.MulticlusterGlobalHub{}
+		if err := r.GetClient().Get(ctx, client.ObjectKeyFromObject(mgh), current); err != nil {
+			return err
+		}
+		current.Status.DatabaseRestore = &v1alpha4.DatabaseRestoreStatus{
+			Backup:             job.Annotations[RestoreBackupAnnotationKey],
+			ObservedGeneration: current.Generation,
+			Failed:             jobState(job) == jobFailed,
+			CompletionTime:     finishTime(job),
+		}
+		log.Infow("the database restore is finished", "backup", current.Status.DatabaseRestore.Backup,
+			"failed", current.Status.DatabaseRestore.Failed)
+		return r.GetClient().Status().Update(ctx, current)
+	})
+}
+
+func (r *DatabaseBackupReconciler) updateBackupCondition(ctx context.Context, mgh *v1alpha4.MulticlusterGlobalHub,
+	backup *v1alpha4.DatabaseBackupSpec,
+) error {
+	backupJobs := &batchv1.JobList{}
+	err := r.GetClient().List(ctx, backupJobs, client.InNamespace(mgh.Namespace),
+		client.MatchingLabels{JobTypeLabelKey: jobTypeBackup})
+	if err != nil {
+		return err
+	}
+	latest := latestJob(backupJobs.Items)
+	message, err := r.terminationMessage(ctx, latest)
+	if err != nil {
+		return err
+	}
+	return r.updateCondition(ctx, mgh, backupCondition(latest, backup.Schedule, message))
+}
+
+// terminationMessage returns the message of the finished job, it's the backup name when the job succeeds, or the
+// tail of the logs when it fails
+func (r *DatabaseBackupReconciler) terminationMessage(ctx context.Context, job *batchv1.Job) (string, error) {
+	if job == nil || jobState(job) == jobRunning {
+		return "", nil
+	}
+	pods := &corev1.PodList{}
+	err := r.GetClient().List(ctx, pods, client.InNamespace(job.Namespace),
+		client.MatchingLabels{"job-name": job.Name})
+	if err != nil {
+		return "", err
+	}
+	var message string
+	var finishedAt time.Time
+	for _, pod := range pods.Items {
+		for _, status := range pod.Status.ContainerStatuses {
+			terminated := status.State.Terminated
+			if terminated != nil && !terminated.FinishedAt.Time.Before(finishedAt) {
+				message, finishedAt = strings.TrimSpace(terminated.Message), terminated.FinishedAt.Time
+			}
+		}
+	}
+	return message, nil
+}
+
+func (r *DatabaseBackupReconciler) updateCondition(ctx context.Context, mgh *v1alpha4.MulticlusterGlobalHub,
+	cond metav1.Condition,
+) error {
+	return config.UpdateCondition(ctx, r.GetClient(), types.NamespacedName{
+		Namespace: mgh.Namespace,
+		Name:      mgh.Name,
+	}, cond, "")
+}
+
+// pruneResources removes the backup cronjob and the jobs when the backup is disabled, the backups are kept. The
+// restored backup is recorded in the status, so it isn't restored again once the backup is enabled again
+func (r *DatabaseBackupReconciler) pruneResources(ctx context.Context, namespace string) error {
+	objects := []client.Object{
+		&batchv1.CronJob{ObjectMeta: metav1.ObjectMeta{Name: BackupName, Namespace: namespace}},
+		&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: BackupName, Namespace: namespace}},
+		&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: BackupName, Namespace: namespace}},
+	}
+	for _, obj := range objects {
+		err := r.GetClient().Delete(ctx, obj, client.PropagationPolicy(metav1.DeletePropagationBackground))
+		if err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+	}
+	return r.GetClient().DeleteAllOf(ctx, &batchv1.Job{}, client.InNamespace(namespace),
+		client.HasLabels{JobTypeLabelKey}, client.PropagationPolicy(metav1.DeletePropagationBackground))
+}
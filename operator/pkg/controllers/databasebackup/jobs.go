@@ -0,0 +1,182 @@
+package databasebackup
+
+import (
+	"fmt"
+	"hash/fnv"
+	"time"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/stolostron/multicluster-global-hub/operator/pkg/config"
+)
+
+const (
+	// JobTypeLabelKey marks the jobs of the database, the value is "backup" or "restore"
+	JobTypeLabelKey = "global-hub.open-cluster-management.io/database-job"
+	// BackupRequestAnnotationKey is the request of the on-demand backup job
+	BackupRequestAnnotationKey = "global-hub.open-cluster-management.io/backup-request"
+	// RestoreBackupAnnotationKey is the backup of the restore job
+	RestoreBackupAnnotationKey = "global-hub.open-cluster-management.io/restore-backup"
+
+	jobTypeBackup  = "backup"
+	jobTypeRestore = "restore"
+
+	restoreJobPrefix = "multicluster-global-hub-database-restore"
+)
+
+type jobStateType string
+
+const (
+	jobRunning   jobStateType = "Running"
+	jobSucceeded jobStateType = "Succeeded"
+	jobFailed    jobStateType = "Failed"
+)
+
+// newBackupJob creates the job from the template of the backup cronjob, each request runs the backup once
+func newBackupJob(cronJob *batchv1.CronJob, request string) *batchv1.Job {
+	job := newJob(cronJob, fmt.Sprintf("%s-%s", BackupName, hash(request)))
+	job.Annotations[BackupRequestAnnotationKey] = request
+	return job
+}
+
+// newRestoreJob creates the job from the template of the backup cronjob to restore the backup, so that it runs with
+// the same image, database and storage of the backups
+func newRestoreJob(cronJob *batchv1.CronJob, backup string) *batchv1.Job {
+	job := newJob(cronJob, fmt.Sprintf("%s-%s", restoreJobPrefix, hash(backup)))
+	job.Labels[JobTypeLabelKey] = jobTypeRestore
+	job.Labels["name"] = restoreJobPrefix
+	job.Annotations[RestoreBackupAnnotationKey] = backup
+	job.Spec.Template.Labels["name"] = restoreJobPrefix
+
+	container := &job.Spec.Template.Spec.Containers[0]
+	container.Name = "database-restore"
+	container.Command = []string{"/bin/bash", "/scripts/restore.sh"}
+	container.Env = append(container.Env, corev1.EnvVar{Name: "BACKUP_NAME", Value: backup})
+	return job
+}
+
+func newJob(cronJob *batchv1.CronJob, name string) *batchv1.Job {
+	template := cronJob.Spec.JobTemplate.DeepCopy()
+	job := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Namespace:   cronJob.Namespace,
+			Labels:      template.Labels,
+			Annotations: template.Annotations,
+		},
+		Spec: template.Spec,
+	}
+	if job.Labels == nil {
+		job.Labels = map[string]string{}
+	}
+	if job.Annotations == nil {
+		job.Annotations = map[string]string{}
+	}
+	if job.Spec.Template.Labels == nil {
+		job.Spec.Template.Labels = map[string]string{}
+	}
+	return job
+}
+
+func hash(value string) string {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(value))
+	return fmt.Sprintf("%08x", h.Sum32())
+}
+
+func jobState(job *batchv1.Job) jobStateType {
+	for _, cond := range job.Status.Conditions {
+		if cond.Status != corev1.ConditionTrue {
+			continue
+		}
+		switch cond.Type {
+		case batchv1.JobComplete:
+			return jobSucceeded
+		case batchv1.JobFailed:
+			return jobFailed
+		}
+	}
+	return jobRunning
+}
+
+func latestJob(jobs []batchv1.Job) *batchv1.Job {
+	var latest *batchv1.Job
+	for i := range jobs {
+		if latest == nil || latest.CreationTimestamp.Before(&jobs[i].CreationTimestamp) {
+			latest = &jobs[i]
+		}
+	}
+	return latest
+}
+
+// finishTime returns the time when the job succeeded or failed, or nil if it's running
+func finishTime(job *batchv1.Job) *metav1.Time {
+	if job.Status.CompletionTime != nil {
+		return job.Status.CompletionTime
+	}
+	for _, cond := range job.Status.Conditions {
+		if cond.Type == batchv1.JobFailed {
+			return &cond.LastTransitionTime
+		}
+	}
+	return nil
+}
+
+func completionTime(job *batchv1.Job) string {
+	if finished := finishTime(job); finished != nil {
+		return finished.UTC().Format(time.RFC3339)
+	}
+	return ""
+}
+
+// backupCondition reports the latest backup job, the message is the termination message of the finished job
+func backupCondition(job *batchv1.Job, schedule, message string) metav1.Condition {
+	cond := metav1.Condition{Type: config.CONDITION_TYPE_DATABASE_BACKUP}
+	if job == nil {
+		cond.Status = config.CONDITION_STATUS_TRUE
+		cond.Reason = config.CONDITION_REASON_BACKUP_SCHEDULED
+		cond.Message = fmt.Sprintf("The database is backed up on the schedule %q", schedule)
+		return cond
+	}
+
+	switch jobState(job) {
+	case jobSucceeded:
+		cond.Status = config.CONDITION_STATUS_TRUE
+		cond.Reason = config.CONDITION_REASON_BACKUP_SUCCEEDED
+		cond.Message = fmt.Sprintf("The backup %s is completed by the job %s at %s", message, job.Name,
+			completionTime(job))
+	case jobFailed:
+		cond.Status = config.CONDITION_STATUS_FALSE
+		cond.Reason = config.CONDITION_REASON_BACKUP_FAILED
+		cond.Message = fmt.Sprintf("The backup job %s failed at %s: %s", job.Name, completionTime(job), message)
+	default:
+		cond.Status = config.CONDITION_STATUS_UNKNOWN
+		cond.Reason = config.CONDITION_REASON_BACKUP_RUNNING
+		cond.Message = fmt.Sprintf("The backup job %s is running", job.Name)
+	}
+	return cond
+}
+
+// restoreCondition reports the restore job of the backup
+func restoreCondition(job *batchv1.Job, message string) metav1.Condition {
+	backup := job.Annotations[RestoreBackupAnnotationKey]
+	cond := metav1.Condition{Type: config.CONDITION_TYPE_DATABASE_RESTORE}
+	switch jobState(job) {
+	case jobSucceeded:
+		cond.Status = config.CONDITION_STATUS_TRUE
+		cond.Reason = config.CONDITION_REASON_RESTORE_SUCCEEDED
+		cond.Message = fmt.Sprintf("The backup %s is restored by the job %s at %s", backup, job.Name,
+			completionTime(job))
+	case jobFailed:
+		cond.Status = config.CONDITION_STATUS_FALSE
+		cond.Reason = config.CONDITION_REASON_RESTORE_FAILED
+		cond.Message = fmt.Sprintf("Failed to restore the backup %s by the job %s: %s", backup, job.Name, message)
+	default:
+		cond.Status = config.CONDITION_STATUS_UNKNOWN
+		cond.Reason = config.CONDITION_REASON_RESTORE_RUNNING
+		cond.Message = fmt.Sprintf("The backup %s is being restored by the job %s", backup, job.Name)
+	}
+	return cond
+}
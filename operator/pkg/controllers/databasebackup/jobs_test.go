@@ -0,0 +1,151 @@
+package databasebackup
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/stolostron/multicluster-global-hub/operator/api/operator/v1alpha4"
+	"github.com/stolostron/multicluster-global-hub/operator/pkg/config"
+	"github.com/stolostron/multicluster-global-hub/operator/pkg/renderer"
+)
+
+func renderCronJob(t *testing.T, pvc string, s3 *v1alpha4.S3ArchiveSpec) *batchv1.CronJob {
+	objects, err := renderer.NewHoHRenderer(fs).Render("manifests", "", func(profile string) (interface{}, error) {
+		return map[string]interface{}{
+			"Image":                 "quay.io/stolostron/postgresql-16:latest",
+			"ImagePullPolicy":       "Always",
+			"Namespace":             "multicluster-global-hub",
+			"DatabaseURI":           "cG9zdGdyZXM6Ly8=",
+			"CACert":                "",
+			"Schedule":              "0 0 * * *",
+			"Retention":             int32(7),
+			"Schemas":               "status event",
+			"PersistentVolumeClaim": pvc,
+			"S3":                    s3,
+		}, nil
+	})
+	require.NoError(t, err)
+
+	for _, obj := range objects {
+		if obj.GetKind() != "CronJob" {
+			continue
+		}
+		cronJob := &batchv1.CronJob{}
+		require.NoError(t, runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, cronJob))
+		return cronJob
+	}
+	t.Fatal("the backup cronjob isn't rendered")
+	return nil
+}
+
+func env(container corev1.Container, name string) (string, bool) {
+	for _, e := range container.Env {
+		if e.Name == name {
+			return e.Value, true
+		}
+	}
+	return "", false
+}
+
+func TestBackupCronJob(t *testing.T) {
+	cronJob := renderCronJob(t, "backup-pvc", nil)
+	assert.Equal(t, "0 0 * * *", cronJob.Spec.Schedule)
+	assert.Equal(t, jobTypeBackup, cronJob.Spec.JobTemplate.Labels[JobTypeLabelKey])
+
+	podSpec := cronJob.Spec.JobTemplate.Spec.Template.Spec
+	assert.Equal(t, "backup-pvc", podSpec.Volumes[len(podSpec.Volumes)-1].PersistentVolumeClaim.ClaimName)
+	_, ok := env(podSpec.Containers[0], "S3_BUCKET")
+	assert.False(t, ok)
+	_, ok = env(podSpec.Containers[0], "PGSSLROOTCERT")
+	assert.False(t, ok, "the CA certificate isn't provided")
+
+	cronJob = renderCronJob(t, "", &v1alpha4.S3ArchiveSpec{
+		Endpoint:         "minio:9000",
+		Bucket:           "backups",
+		Insecure:         true,
+		CredentialSecret: "backup-credential",
+	})
+	container := cronJob.Spec.JobTemplate.Spec.Template.Spec.Containers[0]
+	scheme, _ := env(container, "S3_SCHEME")
+	assert.Equal(t, "http", scheme)
+	bucket, _ := env(container, "S3_BUCKET")
+	assert.Equal(t, "backups", bucket)
+}
+
+func TestNewJobs(t *testing.T) {
+	cronJob := renderCronJob(t, "backup-pvc", nil)
+
+	backupJob := newBackupJob(cronJob, "2025-01-01")
+	assert.Equal(t, backupJob.Name, newBackupJob(cronJob, "2025-01-01").Name, "the request runs only once")
+	assert.NotEqual(t, backupJob.Name, newBackupJob(cronJob, "2025-01-02").Name)
+	assert.Equal(t, jobTypeBackup, backupJob.Labels[JobTypeLabelKey])
+	assert.Equal(t, []string{"/bin/bash", "/scripts/backup.sh"}, backupJob.Spec.Template.Spec.Containers[0].Command)
+
+	restoreJob := newRestoreJob(cronJob, "globalhub-20250101000000.sql.gz")
+	assert.LessOrEqual(t, len(restoreJob.Name), 63)
+	assert.Equal(t, jobTypeRestore, restoreJob.Labels[JobTypeLabelKey])
+	assert.Equal(t, "globalhub-20250101000000.sql.gz", restoreJob.Annotations[RestoreBackupAnnotationKey])
+	container := restoreJob.Spec.Template.Spec.Containers[0]
+	assert.Equal(t, []string{"/bin/bash", "/scripts/restore.sh"}, container.Command)
+	backup, _ := env(container, "BACKUP_NAME")
+	assert.Equal(t, "globalhub-20250101000000.sql.gz", backup)
+
+	// the template of the cronjob isn't changed
+	assert.Equal(t, []string{"/bin/bash", "/scripts/backup.sh"},
+		cronJob.Spec.JobTemplate.Spec.Template.Spec.Containers[0].Command)
+}
+
+func TestJobConditions(t *testing.T) {
+	cond := backupCondition(nil, "0 0 * * *", "")
+	assert.Equal(t, config.CONDITION_REASON_BACKUP_SCHEDULED, cond.Reason)
+
+	now := metav1.NewTime(time.Date(2025, 1, 1, 0, 1, 0, 0, time.UTC))
+	older := &batchv1.Job{ObjectMeta: metav1.ObjectMeta{
+		Name:              "backup-1",
+		CreationTimestamp: metav1.NewTime(now.Add(-time.Hour)),
+	}}
+	latest := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{Name: "backup-2", CreationTimestamp: now},
+		Status: batchv1.JobStatus{
+			CompletionTime: &now,
+			Conditions: []batchv1.JobCondition{
+				{Type: batchv1.JobComplete, Status: corev1.ConditionTrue},
+			},
+		},
+	}
+	job := latestJob([]batchv1.Job{*older, *latest})
+	require.NotNil(t, job)
+	assert.Equal(t, "backup-2", job.Name)
+
+	cond = backupCondition(job, "0 0 * * *", "globalhub-20250101000000.sql.gz")
+	assert.Equal(t, metav1.ConditionStatus(config.CONDITION_STATUS_TRUE), cond.Status)
+	assert.Equal(t, config.CONDITION_REASON_BACKUP_SUCCEEDED, cond.Reason)
+	assert.Contains(t, cond.Message, "globalhub-20250101000000.sql.gz")
+
+	cond = backupCondition(older, "0 0 * * *", "")
+	assert.Equal(t, metav1.ConditionStatus(config.CONDITION_STATUS_UNKNOWN), cond.Status)
+	assert.Equal(t, config.CONDITION_REASON_BACKUP_RUNNING, cond.Reason)
+
+	restoreJob := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "restore",
+			Annotations: map[string]string{RestoreBackupAnnotationKey: "globalhub-20250101000000.sql.gz"},
+		},
+		Status: batchv1.JobStatus{
+			Conditions: []batchv1.JobCondition{
+				{Type: batchv1.JobFailed, Status: corev1.ConditionTrue, LastTransitionTime: now},
+			},
+		},
+	}
+	cond = restoreCondition(restoreJob, "the backup globalhub-20250101000000.sql.gz isn't found")
+	assert.Equal(t, metav1.ConditionStatus(config.CONDITION_STATUS_FALSE), cond.Status)
+	assert.Equal(t, config.CONDITION_REASON_RESTORE_FAILED, cond.Reason)
+	assert.Contains(t, cond.Message, "isn't found")
+}
@@ -0,0 +1,86 @@
+package databasebackup
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/stolostron/multicluster-global-hub/operator/pkg/renderer"
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	"github.com/stolostron/multicluster-global-hub/test/integration/utils/testpostgres"
+)
+
+// partitionHeader is the header line of the backup which restore.sh turns into the partition creation
+var partitionHeader = regexp.MustCompile(`^-- global hub partition: ([a-z_.]*) ([0-9-]*)$`)
+
+func renderScripts(t *testing.T) map[string]string {
+	objects, err := renderer.NewHoHRenderer(fs).Render("manifests", "", func(profile string) (interface{}, error) {
+		return map[string]interface{}{"Namespace": "multicluster-global-hub"}, nil
+	})
+	require.NoError(t, err)
+
+	for _, obj := range objects {
+		if obj.GetKind() != "ConfigMap" {
+			continue
+		}
+		cm := &corev1.ConfigMap{}
+		require.NoError(t, runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, cm))
+		return cm.Data
+	}
+	t.Fatal("the backup scripts aren't rendered")
+	return nil
+}
+
+func TestRestoreOlderPartitions(t *testing.T) {
+	testPostgres, err := testpostgres.NewTestPostgres()
+	require.NoError(t, err)
+	defer func() {
+		_ = testPostgres.Stop()
+	}()
+	require.NoError(t, testpostgres.InitDatabase(testPostgres.URI))
+	db := database.GetGorm()
+
+	// the backup has the events older than two months
+	createdAt := time.Now().UTC().AddDate(0, -3, 0)
+	month := time.Date(createdAt.Year(), createdAt.Month(), 1, 0, 0, 0, 0, time.UTC).Format(time.DateOnly)
+	require.NoError(t, db.Exec("SELECT create_monthly_range_partitioned_table('event.managed_clusters', ?)",
+		month).Error)
+	insert := `INSERT INTO event.managed_clusters (event_namespace, event_name, cluster_name, cluster_id,
+		leaf_hub_name, event_type, created_at) VALUES ('cluster1', 'event1', 'cluster1', gen_random_uuid(), 'hub1',
+		'Normal', ?)`
+	require.NoError(t, db.Exec(insert, createdAt).Error)
+
+	// the backup records the partitions of the schemas
+	query := strings.ReplaceAll(renderScripts(t)["partitions.sql"], ":'schemas'", "'status,event,history'")
+	headers := []string{}
+	require.NoError(t, db.Raw(query).Scan(&headers).Error)
+	assert.Contains(t, headers, "-- global hub partition: event.managed_clusters "+month)
+
+	// the fresh database only has the partitions of the current and previous month
+	require.NoError(t, db.Exec("SELECT delete_monthly_range_partitioned_table('event.managed_clusters', ?)",
+		month).Error)
+	err = db.Exec(insert, createdAt).Error
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "no partition of relation")
+
+	// the restore creates the partitions of the backup before loading the data
+	tx := db.Begin()
+	defer tx.Rollback()
+	for _, header := range headers {
+		matches := partitionHeader.FindStringSubmatch(header)
+		require.Len(t, matches, 3, header)
+		require.NoError(t, tx.Exec("SELECT create_monthly_range_partitioned_table(?, ?)",
+			matches[1], matches[2]).Error)
+	}
+	require.NoError(t, tx.Exec(insert, createdAt).Error)
+	var count int64
+	require.NoError(t, tx.Raw("SELECT count(*) FROM event.managed_clusters WHERE created_at < ?",
+		time.Now().UTC().AddDate(0, -2, 0)).Scan(&count).Error)
+	assert.Equal(t, int64(1), count)
+}
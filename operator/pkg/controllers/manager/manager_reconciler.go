@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"reflect"
 	"strconv"
+	"strings"
 	"time"
 
 	routev1 "github.com/openshift/api/route/v1"
@@ -65,6 +66,7 @@ import (
 // +kubebuilder:rbac:groups=cluster.open-cluster-management.io,resources=managedclustersets,verbs=get;list;patch;update
 // +kubebuilder:rbac:groups="authentication.open-cluster-management.io",resources=managedserviceaccounts,verbs=get;list;watch;create;update;delete
 // +kubebuilder:rbac:groups="global-hub.open-cluster-management.io",resources=managedclustermigrations,verbs=get;list;watch;update
+// +kubebuilder:rbac:groups="global-hub.open-cluster-management.io",resources=managedclustermigrations/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=policy.open-cluster-management.io,resources=placementbindings,verbs=get;list;patch;update
 // +kubebuilder:rbac:groups="",resources=namespaces,verbs=get;list;watch;update
 
@@ -239,11 +241,20 @@ func (r *ManagerReconciler) Reconcile(ctx context.Context,
 	if months < 1 {
 		months = 1
 	}
+	tableRetentions, err := getTableRetentions(mgh.Spec.DataLayerSpec.Postgres.TableRetentions)
+	if err != nil {
+		reconcileErr = fmt.Errorf("failed to parse table retention: %v", err)
+		return ctrl.Result{}, reconcileErr
+	}
 
 	replicas := int32(1)
 	if mgh.Spec.AvailabilityConfig == v1alpha4.HAHigh {
 		replicas = 2
 	}
+	// the manager is stopped while the database backup is restored, so that it doesn't write to the database
+	if config.GetPendingDatabaseRestore(mgh) != "" {
+		replicas = 0
+	}
 
 	transportConn := config.GetTransporterConn()
 	if transportConn == nil || transportConn.BootstrapServer == "" {
@@ -296,11 +307,14 @@ func (r *ManagerReconciler) Reconcile(ctx context.Context,
 			RenewDeadline:             strconv.Itoa(electionConfig.RenewDeadline),
 			RetryPeriod:               strconv.Itoa(electionConfig.RetryPeriod),
 			SchedulerInterval:         config.GetSchedulerInterval(mgh),
+			HubGracePeriod:            config.GetHubGracePeriod(mgh),
 			SkipAuth:                  config.SkipAuth(mgh),
 			LaunchJobNames:            config.GetLaunchJobNames(mgh),
 			NodeSelector:              mgh.Spec.NodeSelector,
 			Tolerations:               mgh.Spec.Tolerations,
 			RetentionMonth:            months,
+			TableRetentions:           tableRetentions,
+			Archive:                   mgh.Spec.DataLayerSpec.Postgres.Archive,
 			StatisticLogInterval:      config.GetStatisticLogInterval(),
 			EnableGlobalResource:      r.operatorConfig.GlobalResourceEnabled,
 			ImportClusterInHosted:     config.GetImportClusterInHosted(),
@@ -454,11 +468,14 @@ type ManagerVariables struct {
 	RenewDeadline             string
 	RetryPeriod               string
 	SchedulerInterval         string
+	HubGracePeriod            string
 	SkipAuth                  bool
 	LaunchJobNames            string
 	NodeSelector              map[string]string
 	Tolerations               []corev1.Toleration
 	RetentionMonth            int
+	TableRetentions           string
+	Archive                   *v1alpha4.ArchiveSpec
 	StatisticLogInterval      string
 	EnableGlobalResource      bool
 	ImportClusterInHosted     bool
@@ -467,3 +484,21 @@ type ManagerVariables struct {
 	WithACM                   bool
 	TransportFailureThreshold int
 }
+
+// getTableRetentions returns the retention months of the tables in the format of the manager flag, e.g.
+// "event.managed_clusters=3,history.local_compliance=24"
+func getTableRetentions(retentions []v1alpha4.TableRetention) (string, error) {
+	tables := make([]string, 0, len(retentions))
+	for _, retention := range retentions {
+		months, err := commonutils.ParseRetentionMonth(retention.Retention)
+		if err != nil {
+			return "", fmt.Errorf("invalid retention of the table %s: %v", retention.Table, err)
+		}
+		// same as the data retention, keep at least the current month
+		if months < 1 {
+			months = 1
+		}
+		tables = append(tables, fmt.Sprintf("%s=%d", retention.Table, months))
+	}
+	return strings.Join(tables, ","), nil
+}
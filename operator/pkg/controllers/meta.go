@@ -41,6 +41,7 @@ import (
 	"github.com/stolostron/multicluster-global-hub/operator/pkg/controllers/acm"
 	"github.com/stolostron/multicluster-global-hub/operator/pkg/controllers/agent"
 	"github.com/stolostron/multicluster-global-hub/operator/pkg/controllers/backup"
+	"github.com/stolostron/multicluster-global-hub/operator/pkg/controllers/databasebackup"
 	"github.com/stolostron/multicluster-global-hub/operator/pkg/controllers/grafana"
 	"github.com/stolostron/multicluster-global-hub/operator/pkg/controllers/inventory"
 	"github.com/stolostron/multicluster-global-hub/operator/pkg/controllers/managedhub"
@@ -70,6 +71,7 @@ var controllerStartFuncMap = map[string]Func{
 	"acm":              acm.StartController,
 	"backup":           backup.StartController,
 	"inventory":        inventory.StartController,
+	"databaseBackup":   databasebackup.StartController,
 }
 
 var log = logger.DefaultZapLogger()
@@ -223,6 +223,31 @@ CREATE INDEX IF NOT EXISTS compliance_leaf_hub_non_compliant_idx ON status.compl
 
 CREATE UNIQUE INDEX IF NOT EXISTS compliance_leaf_hub_policy_cluster_idx ON status.compliance (leaf_hub_name, policy_id, cluster_name);
 
+-- the compliance changes of the global policies, they're recorded by the triggers of status.compliance
+CREATE TABLE IF NOT EXISTS event.compliance_changes (
+    policy_id uuid NOT NULL,
+    cluster_name character varying(254) NOT NULL,
+    leaf_hub_name character varying(254) NOT NULL,
+    cluster_id uuid,
+    compliance status.compliance_type NOT NULL,
+    previous_compliance status.compliance_type,
+    created_at timestamp without time zone DEFAULT now() NOT NULL
+) PARTITION BY RANGE (created_at);
+CREATE INDEX IF NOT EXISTS compliance_changes_policy_idx ON event.compliance_changes (policy_id, created_at);
+
+-- the daily compliance of the global policies, it's snapshotted from status.compliance by the compliance history job
+-- and updated by the compliance changes within the day
+CREATE TABLE IF NOT EXISTS history.compliance (
+    policy_id uuid NOT NULL,
+    cluster_name character varying(254) NOT NULL,
+    leaf_hub_name character varying(254) NOT NULL,
+    cluster_id uuid,
+    compliance_date DATE DEFAULT CURRENT_DATE NOT NULL,
+    compliance status.compliance_type NOT NULL,
+    compliance_changed_frequency integer NOT NULL DEFAULT 0,
+    CONSTRAINT compliance_unique_constraint UNIQUE (leaf_hub_name, policy_id, cluster_name, compliance_date)
+) PARTITION BY RANGE (compliance_date);
+
 CREATE UNIQUE INDEX IF NOT EXISTS placementdecisions_leaf_hub_name_and_payload_id_namespace_idx ON status.placementdecisions (leaf_hub_name, id, (((payload -> 'metadata'::text) ->> 'namespace'::text)));
 
 CREATE INDEX IF NOT EXISTS placementdecisions_payload_name_and_namespace_idx ON status.placementdecisions ((((payload -> 'metadata'::text) ->> 'name'::text)), (((payload -> 'metadata'::text) ->> 'namespace'::text)));
@@ -277,3 +277,68 @@ BEGIN
   RETURN NEW;
 END;
 $$;
+
+--- trigger function to record the compliance changes of status.compliance to event.compliance_changes
+CREATE OR REPLACE FUNCTION history.record_compliance_change() RETURNS trigger
+    LANGUAGE plpgsql
+    AS $$
+BEGIN
+    INSERT INTO event.compliance_changes (
+        policy_id,
+        cluster_name,
+        leaf_hub_name,
+        cluster_id,
+        compliance,
+        previous_compliance
+    ) VALUES (
+        NEW.policy_id,
+        NEW.cluster_name,
+        NEW.leaf_hub_name,
+        NEW.cluster_id,
+        NEW.compliance,
+        CASE WHEN TG_OP = 'UPDATE' THEN OLD.compliance END
+    );
+    RETURN NEW;
+END;
+$$;
+
+--- trigger function to update the history.compliance by event.compliance_changes, the daily compliance is the worst
+--- compliance of the day
+CREATE OR REPLACE FUNCTION history.update_compliance_by_event() RETURNS trigger
+    LANGUAGE plpgsql
+    AS $$
+BEGIN
+    INSERT INTO history.compliance (
+        policy_id,
+        cluster_name,
+        leaf_hub_name,
+        cluster_id,
+        compliance,
+        compliance_date,
+        compliance_changed_frequency
+    ) VALUES (
+        NEW.policy_id,
+        NEW.cluster_name,
+        NEW.leaf_hub_name,
+        NEW.cluster_id,
+        NEW.compliance,
+        NEW.created_at::DATE,
+        0
+    ) ON CONFLICT (leaf_hub_name, policy_id, cluster_name, compliance_date)
+    DO UPDATE SET
+        cluster_id = COALESCE(EXCLUDED.cluster_id, history.compliance.cluster_id),
+        compliance =
+            CASE
+                WHEN history.compliance.compliance = 'pending' OR EXCLUDED.compliance = 'pending' THEN 'pending'::status.compliance_type
+                WHEN history.compliance.compliance = 'unknown' OR EXCLUDED.compliance = 'unknown' THEN 'unknown'::status.compliance_type
+                WHEN history.compliance.compliance = 'non_compliant' OR EXCLUDED.compliance = 'non_compliant' THEN 'non_compliant'::status.compliance_type
+                ELSE 'compliant'::status.compliance_type
+            END,
+        compliance_changed_frequency =
+            CASE
+                WHEN history.compliance.compliance <> EXCLUDED.compliance THEN history.compliance.compliance_changed_frequency + 1
+                ELSE history.compliance.compliance_changed_frequency
+            END;
+    RETURN NEW;
+END;
+$$;
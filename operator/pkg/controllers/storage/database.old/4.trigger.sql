@@ -71,3 +71,37 @@ CREATE TRIGGER update_compliance_cluster_id_trigger
 AFTER INSERT ON status.managed_clusters
 FOR EACH ROW
 EXECUTE FUNCTION public.update_compliance_cluster_id();
+
+-- record the changes of the policies and their compliance for the watch of the rest api
+DROP TRIGGER IF EXISTS notify_resource_change ON spec.policies;
+CREATE TRIGGER notify_resource_change AFTER INSERT OR UPDATE OR DELETE ON spec.policies
+FOR EACH ROW EXECUTE FUNCTION public.notify_resource_change('id', 'deleted');
+
+-- the transition table can only be specified for the trigger with one event
+DROP TRIGGER IF EXISTS notify_compliance_insert ON status.compliance;
+CREATE TRIGGER notify_compliance_insert AFTER INSERT ON status.compliance REFERENCING NEW TABLE AS changed_rows
+FOR EACH STATEMENT EXECUTE FUNCTION public.notify_compliance_change('spec.policies');
+DROP TRIGGER IF EXISTS notify_compliance_update ON status.compliance;
+CREATE TRIGGER notify_compliance_update AFTER UPDATE ON status.compliance REFERENCING NEW TABLE AS changed_rows
+FOR EACH STATEMENT EXECUTE FUNCTION public.notify_compliance_change('spec.policies');
+DROP TRIGGER IF EXISTS notify_compliance_delete ON status.compliance;
+CREATE TRIGGER notify_compliance_delete AFTER DELETE ON status.compliance REFERENCING OLD TABLE AS changed_rows
+FOR EACH STATEMENT EXECUTE FUNCTION public.notify_compliance_change('spec.policies');
+
+--- create the current and previous month partitioned tables for the compliance history of the global policies
+SELECT create_monthly_range_partitioned_table('event.compliance_changes', to_char(current_date, 'YYYY-MM-DD'));
+SELECT create_monthly_range_partitioned_table('history.compliance', to_char(current_date, 'YYYY-MM-DD'));
+SELECT create_monthly_range_partitioned_table('event.compliance_changes', to_char(current_date - interval '1 month', 'YYYY-MM-DD'));
+SELECT create_monthly_range_partitioned_table('history.compliance', to_char(current_date - interval '1 month', 'YYYY-MM-DD'));
+
+-- record the compliance changes of the global policies, the updates which don't change the compliance are skipped
+DROP TRIGGER IF EXISTS record_compliance_insert ON status.compliance;
+CREATE TRIGGER record_compliance_insert AFTER INSERT ON status.compliance
+FOR EACH ROW EXECUTE FUNCTION history.record_compliance_change();
+DROP TRIGGER IF EXISTS record_compliance_update ON status.compliance;
+CREATE TRIGGER record_compliance_update AFTER UPDATE OF compliance ON status.compliance
+FOR EACH ROW WHEN (OLD.compliance IS DISTINCT FROM NEW.compliance) EXECUTE FUNCTION history.record_compliance_change();
+
+DROP TRIGGER IF EXISTS update_compliance_history_by_event ON event.compliance_changes;
+CREATE TRIGGER update_compliance_history_by_event AFTER INSERT ON event.compliance_changes
+FOR EACH ROW EXECUTE FUNCTION history.update_compliance_by_event();
@@ -4,5 +4,6 @@ DO $$ BEGIN
         GRANT USAGE ON SCHEMA spec TO "$1";
 
         GRANT SELECT ON ALL TABLES IN SCHEMA spec TO "$1";
+        GRANT SELECT ON event.compliance_changes, history.compliance TO "$1";
    END IF;
 END $$;
@@ -144,6 +144,31 @@ CREATE TABLE IF NOT EXISTS status.transport (
     updated_at timestamp without time zone DEFAULT now() NOT NULL
 );
 
+-- the status events which can't be handled by the manager within the retry budget
+CREATE TABLE IF NOT EXISTS status.dead_letter_events (
+    id bigserial PRIMARY KEY,
+    leaf_hub_name character varying(254) NOT NULL,
+    event_type character varying(254) NOT NULL,
+    event_id character varying(254),
+    version character varying(64),
+    dependency_version character varying(64),
+    error text NOT NULL,
+    payload bytea,
+    created_at timestamp without time zone DEFAULT now() NOT NULL,
+    replayed_at timestamp without time zone
+);
+CREATE INDEX IF NOT EXISTS dead_letter_events_leaf_hub_idx ON status.dead_letter_events (leaf_hub_name, created_at);
+
+-- the changes of the resources served by the watch of the rest api, the resource_version orders the changes
+CREATE TABLE IF NOT EXISTS status.resource_changes (
+    resource_version bigserial PRIMARY KEY,
+    table_name character varying(254) NOT NULL,
+    resource_id uuid NOT NULL,
+    change_type character varying(16) NOT NULL,
+    created_at timestamp without time zone DEFAULT now() NOT NULL
+);
+CREATE INDEX IF NOT EXISTS resource_changes_created_at_idx ON status.resource_changes (created_at);
+
 CREATE TABLE IF NOT EXISTS security.alert_counts (
     hub_name text NOT NULL,
     low integer NOT NULL,
@@ -189,4 +189,72 @@ BEGIN
 
     RETURN NEW;
 END;
-$$ LANGUAGE plpgsql;
\ No newline at end of file
+$$ LANGUAGE plpgsql;
+
+--- trigger function to record the row changes of the resources served by the watch of the rest api, the arguments
+--- are the uuid column and the soft deletion column of the table, e.g. ('cluster_id', 'deleted_at')
+CREATE OR REPLACE FUNCTION public.notify_resource_change() RETURNS trigger
+    LANGUAGE plpgsql
+    AS $$
+DECLARE
+    changed_table text := TG_TABLE_SCHEMA || '.' || TG_TABLE_NAME;
+    old_deleted boolean;
+    new_deleted boolean;
+    changed_type text;
+    row_data jsonb;
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        row_data := to_jsonb(OLD);
+        changed_type := 'DELETED';
+    ELSE
+        row_data := to_jsonb(NEW);
+        -- the soft deleted column is either a timestamp or a boolean
+        new_deleted := COALESCE(row_data ->> TG_ARGV[1], 'false') <> 'false';
+        IF TG_OP = 'INSERT' THEN
+            old_deleted := true;
+        ELSE
+            -- skip the update which doesn't change the resource
+            IF (to_jsonb(OLD) - 'updated_at') = (row_data - 'updated_at') THEN
+                RETURN NULL;
+            END IF;
+            old_deleted := COALESCE(to_jsonb(OLD) ->> TG_ARGV[1], 'false') <> 'false';
+        END IF;
+
+        IF new_deleted AND old_deleted THEN
+            RETURN NULL;
+        ELSIF new_deleted THEN
+            changed_type := 'DELETED';
+        ELSIF old_deleted THEN
+            changed_type := 'ADDED';
+        ELSE
+            changed_type := 'MODIFIED';
+        END IF;
+    END IF;
+
+    INSERT INTO status.resource_changes (table_name, resource_id, change_type)
+    VALUES (changed_table, (row_data ->> TG_ARGV[0])::uuid, changed_type);
+    -- the notifications with the same payload are folded into one in a transaction
+    PERFORM pg_notify('resource_changes', changed_table);
+    RETURN NULL;
+END;
+$$;
+
+--- trigger function to record the compliance changes as the changes of the policies, it's a statement trigger with
+--- the transition table changed_rows
+CREATE OR REPLACE FUNCTION public.notify_compliance_change() RETURNS trigger
+    LANGUAGE plpgsql
+    AS $$
+BEGIN
+    -- the cluster_id is set to the compliance by the other triggers, the compliance isn't changed
+    IF pg_trigger_depth() > 1 THEN
+        RETURN NULL;
+    END IF;
+
+    INSERT INTO status.resource_changes (table_name, resource_id, change_type)
+    SELECT DISTINCT TG_ARGV[0], policy_id, 'MODIFIED' FROM changed_rows;
+    IF FOUND THEN
+        PERFORM pg_notify('resource_changes', TG_ARGV[0]);
+    END IF;
+    RETURN NULL;
+END;
+$$;
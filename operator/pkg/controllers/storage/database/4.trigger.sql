@@ -34,4 +34,9 @@ SELECT create_monthly_range_partitioned_table('event.managed_clusters', to_char(
 DROP TRIGGER IF EXISTS trg_update_history_compliance_by_event ON event.local_policies;
 CREATE TRIGGER trg_update_history_compliance_by_event AFTER INSERT ON event.local_policies FOR EACH ROW
 EXECUTE FUNCTION history.update_history_compliance_by_event();
-COMMENT ON TRIGGER trg_update_history_compliance_by_event ON event.local_policies IS 'Trigger to update history.local_compliance based on event.local_policies inserts';
\ No newline at end of file
+COMMENT ON TRIGGER trg_update_history_compliance_by_event ON event.local_policies IS 'Trigger to update history.local_compliance based on event.local_policies inserts';
+
+-- record the changes of the managed clusters for the watch of the rest api
+DROP TRIGGER IF EXISTS notify_resource_change ON status.managed_clusters;
+CREATE TRIGGER notify_resource_change AFTER INSERT OR UPDATE OR DELETE ON status.managed_clusters
+FOR EACH ROW EXECUTE FUNCTION public.notify_resource_change('cluster_id', 'deleted_at');
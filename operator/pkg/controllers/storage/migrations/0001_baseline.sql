@@ -0,0 +1,7 @@
+-- The baseline of the versioned migrations. The schema of the baseline is created by the idempotent SQL files in the
+-- "database" and "database.old" directories, which are applied before the migrations.
+--
+-- The later schema changes are added as the numbered migrations "<version>_<name>.sql", each of them is applied
+-- once in a transaction and recorded in public.schema_migrations with its checksum, so the applied migration must
+-- never be modified. Bump migration.SchemaVersion along with the new migration.
+SELECT 1;
@@ -0,0 +1,16 @@
+-- The managed hub is unreachable before it's inactive, and each status transition of the hub is recorded as the event.
+ALTER TABLE status.leaf_hub_heartbeats ALTER COLUMN status TYPE varchar(16);
+ALTER TABLE status.leaf_hub_heartbeats ADD COLUMN IF NOT EXISTS reason varchar(64);
+ALTER TABLE status.leaf_hub_heartbeats ADD COLUMN IF NOT EXISTS last_transition_time timestamp without time zone;
+
+CREATE TABLE IF NOT EXISTS event.leaf_hubs (
+    id bigserial PRIMARY KEY,
+    leaf_hub_name character varying(254) NOT NULL,
+    previous_status character varying(16),
+    status character varying(16) NOT NULL,
+    reason character varying(64) NOT NULL,
+    message text,
+    last_heartbeat timestamp without time zone,
+    created_at timestamp without time zone DEFAULT now() NOT NULL
+);
+CREATE INDEX IF NOT EXISTS leaf_hubs_leaf_hub_name_created_at_idx ON event.leaf_hubs (leaf_hub_name, created_at);
@@ -0,0 +1,18 @@
+-- The resources collected from the managed hubs by the declarations of the agent configuration, the kind of the resource
+-- isn't known in advance, so the identity columns are generated from the payload for the queries.
+CREATE TABLE IF NOT EXISTS status.resources (
+    id uuid NOT NULL,
+    leaf_hub_name character varying(254) NOT NULL,
+    payload jsonb NOT NULL,
+    api_version character varying(254) GENERATED ALWAYS AS ((payload ->> 'apiVersion'::text)) STORED,
+    kind character varying(254) GENERATED ALWAYS AS ((payload ->> 'kind'::text)) STORED,
+    namespace character varying(254) GENERATED ALWAYS AS (((payload -> 'metadata'::text) ->> 'namespace'::text)) STORED,
+    name character varying(254) GENERATED ALWAYS AS (((payload -> 'metadata'::text) ->> 'name'::text)) STORED,
+    created_at timestamp without time zone DEFAULT now() NOT NULL,
+    updated_at timestamp without time zone DEFAULT now() NOT NULL,
+    PRIMARY KEY (leaf_hub_name, id)
+);
+CREATE INDEX IF NOT EXISTS resources_kind_namespace_name_idx ON status.resources (api_version, kind, namespace, name);
+
+DROP TRIGGER IF EXISTS set_timestamp ON status.resources;
+CREATE TRIGGER set_timestamp BEFORE UPDATE ON status.resources FOR EACH ROW EXECUTE FUNCTION public.trigger_set_timestamp();
@@ -0,0 +1,19 @@
+-- The spec objects applied on the managed hubs with the identity of the users, which are reported by the agents if the
+-- RBAC is enforced on the hubs. The records are only appended, and cleaned up by the data retention job.
+CREATE TABLE IF NOT EXISTS event.spec_audits (
+    id bigserial PRIMARY KEY,
+    leaf_hub_name character varying(254) NOT NULL,
+    event_type character varying(254) NOT NULL,
+    version bigint NOT NULL,
+    user_identity character varying(254) NOT NULL,
+    user_groups jsonb,
+    kind character varying(254) NOT NULL,
+    namespace character varying(254) NOT NULL,
+    name character varying(254) NOT NULL,
+    operation character varying(16) NOT NULL,
+    applied boolean NOT NULL,
+    reason text,
+    created_at timestamp without time zone DEFAULT now() NOT NULL
+);
+CREATE INDEX IF NOT EXISTS spec_audits_leaf_hub_name_created_at_idx ON event.spec_audits (leaf_hub_name, created_at);
+CREATE INDEX IF NOT EXISTS spec_audits_user_identity_idx ON event.spec_audits (user_identity);
@@ -0,0 +1,17 @@
+-- The latest result of applying the spec objects on the managed hubs. It was created by the database.old scripts, which
+-- are only applied if the global resources are enabled, so the table is missing otherwise.
+CREATE TABLE IF NOT EXISTS status.spec_apply (
+    leaf_hub_name character varying(254) NOT NULL,
+    event_type character varying(254) NOT NULL,
+    kind character varying(254) NOT NULL,
+    namespace character varying(254) NOT NULL DEFAULT '',
+    name character varying(254) NOT NULL,
+    operation character varying(16) NOT NULL,
+    version bigint NOT NULL DEFAULT 0,
+    applied boolean NOT NULL,
+    reason text,
+    created_at timestamp without time zone DEFAULT now() NOT NULL,
+    updated_at timestamp without time zone DEFAULT now() NOT NULL,
+    PRIMARY KEY (leaf_hub_name, kind, namespace, name)
+);
+CREATE INDEX IF NOT EXISTS spec_apply_failed_idx ON status.spec_apply (leaf_hub_name) WHERE (applied = false);
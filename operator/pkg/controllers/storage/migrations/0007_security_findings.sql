@@ -0,0 +1,64 @@
+-- The security findings of the clusters, the deployments and the images, which are collected by the security
+-- providers on the managed hubs and replaced by each report of them.
+CREATE TABLE IF NOT EXISTS security.cluster_violations (
+    hub_name text NOT NULL,
+    source text NOT NULL,
+    cluster_name text NOT NULL,
+    low integer NOT NULL,
+    medium integer NOT NULL,
+    high integer NOT NULL,
+    critical integer NOT NULL,
+    detail_url text NOT NULL,
+    created_at timestamp without time zone DEFAULT now() NOT NULL,
+    updated_at timestamp without time zone DEFAULT now() NOT NULL,
+    PRIMARY KEY (hub_name, source, cluster_name)
+);
+
+CREATE TABLE IF NOT EXISTS security.deployment_violations (
+    hub_name text NOT NULL,
+    source text NOT NULL,
+    cluster_name text NOT NULL,
+    namespace text NOT NULL,
+    deployment_name text NOT NULL,
+    low integer NOT NULL,
+    medium integer NOT NULL,
+    high integer NOT NULL,
+    critical integer NOT NULL,
+    policies jsonb NOT NULL DEFAULT '[]'::jsonb,
+    detail_url text NOT NULL,
+    created_at timestamp without time zone DEFAULT now() NOT NULL,
+    updated_at timestamp without time zone DEFAULT now() NOT NULL,
+    PRIMARY KEY (hub_name, source, cluster_name, namespace, deployment_name)
+);
+
+CREATE TABLE IF NOT EXISTS security.deployment_vulnerabilities (
+    hub_name text NOT NULL,
+    source text NOT NULL,
+    cluster_name text NOT NULL,
+    namespace text NOT NULL,
+    deployment_name text NOT NULL,
+    low integer NOT NULL,
+    moderate integer NOT NULL,
+    important integer NOT NULL,
+    critical integer NOT NULL,
+    fixable integer NOT NULL,
+    detail_url text NOT NULL,
+    created_at timestamp without time zone DEFAULT now() NOT NULL,
+    updated_at timestamp without time zone DEFAULT now() NOT NULL,
+    PRIMARY KEY (hub_name, source, cluster_name, namespace, deployment_name)
+);
+
+CREATE TABLE IF NOT EXISTS security.image_vulnerabilities (
+    hub_name text NOT NULL,
+    source text NOT NULL,
+    image_name text NOT NULL,
+    low integer NOT NULL,
+    moderate integer NOT NULL,
+    important integer NOT NULL,
+    critical integer NOT NULL,
+    fixable integer NOT NULL,
+    detail_url text NOT NULL,
+    created_at timestamp without time zone DEFAULT now() NOT NULL,
+    updated_at timestamp without time zone DEFAULT now() NOT NULL,
+    PRIMARY KEY (hub_name, source, image_name)
+);
@@ -0,0 +1,5 @@
+-- The managed clusters, policies and compliance of an unreachable hub are kept for the grace period, they're flagged
+-- with the time the hub became unreachable, and the flag is cleared once the hub is active again.
+ALTER TABLE status.managed_clusters ADD COLUMN IF NOT EXISTS stale_at timestamp without time zone;
+ALTER TABLE local_spec.policies ADD COLUMN IF NOT EXISTS stale_at timestamp without time zone;
+ALTER TABLE local_status.compliance ADD COLUMN IF NOT EXISTS stale_at timestamp without time zone;
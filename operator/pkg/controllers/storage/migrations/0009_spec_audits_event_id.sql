@@ -0,0 +1,4 @@
+-- The audits record the id of the acknowledgement event, so that the redelivered acknowledgements of the agents
+-- aren't appended again, while the objects applied again with the same bundle version are still recorded.
+ALTER TABLE event.spec_audits ADD COLUMN IF NOT EXISTS event_id character varying(64);
+CREATE INDEX IF NOT EXISTS spec_audits_leaf_hub_name_event_id_idx ON event.spec_audits (leaf_hub_name, event_id);
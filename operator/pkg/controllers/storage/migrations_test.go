@@ -0,0 +1,23 @@
+package storage
+
+import (
+	"testing"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/database/migration"
+)
+
+func TestMigrations(t *testing.T) {
+	migrations, err := Migrations()
+	if err != nil {
+		t.Fatalf("failed to load the migrations: %v", err)
+	}
+	if len(migrations) == 0 {
+		t.Fatal("expected the baseline migration")
+	}
+	// the manager checks the schema version against the latest migration
+	latest := migrations[len(migrations)-1].Version
+	if latest != migration.SchemaVersion {
+		t.Errorf("Expected the latest migration version %d equals to the schema version %d", latest,
+			migration.SchemaVersion)
+	}
+}
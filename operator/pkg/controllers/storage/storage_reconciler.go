@@ -33,6 +33,7 @@ import (
 	"github.com/stolostron/multicluster-global-hub/operator/pkg/utils"
 	"github.com/stolostron/multicluster-global-hub/pkg/constants"
 	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	"github.com/stolostron/multicluster-global-hub/pkg/database/migration"
 	"github.com/stolostron/multicluster-global-hub/pkg/logger"
 	commonutils "github.com/stolostron/multicluster-global-hub/pkg/utils"
 )
@@ -53,8 +54,8 @@ var databaseFS embed.FS
 //go:embed database.old
 var databaseOldFS embed.FS
 
-//go:embed upgrade
-var upgradeFS embed.FS
+//go:embed migrations
+var migrationsFS embed.FS
 
 //go:embed manifests.sts
 var stsPostgresFS embed.FS
@@ -559,18 +560,10 @@ func (r *StorageReconciler) applyGlobalHubInitSQL(ctx context.Context, conn *pgx
 	}
 
 	if backupEnabled || !r.upgrade {
-		lockSql := fmt.Sprintf("select pg_advisory_lock(%s)", constants.LockId)
-		unLockSql := fmt.Sprintf("select pg_advisory_unlock(%s)", constants.LockId)
-		defer func() {
-			_, err = conn.Exec(ctx, unLockSql)
-			if err != nil {
-				log.Errorf("failed to unlock db: %v", err)
-			}
-		}()
-		_, err = conn.Exec(ctx, lockSql)
-		if err != nil {
-			return fmt.Errorf("failed to parse database_uri_with_readonlyuser: %v", err)
+		if err = database.LockPostgresConnection(ctx, conn); err != nil {
+			return fmt.Errorf("failed to lock the database: %v", err)
 		}
+		defer database.UnlockPostgresConnection(conn)
 	}
 
 	objURI, err := url.Parse(readonlyUserURI)
@@ -590,16 +583,52 @@ func (r *StorageReconciler) applyGlobalHubInitSQL(ctx context.Context, conn *pgx
 	}
 
 	if !r.upgrade {
-		err = applySQL(ctx, conn, upgradeFS, "upgrade", readonlyUsername)
+		migrations, err := Migrations()
+		if err != nil {
+			return err
+		}
+		applied, err := migration.NewMigrator(conn, migrations).Migrate(ctx)
 		if err != nil {
-			return fmt.Errorf("failed to apply the upgrade sql: %v", err)
+			return fmt.Errorf("failed to apply the migrations: %v", err)
 		}
+		log.Infof("applied %d migrations, the schema version is %d", len(applied), migration.SchemaVersion)
 		r.upgrade = true
 	}
 
+	// the tables created by the migrations are granted to the readonly user as well
+	if err = applyPrivileges(ctx, conn, databaseFS, "database", readonlyUsername); err != nil {
+		return fmt.Errorf("failed to grant the privileges: %v", err)
+	}
+
 	return nil
 }
 
+// Migrations returns the versioned migrations of the global hub database, they're applied after the idempotent SQL of
+// the "database" and "database.old" directories.
+func Migrations() ([]migration.Migration, error) {
+	migrations, err := migration.Load(migrationsFS, "migrations")
+	if err != nil {
+		return nil, fmt.Errorf("failed to load the migrations: %w", err)
+	}
+	return migrations, nil
+}
+
+const privilegesFile = "5.privileges.sql"
+
+// applyPrivileges grants the select on all the tables of the global hub schemas to the readonly user, it's applied
+// once the tables are created, since the grant doesn't cover the tables created after it.
+func applyPrivileges(ctx context.Context, conn *pgx.Conn, databaseFS embed.FS, rootDir, username string) error {
+	if username == "" {
+		return nil
+	}
+	sqlBytes, err := databaseFS.ReadFile(rootDir + "/" + privilegesFile)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", privilegesFile, err)
+	}
+	_, err = conn.Exec(ctx, strings.ReplaceAll(string(sqlBytes), "$1", username))
+	return err
+}
+
 func applySQL(ctx context.Context, conn *pgx.Conn, databaseFS embed.FS, rootDir, username string) error {
 	err := iofs.WalkDir(databaseFS, rootDir, func(file string, d iofs.DirEntry, beforeError error) error {
 		if beforeError != nil {
@@ -612,10 +641,8 @@ func applySQL(ctx context.Context, conn *pgx.Conn, databaseFS embed.FS, rootDir,
 		if err != nil {
 			return fmt.Errorf("failed to read %s: %w", file, err)
 		}
-		if file == rootDir+"/5.privileges.sql" {
-			if username != "" {
-				_, err = conn.Exec(ctx, strings.ReplaceAll(string(sqlBytes), "$1", username))
-			}
+		if file == rootDir+"/"+privilegesFile {
+			err = applyPrivileges(ctx, conn, databaseFS, rootDir, username)
 		} else {
 			_, err = conn.Exec(ctx, string(sqlBytes))
 		}
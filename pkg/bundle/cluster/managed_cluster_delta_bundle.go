@@ -0,0 +1,18 @@
+package cluster
+
+import (
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+)
+
+// ManagedClusterDeltaBundle carries the managed clusters which are changed since the last delta bundle, the complete
+// bundle is still sent periodically to recover the changes which are lost.
+type ManagedClusterDeltaBundle struct {
+	Update []clusterv1.ManagedCluster `json:"update,omitempty"`
+	Delete []DeletedManagedCluster    `json:"delete,omitempty"`
+}
+
+// DeletedManagedCluster identifies the managed cluster which is deleted from the hub.
+type DeletedManagedCluster struct {
+	Name      string `json:"name"`
+	ClusterID string `json:"clusterId"`
+}
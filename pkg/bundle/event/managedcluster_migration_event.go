@@ -6,13 +6,29 @@ import (
 	corev1 "k8s.io/api/core/v1"
 )
 
+// the stages of the migration handled by the managed hubs, an empty stage is handled as deploying
+const (
+	// MigrationStageDeploying points the managed clusters to the target hub
+	MigrationStageDeploying = "Deploying"
+	// MigrationStageCleaning removes the migrated managed clusters from the source hub
+	MigrationStageCleaning = "Cleaning"
+	// MigrationStageRollingBack points the managed clusters back to the source hub
+	MigrationStageRollingBack = "RollingBack"
+)
+
 type ManagedClusterMigrationFromEvent struct {
-	ManagedClusters  []string                             `json:"managedClusters"`
-	BootstrapSecret  *corev1.Secret                       `json:"bootstrapSecret"`
-	KlusterletConfig *klusterletv1alpha1.KlusterletConfig `json:"klusterletConfig"`
+	Stage           string         `json:"stage,omitempty"`
+	ManagedClusters []string       `json:"managedClusters"`
+	BootstrapSecret *corev1.Secret `json:"bootstrapSecret"`
+	// SourceBootstrapSecret is the bootstrap kubeconfig of the source hub, the klusterlet falls back to it if the
+	// managed cluster can't register to the target hub
+	SourceBootstrapSecret *corev1.Secret                       `json:"sourceBootstrapSecret,omitempty"`
+	KlusterletConfig      *klusterletv1alpha1.KlusterletConfig `json:"klusterletConfig"`
 }
 
 type ManagedClusterMigrationToEvent struct {
+	Stage                                 string                         `json:"stage,omitempty"`
+	ManagedClusters                       []string                       `json:"managedClusters,omitempty"`
 	ManagedServiceAccountName             string                         `json:"managedServiceAccountName"`
 	ManagedServiceAccountInstallNamespace string                         `json:"managedServiceAccountInstallNamespace"`
 	KlusterletAddonConfig                 *addonv1.KlusterletAddonConfig `json:"klusterletAddonConfig"`
@@ -6,9 +6,20 @@ import "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 type GenericSpecBundle struct {
 	Objects        []*unstructured.Unstructured `json:"objects"`
 	DeletedObjects []*unstructured.Unstructured `json:"deletedObjects"`
+	// Version is the latest update time(unix microseconds) of the objects in the spec table, it's 0 if the manager
+	// doesn't version the bundle
+	Version int64 `json:"version,omitempty"`
+	// BaseVersion is the version applied by the agent which the delta bundle is based on, the bundle only contains the
+	// objects updated or deleted after it. It's 0 if the bundle contains all the objects.
+	BaseVersion int64 `json:"baseVersion,omitempty"`
 }
 
 // NewGenericBundle returns a new instance of GenericBundle.
 func NewGenericSpecBundle() *GenericSpecBundle {
 	return &GenericSpecBundle{}
 }
+
+// IsDelta returns whether the bundle only contains the objects changed after the base version
+func (b *GenericSpecBundle) IsDelta() bool {
+	return b.BaseVersion > 0
+}
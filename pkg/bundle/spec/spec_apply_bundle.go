@@ -0,0 +1,37 @@
+package spec
+
+const (
+	// SpecApplyOperationUpdate means the object is created or updated on the hub
+	SpecApplyOperationUpdate = "update"
+	// SpecApplyOperationDelete means the object is deleted from the hub
+	SpecApplyOperationDelete = "delete"
+)
+
+// SpecApplyResult is the result of applying a spec object on the hub
+type SpecApplyResult struct {
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name"`
+	Operation string `json:"operation"`
+	Applied   bool   `json:"applied"`
+	// Reason is the error of applying the object, it's empty if the object is applied
+	Reason string `json:"reason,omitempty"`
+	// Audit is the identity which the object is applied with, it's only reported if the RBAC is enforced on the hub
+	Audit *SpecApplyAudit `json:"audit,omitempty"`
+}
+
+// SpecApplyAudit records who applied the spec object on the hub. The user is empty if the object doesn't carry the
+// user identity, then it's applied with the identity of the agent.
+type SpecApplyAudit struct {
+	User   string   `json:"user,omitempty"`
+	Groups []string `json:"groups,omitempty"`
+}
+
+// Agent to Manager: SpecApplyBundle acknowledges the results of applying a spec bundle on the hub
+type SpecApplyBundle struct {
+	// EventType is the type of the applied spec bundle
+	EventType string `json:"eventType"`
+	// Version is the version of the applied spec bundle, it's 0 if the bundle isn't versioned
+	Version int64             `json:"version"`
+	Results []SpecApplyResult `json:"results"`
+}
@@ -0,0 +1,19 @@
+package spec
+
+// AppliedSpecVersion is the version of the spec bundle applied by the agent
+type AppliedSpecVersion struct {
+	Version int64 `json:"version"`
+	// Gap means the agent skipped a delta bundle whose base version isn't applied, it requires the bundle based on the
+	// applied version, or a full bundle if the version is 0
+	Gap bool `json:"gap,omitempty"`
+}
+
+// Agent to Manager: SpecVersionBundle reports the applied versions of the spec bundles by the event type
+type SpecVersionBundle struct {
+	Versions map[string]*AppliedSpecVersion `json:"versions"`
+}
+
+// NewSpecVersionBundle returns a new instance of SpecVersionBundle.
+func NewSpecVersionBundle() *SpecVersionBundle {
+	return &SpecVersionBundle{Versions: map[string]*AppliedSpecVersion{}}
+}
@@ -44,3 +44,27 @@ func prettyMessage(i interface{}) string {
 	s, _ := json.MarshalIndent(i, "", "\t")
 	return string(s)
 }
+
+func TestCompressors(t *testing.T) {
+	payload := []byte(`{"eventName":"kube-system.provision.17ad7b80d4e6f6a4","message":"The cluster is being provisioned"}`)
+
+	for _, compressionType := range []compressor.CompressionType{
+		compressor.NoOp, compressor.GZip, compressor.Zstd, compressor.Snappy,
+	} {
+		t.Run(string(compressionType), func(t *testing.T) {
+			c, err := compressor.NewCompressor(compressionType)
+			assert.Nil(t, err)
+			assert.Equal(t, string(compressionType), c.GetType())
+
+			compressed, err := c.Compress(payload)
+			assert.Nil(t, err)
+
+			decompressed, err := c.Decompress(compressed)
+			assert.Nil(t, err)
+			assert.Equal(t, payload, decompressed)
+		})
+	}
+
+	_, err := compressor.NewCompressor("lz4")
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,41 @@
+package compressor
+
+import (
+	"fmt"
+
+	"github.com/golang/snappy"
+)
+
+const (
+	snappyCompressorErrorString = "snappy compressor error"
+	snappyCompressorErrorFormat = "%s - %w"
+	snappyType                  = "snappy"
+)
+
+// newSnappyCompressor returns a new instance of snappy-based compressor.
+func newSnappyCompressor() Compressor {
+	return &CompressorSnappy{}
+}
+
+// CompressorSnappy implements Compressor with snappy-based logic, using the snappy block format.
+type CompressorSnappy struct{}
+
+// GetType returns the string identifier for snappy compressor.
+func (compressor *CompressorSnappy) GetType() string {
+	return snappyType
+}
+
+// Compress compresses a slice of bytes using snappy lib.
+func (compressor *CompressorSnappy) Compress(data []byte) ([]byte, error) {
+	return snappy.Encode(nil, data), nil
+}
+
+// Decompress decompresses a slice of snappy-compressed bytes using snappy lib.
+func (compressor *CompressorSnappy) Decompress(compressedData []byte) ([]byte, error) {
+	data, err := snappy.Decode(nil, compressedData)
+	if err != nil {
+		return nil, fmt.Errorf(snappyCompressorErrorFormat, snappyCompressorErrorString, err)
+	}
+
+	return data, nil
+}
@@ -0,0 +1,55 @@
+package compressor
+
+import (
+	"fmt"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+const (
+	zstdCompressorErrorString = "zstd compressor error"
+	zstdCompressorErrorFormat = "%s - %w"
+	zstdType                  = "zstd"
+)
+
+// newZstdCompressor returns a new instance of zstd-based compressor.
+func newZstdCompressor() (Compressor, error) {
+	encoder, err := zstd.NewWriter(nil)
+	if err != nil {
+		return nil, fmt.Errorf(zstdCompressorErrorFormat, zstdCompressorErrorString, err)
+	}
+
+	decoder, err := zstd.NewReader(nil)
+	if err != nil {
+		return nil, fmt.Errorf(zstdCompressorErrorFormat, zstdCompressorErrorString, err)
+	}
+
+	return &CompressorZstd{encoder: encoder, decoder: decoder}, nil
+}
+
+// CompressorZstd implements Compressor with zstd-based logic. The encoder and decoder are safe for concurrent use
+// when only the EncodeAll/DecodeAll functions are called.
+type CompressorZstd struct {
+	encoder *zstd.Encoder
+	decoder *zstd.Decoder
+}
+
+// GetType returns the string identifier for zstd compressor.
+func (compressor *CompressorZstd) GetType() string {
+	return zstdType
+}
+
+// Compress compresses a slice of bytes using zstd lib.
+func (compressor *CompressorZstd) Compress(data []byte) ([]byte, error) {
+	return compressor.encoder.EncodeAll(data, make([]byte, 0, len(data))), nil
+}
+
+// Decompress decompresses a slice of zstd-compressed bytes using zstd lib.
+func (compressor *CompressorZstd) Decompress(compressedData []byte) ([]byte, error) {
+	data, err := compressor.decoder.DecodeAll(compressedData, nil)
+	if err != nil {
+		return nil, fmt.Errorf(zstdCompressorErrorFormat, zstdCompressorErrorString, err)
+	}
+
+	return data, nil
+}
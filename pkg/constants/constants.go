@@ -119,6 +119,11 @@ const (
 	UpgradeKafkaFromZookeeperAnnotation = "global-hub.open-cluster-management.io/upgrade-from-zookeeper"
 	// resync the kafka client secret in agent
 	ResyncKafkaClientSecretAnnotation = "global-hub.open-cluster-management.io/resign-kafka-client-secret" // #nosec G101
+	// specify the compression type of the transport events sent by the agent on the managed hub
+	TransportCompressionTypeAnnotation = "global-hub.open-cluster-management.io/transport-compression-type"
+	// the label selector of the managed hubs which the global resource is delivered to, the selector matches the
+	// labels of the managed hub clusters on the global hub
+	HubSelectorAnnotation = "global-hub.open-cluster-management.io/hub-selector"
 )
 
 // store all the finalizers
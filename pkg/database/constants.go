@@ -48,8 +48,32 @@ const (
 	LocalPolicyEventTableName     = "local_policies"
 	LocalRootPolicyEventTableName = "local_root_policies"
 
+	// SpecApplyTableName table name of the results of applying the spec objects on the hubs.
+	SpecApplyTableName = "spec_apply"
+
+	// DeadLetterEventsTableName table name of the status events which aren't handled within the retry budget.
+	DeadLetterEventsTableName = "dead_letter_events"
+
+	// ResourcesTableName table name of the resources which are collected by the declarations of the agent configuration.
+	ResourcesTableName = "resources"
+
+	// ResourceChangesTableName table name of the resource changes served by the watch of the rest api.
+	ResourceChangesTableName = "resource_changes"
+
 	// SecurityAlertCountsTable is the name of the table for security alert counts.
 	SecurityAlertCountsTable = "alert_counts"
+
+	// SecurityClusterViolationsTable is the name of the table for the violation counts of the clusters.
+	SecurityClusterViolationsTable = "cluster_violations"
+
+	// SecurityDeploymentViolationsTable is the name of the table for the violations of the deployments.
+	SecurityDeploymentViolationsTable = "deployment_violations"
+
+	// SecurityDeploymentVulnerabilitiesTable is the name of the table for the image CVE counts of the deployments.
+	SecurityDeploymentVulnerabilitiesTable = "deployment_vulnerabilities"
+
+	// SecurityImageVulnerabilitiesTable is the name of the table for the CVE counts of the images.
+	SecurityImageVulnerabilitiesTable = "image_vulnerabilities"
 )
 
 // default values.
@@ -19,6 +19,11 @@ import (
 
 const PostgresDialect = "postgres"
 
+const (
+	lockSQL   = "select pg_advisory_lock($1)"
+	unlockSQL = "select pg_advisory_unlock($1)"
+)
+
 var (
 	IsBackupEnabled bool
 
@@ -129,7 +134,7 @@ func Lock(lockConn *sql.Conn) error {
 	}
 	log.Debug("Add db lock")
 	defer log.Debug("db locked")
-	_, err := lockConn.ExecContext(ctx, "select pg_advisory_lock($1)", constants.LockId)
+	_, err := lockConn.ExecContext(ctx, lockSQL, constants.LockId)
 	return err
 }
 
@@ -147,7 +152,7 @@ func Unlock(lockConn *sql.Conn) {
 		return false
 	},
 		func() error {
-			_, err := lockConn.ExecContext(ctx, "select pg_advisory_unlock($1)", constants.LockId)
+			_, err := lockConn.ExecContext(ctx, unlockSQL, constants.LockId)
 			return err
 		})
 	if err != nil {
@@ -0,0 +1,23 @@
+package database
+
+import (
+	"time"
+
+	"github.com/lib/pq"
+)
+
+const (
+	listenerMinReconnectInterval = 1 * time.Second
+	listenerMaxReconnectInterval = 1 * time.Minute
+)
+
+// NewListener creates the dedicated connection for the LISTEN/NOTIFY of the database, it reconnects in the
+// background, the nil notification is sent once the connection is re-established.
+func NewListener(config *DatabaseConfig, eventCallback pq.EventCallbackType) (*pq.Listener, error) {
+	urlObj, err := completePostgres(config.URL, config.CaCertPath)
+	if err != nil {
+		return nil, err
+	}
+	return pq.NewListener(urlObj.String(), listenerMinReconnectInterval, listenerMaxReconnectInterval,
+		eventCallback), nil
+}
@@ -0,0 +1,213 @@
+package migration
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	"github.com/stolostron/multicluster-global-hub/pkg/logger"
+)
+
+var log = logger.ZapLogger("database-migration")
+
+// SchemaVersion is the schema version which the binaries of this release expect, it must be the version of the
+// latest migration.
+const SchemaVersion = 9
+
+// TableName is the table recording the applied migrations.
+const TableName = "public.schema_migrations"
+
+const createTableSQL = `CREATE TABLE IF NOT EXISTS public.schema_migrations (
+    version integer PRIMARY KEY,
+    name character varying(254) NOT NULL,
+    checksum character varying(64) NOT NULL,
+    applied_at timestamp without time zone DEFAULT now() NOT NULL
+)`
+
+// the migration file is named as "<version>_<name>.sql", e.g. "0002_add_compliance_history.sql"
+var fileNamePattern = regexp.MustCompile(`^(\d+)_([a-z0-9_]+)\.sql$`)
+
+// Migration is a numbered SQL script which changes the schema, it's applied once in a transaction.
+type Migration struct {
+	Version  int
+	Name     string
+	SQL      string
+	Checksum string
+}
+
+// AppliedMigration is the migration recorded in the schema migrations table.
+type AppliedMigration struct {
+	Version   int
+	Name      string
+	Checksum  string
+	AppliedAt time.Time
+}
+
+// Load reads the migrations from the directory in order of the version, the versions must be unique.
+func Load(fsys fs.FS, dir string) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the migrations directory %s: %w", dir, err)
+	}
+
+	migrations := []Migration{}
+	versions := map[int]string{}
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		matches := fileNamePattern.FindStringSubmatch(entry.Name())
+		if matches == nil {
+			return nil, fmt.Errorf("invalid migration file name %s: it must be <version>_<name>.sql", entry.Name())
+		}
+		version, err := strconv.Atoi(matches[1])
+		if err != nil || version <= 0 {
+			return nil, fmt.Errorf("invalid migration version of the file %s", entry.Name())
+		}
+		if existing, ok := versions[version]; ok {
+			return nil, fmt.Errorf("the migrations %s and %s have the same version %d", existing, entry.Name(),
+				version)
+		}
+		versions[version] = entry.Name()
+
+		content, err := fs.ReadFile(fsys, path.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the migration %s: %w", entry.Name(), err)
+		}
+		sum := sha256.Sum256(content)
+		migrations = append(migrations, Migration{
+			Version:  version,
+			Name:     matches[2],
+			SQL:      string(content),
+			Checksum: hex.EncodeToString(sum[:]),
+		})
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations, nil
+}
+
+// Pending returns the migrations which aren't applied. It fails if an applied migration is modified after it's
+// applied, the applied migrations which are unknown to the binary are from a newer release and are skipped.
+func Pending(migrations []Migration, applied []AppliedMigration) ([]Migration, error) {
+	appliedChecksums := map[int]string{}
+	for _, m := range applied {
+		appliedChecksums[m.Version] = m.Checksum
+	}
+
+	pending := []Migration{}
+	for _, m := range migrations {
+		checksum, ok := appliedChecksums[m.Version]
+		if !ok {
+			pending = append(pending, m)
+			continue
+		}
+		if checksum != m.Checksum {
+			return nil, fmt.Errorf("the migration %d_%s is modified after it's applied: checksum %s, applied %s",
+				m.Version, m.Name, m.Checksum, checksum)
+		}
+	}
+	return pending, nil
+}
+
+// Migrator applies the migrations to the database with the connection.
+type Migrator struct {
+	conn       *pgx.Conn
+	migrations []Migration
+}
+
+func NewMigrator(conn *pgx.Conn, migrations []Migration) *Migrator {
+	return &Migrator{conn: conn, migrations: migrations}
+}
+
+// Applied returns the applied migrations in order of the version. It doesn't change the database, so it's empty if
+// the schema migrations table doesn't exist.
+func (m *Migrator) Applied(ctx context.Context) ([]AppliedMigration, error) {
+	var exists bool
+	if err := m.conn.QueryRow(ctx, "SELECT to_regclass($1) IS NOT NULL", TableName).Scan(&exists); err != nil {
+		return nil, fmt.Errorf("failed to check the table %s: %w", TableName, err)
+	}
+	if !exists {
+		return []AppliedMigration{}, nil
+	}
+
+	rows, err := m.conn.Query(ctx,
+		"SELECT version, name, checksum, applied_at FROM public.schema_migrations ORDER BY version")
+	if err != nil {
+		return nil, fmt.Errorf("failed to query the applied migrations: %w", err)
+	}
+	defer rows.Close()
+
+	applied := []AppliedMigration{}
+	for rows.Next() {
+		a := AppliedMigration{}
+		if err := rows.Scan(&a.Version, &a.Name, &a.Checksum, &a.AppliedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan the applied migration: %w", err)
+		}
+		applied = append(applied, a)
+	}
+	return applied, rows.Err()
+}
+
+// Pending returns the migrations which aren't applied to the database.
+func (m *Migrator) Pending(ctx context.Context) ([]Migration, error) {
+	applied, err := m.Applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return Pending(m.migrations, applied)
+}
+
+// Migrate applies the pending migrations in order under the advisory lock of the database, each migration is applied
+// and recorded in a transaction. It returns the applied migrations.
+func (m *Migrator) Migrate(ctx context.Context) ([]Migration, error) {
+	// the advisory lock is reentrant, it's fine if the caller holds the lock on the same connection
+	if err := database.LockPostgresConnection(ctx, m.conn); err != nil {
+		return nil, fmt.Errorf("failed to lock the database: %w", err)
+	}
+	defer database.UnlockPostgresConnection(m.conn)
+
+	if _, err := m.conn.Exec(ctx, createTableSQL); err != nil {
+		return nil, fmt.Errorf("failed to create the table %s: %w", TableName, err)
+	}
+
+	// the pending migrations are read after locking, the other process might have applied them
+	pending, err := m.Pending(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	applied := []Migration{}
+	for _, migration := range pending {
+		if err := m.apply(ctx, migration); err != nil {
+			return applied, err
+		}
+		log.Infof("applied the migration %d_%s", migration.Version, migration.Name)
+		applied = append(applied, migration)
+	}
+	return applied, nil
+}
+
+func (m *Migrator) apply(ctx context.Context, migration Migration) error {
+	err := pgx.BeginFunc(ctx, m.conn, func(tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx, migration.SQL); err != nil {
+			return err
+		}
+		_, err := tx.Exec(ctx, "INSERT INTO public.schema_migrations (version, name, checksum) VALUES ($1, $2, $3)",
+			migration.Version, migration.Name, migration.Checksum)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to apply the migration %d_%s: %w", migration.Version, migration.Name, err)
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+package migration
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoad(t *testing.T) {
+	fsys := fstest.MapFS{
+		"migrations/0010_add_index.sql":      {Data: []byte("CREATE INDEX foo_idx ON foo (bar);")},
+		"migrations/0002_add_table.sql":      {Data: []byte("CREATE TABLE foo (bar int);")},
+		"migrations/0001_baseline.sql":       {Data: []byte("SELECT 1;")},
+		"migrations/nested/0003_ignored.sql": {Data: []byte("SELECT 1;")},
+	}
+	migrations, err := Load(fsys, "migrations")
+	require.NoError(t, err)
+	require.Len(t, migrations, 3)
+	assert.Equal(t, 1, migrations[0].Version)
+	assert.Equal(t, "baseline", migrations[0].Name)
+	assert.Equal(t, 2, migrations[1].Version)
+	assert.Equal(t, "add_table", migrations[1].Name)
+	assert.Equal(t, "CREATE TABLE foo (bar int);", migrations[1].SQL)
+	assert.Equal(t, 10, migrations[2].Version)
+	assert.Len(t, migrations[0].Checksum, 64)
+	assert.NotEqual(t, migrations[0].Checksum, migrations[1].Checksum)
+
+	_, err = Load(fstest.MapFS{
+		"migrations/0001_baseline.sql": {Data: []byte("SELECT 1;")},
+		"migrations/1_duplicated.sql":  {Data: []byte("SELECT 1;")},
+	}, "migrations")
+	assert.ErrorContains(t, err, "the same version 1")
+
+	_, err = Load(fstest.MapFS{"migrations/add_table.sql": {Data: []byte("SELECT 1;")}}, "migrations")
+	assert.ErrorContains(t, err, "invalid migration file name")
+
+	_, err = Load(fstest.MapFS{"migrations/0000_zero.sql": {Data: []byte("SELECT 1;")}}, "migrations")
+	assert.ErrorContains(t, err, "invalid migration version")
+}
+
+func TestPending(t *testing.T) {
+	migrations := []Migration{
+		{Version: 1, Name: "baseline", Checksum: "a"},
+		{Version: 2, Name: "add_table", Checksum: "b"},
+		{Version: 3, Name: "add_index", Checksum: "c"},
+	}
+
+	pending, err := Pending(migrations, []AppliedMigration{})
+	require.NoError(t, err)
+	assert.Len(t, pending, 3)
+
+	// the migration 4 is applied by a newer release
+	pending, err = Pending(migrations, []AppliedMigration{
+		{Version: 1, Checksum: "a"}, {Version: 2, Checksum: "b"}, {Version: 4, Checksum: "d"},
+	})
+	require.NoError(t, err)
+	require.Len(t, pending, 1)
+	assert.Equal(t, 3, pending[0].Version)
+
+	_, err = Pending(migrations, []AppliedMigration{{Version: 1, Checksum: "a"}, {Version: 2, Checksum: "x"}})
+	assert.ErrorContains(t, err, "the migration 2_add_table is modified")
+}
@@ -0,0 +1,41 @@
+package migration
+
+import (
+	"context"
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+// CurrentVersion returns the version of the latest applied migration, it's 0 if no migration is applied.
+func CurrentVersion(ctx context.Context, db *gorm.DB) (int, error) {
+	var exists bool
+	if err := db.WithContext(ctx).Raw("SELECT to_regclass(?) IS NOT NULL", TableName).
+		Scan(&exists).Error; err != nil {
+		return 0, fmt.Errorf("failed to check the table %s: %w", TableName, err)
+	}
+	if !exists {
+		return 0, nil
+	}
+
+	var version int
+	if err := db.WithContext(ctx).Raw("SELECT COALESCE(MAX(version), 0) FROM public.schema_migrations").
+		Scan(&version).Error; err != nil {
+		return 0, fmt.Errorf("failed to get the schema version: %w", err)
+	}
+	return version, nil
+}
+
+// CheckVersion returns an error if the schema of the database is older than the expected version, the binary
+// shouldn't run against the schema until the migrations are applied.
+func CheckVersion(ctx context.Context, db *gorm.DB, expected int) error {
+	version, err := CurrentVersion(ctx, db)
+	if err != nil {
+		return err
+	}
+	if version < expected {
+		return fmt.Errorf("the database schema version %d is older than the expected version %d, "+
+			"wait for the operator to apply the migrations", version, expected)
+	}
+	return nil
+}
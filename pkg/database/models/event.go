@@ -37,6 +37,22 @@ func (LocalRootPolicyEvent) TableName() string {
 	return "event.local_root_policies"
 }
 
+// ComplianceChange is a compliance change of the global policy on a cluster, it's recorded by the trigger of
+// status.compliance
+type ComplianceChange struct {
+	PolicyID           string    `gorm:"column:policy_id;type:uuid;not null"`
+	ClusterName        string    `gorm:"column:cluster_name;not null"`
+	LeafHubName        string    `gorm:"column:leaf_hub_name;not null"`
+	ClusterID          *string   `gorm:"column:cluster_id;type:uuid"`
+	Compliance         string    `gorm:"column:compliance;not null"`
+	PreviousCompliance *string   `gorm:"column:previous_compliance"`
+	CreatedAt          time.Time `gorm:"column:created_at;default:now();not null"`
+}
+
+func (ComplianceChange) TableName() string {
+	return "event.compliance_changes"
+}
+
 type DataRetentionJobLog struct {
 	Name         string    `gorm:"column:table_name"`
 	StartAt      time.Time `gorm:"column:start_at"`
@@ -68,3 +84,43 @@ type ManagedClusterEvent struct {
 func (ManagedClusterEvent) TableName() string {
 	return "event.managed_clusters"
 }
+
+// LeafHubEvent is a status transition of the managed hub, e.g. from active to unreachable, it's recorded by the hub
+// management of the manager
+type LeafHubEvent struct {
+	ID             int64     `gorm:"column:id;primaryKey;autoIncrement" json:"id"`
+	LeafHubName    string    `gorm:"column:leaf_hub_name;type:varchar(254);not null" json:"leafHubName"`
+	PreviousStatus string    `gorm:"column:previous_status;type:varchar(16)" json:"previousStatus"`
+	Status         string    `gorm:"column:status;type:varchar(16);not null" json:"status"`
+	Reason         string    `gorm:"column:reason;type:varchar(64);not null" json:"reason"`
+	Message        string    `gorm:"column:message;type:text" json:"message"`
+	LastHeartbeat  time.Time `gorm:"column:last_heartbeat" json:"lastHeartbeat"`
+	CreatedAt      time.Time `gorm:"column:created_at;default:now();not null" json:"createdAt"`
+}
+
+func (LeafHubEvent) TableName() string {
+	return "event.leaf_hubs"
+}
+
+// SpecAudit records the spec object applied on the managed hub with the identity of the user, it's reported by the agent
+// only if the RBAC is enforced on the hub
+type SpecAudit struct {
+	ID          int64          `gorm:"column:id;primaryKey;autoIncrement" json:"id"`
+	LeafHubName string         `gorm:"column:leaf_hub_name;type:varchar(254);not null" json:"leafHubName"`
+	EventType   string         `gorm:"column:event_type;not null" json:"eventType"`
+	Version     int64          `gorm:"column:version;not null" json:"version"`
+	EventID     string         `gorm:"column:event_id" json:"eventId,omitempty"`
+	User        string         `gorm:"column:user_identity;not null" json:"user"`
+	Groups      datatypes.JSON `gorm:"column:user_groups;type:jsonb" json:"groups"`
+	Kind        string         `gorm:"column:kind;not null" json:"kind"`
+	Namespace   string         `gorm:"column:namespace;not null" json:"namespace"`
+	Name        string         `gorm:"column:name;not null" json:"name"`
+	Operation   string         `gorm:"column:operation;not null" json:"operation"`
+	Applied     bool           `gorm:"column:applied;not null" json:"applied"`
+	Reason      string         `gorm:"column:reason" json:"reason,omitempty"`
+	CreatedAt   time.Time      `gorm:"column:created_at;default:now();not null" json:"createdAt"`
+}
+
+func (SpecAudit) TableName() string {
+	return "event.spec_audits"
+}
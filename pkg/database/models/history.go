@@ -29,3 +29,18 @@ type LocalComplianceHistory struct {
 func (LocalComplianceHistory) TableName() string {
 	return "history.local_compliance"
 }
+
+// ComplianceHistory is the daily compliance of the global policy on a cluster
+type ComplianceHistory struct {
+	PolicyID                   string    `gorm:"column:policy_id"`
+	ClusterName                string    `gorm:"column:cluster_name"`
+	LeafHubName                string    `gorm:"type:varchar(254);not null;column:leaf_hub_name"`
+	ClusterID                  *string   `gorm:"column:cluster_id"`
+	ComplianceDate             time.Time `gorm:"type:date;column:compliance_date"`
+	Compliance                 string    `gorm:"type:compliance_type;not null;column:compliance"`
+	ComplianceChangedFrequency int       `gorm:"type:integer;column:compliance_changed_frequency"`
+}
+
+func (ComplianceHistory) TableName() string {
+	return "history.compliance"
+}
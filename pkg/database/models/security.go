@@ -1,6 +1,10 @@
 package models
 
-import "time"
+import (
+	"time"
+
+	"gorm.io/datatypes"
+)
 
 // SecurityAlertCounts contains a summary of the security alerts from a hub.
 type SecurityAlertCounts struct {
@@ -39,3 +43,83 @@ type SecurityAlertCounts struct {
 func (SecurityAlertCounts) TableName() string {
 	return "security.alert_counts"
 }
+
+// SecurityClusterViolations contains the violation counts of a managed cluster secured by a Central CR instance.
+type SecurityClusterViolations struct {
+	HubName     string    `gorm:"column:hub_name;primaryKey"`
+	Source      string    `gorm:"column:source;primaryKey"`
+	ClusterName string    `gorm:"column:cluster_name;primaryKey"`
+	Low         int       `gorm:"column:low;not null"`
+	Medium      int       `gorm:"column:medium;not null"`
+	High        int       `gorm:"column:high;not null"`
+	Critical    int       `gorm:"column:critical;not null"`
+	DetailURL   string    `gorm:"column:detail_url;not null"`
+	CreatedAt   time.Time `gorm:"column:created_at;autoCreateTime:true"`
+	UpdatedAt   time.Time `gorm:"column:updated_at;autoUpdateTime:true"`
+}
+
+func (SecurityClusterViolations) TableName() string {
+	return "security.cluster_violations"
+}
+
+// SecurityDeploymentViolations contains the violation counts and the violated policies of a deployment.
+type SecurityDeploymentViolations struct {
+	HubName        string `gorm:"column:hub_name;primaryKey"`
+	Source         string `gorm:"column:source;primaryKey"`
+	ClusterName    string `gorm:"column:cluster_name;primaryKey"`
+	Namespace      string `gorm:"column:namespace;primaryKey"`
+	DeploymentName string `gorm:"column:deployment_name;primaryKey"`
+	Low            int    `gorm:"column:low;not null"`
+	Medium         int    `gorm:"column:medium;not null"`
+	High           int    `gorm:"column:high;not null"`
+	Critical       int    `gorm:"column:critical;not null"`
+	// Policies is the JSON array of the names of the violated policies.
+	Policies  datatypes.JSON `gorm:"column:policies;type:jsonb"`
+	DetailURL string         `gorm:"column:detail_url;not null"`
+	CreatedAt time.Time      `gorm:"column:created_at;autoCreateTime:true"`
+	UpdatedAt time.Time      `gorm:"column:updated_at;autoUpdateTime:true"`
+}
+
+func (SecurityDeploymentViolations) TableName() string {
+	return "security.deployment_violations"
+}
+
+// SecurityDeploymentVulnerabilities contains the CVE counts of the images used by a deployment.
+type SecurityDeploymentVulnerabilities struct {
+	HubName        string    `gorm:"column:hub_name;primaryKey"`
+	Source         string    `gorm:"column:source;primaryKey"`
+	ClusterName    string    `gorm:"column:cluster_name;primaryKey"`
+	Namespace      string    `gorm:"column:namespace;primaryKey"`
+	DeploymentName string    `gorm:"column:deployment_name;primaryKey"`
+	Low            int       `gorm:"column:low;not null"`
+	Moderate       int       `gorm:"column:moderate;not null"`
+	Important      int       `gorm:"column:important;not null"`
+	Critical       int       `gorm:"column:critical;not null"`
+	Fixable        int       `gorm:"column:fixable;not null"`
+	DetailURL      string    `gorm:"column:detail_url;not null"`
+	CreatedAt      time.Time `gorm:"column:created_at;autoCreateTime:true"`
+	UpdatedAt      time.Time `gorm:"column:updated_at;autoUpdateTime:true"`
+}
+
+func (SecurityDeploymentVulnerabilities) TableName() string {
+	return "security.deployment_vulnerabilities"
+}
+
+// SecurityImageVulnerabilities contains the CVE counts of an image.
+type SecurityImageVulnerabilities struct {
+	HubName   string    `gorm:"column:hub_name;primaryKey"`
+	Source    string    `gorm:"column:source;primaryKey"`
+	ImageName string    `gorm:"column:image_name;primaryKey"`
+	Low       int       `gorm:"column:low;not null"`
+	Moderate  int       `gorm:"column:moderate;not null"`
+	Important int       `gorm:"column:important;not null"`
+	Critical  int       `gorm:"column:critical;not null"`
+	Fixable   int       `gorm:"column:fixable;not null"`
+	DetailURL string    `gorm:"column:detail_url;not null"`
+	CreatedAt time.Time `gorm:"column:created_at;autoCreateTime:true"`
+	UpdatedAt time.Time `gorm:"column:updated_at;autoUpdateTime:true"`
+}
+
+func (SecurityImageVulnerabilities) TableName() string {
+	return "security.image_vulnerabilities"
+}
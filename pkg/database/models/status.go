@@ -76,6 +76,9 @@ type LeafHubHeartbeat struct {
 	Name         string    `gorm:"column:leaf_hub_name;primaryKey"`
 	Status       string    `gorm:"column:status;default:(-)"`
 	LastUpdateAt time.Time `gorm:"column:last_timestamp;autoUpdateTime:false"`
+	// Reason and LastTransitionTime describe the latest status transition, the history is in the event.leaf_hubs
+	Reason             string     `gorm:"column:reason;default:(-)"`
+	LastTransitionTime *time.Time `gorm:"column:last_transition_time"`
 }
 
 func (LeafHubHeartbeat) TableName() string {
@@ -98,3 +101,72 @@ type SubscriptionReport struct {
 func (SubscriptionReport) TableName() string {
 	return "status.subscription_reports"
 }
+
+// SpecApply is the latest result of applying a spec object on the hub
+type SpecApply struct {
+	LeafHubName string    `gorm:"column:leaf_hub_name;primaryKey"`
+	EventType   string    `gorm:"column:event_type;not null"`
+	Kind        string    `gorm:"column:kind;primaryKey"`
+	Namespace   string    `gorm:"column:namespace;primaryKey"`
+	Name        string    `gorm:"column:name;primaryKey"`
+	Operation   string    `gorm:"column:operation;not null"`
+	Version     int64     `gorm:"column:version;not null"`
+	Applied     bool      `gorm:"column:applied;not null"`
+	Reason      string    `gorm:"column:reason"`
+	CreatedAt   time.Time `gorm:"column:created_at;autoCreateTime:true"`
+	UpdatedAt   time.Time `gorm:"column:updated_at;autoUpdateTime:true"`
+}
+
+func (SpecApply) TableName() string {
+	return "status.spec_apply"
+}
+
+// DeadLetterEvent is the status event which isn't handled within the retry budget of the conflation element
+type DeadLetterEvent struct {
+	ID                int64      `gorm:"column:id;primaryKey;autoIncrement" json:"id"`
+	LeafHubName       string     `gorm:"column:leaf_hub_name;not null" json:"leafHubName"`
+	EventType         string     `gorm:"column:event_type;not null" json:"eventType"`
+	EventID           string     `gorm:"column:event_id" json:"eventID"`
+	Version           string     `gorm:"column:version" json:"version"`
+	DependencyVersion string     `gorm:"column:dependency_version" json:"dependencyVersion,omitempty"`
+	Error             string     `gorm:"column:error;not null" json:"error"`
+	Payload           []byte     `gorm:"column:payload;type:bytea" json:"payload"`
+	CreatedAt         time.Time  `gorm:"column:created_at;autoCreateTime:true" json:"createdAt"`
+	ReplayedAt        *time.Time `gorm:"column:replayed_at" json:"replayedAt,omitempty"`
+}
+
+func (DeadLetterEvent) TableName() string {
+	return "status.dead_letter_events"
+}
+
+// ResourceChange is the change of the resource recorded by the database triggers, it's served by the watch of the
+// rest api in order of the resource version
+type ResourceChange struct {
+	ResourceVersion int64     `gorm:"column:resource_version;primaryKey;autoIncrement"`
+	ResourceTable   string    `gorm:"column:table_name;not null"`
+	ResourceID      string    `gorm:"column:resource_id;not null"`
+	ChangeType      string    `gorm:"column:change_type;not null"`
+	CreatedAt       time.Time `gorm:"column:created_at;autoCreateTime:true"`
+}
+
+func (ResourceChange) TableName() string {
+	return "status.resource_changes"
+}
+
+// Resource is the resource collected from the managed hub by the declarations of the agent configuration, the identity
+// columns are generated from the payload by the database
+type Resource struct {
+	ID          string         `gorm:"column:id;primaryKey" json:"id"`
+	LeafHubName string         `gorm:"column:leaf_hub_name;primaryKey" json:"leafHubName"`
+	APIVersion  string         `gorm:"column:api_version;->" json:"apiVersion"`
+	Kind        string         `gorm:"column:kind;->" json:"kind"`
+	Namespace   string         `gorm:"column:namespace;->" json:"namespace,omitempty"`
+	Name        string         `gorm:"column:name;->" json:"name"`
+	Payload     datatypes.JSON `gorm:"column:payload;type:jsonb" json:"payload"`
+	CreatedAt   time.Time      `gorm:"column:created_at;autoCreateTime:true" json:"createdAt"`
+	UpdatedAt   time.Time      `gorm:"column:updated_at;autoUpdateTime:true" json:"updatedAt"`
+}
+
+func (Resource) TableName() string {
+	return "status.resources"
+}
@@ -10,6 +10,9 @@ import (
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
+	"k8s.io/client-go/util/retry"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/constants"
 )
 
 const errMessageFileNotFound = "no such file or directory"
@@ -27,6 +30,29 @@ func PostgresConnection(ctx context.Context, URI string, cert []byte) (*pgx.Conn
 	return conn, nil
 }
 
+// LockPostgresConnection takes the same advisory lock as the Lock on the pgx connection, it's used by the operator
+// which connects the database with pgx, and it isn't skipped if the backup is disabled. The lock is reentrant in the
+// session, so it must be released as many times as it's taken.
+func LockPostgresConnection(ctx context.Context, conn *pgx.Conn) error {
+	_, err := conn.Exec(ctx, lockSQL, constants.LockId)
+	return err
+}
+
+// UnlockPostgresConnection releases the advisory lock taken by the LockPostgresConnection, it's retried like the Unlock.
+func UnlockPostgresConnection(conn *pgx.Conn) {
+	err := retry.OnError(retry.DefaultRetry, func(err error) bool {
+		log.Warnf("unlock failed, retry unlock. err: %s", err)
+		return true
+	}, func() error {
+		// the context of the caller might be canceled, the lock must be released anyway
+		_, err := conn.Exec(context.Background(), unlockSQL, constants.LockId)
+		return err
+	})
+	if err != nil {
+		log.Error(err, "Failed to unlock db")
+	}
+}
+
 func GetPostgresConfig(URI string, cert []byte) (*pgx.ConnConfig, error) {
 	config, err := pgx.ParseConfig(URI)
 	if err != nil {
@@ -7,8 +7,11 @@ type EventType string
 const (
 	HubClusterInfoType        EventType = "io.open-cluster-management.operator.multiclusterglobalhubs.managedhub.info"
 	HubClusterHeartbeatType   EventType = "io.open-cluster-management.operator.multiclusterglobalhubs.managedhub.heartbeat"
+	HubSpecVersionType        EventType = "io.open-cluster-management.operator.multiclusterglobalhubs.managedhub.specversion"
+	HubSpecApplyType          EventType = "io.open-cluster-management.operator.multiclusterglobalhubs.managedhub.specapply"
 	KlusterletAddonConfigType EventType = "io.open-cluster-management.operator.multiclusterglobalhubs.managedcluster.klusterletaddonconfig"
 	ManagedClusterType        EventType = "io.open-cluster-management.operator.multiclusterglobalhubs.managedcluster"
+	ManagedClusterDeltaType   EventType = "io.open-cluster-management.operator.multiclusterglobalhubs.managedcluster.delta"
 	ManagedClusterInfoType    EventType = "io.open-cluster-management.operator.multiclusterglobalhubs.managedclusterinfo"
 	SubscriptionReportType    EventType = "io.open-cluster-management.operator.multiclusterglobalhubs.subscription.report"
 	SubscriptionStatusType    EventType = "io.open-cluster-management.operator.multiclusterglobalhubs.subscription.status"
@@ -41,6 +44,15 @@ const (
 	PlacementRuleSpecType      EventType = "io.open-cluster-management.operator.multiclusterglobalhubs.placementrule.spec"
 	PlacementSpecType          EventType = "io.open-cluster-management.operator.multiclusterglobalhubs.placement.spec"
 
+	// Used to send the resources declared in the agent configuration, the objects of all the kinds are in one bundle
+	ResourceType EventType = "io.open-cluster-management.operator.multiclusterglobalhubs.resource"
+
 	// Used to send security alerts:
 	SecurityAlertCountsType EventType = "io.open-cluster-management.operator.multiclusterglobalhubs.security.alertcounts"
+	//nolint: go:S103
+	SecurityClusterViolationsType EventType = "io.open-cluster-management.operator.multiclusterglobalhubs.security.clusterviolations"
+	//nolint: go:S103
+	SecurityDeploymentViolationsType EventType = "io.open-cluster-management.operator.multiclusterglobalhubs.security.deploymentviolations"
+	//nolint: go:S103
+	SecurityVulnerabilitiesType EventType = "io.open-cluster-management.operator.multiclusterglobalhubs.security.vulnerabilities"
 )
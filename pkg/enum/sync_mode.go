@@ -9,3 +9,36 @@ const (
 	// DeltaStateMode used to identify sync mode of delta state bundles.
 	DeltaStateMode EventSyncMode = iota
 )
+
+// completeStateTypes are the event types of the complete state bundles, a newer bundle of them supersedes the older
+// one, while the other bundles(delta, events, ...) must be delivered one by one in order.
+var completeStateTypes = map[EventType]struct{}{
+	HubClusterInfoType:               {},
+	HubClusterHeartbeatType:          {},
+	HubSpecVersionType:               {},
+	KlusterletAddonConfigType:        {},
+	ManagedClusterType:               {},
+	SubscriptionReportType:           {},
+	SubscriptionStatusType:           {},
+	LocalComplianceType:              {},
+	LocalCompleteComplianceType:      {},
+	LocalPolicySpecType:              {},
+	ComplianceType:                   {},
+	CompleteComplianceType:           {},
+	MiniComplianceType:               {},
+	PlacementDecisionType:            {},
+	LocalPlacementRuleSpecType:       {},
+	PlacementRuleSpecType:            {},
+	PlacementSpecType:                {},
+	ResourceType:                     {},
+	SecurityAlertCountsType:          {},
+	SecurityClusterViolationsType:    {},
+	SecurityDeploymentViolationsType: {},
+	SecurityVulnerabilitiesType:      {},
+}
+
+// IsCompleteStateType returns true if the event type is a complete state bundle
+func IsCompleteStateType(eventType string) bool {
+	_, ok := completeStateTypes[EventType(eventType)]
+	return ok
+}
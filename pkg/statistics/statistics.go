@@ -144,9 +144,10 @@ func (s *Statistics) run(ctx context.Context, duration time.Duration) {
 					storageAvg = float64(metrics.database.totalDuration / metrics.database.successes)
 				}
 			}
-			metrics := fmt.Sprintf("{CU=%d, CUQueue=%d, idleDBW=%d, success=%d, fail=%d, CU Avg=%.0f ms, DB Avg=%.0f ms}",
+			metrics := fmt.Sprintf(
+				"{CU=%d, CUQueue=%d, idleDBW=%d, success=%d, fail=%d, CU Avg=%.0f ms, DB Avg=%.0f ms, %s}",
 				s.numOfConflationUnits, s.conflationReadyQueueSize, s.numOfAvailableDBWorkers, success, fail,
-				conflationAvg, storageAvg)
+				conflationAvg, storageAvg, transportMetricsString())
 
 			s.log.Debug(fmt.Sprintf("%s\n%s", metrics, stringBuilder.String()))
 		}
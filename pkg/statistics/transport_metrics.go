@@ -0,0 +1,53 @@
+package statistics
+
+import (
+	"fmt"
+	"sync/atomic"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const (
+	// DropReasonExpired means the chunks of the bundle aren't completed within the max age
+	DropReasonExpired = "expired"
+	// DropReasonEvicted means the chunks of the bundle are evicted since the assembler exceeds the memory budget
+	DropReasonEvicted = "evicted"
+	// DropReasonReplaced means the chunks of the bundle are replaced by the ones with a different size or checksum
+	DropReasonReplaced = "replaced"
+)
+
+var (
+	droppedBundlesCounter = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "multicluster_global_hub_transport_dropped_bundles_total",
+		Help: "The number of the chunked bundles dropped by the transport consumer before they are assembled.",
+	}, []string{"reason"})
+	corruptBundlesCounter = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "multicluster_global_hub_transport_corrupt_bundles_total",
+		Help: "The number of the assembled bundles which don't match the size or checksum of the producer.",
+	})
+
+	droppedBundles atomic.Int64
+	corruptBundles atomic.Int64
+)
+
+// RegisterTransportMetrics will register the transport metrics with the global prometheus registry
+func RegisterTransportMetrics() {
+	metrics.Registry.MustRegister(droppedBundlesCounter, corruptBundlesCounter)
+}
+
+// IncrementDroppedBundles increments the number of the dropped bundles with the reason
+func IncrementDroppedBundles(reason string) {
+	droppedBundles.Add(1)
+	droppedBundlesCounter.WithLabelValues(reason).Inc()
+}
+
+// IncrementCorruptBundles increments the number of the corrupt bundles
+func IncrementCorruptBundles() {
+	corruptBundles.Add(1)
+	corruptBundlesCounter.Inc()
+}
+
+func transportMetricsString() string {
+	return fmt.Sprintf("droppedBundles=%d, corruptBundles=%d", droppedBundles.Load(), corruptBundles.Load())
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 
+	"github.com/stolostron/multicluster-global-hub/pkg/compressor"
 	"github.com/stolostron/multicluster-global-hub/pkg/transport"
 	"github.com/stolostron/multicluster-global-hub/pkg/transport/consumer"
 	"github.com/stolostron/multicluster-global-hub/pkg/transport/producer"
@@ -24,7 +25,7 @@ func TestAssembler(t *testing.T) {
 	}
 
 	transportConfig.IsManager = true
-	genericProducer, err := producer.NewGenericProducer(transportConfig)
+	genericProducer, err := producer.NewGenericProducer(context.Background(), transportConfig)
 	assert.Nil(t, err)
 	genericProducer.SetDataLimit(5)
 
@@ -51,3 +52,45 @@ func TestAssembler(t *testing.T) {
 	evt := <-genericConsumer.EventChan()
 	fmt.Println("whole", evt)
 }
+
+func TestCompressedAssembler(t *testing.T) {
+	transportConfig := &transport.TransportInternalConfig{
+		TransportType: string(transport.Chan),
+		KafkaCredential: &transport.KafkaConfig{
+			SpecTopic:   "compressed-spec",
+			StatusTopic: "compressed-status",
+		},
+		CompressionType: compressor.Zstd,
+	}
+
+	transportConfig.IsManager = true
+	genericProducer, err := producer.NewGenericProducer(context.Background(), transportConfig)
+	assert.Nil(t, err)
+	genericProducer.SetDataLimit(10)
+
+	transportConfig.IsManager = false
+	genericConsumer, err := consumer.NewGenericConsumer(transportConfig)
+	assert.Nil(t, err)
+	go func() {
+		err = genericConsumer.Start(context.TODO())
+		assert.Nil(t, err)
+	}()
+
+	data := map[string]interface{}{
+		"id":      1,
+		"message": "Hello, Compressed World!",
+	}
+	e := cloudevents.NewEvent()
+	e.SetID(uuid.New().String())
+	e.SetType("com.cloudevents.sample.sent")
+	e.SetSource("https://github.com/cloudevents/sdk-go/samples/kafka/sender")
+	_ = e.SetData(cloudevents.ApplicationJSON, data)
+	expected := e.Data()
+
+	err = genericProducer.SendEvent(context.TODO(), e)
+	assert.Nil(t, err)
+
+	evt := <-genericConsumer.EventChan()
+	assert.Equal(t, expected, evt.Data())
+	assert.Nil(t, evt.Extensions()[transport.CompressionKey])
+}
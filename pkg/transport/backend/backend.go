@@ -0,0 +1,54 @@
+// Copyright (c) 2025 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package backend
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/transport"
+)
+
+// Backend builds the cloudevents sender/receiver protocol of a transport type from the transport config, the
+// protocol is used to create the cloudevents client of the generic producer and consumer.
+type Backend interface {
+	// NewSender returns the cloudevents sender protocol, which sends to the spec topic on the manager, and the status
+	// topic on the agent
+	NewSender(config *transport.TransportInternalConfig) (interface{}, error)
+	// NewReceiver returns the cloudevents receiver protocol, which receives from the status topic on the manager, and
+	// the spec topic on the agent
+	NewReceiver(config *transport.TransportInternalConfig) (interface{}, error)
+}
+
+var (
+	backends     = map[string]Backend{}
+	backendsLock sync.RWMutex
+)
+
+// Register makes the backend available for the transport type, it overrides the previous one with the same type.
+// It's supposed to be invoked in the init function of the backend implementation.
+func Register(transportType transport.TransportType, backend Backend) {
+	backendsLock.Lock()
+	defer backendsLock.Unlock()
+	backends[string(transportType)] = backend
+}
+
+// Get returns the registered backend of the transport type
+func Get(transportType string) (Backend, error) {
+	backendsLock.RLock()
+	defer backendsLock.RUnlock()
+	backend, found := backends[transportType]
+	if !found {
+		return nil, fmt.Errorf("transport-type - %s is not a valid option", transportType)
+	}
+	return backend, nil
+}
+
+// Registered returns whether the transport type has a registered backend
+func Registered(transportType string) bool {
+	backendsLock.RLock()
+	defer backendsLock.RUnlock()
+	_, found := backends[transportType]
+	return found
+}
@@ -0,0 +1,44 @@
+package backend
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/transport"
+)
+
+func TestBackendRegistry(t *testing.T) {
+	for _, transportType := range []transport.TransportType{transport.Kafka, transport.Chan, transport.Mqtt} {
+		assert.True(t, Registered(string(transportType)))
+		_, err := Get(string(transportType))
+		assert.NoError(t, err)
+	}
+
+	_, err := Get(string(transport.Rest))
+	assert.EqualError(t, err, "transport-type - rest is not a valid option")
+
+	// the gochan sender of manager and the receiver of agent share the same spec topic
+	chanBackend, err := Get(string(transport.Chan))
+	assert.NoError(t, err)
+	tranConfig := &transport.TransportInternalConfig{
+		TransportType: string(transport.Chan),
+		IsManager:     true,
+		KafkaCredential: &transport.KafkaConfig{
+			SpecTopic:   "spec",
+			StatusTopic: "status",
+		},
+	}
+	sender, err := chanBackend.NewSender(tranConfig)
+	assert.NoError(t, err)
+	tranConfig.IsManager = false
+	receiver, err := chanBackend.NewReceiver(tranConfig)
+	assert.NoError(t, err)
+	assert.Same(t, sender, receiver)
+
+	// the mqtt backend requires the mqtt credential
+	mqttBackend, err := Get(string(transport.Mqtt))
+	assert.NoError(t, err)
+	_, err = mqttBackend.NewSender(tranConfig)
+	assert.EqualError(t, err, "the mqtt credential must not be nil")
+}
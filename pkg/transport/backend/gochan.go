@@ -0,0 +1,44 @@
+// Copyright (c) 2025 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package backend
+
+import (
+	"github.com/cloudevents/sdk-go/v2/protocol/gochan"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/transport"
+)
+
+func init() {
+	Register(transport.Chan, &gochanBackend{})
+}
+
+// gochanBackend shares the go chan protocol between the sender and receiver of the same topic through the Extends
+// of the transport config, it's mainly used by the testing.
+type gochanBackend struct{}
+
+func (b *gochanBackend) NewSender(tranConfig *transport.TransportInternalConfig) (interface{}, error) {
+	topic := tranConfig.KafkaCredential.SpecTopic
+	if !tranConfig.IsManager {
+		topic = tranConfig.KafkaCredential.StatusTopic
+	}
+	return b.protocol(tranConfig, topic), nil
+}
+
+func (b *gochanBackend) NewReceiver(tranConfig *transport.TransportInternalConfig) (interface{}, error) {
+	topic := tranConfig.KafkaCredential.StatusTopic
+	if !tranConfig.IsManager {
+		topic = tranConfig.KafkaCredential.SpecTopic
+	}
+	return b.protocol(tranConfig, topic), nil
+}
+
+func (b *gochanBackend) protocol(tranConfig *transport.TransportInternalConfig, topic string) interface{} {
+	if tranConfig.Extends == nil {
+		tranConfig.Extends = make(map[string]interface{})
+	}
+	if _, found := tranConfig.Extends[topic]; !found {
+		tranConfig.Extends[topic] = gochan.New()
+	}
+	return tranConfig.Extends[topic]
+}
@@ -0,0 +1,54 @@
+// Copyright (c) 2025 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package backend
+
+import (
+	"fmt"
+
+	kafka_confluent "github.com/cloudevents/sdk-go/protocol/kafka_confluent/v2"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/transport"
+	"github.com/stolostron/multicluster-global-hub/pkg/transport/config"
+)
+
+func init() {
+	Register(transport.Kafka, &kafkaBackend{})
+}
+
+// kafkaBackend builds the cloudevents protocol with the confluent kafka client
+type kafkaBackend struct{}
+
+func (b *kafkaBackend) NewSender(tranConfig *transport.TransportInternalConfig) (interface{}, error) {
+	if tranConfig.KafkaCredential == nil {
+		return nil, fmt.Errorf("the kafka credential must not be nil")
+	}
+	topic := tranConfig.KafkaCredential.SpecTopic
+	if !tranConfig.IsManager {
+		topic = tranConfig.KafkaCredential.StatusTopic
+	}
+
+	configMap, err := config.GetConfluentConfigMapByKafkaCredential(tranConfig.KafkaCredential, "")
+	if err != nil {
+		return nil, err
+	}
+	return kafka_confluent.New(kafka_confluent.WithConfigMap(configMap), kafka_confluent.WithSenderTopic(topic))
+}
+
+func (b *kafkaBackend) NewReceiver(tranConfig *transport.TransportInternalConfig) (interface{}, error) {
+	if tranConfig.KafkaCredential == nil {
+		return nil, fmt.Errorf("the kafka credential must not be nil")
+	}
+	topics := []string{tranConfig.KafkaCredential.StatusTopic}
+	if !tranConfig.IsManager {
+		topics[0] = tranConfig.KafkaCredential.SpecTopic
+	}
+
+	configMap, err := config.GetConfluentConfigMapByKafkaCredential(tranConfig.KafkaCredential,
+		tranConfig.ConsumerGroupId)
+	if err != nil {
+		return nil, err
+	}
+	return kafka_confluent.New(kafka_confluent.WithConfigMap(configMap),
+		kafka_confluent.WithReceiverTopics(topics))
+}
@@ -0,0 +1,123 @@
+// Copyright (c) 2025 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package backend
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"time"
+
+	mqtt_paho "github.com/cloudevents/sdk-go/protocol/mqtt_paho/v2"
+	"github.com/eclipse/paho.golang/paho"
+	"github.com/google/uuid"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/transport"
+	"github.com/stolostron/multicluster-global-hub/pkg/transport/config"
+)
+
+const (
+	mqttDialTimeout      = 30 * time.Second
+	mqttDefaultKeepAlive = 30
+	// mqttSessionExpiryInterval is how long(seconds) the broker keeps the session after the client disconnects, the
+	// in-flight QoS1 messages of the session are delivered once the client reconnects within the interval
+	mqttSessionExpiryInterval uint32 = 3600
+)
+
+func init() {
+	Register(transport.Mqtt, &mqttBackend{})
+}
+
+// mqttBackend builds the cloudevents protocol with the paho mqtt(v5) client. It's used by the hubs which can't reach
+// the kafka brokers, each sender/receiver holds its own connection to the broker.
+type mqttBackend struct{}
+
+func (b *mqttBackend) NewSender(tranConfig *transport.TransportInternalConfig) (interface{}, error) {
+	if tranConfig.MqttCredential == nil {
+		return nil, fmt.Errorf("the mqtt credential must not be nil")
+	}
+	topic := tranConfig.MqttCredential.SpecTopic
+	if !tranConfig.IsManager {
+		topic = tranConfig.MqttCredential.StatusTopic
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), mqttDialTimeout)
+	defer cancel()
+	clientConfig, err := b.clientConfig(ctx, tranConfig, "producer")
+	if err != nil {
+		return nil, err
+	}
+	return mqtt_paho.New(ctx, clientConfig,
+		mqtt_paho.WithConnect(b.connect(tranConfig.MqttCredential, clientConfig.ClientID)),
+		mqtt_paho.WithPublish(&paho.Publish{Topic: topic, QoS: tranConfig.MqttCredential.QoS}))
+}
+
+func (b *mqttBackend) NewReceiver(tranConfig *transport.TransportInternalConfig) (interface{}, error) {
+	if tranConfig.MqttCredential == nil {
+		return nil, fmt.Errorf("the mqtt credential must not be nil")
+	}
+	topic := tranConfig.MqttCredential.StatusTopic
+	if !tranConfig.IsManager {
+		topic = tranConfig.MqttCredential.SpecTopic
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), mqttDialTimeout)
+	defer cancel()
+	clientConfig, err := b.clientConfig(ctx, tranConfig, "consumer")
+	if err != nil {
+		return nil, err
+	}
+	return mqtt_paho.New(ctx, clientConfig,
+		mqtt_paho.WithConnect(b.connect(tranConfig.MqttCredential, clientConfig.ClientID)),
+		mqtt_paho.WithSubscribe(&paho.Subscribe{
+			Subscriptions: []paho.SubscribeOptions{{Topic: topic, QoS: tranConfig.MqttCredential.QoS}},
+		}))
+}
+
+// clientConfig dials the broker, with tls if the ca cert is provided. The client id is derived from the consumer
+// group id(the hub name on the agent), so that the reconnected client takes over the previous session
+func (b *mqttBackend) clientConfig(ctx context.Context, tranConfig *transport.TransportInternalConfig,
+	role string,
+) (*paho.ClientConfig, error) {
+	tlsConfig, err := config.GetMqttTLSConfig(tranConfig.MqttCredential)
+	if err != nil {
+		return nil, err
+	}
+
+	var conn net.Conn
+	dialer := &net.Dialer{Timeout: mqttDialTimeout}
+	if tlsConfig != nil {
+		conn, err = (&tls.Dialer{NetDialer: dialer, Config: tlsConfig}).DialContext(ctx, "tcp",
+			tranConfig.MqttCredential.BrokerHost)
+	} else {
+		conn, err = dialer.DialContext(ctx, "tcp", tranConfig.MqttCredential.BrokerHost)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to the mqtt broker %s: %w", tranConfig.MqttCredential.BrokerHost, err)
+	}
+
+	clientID := tranConfig.ConsumerGroupId
+	if clientID == "" {
+		clientID = uuid.New().String()
+	}
+	return &paho.ClientConfig{
+		ClientID: fmt.Sprintf("%s-%s", clientID, role),
+		Conn:     conn,
+	}, nil
+}
+
+func (b *mqttBackend) connect(mqttConfig *transport.MqttConfig, clientID string) *paho.Connect {
+	keepAlive := mqttConfig.KeepAlive
+	if keepAlive == 0 {
+		keepAlive = mqttDefaultKeepAlive
+	}
+	sessionExpiryInterval := mqttSessionExpiryInterval
+	return &paho.Connect{
+		ClientID:   clientID,
+		KeepAlive:  keepAlive,
+		CleanStart: false,
+		Properties: &paho.ConnectProperties{SessionExpiryInterval: &sessionExpiryInterval},
+	}
+}
@@ -0,0 +1,17 @@
+// Copyright (c) 2025 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package transport
+
+import (
+	"fmt"
+	"hash/crc32"
+)
+
+var crc32cTable = crc32.MakeTable(crc32.Castagnoli)
+
+// ChunkChecksum returns the crc32(castagnoli) checksum of the chunked payload in hex, it's carried by the
+// ChunkChecksumKey extension of each chunk, so that the consumer can verify the assembled payload
+func ChunkChecksum(payload []byte) string {
+	return fmt.Sprintf("%08x", crc32.Checksum(payload, crc32cTable))
+}
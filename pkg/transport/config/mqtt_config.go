@@ -0,0 +1,53 @@
+package config
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/kustomize/kyaml/yaml"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/transport"
+)
+
+func GetMqttCredentialBySecret(transportSecret *corev1.Secret, c client.Client) (*transport.MqttConfig, error) {
+	mqttYaml, ok := transportSecret.Data["mqtt.yaml"]
+	if !ok {
+		return nil, fmt.Errorf("must set the `mqtt.yaml` in the transport secret(%s)", transportSecret.Name)
+	}
+	conn := &transport.MqttConfig{}
+	if err := yaml.Unmarshal(mqttYaml, conn); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal mqtt config to transport credentail: %w", err)
+	}
+
+	err := ParseCredentailConn(transportSecret.Namespace, c, conn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the cert credentail: %w", err)
+	}
+	return conn, nil
+}
+
+// GetMqttTLSConfig returns the tls config based on the certs in the mqtt credential, return nil if the ca isn't set
+func GetMqttTLSConfig(conn *transport.MqttConfig) (*tls.Config, error) {
+	if conn.CACert == "" {
+		return nil, nil
+	}
+	caCertPool := x509.NewCertPool()
+	if ok := caCertPool.AppendCertsFromPEM([]byte(conn.CACert)); !ok {
+		return nil, fmt.Errorf("failed to append the mqtt ca cert")
+	}
+	tlsConfig := &tls.Config{
+		RootCAs:    caCertPool,
+		MinVersion: tls.VersionTLS12,
+	}
+	if conn.ClientCert != "" && conn.ClientKey != "" {
+		cert, err := tls.X509KeyPair([]byte(conn.ClientCert), []byte(conn.ClientKey))
+		if err != nil {
+			return nil, fmt.Errorf("failed to load the mqtt client cert: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+	return tlsConfig, nil
+}
@@ -14,15 +14,16 @@ import (
 	"github.com/cloudevents/sdk-go/v2/client"
 	cectx "github.com/cloudevents/sdk-go/v2/context"
 	ceprotocol "github.com/cloudevents/sdk-go/v2/protocol"
-	"github.com/cloudevents/sdk-go/v2/protocol/gochan"
+	"github.com/cloudevents/sdk-go/v2/types"
 	"github.com/confluentinc/confluent-kafka-go/v2/kafka"
 	"go.uber.org/zap"
 
+	"github.com/stolostron/multicluster-global-hub/pkg/compressor"
 	"github.com/stolostron/multicluster-global-hub/pkg/database"
 	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
 	"github.com/stolostron/multicluster-global-hub/pkg/logger"
 	"github.com/stolostron/multicluster-global-hub/pkg/transport"
-	"github.com/stolostron/multicluster-global-hub/pkg/transport/config"
+	"github.com/stolostron/multicluster-global-hub/pkg/transport/backend"
 )
 
 var transportID string
@@ -38,6 +39,10 @@ type GenericConsumer struct {
 	consumerCancel context.CancelFunc
 	client         cloudevents.Client
 
+	// compressors caches the decompressors by the compression type in the received events
+	compressors     map[string]compressor.Compressor
+	compressorsLock sync.Mutex
+
 	mutex sync.Mutex
 }
 
@@ -58,6 +63,7 @@ func NewGenericConsumer(tranConfig *transport.TransportInternalConfig,
 		eventChan:            make(chan *cloudevents.Event),
 		assembler:            newMessageAssembler(),
 		enableDatabaseOffset: tranConfig.EnableDatabaseOffset,
+		compressors:          make(map[string]compressor.Compressor),
 	}
 	if err := c.initClient(tranConfig); err != nil {
 		return nil, err
@@ -73,31 +79,19 @@ func (c *GenericConsumer) initClient(tranConfig *transport.TransportInternalConf
 	var err error
 	var clientProtocol interface{}
 
-	c.clusterID = tranConfig.KafkaCredential.ClusterID
-	topics := []string{tranConfig.KafkaCredential.StatusTopic}
-	if !tranConfig.IsManager {
-		topics[0] = tranConfig.KafkaCredential.SpecTopic
+	// the kafka cluster id is the owner identity of the offsets persisted in the database
+	if tranConfig.KafkaCredential != nil {
+		c.clusterID = tranConfig.KafkaCredential.ClusterID
 	}
 
-	switch tranConfig.TransportType {
-	case string(transport.Kafka):
-		c.log.Info("transport consumer with cloudevents-kafka receiver")
-		clientProtocol, err = getConfluentReceiverProtocol(tranConfig, topics)
-		if err != nil {
-			return err
-		}
-	case string(transport.Chan):
-		c.log.Info("transport consumer with go chan receiver")
-		if tranConfig.Extends == nil {
-			tranConfig.Extends = make(map[string]interface{})
-		}
-		topic := topics[0]
-		if _, found := tranConfig.Extends[topic]; !found {
-			tranConfig.Extends[topic] = gochan.New()
-		}
-		clientProtocol = tranConfig.Extends[topic]
-	default:
-		return fmt.Errorf("transport-type - %s is not a valid option", tranConfig.TransportType)
+	ceBackend, err := backend.Get(tranConfig.TransportType)
+	if err != nil {
+		return err
+	}
+	c.log.Infof("transport consumer with cloudevents-%s receiver", tranConfig.TransportType)
+	clientProtocol, err = ceBackend.NewReceiver(tranConfig)
+	if err != nil {
+		return err
 	}
 
 	c.client, err = cloudevents.NewClient(clientProtocol, client.WithPollGoroutines(1))
@@ -154,19 +148,20 @@ func (c *GenericConsumer) Start(ctx context.Context) error {
 	}
 
 	c.consumerCtx, c.consumerCancel = context.WithCancel(receiveContext)
+	go c.assembler.evictPeriodically(c.consumerCtx)
 	err := c.client.StartReceiver(c.consumerCtx, func(ctx context.Context, event cloudevents.Event) ceprotocol.Result {
 		c.log.Debugw("received message", "event.Source", event.Source(), "event.Type", event.Type())
 
 		chunk, isChunk := c.assembler.messageChunk(event)
 		if !isChunk {
-			c.eventChan <- &event
+			c.sendEvent(&event)
 			return ceprotocol.ResultACK
 		}
 		if payload := c.assembler.assemble(chunk); payload != nil {
 			if err := event.SetData(cloudevents.ApplicationJSON, payload); err != nil {
 				c.log.Error(err, "failed the set the assembled data to event")
 			} else {
-				c.eventChan <- &event
+				c.sendEvent(&event)
 			}
 		}
 		return ceprotocol.ResultACK
@@ -178,6 +173,54 @@ func (c *GenericConsumer) Start(ctx context.Context) error {
 	return nil
 }
 
+// sendEvent decompresses the event data if it's compressed by the producer, then delivers it to the event channel
+func (c *GenericConsumer) sendEvent(evt *cloudevents.Event) {
+	if err := c.decompress(evt); err != nil {
+		c.log.Errorw("failed to decompress the event", "error", err, "type", evt.Type(), "source", evt.Source())
+		return
+	}
+	c.eventChan <- evt
+}
+
+// decompress replaces the event data with the decompressed one, the event without compression extension is from the
+// producer that doesn't compress the data, then it's returned as-is
+func (c *GenericConsumer) decompress(evt *cloudevents.Event) error {
+	compressionType, err := types.ToString(evt.Extensions()[transport.CompressionKey])
+	if err != nil || compressionType == "" || compressionType == string(compressor.NoOp) {
+		return nil
+	}
+
+	eventCompressor, err := c.getCompressor(compressionType)
+	if err != nil {
+		return err
+	}
+
+	payload, err := eventCompressor.Decompress(evt.Data())
+	if err != nil {
+		return err
+	}
+	if err := evt.SetData(cloudevents.ApplicationJSON, payload); err != nil {
+		return fmt.Errorf("failed to set the decompressed data to event: %w", err)
+	}
+	evt.SetExtension(transport.CompressionKey, nil)
+	return nil
+}
+
+func (c *GenericConsumer) getCompressor(compressionType string) (compressor.Compressor, error) {
+	c.compressorsLock.Lock()
+	defer c.compressorsLock.Unlock()
+
+	if eventCompressor, found := c.compressors[compressionType]; found {
+		return eventCompressor, nil
+	}
+	eventCompressor, err := compressor.NewCompressor(compressor.CompressionType(compressionType))
+	if err != nil {
+		return nil, fmt.Errorf("failed to init the compressor %s: %w", compressionType, err)
+	}
+	c.compressors[compressionType] = eventCompressor
+	return eventCompressor, nil
+}
+
 func (c *GenericConsumer) EventChan() chan *cloudevents.Event {
 	return c.eventChan
 }
@@ -207,33 +250,6 @@ func getInitOffset(kafkaClusterIdentity string) ([]kafka.TopicPartition, error)
 	return offsetToStart, nil
 }
 
-// func getSaramaReceiverProtocol(transportConfig *transport.TransportConfig) (interface{}, error) {
-// 	saramaConfig, err := config.GetSaramaConfig(transportConfig.KafkaConfig)
-// 	if err != nil {
-// 		return nil, err
-// 	}
-// 	// if set this to false, it will consume message from beginning when restart the client
-// 	saramaConfig.Consumer.Offsets.AutoCommit.Enable = true
-// 	saramaConfig.Consumer.Offsets.Initial = sarama.OffsetOldest
-// 	// set the consumer groupId = clientId
-// 	return kafka_sarama.NewConsumer([]string{transportConfig.KafkaConfig.BootstrapServer}, saramaConfig,
-// 		transportConfig.KafkaConfig.ConsumerConfig.ConsumerID,
-// 		transportConfig.KafkaConfig.ConsumerConfig.ConsumerTopic)
-// }
-
-func getConfluentReceiverProtocol(transportConfig *transport.TransportInternalConfig, topics []string) (
-	interface{}, error,
-) {
-	configMap, err := config.GetConfluentConfigMapByKafkaCredential(transportConfig.KafkaCredential,
-		transportConfig.ConsumerGroupId)
-	if err != nil {
-		return nil, err
-	}
-
-	return kafka_confluent.New(kafka_confluent.WithConfigMap(configMap),
-		kafka_confluent.WithReceiverTopics(topics))
-}
-
 func TransportID() string {
 	return transportID
 }
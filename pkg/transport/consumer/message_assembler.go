@@ -5,46 +5,73 @@ package consumer
 
 import (
 	"bytes"
+	"context"
 	"sort"
 	"sync"
+	"time"
 
 	cloudevents "github.com/cloudevents/sdk-go/v2"
 	"github.com/cloudevents/sdk-go/v2/types"
 	"go.uber.org/zap"
 
 	"github.com/stolostron/multicluster-global-hub/pkg/logger"
+	"github.com/stolostron/multicluster-global-hub/pkg/statistics"
 	"github.com/stolostron/multicluster-global-hub/pkg/transport"
 )
 
+const (
+	// DefaultChunkCollectionMaxAge is the max duration to wait for the remaining chunks of a bundle
+	DefaultChunkCollectionMaxAge = 10 * time.Minute
+	// DefaultChunkCollectionMaxBytes is the memory budget of all the partially received bundles
+	DefaultChunkCollectionMaxBytes = 256 * 1024 * 1024
+	// chunkEvictionInterval is the interval to evict the expired collections even if no more chunks are received
+	chunkEvictionInterval = time.Minute
+)
+
 // messageChunk represents a chunk of a transport message.
 type messageChunk struct {
-	id     string
-	offset int
-	size   int
-	bytes  []byte
+	id       string
+	offset   int
+	size     int
+	count    int    // the number of the chunks, it's 0 if the producer doesn't carry it
+	checksum string // the checksum of the whole payload, it's empty if the producer doesn't carry it
+	bytes    []byte
 }
 
 // messageChunksCollection holds a collection of chunks and maintains it until completion.
 type messageChunksCollection struct {
 	id              string
 	totalSize       int
+	count           int
+	checksum        string
 	accumulatedSize int
 	chunks          map[int]*messageChunk
 	orderedOffsets  []int
+	createdAt       time.Time
 	lock            sync.Mutex
 }
 
-func newMessageChunksCollection(id string, size int) *messageChunksCollection {
+func newMessageChunksCollection(chunk *messageChunk, now time.Time) *messageChunksCollection {
 	return &messageChunksCollection{
-		id:              id,
-		totalSize:       size,
+		id:              chunk.id,
+		totalSize:       chunk.size,
+		count:           chunk.count,
+		checksum:        chunk.checksum,
 		accumulatedSize: 0,
 		chunks:          make(map[int]*messageChunk),
 		orderedOffsets:  make([]int, 0),
+		createdAt:       now,
 		lock:            sync.Mutex{},
 	}
 }
 
+// matches returns false if the chunk belongs to another bundle with the same id, e.g. the producer restarted and
+// resent the updated bundle with the same id
+func (collection *messageChunksCollection) matches(chunk *messageChunk) bool {
+	return collection.totalSize == chunk.size && collection.count == chunk.count &&
+		collection.checksum == chunk.checksum
+}
+
 func (collection *messageChunksCollection) add(chunk *messageChunk) {
 	collection.lock.Lock()
 	defer collection.lock.Unlock()
@@ -59,6 +86,17 @@ func (collection *messageChunksCollection) add(chunk *messageChunk) {
 	collection.accumulatedSize += len(chunk.bytes)
 }
 
+// completed returns true if all the chunks are received, the count is preferred if the producer carries it
+func (collection *messageChunksCollection) completed() bool {
+	collection.lock.Lock()
+	defer collection.lock.Unlock()
+
+	if collection.count > 0 {
+		return len(collection.chunks) >= collection.count
+	}
+	return collection.totalSize <= collection.accumulatedSize
+}
+
 func (collection *messageChunksCollection) collect() ([]byte, error) {
 	collection.lock.Lock()
 	defer collection.lock.Unlock()
@@ -75,10 +113,24 @@ func (collection *messageChunksCollection) collect() ([]byte, error) {
 	return buffer.Bytes(), nil
 }
 
+// verify checks the assembled payload with the size and checksum of the producer
+func (collection *messageChunksCollection) verify(payload []byte) bool {
+	if len(payload) != collection.totalSize {
+		return false
+	}
+	return collection.checksum == "" || collection.checksum == transport.ChunkChecksum(payload)
+}
+
+// messageAssembler assembles the chunks into the payload. The partially received collections are evicted once they
+// exceed the max age, or the accumulated bytes of all the collections exceed the budget.
 type messageAssembler struct {
 	log                *zap.SugaredLogger
 	lock               sync.Mutex
 	chunkCollectionMap map[string]*messageChunksCollection
+	accumulatedBytes   int
+	maxAge             time.Duration
+	maxBytes           int
+	now                func() time.Time
 }
 
 func newMessageAssembler() *messageAssembler {
@@ -86,6 +138,9 @@ func newMessageAssembler() *messageAssembler {
 		log:                logger.DefaultZapLogger(),
 		lock:               sync.Mutex{},
 		chunkCollectionMap: make(map[string]*messageChunksCollection),
+		maxAge:             DefaultChunkCollectionMaxAge,
+		maxBytes:           DefaultChunkCollectionMaxBytes,
+		now:                time.Now,
 	}
 }
 
@@ -94,31 +149,105 @@ func (assembler *messageAssembler) assemble(chunk *messageChunk) []byte {
 	assembler.lock.Lock()
 	defer assembler.lock.Unlock()
 
+	now := assembler.now()
+	assembler.evictExpired(now)
+
 	chunkCollection, found := assembler.chunkCollectionMap[chunk.id] // chunk.id: PlacementRule
+	if found && !chunkCollection.matches(chunk) {
+		assembler.log.Infow("drop the chunks replaced by the different bundle", "id", chunk.id,
+			"size", chunkCollection.totalSize, "newSize", chunk.size)
+		assembler.drop(chunkCollection, statistics.DropReasonReplaced)
+		found = false
+	}
 	if !found {
-		chunkCollection = newMessageChunksCollection(chunk.id, chunk.size)
+		chunkCollection = newMessageChunksCollection(chunk, now)
 		assembler.chunkCollectionMap[chunk.id] = chunkCollection
 	}
 
+	previousSize := chunkCollection.accumulatedSize
 	chunkCollection.add(chunk)
+	assembler.accumulatedBytes += chunkCollection.accumulatedSize - previousSize
 
-	if chunkCollection.totalSize <= chunkCollection.accumulatedSize {
+	if chunkCollection.completed() {
 		// delete collection from map
-		defer delete(assembler.chunkCollectionMap, chunkCollection.id)
+		defer assembler.remove(chunkCollection)
 
 		transportPayloadBytes, err := chunkCollection.collect()
 		if err != nil {
 			assembler.log.Error(err, "assemble event data failed")
 			return nil
 		}
+		if !chunkCollection.verify(transportPayloadBytes) {
+			assembler.log.Warnw("drop the corrupt event data", "id", chunkCollection.id,
+				"size", chunkCollection.totalSize, "assembledSize", len(transportPayloadBytes))
+			statistics.IncrementCorruptBundles()
+			return nil
+		}
 		assembler.log.Debugw("assemble event data success!", "id", chunkCollection.id,
 			"size", chunkCollection.totalSize)
 		return transportPayloadBytes
 	}
 
+	assembler.evictOverBudget()
 	return nil
 }
 
+// evictPeriodically drops the expired collections on a timer, otherwise they're kept until the next chunk arrives
+func (assembler *messageAssembler) evictPeriodically(ctx context.Context) {
+	ticker := time.NewTicker(chunkEvictionInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			assembler.evict()
+		}
+	}
+}
+
+func (assembler *messageAssembler) evict() {
+	assembler.lock.Lock()
+	defer assembler.lock.Unlock()
+	assembler.evictExpired(assembler.now())
+}
+
+// evictExpired drops the collections which aren't completed within the max age, e.g. a chunk is lost
+func (assembler *messageAssembler) evictExpired(now time.Time) {
+	for _, collection := range assembler.chunkCollectionMap {
+		if now.Sub(collection.createdAt) > assembler.maxAge {
+			assembler.log.Warnw("drop the expired chunks", "id", collection.id, "createdAt", collection.createdAt,
+				"size", collection.totalSize, "accumulatedSize", collection.accumulatedSize)
+			assembler.drop(collection, statistics.DropReasonExpired)
+		}
+	}
+}
+
+// evictOverBudget drops the oldest collections until the accumulated bytes are within the budget
+func (assembler *messageAssembler) evictOverBudget() {
+	for assembler.accumulatedBytes > assembler.maxBytes && len(assembler.chunkCollectionMap) > 0 {
+		var oldest *messageChunksCollection
+		for _, collection := range assembler.chunkCollectionMap {
+			if oldest == nil || collection.createdAt.Before(oldest.createdAt) {
+				oldest = collection
+			}
+		}
+		assembler.log.Warnw("drop the oldest chunks since the assembler exceeds the memory budget", "id", oldest.id,
+			"accumulatedSize", oldest.accumulatedSize, "maxBytes", assembler.maxBytes)
+		assembler.drop(oldest, statistics.DropReasonEvicted)
+	}
+}
+
+func (assembler *messageAssembler) drop(collection *messageChunksCollection, reason string) {
+	assembler.remove(collection)
+	statistics.IncrementDroppedBundles(reason)
+}
+
+func (assembler *messageAssembler) remove(collection *messageChunksCollection) {
+	delete(assembler.chunkCollectionMap, collection.id)
+	assembler.accumulatedBytes -= collection.accumulatedSize
+}
+
 func (assembler *messageAssembler) messageChunk(e cloudevents.Event) (*messageChunk, bool) {
 	offset, err := types.ToInteger(e.Extensions()[transport.ChunkOffsetKey])
 	if err != nil {
@@ -130,10 +259,18 @@ func (assembler *messageAssembler) messageChunk(e cloudevents.Event) (*messageCh
 		return nil, false
 	}
 
-	return &messageChunk{
+	chunk := &messageChunk{
 		id:     e.ID(),
 		offset: int(offset),
 		size:   int(size),
 		bytes:  e.Data(),
-	}, true
+	}
+	// the count and checksum are absent if the event is from the previous version of the producer
+	if count, err := types.ToInteger(e.Extensions()[transport.ChunkCountKey]); err == nil {
+		chunk.count = int(count)
+	}
+	if checksum, err := types.ToString(e.Extensions()[transport.ChunkChecksumKey]); err == nil {
+		chunk.checksum = checksum
+	}
+	return chunk, true
 }
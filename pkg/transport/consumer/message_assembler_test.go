@@ -0,0 +1,113 @@
+// Copyright (c) 2025 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package consumer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/transport"
+)
+
+// splitChunks splits the payload into the chunks of the size as the producer does
+func splitChunks(id string, payload []byte, chunkSize int) []*messageChunk {
+	count := (len(payload) + chunkSize - 1) / chunkSize
+	checksum := transport.ChunkChecksum(payload)
+	chunks := []*messageChunk{}
+	for offset := 0; offset < len(payload); offset += chunkSize {
+		end := min(offset+chunkSize, len(payload))
+		chunks = append(chunks, &messageChunk{
+			id:       id,
+			offset:   end,
+			size:     len(payload),
+			count:    count,
+			checksum: checksum,
+			bytes:    payload[offset:end],
+		})
+	}
+	return chunks
+}
+
+func TestMessageAssembler(t *testing.T) {
+	payload := []byte(`{"message":"Hello, World!"}`)
+
+	t.Run("assemble the chunks out of order", func(t *testing.T) {
+		assembler := newMessageAssembler()
+		chunks := splitChunks("1", payload, 5)
+		for i := len(chunks) - 1; i > 0; i-- {
+			require.Nil(t, assembler.assemble(chunks[i]))
+		}
+		require.Equal(t, payload, assembler.assemble(chunks[0]))
+		require.Empty(t, assembler.chunkCollectionMap)
+		require.Equal(t, 0, assembler.accumulatedBytes)
+	})
+
+	t.Run("drop the corrupt payload", func(t *testing.T) {
+		assembler := newMessageAssembler()
+		chunks := splitChunks("2", payload, 5)
+		chunks[1].bytes = []byte("xxxxx")
+		var assembled []byte
+		for _, chunk := range chunks {
+			assembled = assembler.assemble(chunk)
+		}
+		require.Nil(t, assembled)
+		require.Empty(t, assembler.chunkCollectionMap)
+	})
+
+	t.Run("replace the chunks of the different bundle with the same id", func(t *testing.T) {
+		assembler := newMessageAssembler()
+		require.Nil(t, assembler.assemble(splitChunks("3", []byte(`{"message":"stale"}`), 5)[0]))
+
+		var assembled []byte
+		for _, chunk := range splitChunks("3", payload, 5) {
+			assembled = assembler.assemble(chunk)
+		}
+		require.Equal(t, payload, assembled)
+	})
+
+	t.Run("evict the expired chunks", func(t *testing.T) {
+		now := time.Now()
+		assembler := newMessageAssembler()
+		assembler.now = func() time.Time { return now }
+		require.Nil(t, assembler.assemble(splitChunks("4", payload, 5)[0]))
+		require.Len(t, assembler.chunkCollectionMap, 1)
+
+		now = now.Add(DefaultChunkCollectionMaxAge + time.Second)
+		require.Nil(t, assembler.assemble(splitChunks("5", payload, 5)[0]))
+		require.Len(t, assembler.chunkCollectionMap, 1)
+		require.NotNil(t, assembler.chunkCollectionMap["5"])
+
+		// evict the expired chunks on the timer without receiving any more chunks
+		now = now.Add(DefaultChunkCollectionMaxAge + time.Second)
+		assembler.evict()
+		require.Empty(t, assembler.chunkCollectionMap)
+		require.Equal(t, 0, assembler.accumulatedBytes)
+	})
+
+	t.Run("evict the oldest chunks over the memory budget", func(t *testing.T) {
+		now := time.Now()
+		assembler := newMessageAssembler()
+		assembler.now = func() time.Time { return now }
+		assembler.maxBytes = 12
+
+		require.Nil(t, assembler.assemble(splitChunks("6", payload, 10)[0]))
+		now = now.Add(time.Second)
+		require.Nil(t, assembler.assemble(splitChunks("7", payload, 10)[0]))
+		require.Len(t, assembler.chunkCollectionMap, 1)
+		require.NotNil(t, assembler.chunkCollectionMap["7"])
+		require.Equal(t, 10, assembler.accumulatedBytes)
+	})
+
+	t.Run("assemble the chunks without count and checksum", func(t *testing.T) {
+		assembler := newMessageAssembler()
+		var assembled []byte
+		for _, chunk := range splitChunks("8", payload, 5) {
+			chunk.count, chunk.checksum = 0, ""
+			assembled = assembler.assemble(chunk)
+		}
+		require.Equal(t, payload, assembled)
+	})
+}
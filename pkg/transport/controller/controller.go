@@ -110,6 +110,15 @@ func (c *TransportCtrl) Reconcile(ctx context.Context, request ctrl.Request) (ct
 		c.transportConfig.TransportType = string(transport.Rest)
 	}
 
+	_, isMqtt := secret.Data["mqtt.yaml"]
+	if isMqtt && isKafka {
+		return ctrl.Result{}, fmt.Errorf("the transport secret %s must not configure both kafka.yaml and mqtt.yaml",
+			c.secretName)
+	}
+	if isMqtt {
+		c.transportConfig.TransportType = string(transport.Mqtt)
+	}
+
 	var updated bool
 	var err error
 	switch c.transportConfig.TransportType {
@@ -122,7 +131,20 @@ func (c *TransportCtrl) Reconcile(ctx context.Context, request ctrl.Request) (ct
 			if err := c.ReconcileConsumer(ctx); err != nil {
 				return ctrl.Result{}, err
 			}
-			if err := c.ReconcileProducer(); err != nil {
+			if err := c.ReconcileProducer(ctx); err != nil {
+				return ctrl.Result{}, err
+			}
+		}
+	case string(transport.Mqtt):
+		updated, err = c.ReconcileMqttCredential(ctx, secret)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if updated {
+			if err := c.ReconcileConsumer(ctx); err != nil {
+				return ctrl.Result{}, err
+			}
+			if err := c.ReconcileProducer(ctx); err != nil {
 				return ctrl.Result{}, err
 			}
 		}
@@ -154,9 +176,9 @@ func (c *TransportCtrl) Reconcile(ctx context.Context, request ctrl.Request) (ct
 }
 
 // ReconcileProducer, transport config is changed, then create/update the producer
-func (c *TransportCtrl) ReconcileProducer() error {
+func (c *TransportCtrl) ReconcileProducer(ctx context.Context) error {
 	if c.transportClient.producer == nil {
-		sender, err := producer.NewGenericProducer(c.transportConfig)
+		sender, err := producer.NewGenericProducer(ctx, c.transportConfig)
 		if err != nil {
 			return fmt.Errorf("failed to create/update the producer: %w", err)
 		}
@@ -306,6 +328,29 @@ func (c *TransportCtrl) ReconcileRestfulCredential(ctx context.Context, secret *
 	return
 }
 
+// ReconcileMqttCredential update the mqtt connection credential based on the secret, return true if the mqtt
+// credential is updated
+func (c *TransportCtrl) ReconcileMqttCredential(ctx context.Context, secret *corev1.Secret) (bool, error) {
+	mqttConn, err := config.GetMqttCredentialBySecret(secret, c.runtimeClient)
+	if err != nil {
+		return false, err
+	}
+
+	// update the wathing secret lits
+	if mqttConn.CASecretName != "" && !utils.ContainsString(c.extraSecretNames, mqttConn.CASecretName) {
+		c.extraSecretNames = append(c.extraSecretNames, mqttConn.CASecretName)
+	}
+	if mqttConn.ClientSecretName != "" && !utils.ContainsString(c.extraSecretNames, mqttConn.ClientSecretName) {
+		c.extraSecretNames = append(c.extraSecretNames, mqttConn.ClientSecretName)
+	}
+
+	if reflect.DeepEqual(c.transportConfig.MqttCredential, mqttConn) {
+		return false, nil
+	}
+	c.transportConfig.MqttCredential = mqttConn
+	return true, nil
+}
+
 // SetupWithManager sets up the controller with the Manager.
 func (c *TransportCtrl) SetupWithManager(mgr ctrl.Manager) error {
 	c.runtimeClient = mgr.GetClient()
@@ -163,6 +163,64 @@ func TestInventorySecretCtrlReconcile(t *testing.T) {
 	assert.False(t, result.Requeue)
 }
 
+func TestMqttSecretCtrlReconcile(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)
+
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).Build()
+
+	secretController := &TransportCtrl{
+		secretNamespace: "default",
+		secretName:      "test-secret",
+		transportConfig: &transport.TransportInternalConfig{
+			ConsumerGroupId:  "hub1",
+			FailureThreshold: 100,
+		},
+		transportClient: &TransportClient{},
+		runtimeClient:   fakeClient,
+	}
+
+	ctx := context.TODO()
+
+	mqttConn := &transport.MqttConfig{
+		// nothing is listening on the port, so the connection is refused
+		BrokerHost:  "127.0.0.1:1",
+		SpecTopic:   "gh-spec",
+		StatusTopic: "gh-status/hub1",
+	}
+	mqttConnYaml, err := mqttConn.YamlMarshal(true)
+	assert.NoError(t, err)
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      "test-secret",
+		},
+		Data: map[string][]byte{
+			"mqtt.yaml": mqttConnYaml,
+		},
+	}
+	_ = fakeClient.Create(ctx, secret)
+
+	req := reconcile.Request{
+		NamespacedName: types.NamespacedName{
+			Namespace: "default",
+			Name:      "test-secret",
+		},
+	}
+	_, err = secretController.Reconcile(ctx, req)
+	assert.ErrorContains(t, err, "failed to connect to the mqtt broker")
+	assert.Equal(t, string(transport.Mqtt), secretController.transportConfig.TransportType)
+	assert.Equal(t, "gh-status/hub1", secretController.transportConfig.MqttCredential.StatusTopic)
+	assert.Nil(t, secretController.transportClient.producer)
+
+	// the secret must not configure both the kafka and the mqtt
+	secret.Data["kafka.yaml"] = []byte("bootstrap.server: 127.0.0.1:9092")
+	assert.NoError(t, fakeClient.Update(ctx, secret))
+	_, err = secretController.Reconcile(ctx, req)
+	assert.ErrorContains(t, err, "must not configure both kafka.yaml and mqtt.yaml")
+}
+
 var rootPEM = []byte(`
 -- GlobalSign Root R2, valid until Dec 15, 2021
 -----BEGIN CERTIFICATE-----
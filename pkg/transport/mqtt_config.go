@@ -0,0 +1,80 @@
+package transport
+
+import "sigs.k8s.io/kustomize/kyaml/yaml"
+
+// MqttConfig is used to connect the mqtt broker. The field is persisted to the `mqtt.yaml` of the transport secret
+type MqttConfig struct {
+	// BrokerHost is the address of the broker, e.g. "mqtt.example.com:8883"
+	BrokerHost       string `yaml:"broker.host"`
+	StatusTopic      string `yaml:"topic.status,omitempty"`
+	SpecTopic        string `yaml:"topic.spec,omitempty"`
+	QoS              byte   `yaml:"qos,omitempty"`
+	KeepAlive        uint16 `yaml:"keepAlive,omitempty"`
+	CACert           string `yaml:"ca.crt,omitempty"`
+	ClientCert       string `yaml:"client.crt,omitempty"`
+	ClientKey        string `yaml:"client.key,omitempty"`
+	CASecretName     string `yaml:"ca.secret,omitempty"`
+	ClientSecretName string `yaml:"client.secret,omitempty"`
+}
+
+// YamlMarshal marshal the connection credential object, rawCert specifies whether to keep the cert in the data directly
+func (k *MqttConfig) YamlMarshal(rawCert bool) ([]byte, error) {
+	copy := k.DeepCopy()
+	if rawCert {
+		copy.CASecretName = ""
+		copy.ClientSecretName = ""
+	} else {
+		copy.CACert = ""
+		copy.ClientCert = ""
+		copy.ClientKey = ""
+	}
+	bytes, err := yaml.Marshal(copy)
+	return bytes, err
+}
+
+func (k *MqttConfig) DeepCopy() *MqttConfig {
+	return &MqttConfig{
+		BrokerHost:       k.BrokerHost,
+		StatusTopic:      k.StatusTopic,
+		SpecTopic:        k.SpecTopic,
+		QoS:              k.QoS,
+		KeepAlive:        k.KeepAlive,
+		CACert:           k.CACert,
+		ClientCert:       k.ClientCert,
+		ClientKey:        k.ClientKey,
+		CASecretName:     k.CASecretName,
+		ClientSecretName: k.ClientSecretName,
+	}
+}
+
+func (k *MqttConfig) GetCACert() string {
+	return k.CACert
+}
+
+func (k *MqttConfig) SetCACert(cert string) {
+	k.CACert = cert
+}
+
+func (k *MqttConfig) GetClientCert() string {
+	return k.ClientCert
+}
+
+func (k *MqttConfig) SetClientCert(cert string) {
+	k.ClientCert = cert
+}
+
+func (k *MqttConfig) GetClientKey() string {
+	return k.ClientKey
+}
+
+func (k *MqttConfig) SetClientKey(key string) {
+	k.ClientKey = key
+}
+
+func (k *MqttConfig) GetCASecretName() string {
+	return k.CASecretName
+}
+
+func (k *MqttConfig) GetClientSecretName() string {
+	return k.ClientSecretName
+}
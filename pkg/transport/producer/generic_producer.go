@@ -6,25 +6,29 @@ package producer
 import (
 	"context"
 	"fmt"
+	"sync/atomic"
 	"time"
 
 	kafka_confluent "github.com/cloudevents/sdk-go/protocol/kafka_confluent/v2"
 	"github.com/cloudevents/sdk-go/protocol/kafka_sarama/v2"
 	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/cloudevents/sdk-go/v2/binding"
 	cectx "github.com/cloudevents/sdk-go/v2/context"
 	"github.com/cloudevents/sdk-go/v2/protocol"
-	"github.com/cloudevents/sdk-go/v2/protocol/gochan"
 	"github.com/confluentinc/confluent-kafka-go/v2/kafka"
 	"go.uber.org/zap"
 
+	"github.com/stolostron/multicluster-global-hub/pkg/compressor"
 	"github.com/stolostron/multicluster-global-hub/pkg/logger"
 	"github.com/stolostron/multicluster-global-hub/pkg/transport"
+	"github.com/stolostron/multicluster-global-hub/pkg/transport/backend"
 	"github.com/stolostron/multicluster-global-hub/pkg/transport/config"
 )
 
 const (
 	MaxMessageKBLimit    = 1024
 	DefaultMessageKBSize = 960
+	outboxReplayInterval = 10 * time.Second
 )
 
 type GenericProducer struct {
@@ -32,18 +36,42 @@ type GenericProducer struct {
 	ceProtocol       interface{}
 	ceClient         cloudevents.Client
 	messageSizeLimit int
+	compressor       compressor.Compressor
+
+	// outbox holds the undelivered events, it's nil if the outbox isn't enabled
+	outbox *outbox
+	// healthy indicates whether the latest delivery succeeded, the outbox only replays one entry as a probe until
+	// the transport is healthy again
+	healthy atomic.Bool
+	// asyncDelivery means the delivery result is reported by the events channel(kafka), instead of the send result
+	asyncDelivery bool
 }
 
-func NewGenericProducer(transportConfig *transport.TransportInternalConfig) (*GenericProducer, error) {
+// NewGenericProducer creates the producer, the outbox replaying is stopped once the ctx is done
+func NewGenericProducer(ctx context.Context, transportConfig *transport.TransportInternalConfig,
+) (*GenericProducer, error) {
 	genericProducer := &GenericProducer{
 		log:              logger.ZapLogger(fmt.Sprintf("%s-producer", transportConfig.TransportType)),
 		messageSizeLimit: DefaultMessageKBSize * 1000,
 	}
+	genericProducer.healthy.Store(true)
+
+	if transportConfig.OutboxDir != "" {
+		ob, err := newOutbox(transportConfig.OutboxDir, transportConfig.OutboxMaxSizeKB)
+		if err != nil {
+			return nil, err
+		}
+		genericProducer.outbox = ob
+	}
+
 	err := genericProducer.initClient(transportConfig)
 	if err != nil {
 		return nil, err
 	}
 
+	if genericProducer.outbox != nil {
+		go genericProducer.replayOutboxPeriodically(ctx)
+	}
 	return genericProducer, nil
 }
 
@@ -56,28 +84,86 @@ func (p *GenericProducer) SendEvent(ctx context.Context, evt cloudevents.Event)
 	}
 
 	// data
-	payloadBytes := evt.Data()
+	payloadBytes, err := p.compressPayload(&evt)
+	if err != nil {
+		return err
+	}
+	evts, err := p.chunkEvents(evt, payloadBytes)
+	if err != nil {
+		return err
+	}
+
+	// keep the order with the spilled events, they are replayed before the new ones once the transport recovers
+	if p.outbox != nil && p.outbox.len() > 0 {
+		return p.spill(cectx.TopicFrom(ctx), evts...)
+	}
+
+	for i, e := range evts {
+		if result := p.ceClient.Send(evtCtx, e); cloudevents.IsUndelivered(result) {
+			if p.outbox == nil {
+				return fmt.Errorf("failed to send events to transport: %v", result)
+			}
+			p.log.Warnw("failed to send event to transport, spill it to the outbox", "type", e.Type(),
+				"error", result)
+			p.setHealthy(false)
+			return p.spill(cectx.TopicFrom(ctx), evts[i:]...)
+		}
+		if !p.asyncDelivery {
+			p.setHealthy(true)
+		}
+	}
+	return nil
+}
+
+// chunkEvents splits the event into multiple events if the payload exceeds the message size limit, each of them
+// carries a chunk of the payload with the total size, offset, count and checksum extensions
+func (p *GenericProducer) chunkEvents(evt cloudevents.Event, payloadBytes []byte) ([]cloudevents.Event, error) {
 	chunks := p.splitPayloadIntoChunks(payloadBytes)
 	if len(chunks) <= 1 {
-		if ret := p.ceClient.Send(evtCtx, evt); cloudevents.IsUndelivered(ret) {
-			return fmt.Errorf("failed to send event to transport: %v", ret)
-		}
-		return nil
+		return []cloudevents.Event{evt}, nil
 	}
 
+	// clone the event without the data as the template of the chunks
+	template := evt.Clone()
+	template.DataEncoded = nil
+
+	// the consumer verifies the assembled payload with the checksum and the number of the chunks
+	checksum := transport.ChunkChecksum(payloadBytes)
+
+	evts := make([]cloudevents.Event, 0, len(chunks))
 	chunkOffset := 0
 	for _, chunk := range chunks {
-		evt.SetExtension(transport.ChunkSizeKey, len(payloadBytes))
+		chunkEvt := template.Clone()
+		chunkEvt.SetExtension(transport.ChunkSizeKey, len(payloadBytes))
+		chunkEvt.SetExtension(transport.ChunkCountKey, len(chunks))
+		chunkEvt.SetExtension(transport.ChunkChecksumKey, checksum)
 		chunkOffset += len(chunk)
-		evt.SetExtension(transport.ChunkOffsetKey, chunkOffset)
-		if err := evt.SetData(cloudevents.ApplicationJSON, chunk); err != nil {
-			return fmt.Errorf("failed to set cloudevents data: %v", evt)
-		}
-		if result := p.ceClient.Send(evtCtx, evt); cloudevents.IsUndelivered(result) {
-			return fmt.Errorf("failed to send events to transport: %v", result)
+		chunkEvt.SetExtension(transport.ChunkOffsetKey, chunkOffset)
+		if err := chunkEvt.SetData(cloudevents.ApplicationJSON, chunk); err != nil {
+			return nil, fmt.Errorf("failed to set cloudevents data: %v", evt)
 		}
+		evts = append(evts, chunkEvt)
 	}
-	return nil
+	return evts, nil
+}
+
+// compressPayload compresses the event data and records the compression type into the event extension, so that the
+// consumer can decompress it after the chunks are assembled
+func (p *GenericProducer) compressPayload(evt *cloudevents.Event) ([]byte, error) {
+	if p.compressor == nil || p.compressor.GetType() == string(compressor.NoOp) {
+		return evt.Data(), nil
+	}
+	compressedBytes, err := p.compressor.Compress(evt.Data())
+	if err != nil {
+		return nil, fmt.Errorf("failed to compress the event data with %s: %w", p.compressor.GetType(), err)
+	}
+	// the event context is shared with the caller, clone it to avoid the caller seeing the compressed data
+	*evt = evt.Clone()
+	if err := evt.SetData(cloudevents.ApplicationJSON, compressedBytes); err != nil {
+		return nil, fmt.Errorf("failed to set the compressed cloudevents data: %w", err)
+	}
+	evt.SetExtension(transport.CompressionKey, p.compressor.GetType())
+	return compressedBytes, nil
 }
 
 // Reconnect close the previous producer state and init a new producer
@@ -94,41 +180,41 @@ func (p *GenericProducer) Reconnect(config *transport.TransportInternalConfig) e
 
 // initClient will init/update the client, clientProtocol and messageLimitSize based on the transportConfig
 func (p *GenericProducer) initClient(transportConfig *transport.TransportInternalConfig) error {
-	topic := ""
-	if transportConfig.TransportType == string(transport.Kafka) ||
-		transportConfig.TransportType == string(transport.Chan) {
-		topic = transportConfig.KafkaCredential.SpecTopic
-		if !transportConfig.IsManager {
-			topic = transportConfig.KafkaCredential.StatusTopic
-		}
+	compressionType := transportConfig.CompressionType
+	if compressionType == "" {
+		compressionType = compressor.NoOp
+	}
+	eventCompressor, err := compressor.NewCompressor(compressionType)
+	if err != nil {
+		return fmt.Errorf("failed to init the compressor %s: %w", compressionType, err)
 	}
+	p.compressor = eventCompressor
 
-	switch transportConfig.TransportType {
-	case string(transport.Kafka):
-		kafkaProtocol, err := getConfluentSenderProtocol(transportConfig.KafkaCredential, topic)
-		if err != nil {
-			return err
-		}
+	ceBackend, err := backend.Get(transportConfig.TransportType)
+	if err != nil {
+		return err
+	}
+	p.ceProtocol, err = ceBackend.NewSender(transportConfig)
+	if err != nil {
+		return err
+	}
 
+	// the delivery reports of the confluent kafka client must be read, otherwise the events channel will fill up
+	p.asyncDelivery = false
+	if kafkaProtocol, ok := p.ceProtocol.(*kafka_confluent.Protocol); ok {
 		eventChan, err := kafkaProtocol.Events()
 		if err != nil {
 			return err
 		}
-		handleProducerEvents(p.log, eventChan, transportConfig.FailureThreshold)
-		p.ceProtocol = kafkaProtocol
-	case string(transport.Chan):
-		if transportConfig.Extends == nil {
-			transportConfig.Extends = make(map[string]interface{})
+		var reporter deliveryReporter
+		if p.outbox != nil {
+			reporter = p
 		}
-		if _, found := transportConfig.Extends[topic]; !found {
-			transportConfig.Extends[topic] = gochan.New()
-		}
-		p.ceProtocol = transportConfig.Extends[topic]
-	default:
-		return fmt.Errorf("transport-type - %s is not a valid option", transportConfig.TransportType)
+		handleProducerEvents(p.log, eventChan, transportConfig.FailureThreshold, reporter)
+		p.asyncDelivery = true
 	}
 
-	// kafka or gochan protocol
+	// kafka, mqtt or gochan protocol
 	if p.ceProtocol != nil {
 		client, err := cloudevents.NewClient(p.ceProtocol, cloudevents.WithTimeNow(), cloudevents.WithUUIDs())
 		if err != nil {
@@ -172,17 +258,15 @@ func getSaramaSenderProtocol(kafkaConfig *transport.KafkaInternalConfig, default
 	return sender, nil
 }
 
-func getConfluentSenderProtocol(kafkaCredentail *transport.KafkaConfig,
-	defaultTopic string,
-) (*kafka_confluent.Protocol, error) {
-	configMap, err := config.GetConfluentConfigMapByKafkaCredential(kafkaCredentail, "")
-	if err != nil {
-		return nil, err
-	}
-	return kafka_confluent.New(kafka_confluent.WithConfigMap(configMap), kafka_confluent.WithSenderTopic(defaultTopic))
+// deliveryReporter receives the delivery reports of the kafka producer
+type deliveryReporter interface {
+	delivered()
+	undelivered(msg *kafka.Message)
 }
 
-func handleProducerEvents(log *zap.SugaredLogger, eventChan chan kafka.Event, transportFailureThreshold int) {
+func handleProducerEvents(log *zap.SugaredLogger, eventChan chan kafka.Event, transportFailureThreshold int,
+	reporter deliveryReporter,
+) {
 	// Listen to all the events on the default events channel
 	// It's important to read these events otherwise the events channel will eventually fill up
 	go func() {
@@ -198,6 +282,11 @@ func handleProducerEvents(log *zap.SugaredLogger, eventChan chan kafka.Event, tr
 				m := ev
 				if m.TopicPartition.Error != nil {
 					log.Warnw("delivery failed", "error", m.TopicPartition.Error)
+					if reporter != nil {
+						reporter.undelivered(m)
+					}
+				} else if reporter != nil {
+					reporter.delivered()
 				}
 			case kafka.Error:
 				// Generic client instance-level errors, such as
@@ -216,6 +305,10 @@ func handleProducerEvents(log *zap.SugaredLogger, eventChan chan kafka.Event, tr
 				} else {
 					log.Warnw("transport producer client error", "error", ev)
 
+					// the undelivered events are spilled to the outbox, restarting the pod doesn't help to deliver them
+					if reporter != nil {
+						continue
+					}
 					errorCount++
 					if errorCount >= transportFailureThreshold {
 						log.Panicf("transport producer error > 10 in 5 minutes, error: %v", ev)
@@ -230,3 +323,101 @@ func handleProducerEvents(log *zap.SugaredLogger, eventChan chan kafka.Event, tr
 		}
 	}()
 }
+
+func (p *GenericProducer) setHealthy(healthy bool) {
+	if p.healthy.Swap(healthy) != healthy {
+		p.log.Infow("transport producer delivery state changed", "healthy", healthy)
+	}
+}
+
+func (p *GenericProducer) delivered() {
+	p.setHealthy(true)
+}
+
+// undelivered spills the event of the failed kafka message to the outbox
+func (p *GenericProducer) undelivered(msg *kafka.Message) {
+	p.setHealthy(false)
+	evt, err := eventFromKafkaMessage(msg)
+	if err != nil {
+		p.log.Errorw("failed to convert the undelivered message to event", "error", err)
+		return
+	}
+	if err := p.outbox.add(*msg.TopicPartition.Topic, *evt); err != nil {
+		p.log.Errorw("failed to spill the undelivered event to the outbox", "type", evt.Type(), "error", err)
+	}
+}
+
+func (p *GenericProducer) spill(topic string, evts ...cloudevents.Event) error {
+	if err := p.outbox.add(topic, evts...); err != nil {
+		return fmt.Errorf("failed to spill the events to the outbox: %w", err)
+	}
+	return nil
+}
+
+func (p *GenericProducer) replayOutboxPeriodically(ctx context.Context) {
+	ticker := time.NewTicker(outboxReplayInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			p.replayOutbox()
+		}
+	}
+}
+
+// replayOutbox sends the spilled events in order, it stops at the first failure. If the transport isn't healthy, it
+// only replays the oldest entry to probe whether the transport is recovered.
+func (p *GenericProducer) replayOutbox() {
+	for {
+		entry := p.outbox.peek()
+		if entry == nil {
+			return
+		}
+
+		ctx := cectx.WithLogger(context.Background(), logger.ZapLogger("cloudevents"))
+		ctx = kafka_confluent.WithMessageKey(ctx, entry.Events[0].Type())
+		if entry.Topic != "" {
+			ctx = cectx.WithTopic(ctx, entry.Topic)
+		}
+		for _, evt := range entry.Events {
+			if result := p.ceClient.Send(ctx, evt); cloudevents.IsUndelivered(result) {
+				p.log.Debugw("failed to replay the outbox", "key", entry.Key, "error", result)
+				p.setHealthy(false)
+				return
+			}
+		}
+		p.outbox.remove(entry)
+		p.log.Debugw("replayed the outbox entry", "key", entry.Key, "events", len(entry.Events))
+
+		if !p.asyncDelivery {
+			p.setHealthy(true)
+		}
+		if !p.healthy.Load() {
+			return
+		}
+	}
+}
+
+// eventFromKafkaMessage converts the message back to the cloudevent, the kafka position extensions are removed since
+// the message isn't delivered
+func eventFromKafkaMessage(m *kafka.Message) (*cloudevents.Event, error) {
+	if m.TopicPartition.Topic == nil {
+		return nil, fmt.Errorf("the topic of the message must not be nil")
+	}
+	msg := *m
+	msg.TopicPartition.Partition = 0
+	msg.TopicPartition.Offset = 0
+	evt, err := binding.ToEvent(context.Background(), kafka_confluent.NewMessage(&msg))
+	if err != nil {
+		return nil, err
+	}
+	for _, key := range []string{
+		kafka_confluent.KafkaTopicKey, kafka_confluent.KafkaPartitionKey,
+		kafka_confluent.KafkaOffsetKey, kafka_confluent.KafkaMessageKey,
+	} {
+		evt.SetExtension(key, nil)
+	}
+	return evt, nil
+}
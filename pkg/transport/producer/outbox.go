@@ -0,0 +1,297 @@
+// Copyright (c) 2025 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package producer
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/cloudevents/sdk-go/v2/types"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/constants"
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
+	"github.com/stolostron/multicluster-global-hub/pkg/logger"
+	"github.com/stolostron/multicluster-global-hub/pkg/transport"
+)
+
+const (
+	outboxFileSuffix    = ".json"
+	outboxTmpFileSuffix = ".tmp"
+	// DefaultOutboxMaxSizeKB is the default bound of the outbox on the disk
+	DefaultOutboxMaxSizeKB = 64 * 1024
+)
+
+var (
+	outboxBacklogEvents = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "multicluster_global_hub_transport_outbox_backlog_events",
+		Help: "The number of the undelivered events spilled to the transport outbox.",
+	})
+	outboxBacklogBytes = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "multicluster_global_hub_transport_outbox_backlog_bytes",
+		Help: "The size of the undelivered events spilled to the transport outbox.",
+	})
+	outboxDroppedEvents = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "multicluster_global_hub_transport_outbox_dropped_events_total",
+		Help: "The number of the events dropped from the transport outbox since it exceeds the size bound.",
+	})
+)
+
+// RegisterMetrics will register the outbox metrics with the global prometheus registry
+func RegisterMetrics() {
+	metrics.Registry.MustRegister(outboxBacklogEvents, outboxBacklogBytes, outboxDroppedEvents)
+}
+
+// outboxEntry is the undelivered events of the same key. The events are the chunks of the same bundle, they are
+// replaced once a newer complete state bundle with the same key(topic, event type and cluster name) is spilled.
+type outboxEntry struct {
+	Seq    uint64              `json:"seq"`
+	Key    string              `json:"key"`
+	Topic  string              `json:"topic,omitempty"`
+	Events []cloudevents.Event `json:"events"`
+
+	size int64
+}
+
+// outbox persists the undelivered events into the directory, one file per entry, the file name is the sequence of the
+// entry, so that the entries can be replayed in order after the agent restarted.
+type outbox struct {
+	log      *zap.SugaredLogger
+	dir      string
+	maxBytes int64
+
+	mutex   sync.Mutex
+	seq     uint64
+	entries map[string]*outboxEntry
+	bytes   int64
+}
+
+func newOutbox(dir string, maxSizeKB int) (*outbox, error) {
+	if maxSizeKB <= 0 {
+		maxSizeKB = DefaultOutboxMaxSizeKB
+	}
+	o := &outbox{
+		log:      logger.ZapLogger("transport-outbox"),
+		dir:      dir,
+		maxBytes: int64(maxSizeKB) * 1024,
+		entries:  make(map[string]*outboxEntry),
+	}
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, fmt.Errorf("failed to create the outbox directory %s: %w", dir, err)
+	}
+	if err := o.load(); err != nil {
+		return nil, err
+	}
+	return o, nil
+}
+
+// load restores the entries persisted by the previous process
+func (o *outbox) load() error {
+	files, err := os.ReadDir(o.dir)
+	if err != nil {
+		return fmt.Errorf("failed to read the outbox directory %s: %w", o.dir, err)
+	}
+	for _, file := range files {
+		filePath := filepath.Join(o.dir, file.Name())
+		if strings.HasSuffix(file.Name(), outboxTmpFileSuffix) {
+			_ = os.Remove(filePath)
+			continue
+		}
+		if !strings.HasSuffix(file.Name(), outboxFileSuffix) {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Clean(filePath))
+		if err != nil {
+			return fmt.Errorf("failed to read the outbox file %s: %w", filePath, err)
+		}
+		entry := &outboxEntry{}
+		if err := json.Unmarshal(data, entry); err != nil {
+			o.log.Warnw("drop the corrupted outbox file", "file", filePath, "error", err)
+			_ = os.Remove(filePath)
+			continue
+		}
+		entry.size = int64(len(data))
+		if previous, found := o.entries[entry.Key]; found {
+			// keep the latest one if the previous process crashed before removing the replaced entry
+			if previous.Seq > entry.Seq {
+				_ = os.Remove(filePath)
+				continue
+			}
+			o.removeUnsafe(previous)
+		}
+		o.entries[entry.Key] = entry
+		o.bytes += entry.size
+		if entry.Seq > o.seq {
+			o.seq = entry.Seq
+		}
+	}
+	o.updateMetricsUnsafe()
+	if len(o.entries) > 0 {
+		o.log.Infow("restored the outbox", "entries", len(o.entries), "bytes", o.bytes)
+	}
+	return nil
+}
+
+// add spills the events into the outbox. The events with the same key and event id are the chunks of the same bundle,
+// then they are appended to the existing entry, otherwise the entry is replaced by the new events.
+func (o *outbox) add(topic string, evts ...cloudevents.Event) error {
+	if len(evts) == 0 {
+		return nil
+	}
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	key := outboxKey(topic, evts[0])
+	entry := &outboxEntry{Key: key, Topic: topic}
+	previous, found := o.entries[key]
+	if found && previous.Events[0].ID() == evts[0].ID() {
+		entry.Events = append(entry.Events, previous.Events...)
+	}
+	for _, evt := range evts {
+		if !containsChunk(entry.Events, evt) {
+			entry.Events = append(entry.Events, persistableEvent(evt))
+		}
+	}
+
+	o.seq++
+	entry.Seq = o.seq
+	if err := o.writeUnsafe(entry); err != nil {
+		return err
+	}
+	if found {
+		o.removeUnsafe(previous)
+	}
+	o.entries[key] = entry
+	o.bytes += entry.size
+
+	// drop the oldest entries once the outbox exceeds the bound, except the latest one
+	for o.bytes > o.maxBytes && len(o.entries) > 1 {
+		oldest := o.oldestUnsafe()
+		o.log.Warnw("drop the oldest outbox entry since the outbox is full", "key", oldest.Key,
+			"events", len(oldest.Events), "maxBytes", o.maxBytes)
+		outboxDroppedEvents.Add(float64(len(oldest.Events)))
+		o.removeUnsafe(oldest)
+	}
+	o.updateMetricsUnsafe()
+	return nil
+}
+
+// peek returns the oldest entry of the outbox, return nil if it's empty
+func (o *outbox) peek() *outboxEntry {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+	return o.oldestUnsafe()
+}
+
+// remove deletes the replayed entry, the entry might be replaced by the newer one during the replaying, then the newer
+// one is kept
+func (o *outbox) remove(entry *outboxEntry) {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+	if current, found := o.entries[entry.Key]; found && current.Seq == entry.Seq {
+		o.removeUnsafe(current)
+		o.updateMetricsUnsafe()
+	}
+}
+
+func (o *outbox) len() int {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+	return len(o.entries)
+}
+
+func (o *outbox) oldestUnsafe() *outboxEntry {
+	var oldest *outboxEntry
+	for _, entry := range o.entries {
+		if oldest == nil || entry.Seq < oldest.Seq {
+			oldest = entry
+		}
+	}
+	return oldest
+}
+
+func (o *outbox) writeUnsafe(entry *outboxEntry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to marshal the outbox entry %s: %w", entry.Key, err)
+	}
+	filePath := o.filePath(entry)
+	tmpPath := filePath + outboxTmpFileSuffix
+	if err := os.WriteFile(tmpPath, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write the outbox file %s: %w", tmpPath, err)
+	}
+	if err := os.Rename(tmpPath, filePath); err != nil {
+		return fmt.Errorf("failed to rename the outbox file %s: %w", tmpPath, err)
+	}
+	entry.size = int64(len(data))
+	return nil
+}
+
+func (o *outbox) removeUnsafe(entry *outboxEntry) {
+	if err := os.Remove(o.filePath(entry)); err != nil && !os.IsNotExist(err) {
+		o.log.Warnw("failed to remove the outbox file", "key", entry.Key, "error", err)
+	}
+	delete(o.entries, entry.Key)
+	o.bytes -= entry.size
+}
+
+func (o *outbox) updateMetricsUnsafe() {
+	count := 0
+	for _, entry := range o.entries {
+		count += len(entry.Events)
+	}
+	outboxBacklogEvents.Set(float64(count))
+	outboxBacklogBytes.Set(float64(o.bytes))
+}
+
+func (o *outbox) filePath(entry *outboxEntry) string {
+	return filepath.Join(o.dir, fmt.Sprintf("%020d%s", entry.Seq, outboxFileSuffix))
+}
+
+// outboxKey is the coalescing key of the event, the manager sends the event of the same type to different clusters.
+// Only the complete state bundles are coalesced, the others(delta, events, ...) are keyed by the event id, so that
+// each of them is kept and replayed in order.
+func outboxKey(topic string, evt cloudevents.Event) string {
+	key := topic + "/" + evt.Type()
+	if clusterName, err := types.ToString(evt.Extensions()[constants.CloudEventExtensionKeyClusterName]); err == nil {
+		key = key + "/" + clusterName
+	}
+	if !enum.IsCompleteStateType(evt.Type()) {
+		key = key + "/" + evt.ID()
+	}
+	return key
+}
+
+// persistableEvent makes the data is marshaled with base64, since the compressed or chunked data isn't a valid json
+func persistableEvent(evt cloudevents.Event) cloudevents.Event {
+	cloned := evt.Clone()
+	cloned.DataBase64 = true
+	return cloned
+}
+
+func containsChunk(evts []cloudevents.Event, evt cloudevents.Event) bool {
+	offset := chunkOffset(evt)
+	for _, e := range evts {
+		if chunkOffset(e) == offset {
+			return true
+		}
+	}
+	return false
+}
+
+func chunkOffset(evt cloudevents.Event) string {
+	offset, err := types.ToInteger(evt.Extensions()[transport.ChunkOffsetKey])
+	if err != nil {
+		return ""
+	}
+	return strconv.Itoa(int(offset))
+}
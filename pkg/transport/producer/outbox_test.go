@@ -0,0 +1,192 @@
+// Copyright (c) 2025 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package producer
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/cloudevents/sdk-go/v2/binding"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/constants"
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
+	"github.com/stolostron/multicluster-global-hub/pkg/logger"
+	"github.com/stolostron/multicluster-global-hub/pkg/transport"
+)
+
+func newOutboxEvent(t *testing.T, id, eventType, clusterName string, data string) cloudevents.Event {
+	evt := cloudevents.NewEvent()
+	evt.SetID(id)
+	evt.SetType(eventType)
+	evt.SetSource("hub1")
+	if clusterName != "" {
+		evt.SetExtension(constants.CloudEventExtensionKeyClusterName, clusterName)
+	}
+	require.NoError(t, evt.SetData(cloudevents.ApplicationJSON, []byte(data)))
+	return evt
+}
+
+var (
+	policyType  = string(enum.LocalComplianceType)
+	clusterType = string(enum.ManagedClusterType)
+	eventType   = string(enum.ManagedClusterEventType)
+)
+
+func TestOutbox(t *testing.T) {
+	dir := t.TempDir()
+	ob, err := newOutbox(dir, 0)
+	require.NoError(t, err)
+
+	// coalesce the complete state events with the same type
+	require.NoError(t, ob.add("status", newOutboxEvent(t, "1", policyType, "", `{"v":1}`)))
+	require.NoError(t, ob.add("status", newOutboxEvent(t, "2", clusterType, "", `{"v":2}`)))
+	require.NoError(t, ob.add("status", newOutboxEvent(t, "3", policyType, "", `{"v":3}`)))
+	require.Equal(t, 2, ob.len())
+
+	entry := ob.peek()
+	require.Equal(t, "status/"+clusterType, entry.Key)
+	ob.remove(entry)
+	entry = ob.peek()
+	require.Equal(t, "status/"+policyType, entry.Key)
+	require.Equal(t, "3", entry.Events[0].ID())
+	require.Equal(t, `{"v":3}`, string(entry.Events[0].Data()))
+
+	// the events of the different clusters aren't coalesced
+	require.NoError(t, ob.add("spec", newOutboxEvent(t, "4", policyType, "cluster1", `{}`)))
+	require.NoError(t, ob.add("spec", newOutboxEvent(t, "5", policyType, "cluster2", `{}`)))
+	require.Equal(t, 3, ob.len())
+
+	// the chunks of the same bundle are appended
+	chunk1 := newOutboxEvent(t, "6", "large", "", `{"a":`)
+	chunk1.SetExtension(transport.ChunkOffsetKey, 5)
+	chunk2 := newOutboxEvent(t, "6", "large", "", `1}`)
+	chunk2.SetExtension(transport.ChunkOffsetKey, 7)
+	require.NoError(t, ob.add("status", chunk1))
+	require.NoError(t, ob.add("status", chunk1, chunk2))
+	require.Equal(t, 4, ob.len())
+
+	// restore the entries from the disk
+	restored, err := newOutbox(dir, 0)
+	require.NoError(t, err)
+	require.Equal(t, 4, restored.len())
+	entry = restored.peek()
+	require.Equal(t, "status/"+policyType, entry.Key)
+	ob.remove(entry)
+	restored.remove(entry)
+	require.Equal(t, "spec/"+policyType+"/cluster1", restored.peek().Key)
+
+	for entry := restored.peek(); entry != nil; entry = restored.peek() {
+		if entry.Key == "status/large/6" {
+			require.Len(t, entry.Events, 2)
+			require.Equal(t, `1}`, string(entry.Events[1].Data()))
+		}
+		restored.remove(entry)
+	}
+	require.Equal(t, 0, restored.len())
+}
+
+func TestOutboxDeltaEvents(t *testing.T) {
+	ob, err := newOutbox(t.TempDir(), 0)
+	require.NoError(t, err)
+
+	// the delta events aren't coalesced, they're kept in order
+	require.NoError(t, ob.add("status", newOutboxEvent(t, "1", eventType, "", `{"v":1}`)))
+	require.NoError(t, ob.add("status", newOutboxEvent(t, "2", clusterType, "", `{"v":2}`)))
+	require.NoError(t, ob.add("status", newOutboxEvent(t, "3", eventType, "", `{"v":3}`)))
+	require.Equal(t, 3, ob.len())
+
+	ids := []string{}
+	for entry := ob.peek(); entry != nil; entry = ob.peek() {
+		ids = append(ids, entry.Events[0].ID())
+		ob.remove(entry)
+	}
+	require.Equal(t, []string{"1", "2", "3"}, ids)
+}
+
+func TestOutboxBound(t *testing.T) {
+	ob, err := newOutbox(t.TempDir(), 1)
+	require.NoError(t, err)
+
+	payload := make([]byte, 400)
+	for i := range payload {
+		payload[i] = 'a'
+	}
+	require.NoError(t, ob.add("status", newOutboxEvent(t, "1", "a", "", `"`+string(payload)+`"`)))
+	require.NoError(t, ob.add("status", newOutboxEvent(t, "2", "b", "", `"`+string(payload)+`"`)))
+	require.NoError(t, ob.add("status", newOutboxEvent(t, "3", "c", "", `"`+string(payload)+`"`)))
+
+	// the oldest entries are dropped, the latest one is always kept
+	require.Less(t, ob.len(), 3)
+	require.LessOrEqual(t, ob.bytes, ob.maxBytes)
+	require.NotNil(t, ob.entries["status/c/3"])
+}
+
+// fakeSender records the sent events, and fails the sending if it's unavailable
+type fakeSender struct {
+	mutex       sync.Mutex
+	unavailable bool
+	sent        []string
+}
+
+func (s *fakeSender) Send(ctx context.Context, m binding.Message, transformers ...binding.Transformer) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.unavailable {
+		return errors.New("broker is unavailable")
+	}
+	evt, err := binding.ToEvent(ctx, m, transformers...)
+	if err != nil {
+		return err
+	}
+	s.sent = append(s.sent, evt.ID())
+	return nil
+}
+
+func (s *fakeSender) setUnavailable(unavailable bool) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.unavailable = unavailable
+}
+
+func TestOutboxReplay(t *testing.T) {
+	sender := &fakeSender{}
+	client, err := cloudevents.NewClient(sender)
+	require.NoError(t, err)
+
+	ob, err := newOutbox(t.TempDir(), 0)
+	require.NoError(t, err)
+	p := &GenericProducer{
+		log:              logger.ZapLogger("test-producer"),
+		ceClient:         client,
+		messageSizeLimit: DefaultMessageKBSize * 1000,
+		outbox:           ob,
+	}
+	p.healthy.Store(true)
+
+	// spill the events while the transport is unavailable
+	sender.setUnavailable(true)
+	require.NoError(t, p.SendEvent(context.Background(), newOutboxEvent(t, "1", policyType, "", `{}`)))
+	require.False(t, p.healthy.Load())
+	require.NoError(t, p.SendEvent(context.Background(), newOutboxEvent(t, "2", clusterType, "", `{}`)))
+	require.NoError(t, p.SendEvent(context.Background(), newOutboxEvent(t, "3", eventType, "", `{}`)))
+	require.Equal(t, 3, ob.len())
+
+	// the new events are spilled to keep the order even if the transport is recovered
+	sender.setUnavailable(false)
+	require.NoError(t, p.SendEvent(context.Background(), newOutboxEvent(t, "4", policyType, "", `{}`)))
+	require.Empty(t, sender.sent)
+
+	// the coalesced event "1" is replaced by "4"
+	p.replayOutbox()
+	require.True(t, p.healthy.Load())
+	require.Equal(t, []string{"2", "3", "4"}, sender.sent)
+	require.Equal(t, 0, ob.len())
+
+	require.NoError(t, p.SendEvent(context.Background(), newOutboxEvent(t, "5", policyType, "", `{}`)))
+	require.Equal(t, []string{"2", "3", "4", "5"}, sender.sent)
+}
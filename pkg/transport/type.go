@@ -2,15 +2,21 @@ package transport
 
 import (
 	"time"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/compressor"
 )
 
 const (
-	Broadcast      = "broadcast" // Broadcast can be used as destination when a bundle should be broadcasted.
-	ChunkSizeKey   = "extsize"   // ChunkSizeKey is the key used for total bundle size header.
-	ChunkOffsetKey = "extoffset" // ChunkOffsetKey is the key used for message fragment offset header.
+	Broadcast        = "broadcast"   // Broadcast can be used as destination when a bundle should be broadcasted.
+	ChunkSizeKey     = "extsize"     // ChunkSizeKey is the key used for total bundle size header.
+	ChunkOffsetKey   = "extoffset"   // ChunkOffsetKey is the key used for message fragment offset header.
+	ChunkCountKey    = "extchunks"   // ChunkCountKey is the key used for the number of the bundle fragments header.
+	ChunkChecksumKey = "extchecksum" // ChunkChecksumKey is the key used for the crc32 checksum of the bundle header.
+	// CompressionKey is the key used for the compression type of the event data, the data isn't compressed if absent
+	CompressionKey = "extcompression"
 )
 
-// indicate the transport type, only support kafka or go chan
+// indicate the transport type, the kafka, mqtt and go chan are registered as the backend of the producer and consumer
 type TransportType string
 
 const (
@@ -18,6 +24,7 @@ const (
 	Kafka TransportType = "kafka"
 	Chan  TransportType = "chan"
 	Rest  TransportType = "rest"
+	Mqtt  TransportType = "mqtt"
 )
 
 // transport protocol
@@ -43,8 +50,17 @@ type TransportInternalConfig struct {
 	// set the kafka credentail in the transport controller
 	KafkaCredential   *KafkaConfig
 	RestfulCredential *RestfulConfig
+	MqttCredential    *MqttConfig
 	Extends           map[string]interface{}
 	FailureThreshold  int
+	// CompressionType specifies how the producer compresses the event data, the consumer decompresses the received
+	// event by the type in the event extension, so it accepts the events from both compressed and uncompressed clients
+	CompressionType compressor.CompressionType
+	// OutboxDir enables the producer to spill the undelivered events into the directory, and replay them in order once
+	// the transport recovers. The outbox is disabled if it's empty
+	OutboxDir string
+	// OutboxMaxSizeKB bounds the size of the outbox, the oldest events are dropped once it's exceeded
+	OutboxMaxSizeKB int
 }
 
 // KafkaInternalConfig specifics the configuration for the global hub manager, agent, or even inventory
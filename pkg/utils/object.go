@@ -9,6 +9,7 @@ import (
 	cloudevents "github.com/cloudevents/sdk-go/v2"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	clusterv1 "open-cluster-management.io/api/cluster/v1"
 	policyv1 "open-cluster-management.io/governance-policy-propagator/api/v1"
@@ -22,6 +23,15 @@ const (
 	notFoundErrorSuffix = "not found"
 )
 
+// sensitiveKinds hold the credentials, they're never collected from the managed hubs even if their fields are projected
+var sensitiveKinds = map[schema.GroupKind]bool{
+	{Group: "", Kind: "Secret"}:                                 true,
+	{Group: "oauth.openshift.io", Kind: "OAuthAccessToken"}:     true,
+	{Group: "oauth.openshift.io", Kind: "OAuthAuthorizeToken"}:  true,
+	{Group: "oauth.openshift.io", Kind: "OAuthClient"}:          true,
+	{Group: "oauth.openshift.io", Kind: "UserOAuthAccessToken"}: true,
+}
+
 // UpdateObject function updates a given k8s object.
 func UpdateObject(ctx context.Context, runtimeClient client.Client, obj *unstructured.Unstructured) error {
 	objectBytes, err := obj.MarshalJSON()
@@ -89,6 +99,11 @@ func GetClusterId(ctx context.Context, runtimeClient client.Client, clusterName
 	return clusterId, nil
 }
 
+// IsSensitiveKind returns true if the kind holds the credentials.
+func IsSensitiveKind(groupKind schema.GroupKind) bool {
+	return sensitiveKinds[groupKind]
+}
+
 func GetObjectKey(obj client.Object) string {
 	return obj.GetObjectKind().GroupVersionKind().String()
 }
@@ -24,3 +24,99 @@ type SecurityAlertCounts struct {
 	// This should follow the format: "<namespace>/<name>"
 	Source string `json:"source,omitempty"`
 }
+
+// SecuritySeverityCounts contains the number of violations of each severity.
+type SecuritySeverityCounts struct {
+	Low      int `json:"low,omitempty"`
+	Medium   int `json:"medium,omitempty"`
+	High     int `json:"high,omitempty"`
+	Critical int `json:"critical,omitempty"`
+}
+
+// SecurityClusterViolations contains the violation counts of each managed cluster secured by the Central CR instance,
+// so that it's possible to see which clusters contribute to the alert counts of the hub.
+type SecurityClusterViolations struct {
+	// Clusters contains the counts of the clusters with violations.
+	Clusters []SecurityClusterViolation `json:"clusters"`
+
+	// DetailURL is the URL of the violations tab of the Stackrox Central UI.
+	DetailURL string `json:"detail_url,omitempty"`
+
+	// Source is the Central CR instance from which the data was retrieved, in the format "<namespace>/<name>".
+	Source string `json:"source,omitempty"`
+}
+
+// SecurityClusterViolation contains the violation counts of a managed cluster.
+type SecurityClusterViolation struct {
+	// Cluster is the name of the cluster in the Central.
+	Cluster string `json:"cluster"`
+
+	SecuritySeverityCounts
+}
+
+// SecurityDeploymentViolations contains the active violations of each deployment secured by the Central CR instance.
+type SecurityDeploymentViolations struct {
+	// Deployments contains the deployments with active violations.
+	Deployments []SecurityDeploymentViolation `json:"deployments"`
+
+	// DetailURL is the URL of the violations tab of the Stackrox Central UI.
+	DetailURL string `json:"detail_url,omitempty"`
+
+	// Source is the Central CR instance from which the data was retrieved, in the format "<namespace>/<name>".
+	Source string `json:"source,omitempty"`
+}
+
+// SecurityDeploymentViolation contains the violation counts and the violated policies of a deployment.
+type SecurityDeploymentViolation struct {
+	Cluster   string `json:"cluster"`
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+
+	SecuritySeverityCounts
+
+	// Policies are the sorted names of the violated policies.
+	Policies []string `json:"policies,omitempty"`
+}
+
+// SecurityVulnerabilityCounts contains the number of the image CVEs of each severity, and how many of them are
+// fixable.
+type SecurityVulnerabilityCounts struct {
+	Low       int `json:"low,omitempty"`
+	Moderate  int `json:"moderate,omitempty"`
+	Important int `json:"important,omitempty"`
+	Critical  int `json:"critical,omitempty"`
+	Fixable   int `json:"fixable,omitempty"`
+}
+
+// SecurityVulnerabilities contains the image CVE counts of the deployments and the images scanned by the Central CR
+// instance.
+type SecurityVulnerabilities struct {
+	// Deployments contains the CVE counts of the images used by each deployment.
+	Deployments []SecurityDeploymentVulnerability `json:"deployments"`
+
+	// Images contains the CVE counts of each image.
+	Images []SecurityImageVulnerability `json:"images"`
+
+	// DetailURL is the URL of the vulnerability management of the Stackrox Central UI.
+	DetailURL string `json:"detail_url,omitempty"`
+
+	// Source is the Central CR instance from which the data was retrieved, in the format "<namespace>/<name>".
+	Source string `json:"source,omitempty"`
+}
+
+// SecurityDeploymentVulnerability contains the image CVE counts of a deployment.
+type SecurityDeploymentVulnerability struct {
+	Cluster   string `json:"cluster"`
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+
+	SecurityVulnerabilityCounts
+}
+
+// SecurityImageVulnerability contains the CVE counts of an image.
+type SecurityImageVulnerability struct {
+	// Name is the full name of the image, e.g. "quay.io/org/image:tag".
+	Name string `json:"name"`
+
+	SecurityVulnerabilityCounts
+}
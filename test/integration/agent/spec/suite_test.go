@@ -94,7 +94,7 @@ var _ = BeforeSuite(func() {
 	Expect(err).NotTo(HaveOccurred())
 
 	agentConfig.TransportConfig.IsManager = true
-	genericProducer, err = genericproducer.NewGenericProducer(agentConfig.TransportConfig)
+	genericProducer, err = genericproducer.NewGenericProducer(ctx, agentConfig.TransportConfig)
 	Expect(err).NotTo(HaveOccurred())
 
 	transportClient := controller.TransportClient{}
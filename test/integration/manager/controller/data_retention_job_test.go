@@ -1,8 +1,13 @@
 package controller
 
 import (
+	"compress/gzip"
 	"encoding/json"
 	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/go-co-op/gocron"
@@ -15,6 +20,8 @@ import (
 	clusterv1 "open-cluster-management.io/api/cluster/v1"
 	policyv1 "open-cluster-management.io/governance-policy-propagator/api/v1"
 
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/configs"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/processes/cronjob/archive"
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/processes/cronjob/task"
 	"github.com/stolostron/multicluster-global-hub/pkg/database"
 	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
@@ -110,7 +117,7 @@ var _ = Describe("data retention job", Ordered, func() {
 	It("the data retention job should work", func() {
 		By("Create the data retention job")
 		s := gocron.NewScheduler(time.UTC)
-		_, err := s.Every(1).Week().DoWithJobDetails(task.DataRetention, ctx, retentionMonth)
+		_, err := s.Every(1).Week().DoWithJobDetails(task.DataRetention, ctx, &task.RetentionPolicy{Months: retentionMonth})
 		Expect(err).ToNot(HaveOccurred())
 		s.StartAsync()
 		defer s.Clear()
@@ -244,3 +251,57 @@ func createRetentionData(tableName string, date time.Time) error {
 	}
 	return nil
 }
+
+var _ = Describe("data retention job with the table retention and archive", Ordered, func() {
+	It("should archive the expired partition of the table before dropping it", func() {
+		now := time.Now()
+		currentMonth := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+		tableName := "event.managed_clusters"
+		expiredTime := currentMonth.AddDate(0, -5, 0)
+		partition := fmt.Sprintf("%s_%s", tableName, expiredTime.Format(task.PartitionDateFormat))
+
+		By("Create the expired partition with a record")
+		Expect(createPartitionTable(tableName, expiredTime)).To(Succeed())
+		err := db.Exec(`INSERT INTO event.managed_clusters (event_namespace, event_name, cluster_name, cluster_id,
+			leaf_hub_name, message, reason, event_type, created_at) VALUES ('default', 'archived-event', 'cluster1',
+			?, 'hub1', 'message', 'reason', 'Normal', ?)`, uuid.New().String(), expiredTime.AddDate(0, 0, 1)).Error
+		Expect(err).ToNot(HaveOccurred())
+
+		By("Run the data retention job which keeps the table for 3 months")
+		dir := GinkgoT().TempDir()
+		archiver, err := archive.NewArchiver(&configs.ArchiveConfig{Format: archive.FormatNDJSON, Dir: dir})
+		Expect(err).ToNot(HaveOccurred())
+		s := gocron.NewScheduler(time.UTC)
+		_, err = s.Every(1).Week().DoWithJobDetails(task.DataRetention, ctx, &task.RetentionPolicy{
+			Months:      18,
+			TableMonths: map[string]int{tableName: 3},
+			Archiver:    archiver,
+		})
+		Expect(err).ToNot(HaveOccurred())
+		s.StartAsync()
+		defer s.Clear()
+
+		By("Check whether the partition is archived and dropped")
+		archived := filepath.Join(dir, "event", strings.TrimPrefix(partition, "event.")+".ndjson.gz")
+		Eventually(func() error {
+			var exists bool
+			if err := db.Raw("SELECT to_regclass(?) IS NOT NULL", partition).Scan(&exists).Error; err != nil {
+				return err
+			}
+			if exists {
+				return fmt.Errorf("the partition %s isn't dropped", partition)
+			}
+			_, err := os.Stat(archived)
+			return err
+		}, 10*time.Second, 1*time.Second).ShouldNot(HaveOccurred())
+
+		file, err := os.Open(archived)
+		Expect(err).ToNot(HaveOccurred())
+		defer file.Close()
+		reader, err := gzip.NewReader(file)
+		Expect(err).ToNot(HaveOccurred())
+		content, err := io.ReadAll(reader)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(content)).To(ContainSubstring(`"event_name":"archived-event"`))
+	})
+})
@@ -0,0 +1,101 @@
+package controller
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/go-co-op/gocron"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/processes/cronjob/task"
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
+)
+
+// go test ./test/integration/manager/controller -v -ginkgo.focus "ComplianceHistory"
+var _ = Describe("ComplianceHistory", Ordered, func() {
+	const hubName = "hub-compliance-history"
+	const policyID = "00000000-0000-0000-0000-000000000015"
+
+	It("record the compliance changes of the status.compliance", func() {
+		By("Create the data to the status.compliance table")
+		err := db.Exec(`
+		INSERT INTO "status"."compliance" ("policy_id", "cluster_name", "leaf_hub_name", "error", "compliance") VALUES
+		(?, 'managedcluster-1', ?, 'none', 'compliant'),
+		(?, 'managedcluster-2', ?, 'none', 'compliant'),
+		(?, 'managedcluster-3', ?, 'none', 'pending');
+		`, policyID, hubName, policyID, hubName, policyID, hubName).Error
+		Expect(err).ToNot(HaveOccurred())
+
+		By("Update the compliance of the managedcluster-1, and update the managedcluster-2 without changing it")
+		err = db.Exec(`UPDATE status.compliance SET compliance = 'non_compliant'
+			WHERE leaf_hub_name = ? AND cluster_name = 'managedcluster-1'`, hubName).Error
+		Expect(err).ToNot(HaveOccurred())
+		err = db.Exec(`UPDATE status.compliance SET compliance = 'compliant', error = 'none'
+			WHERE leaf_hub_name = ? AND cluster_name = 'managedcluster-2'`, hubName).Error
+		Expect(err).ToNot(HaveOccurred())
+
+		By("Check the compliance changes")
+		changes := []models.ComplianceChange{}
+		err = db.Where("leaf_hub_name = ?", hubName).Order("created_at").Find(&changes).Error
+		Expect(err).ToNot(HaveOccurred())
+		Expect(changes).To(HaveLen(4))
+		last := changes[len(changes)-1]
+		Expect(last.ClusterName).To(Equal("managedcluster-1"))
+		Expect(last.Compliance).To(Equal("non_compliant"))
+		Expect(*last.PreviousCompliance).To(Equal("compliant"))
+
+		By("Check the daily compliance history")
+		history := models.ComplianceHistory{}
+		err = db.Where("leaf_hub_name = ? AND cluster_name = ?", hubName, "managedcluster-1").
+			First(&history).Error
+		Expect(err).ToNot(HaveOccurred())
+		Expect(history.Compliance).To(Equal("non_compliant"))
+		Expect(history.ComplianceChangedFrequency).To(Equal(1))
+	})
+
+	It("snapshot the status.compliance to the history.compliance", func() {
+		By("Remove the history of today to verify the snapshot")
+		err := db.Where("leaf_hub_name = ?", hubName).Delete(&models.ComplianceHistory{}).Error
+		Expect(err).ToNot(HaveOccurred())
+
+		By("Create the sync job")
+		s := gocron.NewScheduler(time.UTC)
+		complianceJob, err := s.Every(1).Day().DoWithJobDetails(task.ComplianceHistory, ctx)
+		Expect(err).ToNot(HaveOccurred())
+		fmt.Println("set compliance job", "scheduleAt", complianceJob.ScheduledAtTime())
+		s.StartAsync()
+		defer s.Clear()
+
+		By("Check whether the data is synced to the history.compliance table")
+		Eventually(func() error {
+			histories := []models.ComplianceHistory{}
+			if err := db.Where("leaf_hub_name = ?", hubName).Find(&histories).Error; err != nil {
+				return err
+			}
+			if len(histories) != 3 {
+				return fmt.Errorf("expected 3 items, but got %d in the compliance history", len(histories))
+			}
+			for _, history := range histories {
+				if history.ClusterName == "managedcluster-3" && history.Compliance != "pending" {
+					return fmt.Errorf("expected the managedcluster-3 is pending, but got %s", history.Compliance)
+				}
+			}
+			return nil
+		}, 10*time.Second, 2*time.Second).ShouldNot(HaveOccurred())
+
+		By("Check whether the job log is created")
+		Eventually(func() error {
+			var count int64
+			err := db.Model(&models.LocalComplianceJobLog{}).Where("name = ?", task.ComplianceTaskName).
+				Count(&count).Error
+			if err != nil {
+				return err
+			}
+			if count < 1 {
+				return fmt.Errorf("the compliance history job log isn't created")
+			}
+			return nil
+		}, 10*time.Second, 2*time.Second).ShouldNot(HaveOccurred())
+	})
+})
@@ -77,8 +77,10 @@ var _ = Describe("hub management", func() {
 		Expect(now.Add(-60 * time.Second).Format(timeFormat)).To(Equal(updatedHub4.LastUpdateAt.Format(timeFormat)))
 
 		// update
-		hubManagement := hubmanagement.NewHubManagement(&tmpProducer{}, 1*time.Second, 90*time.Second)
-		Expect(hubManagement.Start(ctx)).To(Succeed())
+		hubCtx, hubCancel := context.WithCancel(ctx)
+		defer hubCancel()
+		hubManagement := hubmanagement.NewHubManagement(&tmpProducer{}, 1*time.Second, 90*time.Second, 0)
+		Expect(hubManagement.Start(hubCtx)).To(Succeed())
 
 		time.Sleep(3 * time.Second)
 
@@ -96,6 +98,87 @@ var _ = Describe("hub management", func() {
 			Expect(hubmanagement.HubActive).To(Equal(updatedHub.Status))
 		}
 	})
+
+	It("keep the data of the unreachable hub for the grace period", func() {
+		db := database.GetGorm()
+		hubName := "heartbeat-hub05"
+		err := db.Clauses(clause.OnConflict{UpdateAll: true}).Create(&models.LeafHubHeartbeat{
+			Name:         hubName,
+			LastUpdateAt: time.Now().Add(-120 * time.Second),
+			Status:       hubmanagement.HubActive,
+		}).Error
+		Expect(err).To(Succeed())
+		err = db.Create(&models.ManagedCluster{
+			ClusterID:   "3f406177-34b2-4852-88dd-ff2809680335",
+			LeafHubName: hubName,
+			Payload:     []byte(`{}`),
+			Error:       database.ErrorNone,
+		}).Error
+		Expect(err).To(Succeed())
+
+		hubCtx, hubCancel := context.WithCancel(ctx)
+		defer hubCancel()
+		hubManagement := hubmanagement.NewHubManagement(&tmpProducer{}, 1*time.Second, 90*time.Second, time.Hour)
+		Expect(hubManagement.Start(hubCtx)).To(Succeed())
+
+		Eventually(func() error {
+			var hub models.LeafHubHeartbeat
+			if err := db.Where("leaf_hub_name = ?", hubName).First(&hub).Error; err != nil {
+				return err
+			}
+			if hub.Status != hubmanagement.HubUnreachable {
+				return fmt.Errorf("the hub %s should be unreachable, but got %s", hubName, hub.Status)
+			}
+			if hub.Reason != hubmanagement.ReasonHeartbeatTimeout {
+				return fmt.Errorf("the reason should be %s, but got %s", hubmanagement.ReasonHeartbeatTimeout, hub.Reason)
+			}
+			return nil
+		}, 10*time.Second, 1*time.Second).Should(Succeed())
+
+		// the data of the unreachable hub is kept but stale
+		var count int64
+		err = db.Model(&models.ManagedCluster{}).Where("leaf_hub_name = ?", hubName).Count(&count).Error
+		Expect(err).To(Succeed())
+		Expect(count).To(Equal(int64(1)))
+		err = db.Model(&models.ManagedCluster{}).Where("leaf_hub_name = ? AND stale_at IS NOT NULL", hubName).
+			Count(&count).Error
+		Expect(err).To(Succeed())
+		Expect(count).To(Equal(int64(1)))
+
+		var events []models.LeafHubEvent
+		err = db.Where("leaf_hub_name = ?", hubName).Find(&events).Error
+		Expect(err).To(Succeed())
+		Expect(events).To(HaveLen(1))
+		Expect(events[0].PreviousStatus).To(Equal(hubmanagement.HubActive))
+		Expect(events[0].Status).To(Equal(hubmanagement.HubUnreachable))
+
+		// the heartbeat is resumed
+		err = db.Model(&models.LeafHubHeartbeat{}).Where("leaf_hub_name = ?", hubName).
+			Update("last_timestamp", time.Now()).Error
+		Expect(err).To(Succeed())
+
+		Eventually(func() error {
+			var hub models.LeafHubHeartbeat
+			if err := db.Where("leaf_hub_name = ?", hubName).First(&hub).Error; err != nil {
+				return err
+			}
+			if hub.Status != hubmanagement.HubActive {
+				return fmt.Errorf("the hub %s should be active, but got %s", hubName, hub.Status)
+			}
+			return nil
+		}, 10*time.Second, 1*time.Second).Should(Succeed())
+
+		err = db.Where("leaf_hub_name = ?", hubName).Order("created_at").Find(&events).Error
+		Expect(err).To(Succeed())
+		Expect(events).To(HaveLen(2))
+		Expect(events[1].Reason).To(Equal(hubmanagement.ReasonHeartbeatResumed))
+
+		// the stale flag is cleared once the hub is active again
+		err = db.Model(&models.ManagedCluster{}).Where("leaf_hub_name = ? AND stale_at IS NOT NULL", hubName).
+			Count(&count).Error
+		Expect(err).To(Succeed())
+		Expect(count).To(BeZero())
+	})
 })
 
 type tmpProducer struct{}
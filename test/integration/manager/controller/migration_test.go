@@ -17,6 +17,7 @@ import (
 	clusterv1 "open-cluster-management.io/api/cluster/v1"
 	operatorv1 "open-cluster-management.io/api/operator/v1"
 	"open-cluster-management.io/managed-serviceaccount/apis/authentication/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 
 	migrationv1alpha1 "github.com/stolostron/multicluster-global-hub/operator/api/migration/v1alpha1"
@@ -69,7 +70,10 @@ var _ = Describe("migration", Ordered, func() {
 						}
 						switch clusterName {
 						case "hub1":
-							fromEvent = evt
+							// the source hub is also initialized to accept the rolled back clusters
+							if evt.Type() == constants.CloudEventTypeMigrationFrom {
+								fromEvent = evt
+							}
 						case "hub2":
 							toEvent = evt
 						default:
@@ -83,6 +87,9 @@ var _ = Describe("migration", Ordered, func() {
 			hub2Namespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "hub2"}}
 			Expect(mgr.GetClient().Create(testCtx, hub2Namespace)).Should(Succeed())
 
+			By("mimic the source hub hub1")
+			mockSourceHub(testCtx, "hub1", "migration")
+
 			// create managedclustermigration CR
 			By("create managedclustermigration CR")
 			migrationInstance = &migrationv1alpha1.ManagedClusterMigration{
@@ -131,10 +138,8 @@ var _ = Describe("migration", Ordered, func() {
 		})
 
 		AfterAll(func() {
-			By("delete hub2 namespace")
-			hub2Namespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "hub2"}}
-			Expect(mgr.GetClient().Delete(testCtx, hub2Namespace)).Should(Succeed())
-
+			// the hub2 namespace is kept, since hub2 is a source hub of the next migration and the namespace can't be
+			// recreated once it's terminating
 			By("cancel the test context")
 			testCtxCancel()
 		})
@@ -146,6 +151,14 @@ var _ = Describe("migration", Ordered, func() {
 					Namespace: "hub2",
 				}, &v1beta1.ManagedServiceAccount{})
 			}, 3*time.Second, 100*time.Millisecond).Should(Succeed())
+
+			By("the source hub has managedserviceaccount for rolling back")
+			Eventually(func() error {
+				return mgr.GetClient().Get(testCtx, types.NamespacedName{
+					Name:      "migration",
+					Namespace: "hub1",
+				}, &v1beta1.ManagedServiceAccount{})
+			}, 3*time.Second, 100*time.Millisecond).Should(Succeed())
 		})
 
 		It("should have bootstrap secret generated after managedserviceaccount is reconciled", func() {
@@ -253,12 +266,22 @@ var _ = Describe("migration", Ordered, func() {
 				if klusterletConfig.Spec.BootstrapKubeConfigs.Type != operatorv1.LocalSecrets {
 					return fmt.Errorf("klusterletConfig bootstrap kubeconfig type is not correct")
 				}
-				if len(klusterletConfig.Spec.BootstrapKubeConfigs.LocalSecrets.KubeConfigSecrets) != 1 {
+				if len(klusterletConfig.Spec.BootstrapKubeConfigs.LocalSecrets.KubeConfigSecrets) != 2 {
 					return fmt.Errorf("klusterletConfig bootstrap kubeconfig secrets is not correct")
 				}
 				if klusterletConfig.Spec.BootstrapKubeConfigs.LocalSecrets.KubeConfigSecrets[0].Name != "bootstrap-hub2" {
 					return fmt.Errorf("klusterletConfig bootstrap kubeconfig secret name 0 is not correct")
 				}
+				if klusterletConfig.Spec.BootstrapKubeConfigs.LocalSecrets.KubeConfigSecrets[1].Name != "bootstrap-hub1" {
+					return fmt.Errorf("klusterletConfig bootstrap kubeconfig secret name 1 is not correct")
+				}
+				sourceBootstrapSecret := managedClusterMigrationFromEvent.SourceBootstrapSecret
+				if sourceBootstrapSecret == nil || sourceBootstrapSecret.Name != "bootstrap-hub1" {
+					return fmt.Errorf("sourceBootstrapSecret is not correct")
+				}
+				if _, err = clientcmd.RESTConfigFromKubeConfig(sourceBootstrapSecret.Data["kubeconfig"]); err != nil {
+					return fmt.Errorf("failed to create Kubernetes client config of the source hub: %v", err)
+				}
 
 				managedClusterMigrationToEvent := &bundleevent.ManagedClusterMigrationToEvent{}
 				if err := json.Unmarshal(toEvent.Data(), managedClusterMigrationToEvent); err != nil {
@@ -290,16 +313,66 @@ var _ = Describe("migration", Ordered, func() {
 			}, 3*time.Second, 100*time.Millisecond).Should(Succeed())
 		})
 
+		It("should complete the migration when the managed cluster is registered to the target hub", func() {
+			Eventually(func() error {
+				if err := mgr.GetClient().Get(testCtx, client.ObjectKeyFromObject(migrationInstance),
+					migrationInstance); err != nil {
+					return err
+				}
+				if migrationInstance.Status.Phase != migrationv1alpha1.PhaseRegistering {
+					return fmt.Errorf("the migration should be registering, but got %s", migrationInstance.Status.Phase)
+				}
+				return nil
+			}, 10*time.Second, 100*time.Millisecond).Should(Succeed())
+			Expect(migrationInstance.Status.Clusters).To(HaveLen(1))
+			Expect(migrationInstance.Status.Clusters[0].From).To(Equal("hub1"))
+			Expect(migrationInstance.Status.Clusters[0].Phase).To(Equal(migrationv1alpha1.ClusterPhaseRegistering))
+
+			By("report the managed cluster from the target hub")
+			Expect(db.Exec(`INSERT INTO "status"."managed_clusters" ("leaf_hub_name", "cluster_id", "error", "payload")
+			VALUES ('hub2', 'b3c2a8d6-1c4e-4a63-9d0f-7d1f5a3e6c21', 'none', '{"metadata": {"name": "cluster1"},
+			"status": {"conditions": [{"type": "ManagedClusterConditionAvailable", "status": "True", "reason": "Available",
+			"message": "", "lastTransitionTime": "2025-01-01T00:00:00Z"}]}}')`).Error).ToNot(HaveOccurred())
+			DeferCleanup(func() {
+				Expect(db.Exec(`DELETE FROM "status"."managed_clusters" WHERE cluster_id =
+				'b3c2a8d6-1c4e-4a63-9d0f-7d1f5a3e6c21'`).Error).ToNot(HaveOccurred())
+			})
+
+			Eventually(func() error {
+				if err := mgr.GetClient().Get(testCtx, client.ObjectKeyFromObject(migrationInstance),
+					migrationInstance); err != nil {
+					return err
+				}
+				if migrationInstance.Status.Phase != migrationv1alpha1.PhaseCompleted {
+					return fmt.Errorf("the migration should be completed, but got %s", migrationInstance.Status.Phase)
+				}
+				if migrationInstance.Status.Clusters[0].Phase != migrationv1alpha1.ClusterPhaseCompleted {
+					return fmt.Errorf("the cluster should be completed, but got %s", migrationInstance.Status.Clusters[0].Phase)
+				}
+				return nil
+			}, 30*time.Second, time.Second).Should(Succeed())
+
+			managedClusterMigrationFromEvent := &bundleevent.ManagedClusterMigrationFromEvent{}
+			Expect(json.Unmarshal(fromEvent.Data(), managedClusterMigrationFromEvent)).To(Succeed())
+			Expect(managedClusterMigrationFromEvent.Stage).To(Equal(bundleevent.MigrationStageCleaning))
+			Expect(managedClusterMigrationFromEvent.ManagedClusters).To(Equal([]string{"cluster1"}))
+		})
+
 		It("should have managedserviceaccount deleted when migration is deleted", func() {
 			Expect(mgr.GetClient().Delete(testCtx, migrationInstance)).To(Succeed())
 
 			Eventually(func() bool {
-				msa := &v1beta1.ManagedServiceAccount{}
-				err := mgr.GetClient().Get(testCtx, types.NamespacedName{
-					Name:      "migration",
-					Namespace: "hub2",
-				}, msa)
-				return apierrors.IsNotFound(err)
+				for _, hub := range []string{"hub1", "hub2"} {
+					msa := &v1beta1.ManagedServiceAccount{}
+					err := mgr.GetClient().Get(testCtx, types.NamespacedName{
+						Name:      "migration",
+						Namespace: hub,
+					}, msa)
+					if !apierrors.IsNotFound(err) {
+						return false
+					}
+				}
+				return true
 			}, 1*time.Second, 100*time.Millisecond).Should(BeTrue())
 		})
 	})
@@ -344,6 +417,10 @@ var _ = Describe("migration", Ordered, func() {
 							logf.Log.Info("dropping bundle due to invalid cluster name", "clusterName", clusterNameVal)
 							continue
 						}
+						// the source hubs are also initialized to accept the rolled back clusters
+						if clusterName != "hub3" && evt.Type() != constants.CloudEventTypeMigrationFrom {
+							continue
+						}
 						switch clusterName {
 						case "hub1":
 							fromEvent1 = evt
@@ -408,6 +485,10 @@ var _ = Describe("migration", Ordered, func() {
 			}
 			Expect(mgr.GetClient().Create(testCtx, secret)).To(Succeed())
 
+			By("mimic the source hubs hub1 and hub2")
+			mockSourceHub(testCtx, "hub1", "migration")
+			mockSourceHub(testCtx, "hub2", "migration")
+
 			By("create the managed cluster data in the database")
 			Expect(db.Exec(`INSERT INTO "status"."managed_clusters" ("leaf_hub_name", "cluster_id", "error", "payload") VALUES
 			('hub1', '52fbf2b6-8c6b-4d79-ad51-81f2a5e3e5e0', 'none', '{"metadata": {"name": "cluster1"}}'),
@@ -561,12 +642,15 @@ var _ = Describe("migration", Ordered, func() {
 				if klusterletConfig.Spec.BootstrapKubeConfigs.Type != operatorv1.LocalSecrets {
 					return fmt.Errorf("klusterletConfig bootstrap kubeconfig type is not correct")
 				}
-				if len(klusterletConfig.Spec.BootstrapKubeConfigs.LocalSecrets.KubeConfigSecrets) != 1 {
+				if len(klusterletConfig.Spec.BootstrapKubeConfigs.LocalSecrets.KubeConfigSecrets) != 2 {
 					return fmt.Errorf("klusterletConfig bootstrap kubeconfig secrets is not correct")
 				}
 				if klusterletConfig.Spec.BootstrapKubeConfigs.LocalSecrets.KubeConfigSecrets[0].Name != "bootstrap-hub3" {
 					return fmt.Errorf("klusterletConfig bootstrap kubeconfig secret name 0 is not correct")
 				}
+				if klusterletConfig.Spec.BootstrapKubeConfigs.LocalSecrets.KubeConfigSecrets[1].Name != "bootstrap-hub1" {
+					return fmt.Errorf("klusterletConfig bootstrap kubeconfig secret name 1 is not correct")
+				}
 
 				managedClusterMigrationFromEvent2 := &bundleevent.ManagedClusterMigrationFromEvent{}
 				if err := json.Unmarshal(fromEvent2.Data(), managedClusterMigrationFromEvent2); err != nil {
@@ -600,12 +684,15 @@ var _ = Describe("migration", Ordered, func() {
 				if klusterletConfig.Spec.BootstrapKubeConfigs.Type != operatorv1.LocalSecrets {
 					return fmt.Errorf("klusterletConfig bootstrap kubeconfig type is not correct")
 				}
-				if len(klusterletConfig.Spec.BootstrapKubeConfigs.LocalSecrets.KubeConfigSecrets) != 1 {
+				if len(klusterletConfig.Spec.BootstrapKubeConfigs.LocalSecrets.KubeConfigSecrets) != 2 {
 					return fmt.Errorf("klusterletConfig bootstrap kubeconfig secrets is not correct")
 				}
 				if klusterletConfig.Spec.BootstrapKubeConfigs.LocalSecrets.KubeConfigSecrets[0].Name != "bootstrap-hub3" {
 					return fmt.Errorf("klusterletConfig bootstrap kubeconfig secret name 0 is not correct")
 				}
+				if klusterletConfig.Spec.BootstrapKubeConfigs.LocalSecrets.KubeConfigSecrets[1].Name != "bootstrap-hub2" {
+					return fmt.Errorf("klusterletConfig bootstrap kubeconfig secret name 1 is not correct")
+				}
 
 				managedClusterMigrationToEvent := &bundleevent.ManagedClusterMigrationToEvent{}
 				if err := json.Unmarshal(toEvent.Data(), managedClusterMigrationToEvent); err != nil {
@@ -650,4 +737,189 @@ var _ = Describe("migration", Ordered, func() {
 			}, 1*time.Second, 100*time.Millisecond).Should(BeTrue())
 		})
 	})
+	Context("managed cluster migration tests with rollback", func() {
+		var migrationInstance *migrationv1alpha1.ManagedClusterMigration
+		var testCtx context.Context
+		var testCtxCancel context.CancelFunc
+		var fromEvent, toEvent *cloudevents.Event
+		BeforeAll(func() {
+			testCtx, testCtxCancel = context.WithCancel(ctx)
+			genericConsumer, err := genericconsumer.NewGenericConsumer(transportConfig)
+			Expect(err).NotTo(HaveOccurred())
+			go func() {
+				if err := genericConsumer.Start(testCtx); err != nil {
+					logf.Log.Error(err, "error to start the chan consumer")
+				}
+			}()
+			go func(ctx context.Context) {
+				for {
+					select {
+					case <-ctx.Done():
+						return
+					case evt := <-genericConsumer.EventChan():
+						clusterNameVal, err := evt.Context.GetExtension(constants.CloudEventExtensionKeyClusterName)
+						if err != nil {
+							continue
+						}
+						switch clusterNameVal {
+						case "hub5":
+							if evt.Type() == constants.CloudEventTypeMigrationFrom {
+								fromEvent = evt
+							}
+						case "hub4":
+							toEvent = evt
+						}
+					}
+				}
+			}(testCtx)
+
+			By("create the target hub hub4")
+			Expect(mgr.GetClient().Create(testCtx, &corev1.Namespace{
+				ObjectMeta: metav1.ObjectMeta{Name: "hub4"},
+			})).Should(Succeed())
+			Expect(mgr.GetClient().Create(testCtx, &clusterv1.ManagedCluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "hub4"},
+				Spec: clusterv1.ManagedClusterSpec{
+					ManagedClusterClientConfigs: []clusterv1.ClientConfig{
+						{URL: "https://example.com", CABundle: []byte("test")},
+					},
+				},
+			})).To(Succeed())
+			Expect(mgr.GetClient().Create(testCtx, &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "migration-rollback", Namespace: "hub4"},
+				Data: map[string][]byte{
+					"ca.crt": []byte("test"),
+					"token":  []byte("test"),
+				},
+			})).To(Succeed())
+
+			By("mimic the source hub hub5")
+			mockSourceHub(testCtx, "hub5", "migration-rollback")
+
+			By("create the managed clusters on the source hub hub5")
+			Expect(db.Exec(`INSERT INTO "status"."managed_clusters" ("leaf_hub_name", "cluster_id", "error", "payload") VALUES
+			('hub5', '0f5a4c1e-8d2b-4f7a-b3e9-6c1d2a7f8e90', 'none', '{"metadata": {"name": "cluster5", "labels": {"env": "dev"}}}'),
+			('hub5', '7e3b9d2c-5a1f-4c8e-a6d4-2b9f0e1c3d57', 'none', '{"metadata": {"name": "cluster6", "labels": {"env": "prod"}}}')`).
+				Error).ToNot(HaveOccurred())
+
+			migrationInstance = &migrationv1alpha1.ManagedClusterMigration{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "migration-rollback",
+					Namespace: utils.GetDefaultNamespace(),
+				},
+				Spec: migrationv1alpha1.ManagedClusterMigrationSpec{
+					ManagedClusterSelector: &metav1.LabelSelector{
+						MatchLabels: map[string]string{"env": "dev"},
+					},
+					To:                  "hub4",
+					RegistrationTimeout: metav1.Duration{Duration: time.Second},
+				},
+			}
+			Expect(mgr.GetClient().Create(testCtx, migrationInstance)).To(Succeed())
+		})
+
+		AfterAll(func() {
+			Expect(mgr.GetClient().Delete(testCtx, migrationInstance)).To(Succeed())
+			Expect(db.Exec(`DELETE FROM "status"."managed_clusters" WHERE leaf_hub_name = 'hub5'`).Error).
+				ToNot(HaveOccurred())
+			Expect(mgr.GetClient().Delete(testCtx, &corev1.Namespace{
+				ObjectMeta: metav1.ObjectMeta{Name: "hub4"},
+			})).Should(Succeed())
+			testCtxCancel()
+		})
+
+		It("should roll back the managed clusters which are not registered in time", func() {
+			Eventually(func() error {
+				if err := mgr.GetClient().Get(testCtx, client.ObjectKeyFromObject(migrationInstance),
+					migrationInstance); err != nil {
+					return err
+				}
+				if migrationInstance.Status.Phase != migrationv1alpha1.PhaseRollingBack {
+					return fmt.Errorf("the migration should be rolling back, but got %s", migrationInstance.Status.Phase)
+				}
+				return nil
+			}, 30*time.Second, time.Second).Should(Succeed())
+			Expect(migrationInstance.Status.Clusters).To(HaveLen(1))
+			Expect(migrationInstance.Status.Clusters[0].Phase).To(Equal(migrationv1alpha1.ClusterPhaseRollingBack))
+
+			By("report the managed cluster is accepted and available on the source hub again")
+			now := time.Now().UTC().Format(time.RFC3339)
+			Expect(db.Exec(`UPDATE "status"."managed_clusters" SET payload = jsonb_set(payload, '{status}', ?::jsonb)
+			WHERE cluster_id = '0f5a4c1e-8d2b-4f7a-b3e9-6c1d2a7f8e90'`, fmt.Sprintf(`{"conditions": [
+			{"type": "HubAcceptedManagedCluster", "status": "True", "reason": "HubClusterAdminAccepted", "message": "",
+			"lastTransitionTime": "%s"}, {"type": "ManagedClusterConditionAvailable", "status": "True",
+			"reason": "Available", "message": "", "lastTransitionTime": "%s"}]}`, now, now)).Error).ToNot(HaveOccurred())
+
+			Eventually(func() error {
+				if err := mgr.GetClient().Get(testCtx, client.ObjectKeyFromObject(migrationInstance),
+					migrationInstance); err != nil {
+					return err
+				}
+				if migrationInstance.Status.Phase != migrationv1alpha1.PhaseFailed {
+					return fmt.Errorf("the migration should be failed, but got %s", migrationInstance.Status.Phase)
+				}
+				return nil
+			}, 30*time.Second, time.Second).Should(Succeed())
+
+			// only the selected cluster is migrated
+			Expect(migrationInstance.Status.Clusters).To(HaveLen(1))
+			Expect(migrationInstance.Status.Clusters[0].Name).To(Equal("cluster5"))
+			Expect(migrationInstance.Status.Clusters[0].From).To(Equal("hub5"))
+			Expect(migrationInstance.Status.Clusters[0].Phase).To(Equal(migrationv1alpha1.ClusterPhaseRolledBack))
+
+			Eventually(func() error {
+				if fromEvent == nil || toEvent == nil {
+					return fmt.Errorf("the rollback events are not received")
+				}
+				managedClusterMigrationFromEvent := &bundleevent.ManagedClusterMigrationFromEvent{}
+				if err := json.Unmarshal(fromEvent.Data(), managedClusterMigrationFromEvent); err != nil {
+					return err
+				}
+				if managedClusterMigrationFromEvent.Stage != bundleevent.MigrationStageRollingBack {
+					return fmt.Errorf("the from event should be rolling back, but got %s",
+						managedClusterMigrationFromEvent.Stage)
+				}
+				managedClusterMigrationToEvent := &bundleevent.ManagedClusterMigrationToEvent{}
+				if err := json.Unmarshal(toEvent.Data(), managedClusterMigrationToEvent); err != nil {
+					return err
+				}
+				if managedClusterMigrationToEvent.Stage != bundleevent.MigrationStageRollingBack {
+					return fmt.Errorf("the to event should be rolling back, but got %s", managedClusterMigrationToEvent.Stage)
+				}
+				if len(managedClusterMigrationToEvent.ManagedClusters) != 1 ||
+					managedClusterMigrationToEvent.ManagedClusters[0] != "cluster5" {
+					return fmt.Errorf("the rolled back clusters are not correct: %v",
+						managedClusterMigrationToEvent.ManagedClusters)
+				}
+				return nil
+			}, 10*time.Second, 100*time.Millisecond).Should(Succeed())
+		})
+	})
 })
+
+// mockSourceHub mimics the managed hub and the token of the managed service account on the source hub, the resources
+// are kept across the migrations
+func mockSourceHub(ctx context.Context, hub, msaName string) {
+	for _, obj := range []client.Object{
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: hub}},
+		&clusterv1.ManagedCluster{
+			ObjectMeta: metav1.ObjectMeta{Name: hub},
+			Spec: clusterv1.ManagedClusterSpec{
+				ManagedClusterClientConfigs: []clusterv1.ClientConfig{
+					{URL: "https://example.com", CABundle: []byte("test")},
+				},
+			},
+		},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: msaName, Namespace: hub},
+			Data: map[string][]byte{
+				"ca.crt": []byte("test"),
+				"token":  []byte("test"),
+			},
+		},
+	} {
+		if err := mgr.GetClient().Create(ctx, obj); err != nil {
+			Expect(apierrors.IsAlreadyExists(err)).To(BeTrue())
+		}
+	}
+}
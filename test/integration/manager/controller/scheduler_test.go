@@ -29,7 +29,8 @@ var _ = Describe("scheduler", func() {
 		Expect(err).To(Succeed())
 
 		_, err = scheduler.Every(1).Month(1, 15, 28).At("00:00").Tag(task.RetentionTaskName).
-			DoWithJobDetails(task.DataRetention, ctx, managerConfig.DatabaseConfig.DataRetention)
+			DoWithJobDetails(task.DataRetention, ctx,
+				&task.RetentionPolicy{Months: managerConfig.DatabaseConfig.DataRetention})
 		Expect(err).To(Succeed())
 
 		globalScheduler := cronjob.NewGlobalHubScheduler(scheduler,
@@ -125,7 +125,7 @@ var _ = BeforeSuite(func() {
 
 	By("Create consumer/producer")
 	managerConfig.TransportConfig.IsManager = true
-	producer, err = genericproducer.NewGenericProducer(managerConfig.TransportConfig)
+	producer, err = genericproducer.NewGenericProducer(ctx, managerConfig.TransportConfig)
 	Expect(err).NotTo(HaveOccurred())
 	managerConfig.TransportConfig.IsManager = false
 	consumer, err := genericconsumer.NewGenericConsumer(managerConfig.TransportConfig)
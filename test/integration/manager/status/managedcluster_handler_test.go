@@ -1,6 +1,7 @@
 package status
 
 import (
+	"encoding/json"
 	"fmt"
 	"time"
 
@@ -9,6 +10,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	clusterv1 "open-cluster-management.io/api/cluster/v1"
 
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/cluster"
 	"github.com/stolostron/multicluster-global-hub/pkg/bundle/generic"
 	eventversion "github.com/stolostron/multicluster-global-hub/pkg/bundle/version"
 	"github.com/stolostron/multicluster-global-hub/pkg/database"
@@ -65,4 +67,77 @@ var _ = Describe("ManagedClusterHandler", Ordered, func() {
 			return fmt.Errorf("not found expected resource on the table")
 		}, 30*time.Second, 100*time.Millisecond).ShouldNot(HaveOccurred())
 	})
+
+	It("should be able to apply the managed cluster delta event", func() {
+		leafHubName := "hub-delta"
+		version := eventversion.NewVersion()
+		newCluster := func(name, clusterID, vendor string) clusterv1.ManagedCluster {
+			return clusterv1.ManagedCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:   name,
+					Labels: map[string]string{"vendor": vendor},
+				},
+				Status: clusterv1.ManagedClusterStatus{
+					ClusterClaims: []clusterv1.ManagedClusterClaim{{Name: "id.k8s.io", Value: clusterID}},
+				},
+			}
+		}
+		clusterID1 := "7b1c3f3e-5d2a-4a36-9f43-3f0f4b7c1d01"
+		clusterID2 := "7b1c3f3e-5d2a-4a36-9f43-3f0f4b7c1d02"
+		vendorOf := func() (map[string]string, error) {
+			items := []models.ManagedCluster{}
+			if err := database.GetGorm().Where("leaf_hub_name = ?", leafHubName).Find(&items).Error; err != nil {
+				return nil, err
+			}
+			vendors := map[string]string{}
+			for _, item := range items {
+				cluster := clusterv1.ManagedCluster{}
+				if err := json.Unmarshal(item.Payload, &cluster); err != nil {
+					return nil, err
+				}
+				vendors[item.ClusterID] = cluster.Labels["vendor"]
+			}
+			return vendors, nil
+		}
+
+		By("Add the clusters with the delta event")
+		version.Incr()
+		evt := ToCloudEvent(leafHubName, string(enum.ManagedClusterDeltaType), version,
+			cluster.ManagedClusterDeltaBundle{Update: []clusterv1.ManagedCluster{
+				newCluster("cluster1", clusterID1, "OpenShift"),
+				newCluster("cluster2", clusterID2, "OpenShift"),
+			}})
+		Expect(producer.SendEvent(ctx, *evt)).Should(Succeed())
+		version.Next()
+		Eventually(func() error {
+			vendors, err := vendorOf()
+			if err != nil {
+				return err
+			}
+			if len(vendors) != 2 {
+				return fmt.Errorf("expect 2 clusters, but got %v", vendors)
+			}
+			return nil
+		}, 30*time.Second, 100*time.Millisecond).ShouldNot(HaveOccurred())
+
+		By("Update and delete the clusters with the delta event")
+		version.Incr()
+		evt = ToCloudEvent(leafHubName, string(enum.ManagedClusterDeltaType), version,
+			cluster.ManagedClusterDeltaBundle{
+				Update: []clusterv1.ManagedCluster{newCluster("cluster1", clusterID1, "EKS")},
+				Delete: []cluster.DeletedManagedCluster{{Name: "cluster2", ClusterID: clusterID2}},
+			})
+		Expect(producer.SendEvent(ctx, *evt)).Should(Succeed())
+		version.Next()
+		Eventually(func() error {
+			vendors, err := vendorOf()
+			if err != nil {
+				return err
+			}
+			if len(vendors) != 1 || vendors[clusterID1] != "EKS" {
+				return fmt.Errorf("expect the updated cluster1 only, but got %v", vendors)
+			}
+			return nil
+		}, 30*time.Second, 100*time.Millisecond).ShouldNot(HaveOccurred())
+	})
 })
@@ -0,0 +1,102 @@
+package status
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/generic"
+	eventversion "github.com/stolostron/multicluster-global-hub/pkg/bundle/version"
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
+)
+
+// go test /test/integration/manager/status -v -ginkgo.focus "ResourcesHandler"
+var _ = Describe("ResourcesHandler", Ordered, func() {
+	leafHubName := "hub1"
+	version := eventversion.NewVersion()
+
+	newClusterDeployment := func(name, uid, powerState string) *unstructured.Unstructured {
+		return &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "hive.openshift.io/v1",
+			"kind":       "ClusterDeployment",
+			"metadata": map[string]interface{}{
+				"name":            name,
+				"namespace":       name,
+				"uid":             uid,
+				"resourceVersion": powerState,
+			},
+			"fields": map[string]interface{}{"{.status.powerState}": powerState},
+		}}
+	}
+
+	listResources := func() ([]models.Resource, error) {
+		resources := []models.Resource{}
+		err := database.GetGorm().Where("leaf_hub_name = ? AND kind = ?", leafHubName, "ClusterDeployment").
+			Order("name").Find(&resources).Error
+		return resources, err
+	}
+
+	It("should be able to sync the declared resources", func() {
+		By("Create event")
+		version.Incr()
+		data := generic.GenericObjectBundle{
+			newClusterDeployment("cluster1", "2aa5547c-c172-47ed-b70b-db468c84d327", "Running"),
+			newClusterDeployment("cluster2", "3aa5547c-c172-47ed-b70b-db468c84d327", "Hibernating"),
+		}
+		evt := ToCloudEvent(leafHubName, string(enum.ResourceType), version, data)
+
+		By("Sync event with transport")
+		Expect(producer.SendEvent(ctx, *evt)).Should(Succeed())
+		version.Next()
+
+		By("Check the resources table")
+		Eventually(func() error {
+			resources, err := listResources()
+			if err != nil {
+				return err
+			}
+			if len(resources) != 2 {
+				return fmt.Errorf("expect 2 resources, but got %d", len(resources))
+			}
+			if resources[0].Name != "cluster1" || resources[0].Namespace != "cluster1" ||
+				resources[0].APIVersion != "hive.openshift.io/v1" {
+				return fmt.Errorf("unexpected resource %v", resources[0])
+			}
+			return nil
+		}, 30*time.Second, 100*time.Millisecond).ShouldNot(HaveOccurred())
+	})
+
+	It("should remove the resources which aren't reported", func() {
+		By("Create event")
+		version.Incr()
+		data := generic.GenericObjectBundle{
+			newClusterDeployment("cluster1", "2aa5547c-c172-47ed-b70b-db468c84d327", "Hibernating"),
+		}
+		evt := ToCloudEvent(leafHubName, string(enum.ResourceType), version, data)
+
+		By("Sync event with transport")
+		Expect(producer.SendEvent(ctx, *evt)).Should(Succeed())
+		version.Next()
+
+		By("Check the resources table")
+		Eventually(func() error {
+			resources, err := listResources()
+			if err != nil {
+				return err
+			}
+			if len(resources) != 1 {
+				return fmt.Errorf("expect 1 resource, but got %d", len(resources))
+			}
+			if !strings.Contains(resources[0].Payload.String(), "Hibernating") {
+				return fmt.Errorf("the resource isn't updated: %s", resources[0].Payload.String())
+			}
+			return nil
+		}, 30*time.Second, 100*time.Millisecond).ShouldNot(HaveOccurred())
+	})
+})
@@ -0,0 +1,158 @@
+package status
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	cecontext "github.com/cloudevents/sdk-go/v2/context"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	eventversion "github.com/stolostron/multicluster-global-hub/pkg/bundle/version"
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
+	wiremodels "github.com/stolostron/multicluster-global-hub/pkg/wire/models"
+)
+
+// go test ./test/integration/manager/status -v -ginkgo.focus "SecurityFindingsHandler"
+var _ = Describe("SecurityFindingsHandler", Ordered, func() {
+	const (
+		leafHubName = "hub1"
+		source      = "rhacs-operator/stackrox-central-services"
+		detailURL   = "https://hub1/violations"
+	)
+
+	var (
+		clusterVersion    = eventversion.NewVersion()
+		deploymentVersion = eventversion.NewVersion()
+		vulnVersion       = eventversion.NewVersion()
+		statusTopicCtx    context.Context
+	)
+
+	BeforeAll(func() {
+		statusTopicCtx = cecontext.WithTopic(ctx, "event")
+	})
+
+	sendEvent := func(eventType enum.EventType, version *eventversion.Version, data any) {
+		version.Incr()
+		event := ToCloudEvent(leafHubName, string(eventType), version, data)
+		Expect(producer.SendEvent(statusTopicCtx, *event)).To(Succeed())
+		version.Next()
+	}
+
+	It("Should sync the violations of the clusters and remove the resolved ones", func() {
+		By("Send the violations of two clusters")
+		sendEvent(enum.SecurityClusterViolationsType, clusterVersion, &wiremodels.SecurityClusterViolations{
+			Clusters: []wiremodels.SecurityClusterViolation{
+				{Cluster: "cluster1", SecuritySeverityCounts: wiremodels.SecuritySeverityCounts{Critical: 3}},
+				{Cluster: "cluster2", SecuritySeverityCounts: wiremodels.SecuritySeverityCounts{Low: 1}},
+			},
+			DetailURL: detailURL,
+			Source:    source,
+		})
+		Eventually(func() error {
+			violations := []models.SecurityClusterViolations{}
+			err := database.GetGorm().Where("hub_name = ?", leafHubName).Order("cluster_name").
+				Find(&violations).Error
+			if err != nil {
+				return err
+			}
+			if len(violations) != 2 || violations[0].Critical != 3 || violations[1].Low != 1 {
+				return fmt.Errorf("unexpected cluster violations: %v", violations)
+			}
+			return nil
+		}, 30*time.Second, 100*time.Millisecond).Should(Succeed())
+
+		By("Send the violations without the cluster2")
+		sendEvent(enum.SecurityClusterViolationsType, clusterVersion, &wiremodels.SecurityClusterViolations{
+			Clusters: []wiremodels.SecurityClusterViolation{
+				{Cluster: "cluster1", SecuritySeverityCounts: wiremodels.SecuritySeverityCounts{Critical: 1}},
+			},
+			DetailURL: detailURL,
+			Source:    source,
+		})
+		Eventually(func() error {
+			violations := []models.SecurityClusterViolations{}
+			err := database.GetGorm().Where("hub_name = ?", leafHubName).Find(&violations).Error
+			if err != nil {
+				return err
+			}
+			if len(violations) != 1 || violations[0].ClusterName != "cluster1" || violations[0].Critical != 1 {
+				return fmt.Errorf("unexpected cluster violations: %v", violations)
+			}
+			return nil
+		}, 30*time.Second, 100*time.Millisecond).Should(Succeed())
+	})
+
+	It("Should sync the violations of the deployments with the policies", func() {
+		sendEvent(enum.SecurityDeploymentViolationsType, deploymentVersion, &wiremodels.SecurityDeploymentViolations{
+			Deployments: []wiremodels.SecurityDeploymentViolation{{
+				Cluster:                "cluster1",
+				Namespace:              "default",
+				Name:                   "app",
+				SecuritySeverityCounts: wiremodels.SecuritySeverityCounts{High: 1, Critical: 2},
+				Policies:               []string{"Latest Tag", "Privileged Container"},
+			}},
+			DetailURL: detailURL,
+			Source:    source,
+		})
+		Eventually(func() error {
+			violation := models.SecurityDeploymentViolations{}
+			err := database.GetGorm().Where("hub_name = ? AND deployment_name = ?", leafHubName, "app").
+				First(&violation).Error
+			if err != nil {
+				return err
+			}
+			policies := []string{}
+			if err := json.Unmarshal(violation.Policies, &policies); err != nil {
+				return err
+			}
+			if violation.Critical != 2 || len(policies) != 2 {
+				return fmt.Errorf("unexpected deployment violation: %v", violation)
+			}
+			return nil
+		}, 30*time.Second, 100*time.Millisecond).Should(Succeed())
+	})
+
+	It("Should sync the vulnerabilities of the deployments and the images", func() {
+		counts := wiremodels.SecurityVulnerabilityCounts{Low: 5, Moderate: 4, Important: 3, Critical: 1, Fixable: 4}
+		sendEvent(enum.SecurityVulnerabilitiesType, vulnVersion, &wiremodels.SecurityVulnerabilities{
+			Deployments: []wiremodels.SecurityDeploymentVulnerability{{
+				Cluster:                     "cluster1",
+				Namespace:                   "default",
+				Name:                        "app",
+				SecurityVulnerabilityCounts: counts,
+			}},
+			Images: []wiremodels.SecurityImageVulnerability{{
+				Name:                        "quay.io/org/app:latest",
+				SecurityVulnerabilityCounts: counts,
+			}},
+			DetailURL: detailURL,
+			Source:    source,
+		})
+		Eventually(func() error {
+			deployment := models.SecurityDeploymentVulnerabilities{}
+			err := database.GetGorm().Where("hub_name = ? AND deployment_name = ?", leafHubName, "app").
+				First(&deployment).Error
+			if err != nil {
+				return err
+			}
+			if deployment.Critical != 1 || deployment.Fixable != 4 {
+				return fmt.Errorf("unexpected deployment vulnerabilities: %v", deployment)
+			}
+			image := models.SecurityImageVulnerabilities{}
+			err = database.GetGorm().Where("hub_name = ? AND image_name = ?", leafHubName,
+				"quay.io/org/app:latest").First(&image).Error
+			if err != nil {
+				return err
+			}
+			if image.Low != 5 || image.Important != 3 {
+				return fmt.Errorf("unexpected image vulnerabilities: %v", image)
+			}
+			return nil
+		}, 30*time.Second, 100*time.Millisecond).Should(Succeed())
+	})
+})
@@ -101,7 +101,7 @@ var _ = BeforeSuite(func() {
 
 	By("Start cloudevents producer and consumer")
 	managerConfig.TransportConfig.IsManager = false
-	producer, err = genericproducer.NewGenericProducer(managerConfig.TransportConfig)
+	producer, err = genericproducer.NewGenericProducer(ctx, managerConfig.TransportConfig)
 	Expect(err).NotTo(HaveOccurred())
 
 	managerConfig.TransportConfig.IsManager = true
@@ -1,6 +1,7 @@
 package testpostgres
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -8,6 +9,7 @@ import (
 	"strings"
 
 	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	"github.com/stolostron/multicluster-global-hub/pkg/database/migration"
 )
 
 func InitDatabase(uri string) error {
@@ -51,25 +53,6 @@ func InitDatabase(uri string) error {
 		fmt.Printf("script %s executed successfully.\n", file.Name())
 	}
 
-	sqlDir = filepath.Join(dirname, "operator", "pkg", "controllers", "storage", "upgrade")
-	upgradeFiles, err := os.ReadDir(sqlDir)
-	if err != nil {
-		return err
-	}
-	for _, file := range upgradeFiles {
-		filePath := filepath.Join(sqlDir, file.Name())
-		fileContent, err := os.ReadFile(filePath)
-		if err != nil {
-			return err
-		}
-
-		result := db.Exec(string(fileContent))
-		if result.Error != nil {
-			return result.Error
-		}
-		fmt.Printf("script %s executed successfully.\n", file.Name())
-	}
-
 	sqlDir = filepath.Join(dirname, "operator", "pkg", "controllers", "storage", "database.old")
 	oldfiles, err := os.ReadDir(sqlDir)
 	if err != nil {
@@ -91,5 +74,21 @@ func InitDatabase(uri string) error {
 		}
 		fmt.Printf("script %s executed successfully.\n", file.Name())
 	}
+
+	sqlDir = filepath.Join(dirname, "operator", "pkg", "controllers", "storage", "migrations")
+	migrations, err := migration.Load(os.DirFS(sqlDir), ".")
+	if err != nil {
+		return err
+	}
+	conn, err := database.PostgresConnection(context.Background(), uri, nil)
+	if err != nil {
+		return err
+	}
+	defer conn.Close(context.Background())
+	applied, err := migration.NewMigrator(conn, migrations).Migrate(context.Background())
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%d migrations applied successfully.\n", len(applied))
 	return nil
 }